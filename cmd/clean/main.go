@@ -83,6 +83,18 @@ func main() {
 			if e := cleaner.CleanReportComments(); e != nil {
 				fmt.Printf("[ERROR] %v\n", e)
 			}
+			if e := cleaner.CleanReportEvents(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
+			if e := cleaner.CleanReportSimilarities(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
+			if e := cleaner.CleanReportAttachments(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
+			if e := cleaner.CleanBounties(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
 			if e := cleaner.CleanReports(); e != nil {
 				fmt.Printf("[ERROR] %v\n", e)
 			}
@@ -102,6 +114,9 @@ func main() {
 			}
 		}
 		if *projectsFlag {
+			if e := cleaner.CleanProjectScopes(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
 			if e := cleaner.CleanProjectAttachments(); e != nil {
 				fmt.Printf("[ERROR] %v\n", e)
 			}
@@ -116,12 +131,39 @@ func main() {
 			}
 		}
 		if *usersFlag {
+			if e := cleaner.CleanNotifications(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
 			if e := cleaner.CleanUserUpdateLogs(); e != nil {
 				fmt.Printf("[ERROR] %v\n", e)
 			}
 			if e := cleaner.CleanUserInfoChanges(); e != nil {
 				fmt.Printf("[ERROR] %v\n", e)
 			}
+			if e := cleaner.CleanUploadSessions(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
+			if e := cleaner.CleanAuthTokens(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
+			if e := cleaner.CleanTwoFactor(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
+			if e := cleaner.CleanAccountTokens(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
+			if e := cleaner.CleanSecurityEvents(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
+			if e := cleaner.CleanPasswordHistories(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
+			if e := cleaner.CleanUserIdentities(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
+			if e := cleaner.CleanAPIKeys(); e != nil {
+				fmt.Printf("[ERROR] %v\n", e)
+			}
 			if e := cleaner.CleanUsers(); e != nil {
 				fmt.Printf("[ERROR] %v\n", e)
 			}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/internal/repository"
+	"bug-bounty-lite/internal/service"
+	"bug-bounty-lite/pkg/config"
+	"bug-bounty-lite/pkg/database"
+	"bug-bounty-lite/pkg/upload"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+func main() {
+	// 命令行参数
+	days := flag.Int("days", 7, "只回收超过该天数未被引用的文件（同时作为软删除报告和项目附件的保留期，最少 2 天）")
+	dryRun := flag.Bool("dry-run", false, "只列出孤儿文件，不删除或移动")
+	action := flag.String("action", domain.UploadGCActionQuarantine, "孤儿文件处理方式: quarantine (移动到 orphaned/ 目录) / delete (直接删除)")
+	verbose := flag.Bool("v", false, "输出每个孤儿文件")
+	flag.Parse()
+
+	fmt.Println("=== Bug Bounty Lite Upload Garbage Collection ===")
+
+	// 1. 加载配置
+	cfg := config.LoadConfig()
+
+	// 2. 初始化数据库连接和存储
+	db := database.InitDB(cfg)
+	storage, err := upload.NewStorage(cfg.Storage)
+	if err != nil {
+		log.Fatalf("[FATAL] Failed to init storage: %v", err)
+	}
+
+	// 3. 回收孤儿文件
+	uploadSessionService := service.NewUploadSessionService(repository.NewUploadSessionRepo(db), storage, cfg.Upload.Chunked)
+	gcService := service.NewUploadGCService(repository.NewUploadReferenceRepo(db), uploadSessionService, storage)
+
+	result, err := gcService.Run(context.Background(), domain.UploadGCOptions{
+		OlderThan: time.Duration(*days) * 24 * time.Hour,
+		Action:    *action,
+		DryRun:    *dryRun,
+	})
+	if err != nil {
+		log.Fatalf("[FATAL] Upload garbage collection failed: %v", err)
+	}
+
+	// 4. 输出结果
+	if *dryRun || *verbose {
+		for _, orphan := range result.Orphans {
+			fmt.Printf("[ORPHAN]   %s (%s, modified %s)\n",
+				orphan.Key, upload.FormatSize(orphan.Size), orphan.ModTime.Format("2006-01-02 15:04"))
+		}
+	}
+
+	fmt.Println("--------------------------------")
+	fmt.Printf("Scanned:  %d\n", result.Scanned)
+	fmt.Printf("Orphans:  %d (%s, older than %d days)\n", len(result.Orphans), upload.FormatSize(result.OrphanBytes), *days)
+	if *dryRun {
+		fmt.Println("\n[DRY RUN] No files were changed")
+		return
+	}
+	fmt.Printf("Expired upload sessions: %d\n", result.ExpiredSessions)
+	if *action == domain.UploadGCActionDelete {
+		fmt.Printf("Deleted:  %d\n", result.Removed)
+	} else {
+		fmt.Printf("Moved to %s/: %d\n", upload.OrphanDir, result.Removed)
+	}
+
+	if result.Failed > 0 {
+		fmt.Printf("Failed:   %d\n", result.Failed)
+		fmt.Println("\n[FAIL] Some orphaned files could not be processed")
+		os.Exit(1)
+	}
+	fmt.Println("\n[OK] Upload garbage collection completed")
+}
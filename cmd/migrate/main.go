@@ -4,6 +4,7 @@ import (
 	"bug-bounty-lite/pkg/config"
 	"bug-bounty-lite/pkg/database"
 	"bug-bounty-lite/pkg/migrate"
+	"bug-bounty-lite/pkg/upload"
 	"fmt"
 	"log"
 )
@@ -17,8 +18,13 @@ func main() {
 	// 2. 初始化数据库连接
 	db := database.InitDB(cfg)
 
-	// 3. 创建迁移器
-	migrator := migrate.NewMigrator(db)
+	// 3. 创建迁移器（存储不可用时历史附件不补全大小和 SHA-256）
+	storage, err := upload.NewStorage(cfg.Storage)
+	if err != nil {
+		log.Printf("[WARN] Failed to init storage, attachment hashes will not be backfilled: %v", err)
+		storage = nil
+	}
+	migrator := migrate.NewMigrator(db, storage)
 
 	// 4. 执行迁移
 	fmt.Println("[STEP] Running Migrations...")
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/internal/repository"
+	"bug-bounty-lite/internal/service"
 	"bug-bounty-lite/pkg/config"
 	"bug-bounty-lite/pkg/database"
 
@@ -28,13 +30,20 @@ const (
 
 var db *gorm.DB
 
+// reportService 审核操作统一走报告服务，由工作流引擎校验状态流转
+var reportService domain.ReportService
+
+// cliRole 命令行工具以管理员身份执行审核
+const cliRole = "admin"
+
 func main() {
 	// 命令行参数
 	listPending := flag.Bool("list", false, "列出所有待审核的漏洞报告")
-	listAudited := flag.Bool("audited", false, "列出所有已审核的漏洞报告")
+	listAudited := flag.Bool("audited", false, "列出所有已确认的漏洞报告")
 	listAll := flag.Bool("all", false, "列出所有漏洞报告")
 	approveID := flag.Int("approve", 0, "审核通过指定ID的报告 (需要 -severity 参数)")
-	rejectID := flag.Int("reject", 0, "驳回指定ID的报告")
+	rejectID := flag.Int("reject", 0, "驳回指定ID的报告 (需要 -reason 参数)")
+	reason := flag.String("reason", "", "驳回原因")
 	severity := flag.String("severity", "", "设置危害等级: Critical, High, Medium, Low, None")
 	interactive := flag.Bool("i", false, "交互式审核模式")
 	flag.Parse()
@@ -45,6 +54,12 @@ func main() {
 	// 连接数据库
 	db = database.InitDB(cfg)
 
+	// 组装报告服务
+	projectRepo := repository.NewProjectRepo(db)
+	workflowService := service.NewReportWorkflowService(repository.NewReportWorkflowRepo(db), projectRepo)
+	scopeService := service.NewProjectScopeService(repository.NewProjectScopeRepo(db), projectRepo)
+	reportService = service.NewReportService(repository.NewReportRepo(db), repository.NewSystemConfigRepo(db), workflowService, repository.NewReportSimilarityRepo(db), projectRepo, scopeService)
+
 	printBanner()
 
 	// 根据参数执行不同操作
@@ -65,7 +80,11 @@ func main() {
 		}
 		approveReport(uint(*approveID), *severity)
 	case *rejectID > 0:
-		rejectReport(uint(*rejectID))
+		if *reason == "" {
+			fmt.Printf("%s❌ 驳回时必须提供原因 (-reason)%s\n", colorRed, colorReset)
+			os.Exit(1)
+		}
+		rejectReport(uint(*rejectID), *reason)
 	default:
 		printHelp()
 	}
@@ -82,10 +101,10 @@ func printBanner() {
 func printHelp() {
 	fmt.Println("用法:")
 	fmt.Printf("  %s-list%s              列出所有待审核的漏洞报告\n", colorGreen, colorReset)
-	fmt.Printf("  %s-audited%s           列出所有已审核的漏洞报告\n", colorGreen, colorReset)
+	fmt.Printf("  %s-audited%s           列出所有已确认的漏洞报告\n", colorGreen, colorReset)
 	fmt.Printf("  %s-all%s               列出所有漏洞报告\n", colorGreen, colorReset)
 	fmt.Printf("  %s-approve <ID> -severity <等级>%s  审核通过指定ID的报告\n", colorGreen, colorReset)
-	fmt.Printf("  %s-reject <ID>%s       驳回指定ID的报告\n", colorGreen, colorReset)
+	fmt.Printf("  %s-reject <ID> -reason <原因>%s  驳回指定ID的报告\n", colorGreen, colorReset)
 	fmt.Printf("  %s-i%s                 交互式审核模式\n", colorGreen, colorReset)
 	fmt.Println()
 	fmt.Println("危害等级说明:")
@@ -95,13 +114,13 @@ func printHelp() {
 	fmt.Println("Makefile 命令示例:")
 	fmt.Printf("  make vuln-list                              # 查看待审核列表\n")
 	fmt.Printf("  make vuln-approve ID=5 SEVERITY=High        # 通过ID=5的报告，评为高危\n")
-	fmt.Printf("  make vuln-reject ID=5                       # 驳回ID=5的报告\n")
+	fmt.Printf("  make vuln-reject ID=5 REASON=\"无法复现\"     # 驳回ID=5的报告\n")
 	fmt.Printf("  make vuln-interactive                       # 交互式模式\n")
 }
 
 func listPendingReports() {
 	var reports []domain.Report
-	if err := db.Where("status = ?", "Pending").Order("created_at DESC").Find(&reports).Error; err != nil {
+	if err := db.Where("status = ?", domain.ReportStatusPending).Order("created_at DESC").Find(&reports).Error; err != nil {
 		fmt.Printf("%s❌ 查询失败: %v%s\n", colorRed, err, colorReset)
 		return
 	}
@@ -117,17 +136,17 @@ func listPendingReports() {
 
 func listAuditedReports() {
 	var reports []domain.Report
-	if err := db.Where("status = ?", "Audited").Order("created_at DESC").Find(&reports).Error; err != nil {
+	if err := db.Where("status = ?", domain.ReportStatusTriaged).Order("created_at DESC").Find(&reports).Error; err != nil {
 		fmt.Printf("%s❌ 查询失败: %v%s\n", colorRed, err, colorReset)
 		return
 	}
 
 	if len(reports) == 0 {
-		fmt.Printf("%s暂无已审核的漏洞报告%s\n", colorYellow, colorReset)
+		fmt.Printf("%s暂无已确认的漏洞报告%s\n", colorYellow, colorReset)
 		return
 	}
 
-	fmt.Printf("\n%s✅ 已审核漏洞报告列表 (共 %d 条)%s\n", colorBold, len(reports), colorReset)
+	fmt.Printf("\n%s✅ 已确认漏洞报告列表 (共 %d 条)%s\n", colorBold, len(reports), colorReset)
 	printReportTable(reports)
 }
 
@@ -176,12 +195,24 @@ func getSelfSeverity(r domain.Report) string {
 
 func getStatusDisplay(status string) string {
 	switch status {
-	case "Pending":
+	case domain.ReportStatusPending:
 		return colorYellow + "待审核" + colorReset
-	case "Audited":
-		return colorGreen + "已审核" + colorReset
-	case "Rejected":
+	case domain.ReportStatusNeedsMoreInfo:
+		return colorYellow + "需补充" + colorReset
+	case domain.ReportStatusTriaged:
+		return colorGreen + "已确认" + colorReset
+	case domain.ReportStatusResolved:
+		return colorGreen + "已修复" + colorReset
+	case domain.ReportStatusClosed:
+		return colorCyan + "已关闭" + colorReset
+	case domain.ReportStatusRejected:
 		return colorRed + "已驳回" + colorReset
+	case domain.ReportStatusDuplicate:
+		return colorRed + "重复" + colorReset
+	case domain.ReportStatusInformative:
+		return colorCyan + "仅供参考" + colorReset
+	case domain.ReportStatusWontFix:
+		return colorRed + "不予修复" + colorReset
 	default:
 		return status
 	}
@@ -221,21 +252,13 @@ func approveReport(id uint, severity string) {
 		return
 	}
 
-	var report domain.Report
-	if err := db.First(&report, id).Error; err != nil {
-		fmt.Printf("%s❌ 报告不存在 (ID: %d)%s\n", colorRed, id, colorReset)
-		return
-	}
-
-	if report.Status != "Pending" {
-		fmt.Printf("%s⚠️ 报告已经审核过了 (当前状态: %s)%s\n", colorYellow, report.Status, colorReset)
-		return
-	}
-
-	report.Status = "Audited"
-	report.Severity = severity
-	if err := db.Save(&report).Error; err != nil {
-		fmt.Printf("%s❌ 审核失败: %v%s\n", colorRed, err, colorReset)
+	// 状态流转由工作流引擎校验
+	report, err := reportService.UpdateReport(id, 0, cliRole, &domain.ReportUpdateInput{
+		Status:   domain.ReportStatusTriaged,
+		Severity: severity,
+	})
+	if err != nil {
+		fmt.Printf("%s❌ 审核失败 (ID: %d): %v%s\n", colorRed, id, err, colorReset)
 		return
 	}
 
@@ -245,27 +268,21 @@ func approveReport(id uint, severity string) {
 	fmt.Printf("   危害等级: %s\n", getSeverityDisplay(severity))
 }
 
-func rejectReport(id uint) {
-	var report domain.Report
-	if err := db.First(&report, id).Error; err != nil {
-		fmt.Printf("%s❌ 报告不存在 (ID: %d)%s\n", colorRed, id, colorReset)
-		return
-	}
-
-	if report.Status != "Pending" {
-		fmt.Printf("%s⚠️ 报告已经审核过了 (当前状态: %s)%s\n", colorYellow, report.Status, colorReset)
-		return
-	}
-
-	report.Status = "Rejected"
-	if err := db.Save(&report).Error; err != nil {
-		fmt.Printf("%s❌ 驳回失败: %v%s\n", colorRed, err, colorReset)
+func rejectReport(id uint, reason string) {
+	// 状态流转由工作流引擎校验
+	report, err := reportService.UpdateReport(id, 0, cliRole, &domain.ReportUpdateInput{
+		Status: domain.ReportStatusRejected,
+		Reason: reason,
+	})
+	if err != nil {
+		fmt.Printf("%s❌ 驳回失败 (ID: %d): %v%s\n", colorRed, id, err, colorReset)
 		return
 	}
 
 	fmt.Printf("%s✅ 报告已驳回%s\n", colorYellow, colorReset)
 	fmt.Printf("   ID: %d\n", report.ID)
 	fmt.Printf("   漏洞名称: %s\n", report.VulnerabilityName)
+	fmt.Printf("   驳回原因: %s\n", reason)
 }
 
 func interactiveMode() {
@@ -274,7 +291,7 @@ func interactiveMode() {
 	for {
 		// 获取待审核报告
 		var reports []domain.Report
-		if err := db.Where("status = ?", "Pending").Order("created_at ASC").Find(&reports).Error; err != nil {
+		if err := db.Where("status = ?", domain.ReportStatusPending).Order("created_at ASC").Find(&reports).Error; err != nil {
 			fmt.Printf("%s❌ 查询失败: %v%s\n", colorRed, err, colorReset)
 			return
 		}
@@ -309,7 +326,14 @@ func interactiveMode() {
 		case "5":
 			approveReport(report.ID, "None")
 		case "r", "R":
-			rejectReport(report.ID)
+			fmt.Print("请输入驳回原因: ")
+			reason, _ := reader.ReadString('\n')
+			reason = strings.TrimSpace(reason)
+			if reason == "" {
+				fmt.Printf("%s驳回原因不能为空%s\n", colorRed, colorReset)
+				break
+			}
+			rejectReport(report.ID, reason)
 		case "s", "S":
 			fmt.Println("已跳过")
 		case "q", "Q":
@@ -2,9 +2,15 @@ package main
 
 import (
 	"bug-bounty-lite/internal/router"
+	"bug-bounty-lite/pkg/breached"
 	"bug-bounty-lite/pkg/config"
 	"bug-bounty-lite/pkg/database"
+	"bug-bounty-lite/pkg/lockout"
+	"bug-bounty-lite/pkg/mailer"
 	"bug-bounty-lite/pkg/migrate"
+	"bug-bounty-lite/pkg/oidc"
+	"bug-bounty-lite/pkg/upload"
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -24,9 +30,16 @@ func main() {
 	// 2. 初始化数据库
 	db := database.InitDB(cfg)
 
-	// 3. 可选：执行数据库迁移
+	// 3. 初始化上传文件存储
+	storage, err := upload.NewStorage(cfg.Storage)
+	if err != nil {
+		log.Fatalf("[ERROR] Failed to init storage: %v", err)
+	}
+	fmt.Printf("[INFO] Upload storage driver: %s\n", storageDriverName(cfg.Storage.Driver))
+
+	// 4. 可选：执行数据库迁移
 	if *migrateFlag {
-		migrator := migrate.NewMigrator(db)
+		migrator := migrate.NewMigrator(db, storage)
 		if err := migrator.Run(); err != nil {
 			log.Fatalf("[ERROR] Migration failed: %v", err)
 		}
@@ -34,11 +47,67 @@ func main() {
 		fmt.Println("[INFO] Skipping migrations (use --migrate to run)")
 	}
 
-	// 4. 初始化路由
+	// 5. 初始化报告附件恶意软件扫描器
+	scanner, err := upload.NewScanner(cfg.Upload.Scanner)
+	if err != nil {
+		log.Fatalf("[ERROR] Failed to init scanner: %v", err)
+	}
+	if clamd, ok := scanner.(*upload.ClamdScanner); ok {
+		// clamd 暂不可用不影响启动，附件保持待扫描状态直到重新扫描成功
+		if err := clamd.Ping(context.Background()); err != nil {
+			log.Printf("[WARN] clamd is not reachable, attachments will stay pending: %v", err)
+		}
+	}
+	fmt.Printf("[INFO] Upload scanner driver: %s\n", scannerDriverName(cfg.Upload.Scanner.Driver))
+
+	// 6. 初始化邮件发送器（邮箱验证、找回密码）
+	mail, err := mailer.NewMailer(cfg.Mail)
+	if err != nil {
+		log.Fatalf("[ERROR] Failed to init mailer: %v", err)
+	}
+	fmt.Printf("[INFO] Mail driver: %s\n", mailDriverName(cfg.Mail.Driver))
+
+	// 7. 初始化登录失败计数存储（防暴力破解）
+	attempts, err := lockout.NewStore(cfg.Auth.Lockout.Store, cfg.Redis)
+	if err != nil {
+		log.Fatalf("[ERROR] Failed to init login attempt store: %v", err)
+	}
+	if redisStore, ok := attempts.(*lockout.RedisStore); ok {
+		// Redis 暂不可用不影响启动，期间不限制登录失败次数
+		if err := redisStore.Ping(context.Background()); err != nil {
+			log.Printf("[WARN] Redis is not reachable, login attempts will not be limited: %v", err)
+		}
+	}
+	fmt.Printf("[INFO] Login attempt store: %s\n", lockoutStoreName(cfg.Auth.Lockout.Store))
+
+	// 8. 可选：加载本地泄露密码库（设置密码时离线检查）
+	var breachedList breached.Source
+	if path := cfg.Auth.Password.BreachedList; path != "" {
+		breachedList, err = breached.Open(path)
+		if err != nil {
+			log.Fatalf("[ERROR] Failed to load breached password list: %v", err)
+		}
+		if list, ok := breachedList.(*breached.FileSource); ok {
+			fmt.Printf("[INFO] Breached password list: %s (%d hashes)\n", path, list.Len())
+		} else {
+			fmt.Printf("[INFO] Breached password list: %s (range files)\n", path)
+		}
+	}
+
+	// 9. 初始化 OIDC 单点登录身份提供方（首次登录时才读取发现文档）
+	oidcProviders, err := oidc.NewProviders(cfg.Auth.OIDC.Providers)
+	if err != nil {
+		log.Fatalf("[ERROR] Failed to init OIDC providers: %v", err)
+	}
+	for _, p := range oidcProviders {
+		fmt.Printf("[INFO] OIDC provider: %s (%s)\n", p.Name(), p.Config().Issuer)
+	}
+
+	// 10. 初始化路由
 	// 这一步会将 Repo, Service, Handler, Middleware 全部组装起来
-	r := router.SetupRouter(db, cfg)
+	r := router.SetupRouter(db, cfg, storage, scanner, mail, attempts, breachedList, oidcProviders)
 
-	// 5. 启动 HTTP 服务
+	// 11. 启动 HTTP 服务
 	serverAddr := cfg.Server.Port
 	fmt.Println("--------------------------------")
 	fmt.Printf("[INFO] Server starting on %s ...\n", serverAddr)
@@ -50,3 +119,35 @@ func main() {
 		log.Fatalf("[ERROR] Failed to start server: %v", err)
 	}
 }
+
+// storageDriverName 返回实际使用的存储驱动名称（未配置时为 local）
+func storageDriverName(driver string) string {
+	if driver == "" {
+		return upload.StorageDriverLocal
+	}
+	return driver
+}
+
+// scannerDriverName 返回实际使用的扫描驱动名称（未配置时为 noop）
+func scannerDriverName(driver string) string {
+	if driver == "" {
+		return upload.ScannerDriverNoop
+	}
+	return driver
+}
+
+// lockoutStoreName 返回实际使用的登录失败计数存储名称（未配置时为 memory）
+func lockoutStoreName(driver string) string {
+	if driver == "" {
+		return lockout.StoreDriverMemory
+	}
+	return driver
+}
+
+// mailDriverName 返回实际使用的发信驱动名称（未配置时为 log）
+func mailDriverName(driver string) string {
+	if driver == "" {
+		return mailer.DriverLog
+	}
+	return driver
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"bug-bounty-lite/pkg/database"
+	"bug-bounty-lite/pkg/upload"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"gorm.io/gorm"
+)
+
+// fileRecord 引用上传文件的数据库记录
+type fileRecord struct {
+	ID       uint
+	URL      string
+	SHA256   string
+	FileSize int64
+}
+
+// recordSource 需要校验的表（hasHash 表示表中记录了 sha256 和 file_size，where 为额外的过滤条件）
+type recordSource struct {
+	table   string
+	hasHash bool
+	where   string
+}
+
+var sources = []recordSource{
+	// 感染的附件文件已移入隔离目录，不再校验
+	{table: "report_attachments", hasHash: true, where: "scan_status <> 'infected'"},
+	{table: "project_attachments", hasHash: true},
+	{table: "avatars", hasHash: false},
+}
+
+// verifyStats 校验结果统计
+type verifyStats struct {
+	checked    int
+	missing    int
+	mismatched int
+	unverified int // 历史文件没有记录哈希，只确认文件存在
+	backfilled int
+}
+
+func main() {
+	// 命令行参数
+	backfill := flag.Bool("backfill", false, "为没有记录 SHA-256 的历史附件补全哈希和文件大小")
+	verbose := flag.Bool("v", false, "输出每个文件的校验结果")
+	flag.Parse()
+
+	fmt.Println("=== Bug Bounty Lite Upload Integrity Check ===")
+
+	// 1. 加载配置
+	cfg := config.LoadConfig()
+
+	// 2. 初始化数据库连接和存储
+	db := database.InitDB(cfg)
+	storage, err := upload.NewStorage(cfg.Storage)
+	if err != nil {
+		log.Fatalf("[FATAL] Failed to init storage: %v", err)
+	}
+
+	// 3. 逐表校验
+	ctx := context.Background()
+	var stats verifyStats
+	for _, source := range sources {
+		if !db.Migrator().HasTable(source.table) {
+			continue
+		}
+		if err := verifySource(ctx, db, storage, source, *backfill, *verbose, &stats); err != nil {
+			log.Fatalf("[FATAL] Failed to verify %s: %v", source.table, err)
+		}
+	}
+
+	// 4. 输出结果
+	fmt.Println("--------------------------------")
+	fmt.Printf("Checked:    %d\n", stats.checked)
+	fmt.Printf("Missing:    %d\n", stats.missing)
+	fmt.Printf("Mismatched: %d\n", stats.mismatched)
+	fmt.Printf("Unverified: %d (no recorded hash)\n", stats.unverified)
+	if *backfill {
+		fmt.Printf("Backfilled: %d\n", stats.backfilled)
+	}
+
+	if stats.missing > 0 || stats.mismatched > 0 {
+		fmt.Println("\n[FAIL] Upload integrity check found problems")
+		os.Exit(1)
+	}
+	fmt.Println("\n[OK] All uploaded files are intact")
+}
+
+// verifySource 校验一张表中引用的所有上传文件
+func verifySource(ctx context.Context, db *gorm.DB, storage upload.Storage, source recordSource, backfill, verbose bool, stats *verifyStats) error {
+	columns := "id, url"
+	if source.hasHash {
+		columns = "id, url, sha256, file_size"
+	}
+
+	query := db.Table(source.table).Select(columns)
+	if source.where != "" {
+		query = query.Where(source.where)
+	}
+
+	var records []fileRecord
+	return query.Order("id").
+		FindInBatches(&records, 200, func(tx *gorm.DB, batch int) error {
+			for _, record := range records {
+				verifyRecord(ctx, db, storage, source, record, backfill, verbose, stats)
+			}
+			return nil
+		}).Error
+}
+
+// verifyRecord 读取文件重新计算 SHA-256，与记录的哈希（或内容寻址存储键中的哈希）比对
+func verifyRecord(ctx context.Context, db *gorm.DB, storage upload.Storage, source recordSource, record fileRecord, backfill, verbose bool, stats *verifyStats) {
+	key, ok := upload.FilePathFromURL(record.URL)
+	if !ok {
+		// 不是本平台上传的文件（如外部链接）
+		return
+	}
+	stats.checked++
+	label := fmt.Sprintf("%s#%d %s", source.table, record.ID, key)
+
+	actual, size, err := upload.HashObject(ctx, storage, key)
+	if err != nil {
+		// 读取失败同样视为文件缺失
+		stats.missing++
+		if errors.Is(err, upload.ErrObjectNotFound) {
+			fmt.Printf("[MISSING]  %s\n", label)
+		} else {
+			fmt.Printf("[ERROR]    %s: %v\n", label, err)
+		}
+		return
+	}
+
+	keyHash, _ := upload.HashFromKey(key)
+	expected := record.SHA256
+	if expected == "" {
+		expected = keyHash
+	}
+
+	switch {
+	case expected == "":
+		stats.unverified++
+		if verbose {
+			fmt.Printf("[UNKNOWN]  %s (no recorded hash)\n", label)
+		}
+	case actual != expected || (keyHash != "" && keyHash != expected):
+		stats.mismatched++
+		fmt.Printf("[MISMATCH] %s expected %s, got %s\n", label, expected, actual)
+		return
+	default:
+		if verbose {
+			fmt.Printf("[OK]       %s\n", label)
+		}
+	}
+
+	// 补全历史附件的哈希和文件大小（内容不一致的不补全）
+	if backfill && source.hasHash && record.SHA256 == "" {
+		if err := db.Table(source.table).Where("id = ?", record.ID).
+			Updates(map[string]interface{}{"sha256": actual, "file_size": size}).Error; err != nil {
+			fmt.Printf("[ERROR]    %s: backfill failed: %v\n", label, err)
+			return
+		}
+		stats.backfilled++
+		if verbose {
+			fmt.Printf("[FILLED]   %s %s\n", label, actual)
+		}
+	}
+}
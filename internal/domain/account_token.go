@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// 账号令牌用途
+const (
+	AccountTokenPasswordReset = "password_reset" // 重置密码
+	AccountTokenEmailVerify   = "email_verify"   // 验证邮箱
+)
+
+// 邮箱验证与找回密码相关错误
+var (
+	ErrAccountTokenInvalid     = errors.New("链接无效或已过期，请重新获取")
+	ErrEmailNotSet             = errors.New("请先设置邮箱")
+	ErrEmailAlreadyVerified    = errors.New("邮箱已验证")
+	ErrAccountTokenTooFrequent = errors.New("发送过于频繁，请稍后再试")
+)
+
+// AccountToken 通过邮件发送的一次性令牌（只保存 SHA-256 哈希，使用后或过期即失效）
+type AccountToken struct {
+	ID        uint      `gorm:"primaryKey;comment:令牌ID" json:"id"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UserID    uint      `gorm:"not null;index;comment:用户ID" json:"user_id"`
+	Purpose   string    `gorm:"size:20;not null;comment:用途(password_reset:重置密码/email_verify:验证邮箱)" json:"purpose"`
+	TokenHash string    `gorm:"size:64;uniqueIndex;not null;comment:令牌SHA-256哈希" json:"-"`
+
+	// Email 令牌发送到的邮箱，用户邮箱已变更则令牌失效
+	Email     string     `gorm:"size:100;comment:发送邮箱" json:"email"`
+	ExpiresAt time.Time  `gorm:"not null;index;comment:过期时间" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"comment:使用时间" json:"used_at"`
+}
+
+// TableName 指定表名
+func (AccountToken) TableName() string {
+	return "account_tokens"
+}
+
+// AccountTokenRepository 账号令牌仓库接口
+type AccountTokenRepository interface {
+	// Create 保存新令牌，同时作废该用户同用途的未使用令牌
+	Create(token *AccountToken) error
+	FindByHash(tokenHash string) (*AccountToken, error)
+	// FindLatest 获取用户同用途最近创建的令牌，不存在时返回 nil
+	FindLatest(userID uint, purpose string) (*AccountToken, error)
+	// MarkUsed 以未使用为条件标记令牌已使用，返回是否成功（防止并发重复使用）
+	MarkUsed(id uint, usedAt time.Time) (bool, error)
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+// AccountService 邮箱验证与找回密码服务接口
+type AccountService interface {
+	// RequestPasswordReset 向该邮箱对应的账号发送重置密码邮件，邮箱不存在时同样返回成功（防止枚举）
+	RequestPasswordReset(email string) error
+	// ResetPassword 使用重置令牌设置新密码，并注销该用户的全部会话
+	ResetPassword(token, newPassword string) error
+	// SendVerificationEmail 向用户当前邮箱发送验证邮件
+	SendVerificationEmail(userID uint) error
+	// VerifyEmail 使用验证令牌完成邮箱验证
+	VerifyEmail(token string) error
+	// IsEmailVerified 用户当前邮箱是否已验证
+	IsEmailVerified(userID uint) (bool, error)
+	// CleanupExpired 清理过期的令牌
+	CleanupExpired() (int64, error)
+}
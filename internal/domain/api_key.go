@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// API Key 授权范围
+const (
+	APIKeyScopeReportsRead  = "reports:read"  // 查看报告、附件、评论、时间线
+	APIKeyScopeReportsWrite = "reports:write" // 提交、更新报告，上传附件，发表评论，执行状态流转
+	APIKeyScopeProjectsRead = "projects:read" // 查看项目
+)
+
+// APIKeyScopes 全部授权范围
+var APIKeyScopes = []string{APIKeyScopeReportsRead, APIKeyScopeReportsWrite, APIKeyScopeProjectsRead}
+
+// API Key 相关错误
+var (
+	ErrAPIKeyInvalid       = errors.New("API Key 无效、已过期或已吊销")
+	ErrAPIKeyNotFound      = errors.New("API Key 不存在")
+	ErrAPIKeyNameInvalid   = errors.New("名称不能为空且不能超过 64 个字符")
+	ErrAPIKeyScopeInvalid  = errors.New("无效的授权范围")
+	ErrAPIKeyExpiryInvalid = errors.New("有效期超出允许范围")
+	ErrAPIKeyLimitExceeded = errors.New("API Key 数量已达上限，请先吊销不再使用的 Key")
+)
+
+// APIKey 个人 API Key（只保存 SHA-256 哈希，明文只在创建时返回一次）
+type APIKey struct {
+	ID        uint      `gorm:"primaryKey;comment:API Key ID" json:"id"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UserID    uint      `gorm:"not null;index;comment:用户ID" json:"user_id"`
+	Name      string    `gorm:"size:64;not null;comment:名称" json:"name"`
+
+	// Prefix Key 的前 12 个字符，用于在列表中辨认
+	Prefix  string `gorm:"size:16;not null;comment:Key前缀" json:"prefix"`
+	KeyHash string `gorm:"size:64;uniqueIndex;not null;comment:Key的SHA-256哈希" json:"-"`
+	Scopes  string `gorm:"size:255;not null;comment:授权范围(逗号分隔)" json:"-"`
+
+	ExpiresAt  time.Time  `gorm:"not null;index;comment:过期时间" json:"expires_at"`
+	LastUsedAt *time.Time `gorm:"comment:最近使用时间" json:"last_used_at"`
+	LastUsedIP string     `gorm:"size:64;comment:最近使用IP" json:"last_used_ip"`
+	RevokedAt  *time.Time `gorm:"comment:吊销时间" json:"revoked_at"`
+
+	// ScopeList 授权范围列表（不存储）
+	ScopeList []string `gorm:"-" json:"scopes"`
+}
+
+// TableName 指定表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// HasScope 是否包含指定授权范围
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range strings.Split(k.Scopes, ",") {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidAPIKeyScope 是否为已定义的授权范围
+func IsValidAPIKeyScope(scope string) bool {
+	for _, s := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyCreated 新建 API Key 的结果，Key 为明文，只返回这一次
+type APIKeyCreated struct {
+	Key    string  `json:"key"`
+	APIKey *APIKey `json:"api_key"`
+}
+
+// APIKeyRepository API Key 仓库接口
+type APIKeyRepository interface {
+	Create(key *APIKey) error
+	FindByHash(keyHash string) (*APIKey, error)
+	// ListByUser 获取用户全部未吊销的 Key（包括已过期的）
+	ListByUser(userID uint) ([]APIKey, error)
+	// CountActive 统计用户未吊销且未过期的 Key
+	CountActive(userID uint, now time.Time) (int64, error)
+	// Revoke 吊销用户的 Key，返回是否成功（不存在或已吊销时返回 false）
+	Revoke(userID, id uint, revokedAt time.Time) (bool, error)
+	// RevokeAllByUser 吊销用户全部未吊销的 Key，返回吊销数量
+	RevokeAllByUser(userID uint, revokedAt time.Time) (int64, error)
+	TouchLastUsed(id uint, ip string, usedAt time.Time) error
+	// DeleteExpired 删除过期或吊销时间早于 before 的 Key
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+// APIKeyService API Key 服务接口
+type APIKeyService interface {
+	// Create 创建 API Key，expiresInDays 为 0 时使用默认有效期
+	Create(userID uint, name string, scopes []string, expiresInDays int) (*APIKeyCreated, error)
+	List(userID uint) ([]APIKey, error)
+	Revoke(userID, id uint) error
+	// ValidateAPIKey 校验 Key 并返回所属用户，按间隔记录最近使用时间和 IP
+	ValidateAPIKey(key, clientIP string) (*APIKey, *User, error)
+	// CleanupExpired 清理过期和已吊销的 Key
+	CleanupExpired() (int64, error)
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"bug-bounty-lite/pkg/jwt"
+	"errors"
+	"time"
+)
+
+// 令牌相关错误
+var (
+	ErrRefreshTokenInvalid = errors.New("刷新令牌无效或已过期")
+	ErrRefreshTokenReused  = errors.New("刷新令牌已被使用，该会话已被注销，请重新登录")
+	ErrTokenRevoked        = errors.New("token has been revoked")
+)
+
+// RefreshToken 刷新令牌（服务端只保存 SHA-256 哈希）
+// 每次刷新都会吊销旧令牌并签发新令牌（轮换），已吊销的令牌再次使用时视为泄露，整个会话随之注销
+type RefreshToken struct {
+	ID        uint      `gorm:"primaryKey;comment:刷新令牌ID" json:"id"`
+	CreatedAt time.Time `gorm:"comment:签发时间" json:"created_at"`
+
+	UserID    uint   `gorm:"not null;index;comment:用户ID" json:"user_id"`
+	SessionID string `gorm:"size:32;not null;index;comment:登录会话ID(同一次登录轮换出的令牌相同)" json:"session_id"`
+	TokenHash string `gorm:"size:64;not null;uniqueIndex;comment:令牌SHA-256哈希" json:"-"`
+
+	ExpiresAt    time.Time  `gorm:"not null;index;comment:过期时间" json:"expires_at"`
+	RevokedAt    *time.Time `gorm:"comment:吊销时间(轮换或注销)" json:"revoked_at"`
+	ReplacedByID uint       `gorm:"default:0;comment:轮换后的新令牌ID" json:"replaced_by_id"`
+}
+
+// TableName 指定表名
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// RevokedToken 已吊销的访问令牌（按 jti 记录，过期后清理）
+type RevokedToken struct {
+	TokenID   string    `gorm:"primaryKey;size:32;comment:访问令牌ID(jti)" json:"token_id"`
+	CreatedAt time.Time `gorm:"comment:吊销时间" json:"created_at"`
+	UserID    uint      `gorm:"not null;index;comment:用户ID" json:"user_id"`
+	ExpiresAt time.Time `gorm:"not null;index;comment:令牌原过期时间" json:"expires_at"`
+}
+
+// TableName 指定表名
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+// TokenPair 登录或刷新后签发的令牌
+type TokenPair struct {
+	AccessToken      string `json:"token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`         // 访问令牌有效期（秒）
+	RefreshExpiresIn int    `json:"refresh_expires_in"` // 刷新令牌有效期（秒）
+}
+
+// AuthTokenRepository 令牌和登录会话仓库接口
+type AuthTokenRepository interface {
+	// CreateSession 在同一事务中保存新会话及其第一个刷新令牌
+	CreateSession(session *UserSession, token *RefreshToken) error
+	FindSession(id string) (*UserSession, error)
+	// ListActiveSessions 获取用户未注销且未过期的会话（按最近活动时间倒序）
+	ListActiveSessions(userID uint, now time.Time) ([]UserSession, error)
+	// TouchSession 记录会话最近活动时间和IP
+	TouchSession(id string, ip string, seenAt time.Time) error
+	FindRefreshTokenByHash(hash string) (*RefreshToken, error)
+	// RotateRefreshToken 在同一事务中吊销旧令牌、保存新令牌并顺延会话，旧令牌已被吊销时返回 false（并发刷新或重放）
+	RotateRefreshToken(old *RefreshToken, next *RefreshToken, ip string) (bool, error)
+	// RevokeSession 注销会话并吊销其全部刷新令牌
+	RevokeSession(userID uint, sessionID string) error
+	// RevokeUserSessions 注销用户的全部会话并吊销全部刷新令牌
+	RevokeUserSessions(userID uint) error
+	RevokeAccessToken(token *RevokedToken) error
+	IsAccessTokenRevoked(tokenID string) (bool, error)
+	// DeleteExpired 删除已过期的会话、刷新令牌和吊销记录，返回删除的记录数
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+// AuthTokenService 令牌签发、刷新、吊销和会话管理服务接口
+type AuthTokenService interface {
+	// IssueTokens 登录成功后开启新会话，签发访问令牌和刷新令牌
+	IssueTokens(user *User, client ClientInfo) (*TokenPair, error)
+	// Refresh 使用刷新令牌换取新的令牌（旧刷新令牌随之失效）
+	Refresh(refreshToken string, client ClientInfo) (*User, *TokenPair, error)
+	// Logout 吊销当前访问令牌并注销其所属会话
+	Logout(claims *jwt.Claims) error
+	// LogoutAll 注销用户的全部会话并吊销全部 API Key，已签发的访问令牌和刷新令牌全部失效
+	LogoutAll(userID uint) error
+	// ListSessions 获取用户的活跃会话，currentSessionID 对应的会话标记为当前会话
+	ListSessions(userID uint, currentSessionID string) ([]UserSession, error)
+	// RevokeSession 注销用户的指定会话
+	RevokeSession(userID uint, sessionID string) error
+	// ValidateAccessToken 检查访问令牌是否已被吊销（会话已注销、令牌版本过期或已注销），并记录会话最近活动
+	ValidateAccessToken(claims *jwt.Claims, clientIP string) error
+	// CleanupExpired 清理过期的会话、刷新令牌和吊销记录
+	CleanupExpired() (int64, error)
+}
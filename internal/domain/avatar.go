@@ -8,10 +8,12 @@ type Avatar struct {
 	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
 	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
 
-	Name      string `gorm:"size:100;comment:头像名称" json:"name"`
-	URL       string `gorm:"size:500;not null;comment:头像URL" json:"url"`
-	IsActive  bool   `gorm:"default:true;comment:是否启用" json:"is_active"`
-	SortOrder int    `gorm:"default:0;comment:排序" json:"sort_order"`
+	Name string `gorm:"size:100;comment:头像名称" json:"name"`
+	URL  string `gorm:"size:500;not null;comment:头像URL" json:"url"`
+	// 缩略图地址（列表展示用，历史头像可能为空）
+	ThumbnailURL string `gorm:"size:500;comment:缩略图URL" json:"thumbnail_url"`
+	IsActive     bool   `gorm:"default:true;comment:是否启用" json:"is_active"`
+	SortOrder    int    `gorm:"default:0;comment:排序" json:"sort_order"`
 }
 
 func (Avatar) TableName() string {
@@ -30,7 +32,7 @@ type AvatarRepository interface {
 
 // AvatarService 头像服务接口
 type AvatarService interface {
-	UploadAvatar(name string, url string) (*Avatar, error)
+	UploadAvatar(name string, url string, thumbnailURL string) (*Avatar, error)
 	GetAvatar(id uint) (*Avatar, error)
 	ListAvatars() ([]Avatar, error)
 	ListActiveAvatars() ([]Avatar, error)
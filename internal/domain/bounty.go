@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrBountyRewardConflict 奖励在读取后已被其他操作变更（如已加入发放批次）
+var ErrBountyRewardConflict = errors.New("奖励状态已被其他操作变更，请刷新后重试")
+
+// ErrBountyAlreadyAwarded 报告已有未取消的奖金
+var ErrBountyAlreadyAwarded = errors.New("该报告已有奖金，如需追加请使用额外奖励")
+
+// 奖励类型
+const (
+	BountyTypeAward = "award" // 漏洞奖金（每份报告最多一笔有效奖金）
+	BountyTypeBonus = "bonus" // 额外奖励
+)
+
+// 奖励发放状态
+const (
+	BountyStatusPending   = "pending"   // 待审批
+	BountyStatusApproved  = "approved"  // 已审批，待发放
+	BountyStatusPaid      = "paid"      // 已发放
+	BountyStatusCancelled = "cancelled" // 已取消
+)
+
+// 账本条目类型
+const (
+	BountyEntryCreated       = "created"        // 新增奖励
+	BountyEntryStatusChanged = "status_changed" // 发放状态变更
+	BountyEntryBatched       = "batched"        // 加入发放批次
+)
+
+// 发放批次状态
+const (
+	PayoutBatchExported = "exported" // 已导出，待打款
+	PayoutBatchPaid     = "paid"     // 已打款
+)
+
+// DefaultBountyCurrency 默认币种
+const DefaultBountyCurrency = "CNY"
+
+// BountyReward 报告奖励
+// 记录当前金额和发放状态，所有变更同时追加到 BountyLedgerEntry
+type BountyReward struct {
+	ID        uint      `gorm:"primaryKey;comment:奖励ID" json:"id"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+
+	// 关联报告和获奖白帽子 - 不使用数据库外键
+	ReportID uint    `gorm:"not null;index;comment:关联报告ID" json:"report_id"`
+	Report   *Report `gorm:"-" json:"report,omitempty"` // 手动加载
+	AuthorID uint    `gorm:"not null;index;comment:获奖白帽子ID(报告提交者)" json:"author_id"`
+	Author   *User   `gorm:"-" json:"author,omitempty"` // 手动加载
+
+	// 奖励类型和金额（金额以最小货币单位存储，如 CNY 的分）
+	Type     string `gorm:"size:20;not null;comment:奖励类型(award:奖金, bonus:额外奖励)" json:"type"`
+	Amount   int64  `gorm:"not null;comment:金额(最小货币单位，如分)" json:"amount"`
+	Currency string `gorm:"size:3;not null;default:'CNY';comment:币种(ISO 4217)" json:"currency"`
+	Note     string `gorm:"size:500;comment:备注" json:"note"`
+
+	// 发放状态
+	Status string `gorm:"size:20;not null;default:'pending';index;comment:发放状态(pending:待审批, approved:已审批, paid:已发放, cancelled:已取消)" json:"status"`
+
+	// 所属发放批次（导出后设置）
+	PayoutBatchID *uint `gorm:"index;comment:发放批次ID" json:"payout_batch_id,omitempty"`
+
+	// 发放人
+	AwardedBy uint       `gorm:"comment:发放人ID" json:"awarded_by"`
+	PaidAt    *time.Time `gorm:"comment:发放时间" json:"paid_at,omitempty"`
+}
+
+// TableName 指定表名
+func (BountyReward) TableName() string {
+	return "bounty_rewards"
+}
+
+// BountyLedgerEntry 奖励账本条目
+// 只追加不修改，奖励的每次新增和状态变更都会写入一条
+type BountyLedgerEntry struct {
+	ID        uint      `gorm:"primaryKey;comment:条目ID" json:"id"`
+	CreatedAt time.Time `gorm:"index;comment:记录时间" json:"created_at"`
+
+	RewardID uint `gorm:"not null;index;comment:关联奖励ID" json:"reward_id"`
+	ReportID uint `gorm:"not null;index;comment:关联报告ID" json:"report_id"`
+	AuthorID uint `gorm:"not null;index;comment:获奖白帽子ID" json:"author_id"`
+
+	EntryType string `gorm:"size:30;not null;comment:条目类型(created/status_changed/batched)" json:"entry_type"`
+	Amount    int64  `gorm:"not null;comment:金额(最小货币单位)" json:"amount"`
+	Currency  string `gorm:"size:3;not null;comment:币种" json:"currency"`
+
+	FromStatus string `gorm:"size:20;comment:变更前状态" json:"from_status,omitempty"`
+	ToStatus   string `gorm:"size:20;not null;comment:变更后状态" json:"to_status"`
+
+	PayoutBatchID *uint  `gorm:"index;comment:发放批次ID" json:"payout_batch_id,omitempty"`
+	ActorID       uint   `gorm:"comment:操作人ID" json:"actor_id"`
+	Note          string `gorm:"size:500;comment:备注" json:"note,omitempty"`
+}
+
+// TableName 指定表名
+func (BountyLedgerEntry) TableName() string {
+	return "bounty_ledger_entries"
+}
+
+// PayoutBatch 奖励发放批次
+// 管理员将已审批的奖励打包导出（CSV），线下打款后标记为已发放
+type PayoutBatch struct {
+	ID        uint      `gorm:"primaryKey;comment:批次ID" json:"id"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+
+	Currency    string `gorm:"size:3;not null;comment:币种" json:"currency"`
+	TotalAmount int64  `gorm:"not null;comment:总金额(最小货币单位)" json:"total_amount"`
+	ItemCount   int    `gorm:"not null;comment:奖励笔数" json:"item_count"`
+
+	Status    string     `gorm:"size:20;not null;default:'exported';index;comment:批次状态(exported:已导出, paid:已打款)" json:"status"`
+	CreatedBy uint       `gorm:"comment:创建人ID" json:"created_by"`
+	PaidBy    uint       `gorm:"comment:确认打款人ID" json:"paid_by,omitempty"`
+	PaidAt    *time.Time `gorm:"comment:打款时间" json:"paid_at,omitempty"`
+	Note      string     `gorm:"size:500;comment:备注" json:"note"`
+}
+
+// TableName 指定表名
+func (PayoutBatch) TableName() string {
+	return "payout_batches"
+}
+
+// EarningsTotal 按币种汇总的收益
+type EarningsTotal struct {
+	Currency string `json:"currency"`
+	Pending  int64  `json:"pending"`  // 待审批
+	Approved int64  `json:"approved"` // 已审批待发放
+	Paid     int64  `json:"paid"`     // 已发放
+}
+
+// UserEarnings 白帽子收益汇总
+type UserEarnings struct {
+	Totals  []EarningsTotal `json:"totals"`
+	Rewards []BountyReward  `json:"rewards"`
+}
+
+// BountyAwardInput 新增奖励输入
+type BountyAwardInput struct {
+	Type     string
+	Amount   int64
+	Currency string
+	Note     string
+}
+
+// BountyRepository 奖励仓库接口
+// 奖励的写操作与账本条目在同一事务中写入
+type BountyRepository interface {
+	// CreateReward 创建奖金时锁定报告行，报告已有未取消的奖金则返回 ErrBountyAlreadyAwarded
+	CreateReward(reward *BountyReward, entry *BountyLedgerEntry) error
+	// UpdateRewardStatus 仅当奖励仍为 fromStatus 且未加入发放批次时更新状态，否则返回 ErrBountyRewardConflict
+	UpdateRewardStatus(reward *BountyReward, fromStatus string, entry *BountyLedgerEntry) error
+	FindRewardByID(id uint) (*BountyReward, error)
+	ListRewardsByReport(reportID uint) ([]BountyReward, error)
+	ListRewardsByAuthor(authorID uint) ([]BountyReward, error)
+	ListLedgerByReport(reportID uint) ([]BountyLedgerEntry, error)
+
+	// CreateBatch 将指定币种所有未打包的已审批奖励放入新批次（没有可打包的奖励时返回 gorm.ErrRecordNotFound）
+	CreateBatch(batch *PayoutBatch) error
+	FindBatchByID(id uint) (*PayoutBatch, error)
+	ListBatches(page, pageSize int) ([]PayoutBatch, int64, error)
+	ListRewardsByBatch(batchID uint) ([]BountyReward, error)
+	// MarkBatchPaid 将批次及其中的奖励标记为已发放，并追加账本条目
+	MarkBatchPaid(batch *PayoutBatch, actorID uint) error
+}
+
+// BountyService 奖励业务接口
+type BountyService interface {
+	AwardBounty(reportID uint, actorID uint, actorRole string, input *BountyAwardInput) (*BountyReward, error)
+	UpdateRewardStatus(rewardID uint, status string, actorID uint, actorRole string, note string) (*BountyReward, error)
+	ListReportBounties(reportID uint, userID uint, userRole string) ([]BountyReward, []BountyLedgerEntry, error)
+	GetUserEarnings(userID uint) (*UserEarnings, error)
+
+	CreatePayoutBatch(currency string, actorID uint, actorRole string, note string) (*PayoutBatch, error)
+	ListPayoutBatches(page, pageSize int, actorRole string) ([]PayoutBatch, int64, error)
+	ExportPayoutBatch(batchID uint, actorRole string) (*PayoutBatch, []BountyReward, error)
+	MarkPayoutBatchPaid(batchID uint, actorID uint, actorRole string) (*PayoutBatch, error)
+}
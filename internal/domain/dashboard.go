@@ -8,6 +8,9 @@ type SeverityStatistics struct {
 	Low      int64 `json:"low"`      // 低危
 	None     int64 `json:"none"`     // 无危害
 	Total    int64 `json:"total"`    // 总数
+
+	Scored       int64   `json:"scored"`         // 有 CVSS 分数的数量
+	AvgCVSSScore float64 `json:"avg_cvss_score"` // 平均 CVSS 分数
 }
 
 // TrendItem 趋势数据项
@@ -0,0 +1,16 @@
+package domain
+
+// 受控文件目录（uploads 下的一级目录）
+const (
+	FileDirReports  = "reports"  // 报告附件（漏洞证据）
+	FileDirProjects = "projects" // 项目附件
+)
+
+// FileAccessService 上传文件下载鉴权服务接口
+type FileAccessService interface {
+	// CheckAccess 校验用户能否下载文件，filePath 为 uploads 目录下的相对路径
+	// 管理员和厂商可以下载所有文件；白帽子只能下载自己报告的附件和被指派项目的附件
+	CheckAccess(filePath string, userID uint, userRole string) error
+	// CheckScanStatus 校验文件已通过恶意软件扫描，待扫描或已隔离的报告附件不允许下载
+	CheckScanStatus(filePath string) error
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+)
+
+// 通知类型
+const (
+	NotificationProjectAssigned = "project_assigned" // 被指派到项目
+)
+
+// Notification 站内通知
+type Notification struct {
+	ID        uint      `gorm:"primaryKey;comment:通知ID" json:"id"`
+	CreatedAt time.Time `gorm:"index;comment:创建时间" json:"created_at"`
+
+	// 接收通知的用户 - 不使用数据库外键
+	UserID uint `gorm:"not null;index:idx_notification_user_read;comment:接收用户ID" json:"user_id"`
+
+	// 通知类型和内容
+	Type    string `gorm:"size:30;not null;comment:通知类型(project_assigned)" json:"type"`
+	Title   string `gorm:"size:200;not null;comment:通知标题" json:"title"`
+	Content string `gorm:"type:text;comment:通知内容" json:"content"`
+
+	// 关联对象ID（如项目ID），按通知类型解释
+	RelatedID uint `gorm:"comment:关联对象ID" json:"related_id"`
+
+	// 阅读时间，为空表示未读
+	ReadAt *time.Time `gorm:"index:idx_notification_user_read;comment:阅读时间" json:"read_at"`
+}
+
+// TableName 指定表名
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// NotificationRepository 通知仓库接口
+type NotificationRepository interface {
+	CreateBatch(notifications []Notification) error
+	ListByUser(userID uint, unreadOnly bool, page, pageSize int) ([]Notification, int64, error)
+	CountUnread(userID uint) (int64, error)
+	MarkRead(userID uint, id uint) error
+	MarkAllRead(userID uint) (int64, error)
+}
+
+// NotificationService 通知服务接口
+type NotificationService interface {
+	// Notify 向多个用户发送同一条通知
+	Notify(userIDs []uint, notificationType, title, content string, relatedID uint) error
+	ListNotifications(userID uint, unreadOnly bool, page, pageSize int) ([]Notification, int64, error)
+	CountUnread(userID uint) (int64, error)
+	MarkRead(userID uint, id uint) error
+	MarkAllRead(userID uint) (int64, error)
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// 单点登录相关错误
+var (
+	ErrOIDCProviderNotFound = errors.New("身份提供方不存在")
+	ErrOIDCUnavailable      = errors.New("身份提供方暂时不可用，请稍后再试")
+	ErrOIDCStateInvalid     = errors.New("登录请求无效或已过期，请重新登录")
+	ErrOIDCVerifyFailed     = errors.New("身份提供方验证失败，请重新登录")
+	ErrOIDCAccessDenied     = errors.New("该账号没有通过此身份提供方登录的权限")
+	ErrOIDCAccountNotFound  = errors.New("没有与该身份关联的账号，请联系管理员")
+	ErrOIDCEmailConflict    = errors.New("该邮箱对应多个账号，无法自动关联，请联系管理员")
+)
+
+// UserIdentity 用户在外部身份提供方的身份（provider + subject 唯一）
+type UserIdentity struct {
+	ID        uint      `gorm:"primaryKey;comment:身份ID" json:"id"`
+	CreatedAt time.Time `gorm:"comment:关联时间" json:"created_at"`
+	UserID    uint      `gorm:"not null;index;comment:用户ID" json:"user_id"`
+	Provider  string    `gorm:"size:64;not null;uniqueIndex:idx_identity_provider_subject;comment:身份提供方标识" json:"provider"`
+	Subject   string    `gorm:"size:255;not null;uniqueIndex:idx_identity_provider_subject;comment:身份提供方用户标识(sub)" json:"subject"`
+
+	// Email 最近一次登录时身份提供方返回的邮箱
+	Email       string     `gorm:"size:100;comment:身份提供方邮箱" json:"email"`
+	LastLoginAt *time.Time `gorm:"comment:最后登录时间" json:"last_login_at"`
+}
+
+// TableName 指定表名
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// OIDCLoginState 跳转到身份提供方前保存的登录状态（state 只保存哈希，回调时使用一次即删除）
+type OIDCLoginState struct {
+	ID           uint      `gorm:"primaryKey;comment:记录ID" json:"id"`
+	CreatedAt    time.Time `gorm:"comment:创建时间" json:"created_at"`
+	StateHash    string    `gorm:"size:64;uniqueIndex;not null;comment:state的SHA-256哈希" json:"-"`
+	Provider     string    `gorm:"size:64;not null;comment:身份提供方标识" json:"provider"`
+	Nonce        string    `gorm:"size:64;not null;comment:ID令牌nonce" json:"-"`
+	CodeVerifier string    `gorm:"size:128;not null;comment:PKCE校验码" json:"-"`
+	ExpiresAt    time.Time `gorm:"not null;index;comment:过期时间" json:"expires_at"`
+}
+
+// TableName 指定表名
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// OIDCProviderInfo 登录页展示的身份提供方
+type OIDCProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// OIDCAuthorization 跳转到身份提供方的授权地址
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"` // 前端保存并在回调时提交，服务端与 Cookie 中的哈希比对，防止登录 CSRF
+	ExpiresIn        int    `json:"expires_in"`
+}
+
+// OIDCRepository 单点登录仓库接口
+type OIDCRepository interface {
+	CreateState(state *OIDCLoginState) error
+	// ConsumeState 查找并删除登录状态（只能使用一次），不存在时返回 nil
+	ConsumeState(stateHash string) (*OIDCLoginState, error)
+	DeleteExpiredStates(before time.Time) (int64, error)
+
+	// FindIdentity 根据身份提供方和 subject 查找身份，不存在时返回 nil
+	FindIdentity(provider, subject string) (*UserIdentity, error)
+	CreateIdentity(identity *UserIdentity) error
+	UpdateIdentityLogin(id uint, email string, loginAt time.Time) error
+}
+
+// OIDCService 单点登录服务接口
+type OIDCService interface {
+	// Providers 已配置的身份提供方
+	Providers() []OIDCProviderInfo
+	// Authorize 生成 state、nonce 和 PKCE 校验码，返回身份提供方的授权地址
+	Authorize(provider string) (*OIDCAuthorization, error)
+	// Callback 使用回调中的授权码完成登录：按身份关联、按已验证邮箱关联或自动创建账号，结果与密码登录相同
+	Callback(provider, code, state string, client ClientInfo) (*LoginResult, error)
+	// CleanupExpired 清理过期的登录状态
+	CleanupExpired() (int64, error)
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// 密码策略规则，校验失败时通过 PasswordPolicyError.Rule 返回给前端
+const (
+	PasswordRuleMinLength = "min_length" // 长度不足
+	PasswordRuleMaxLength = "max_length" // 超过 bcrypt 支持的 72 字节
+	PasswordRuleUpper     = "upper"      // 缺少大写字母
+	PasswordRuleLower     = "lower"      // 缺少小写字母
+	PasswordRuleDigit     = "digit"      // 缺少数字
+	PasswordRuleSymbol    = "symbol"     // 缺少符号
+	PasswordRuleClasses   = "classes"    // 字符类别数不足
+	PasswordRuleUsername  = "username"   // 包含用户名
+	PasswordRuleReuse     = "reuse"      // 与最近使用过的密码相同
+	PasswordRuleBreached  = "breached"   // 出现在泄露密码库中
+)
+
+// PasswordPolicyError 新密码不符合密码策略，Rule 为未通过的规则
+type PasswordPolicyError struct {
+	Rule    string
+	Message string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return e.Message
+}
+
+// PasswordHistory 用户使用过的密码哈希（用于禁止重复使用最近的密码）
+type PasswordHistory struct {
+	ID           uint      `gorm:"primaryKey;comment:记录ID" json:"id"`
+	CreatedAt    time.Time `gorm:"comment:设置时间" json:"created_at"`
+	UserID       uint      `gorm:"not null;index;comment:用户ID" json:"user_id"`
+	PasswordHash string    `gorm:"size:255;not null;comment:密码(bcrypt加密)" json:"-"`
+}
+
+// TableName 指定表名
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
+
+// PasswordHistoryRepository 历史密码仓库接口
+type PasswordHistoryRepository interface {
+	Create(history *PasswordHistory) error
+	// ListRecent 获取用户最近 limit 条历史密码（按时间倒序）
+	ListRecent(userID uint, limit int) ([]PasswordHistory, error)
+	// Prune 只保留用户最近 keep 条历史密码
+	Prune(userID uint, keep int) error
+}
+
+// PasswordPolicyService 密码策略服务接口
+type PasswordPolicyService interface {
+	// Validate 校验新密码，user.ID 为 0（注册）时跳过历史密码检查；不符合时返回 *PasswordPolicyError
+	Validate(user *User, password string) error
+	// Remember 记录用户新设置的密码哈希
+	Remember(userID uint, passwordHash string) error
+}
@@ -30,6 +30,41 @@ type Project struct {
 
 	// 项目状态: recruiting(招募中), in_progress(进行中), completed(已完成), closed(已关闭)
 	Status string `gorm:"size:20;default:'recruiting';index;comment:项目状态(recruiting/in_progress/completed/closed)" json:"status"`
+
+	// 提交报告时的范围校验模式: off(不校验), warn(提示), reject(拒绝)
+	ScopeEnforcement string `gorm:"size:10;default:'warn';comment:范围校验模式(off/warn/reject)" json:"scope_enforcement"`
+
+	// 奖励表：各危害等级的奖金范围
+	RewardTable []ProjectRewardTier `gorm:"type:json;serializer:json;comment:奖励表(JSON，各危害等级的奖金范围)" json:"reward_table"`
+}
+
+// ProjectRewardTier 项目奖励表中某个危害等级的奖金范围
+// 金额以最小货币单位存储（如 CNY 的分）
+type ProjectRewardTier struct {
+	SeverityLevelID uint   `json:"severity_level_id"` // 关联危害等级配置ID（config_type=severity_level）
+	Severity        string `json:"severity"`          // 危害等级（Critical/High/Medium/Low），由配置键推导
+	MinAmount       int64  `json:"min_amount"`        // 最低奖金
+	MaxAmount       int64  `json:"max_amount"`        // 最高奖金
+	Currency        string `json:"currency"`          // 币种
+}
+
+// BountySuggestion 根据项目奖励表给出的建议奖金
+type BountySuggestion struct {
+	Severity        string `json:"severity"`
+	MinAmount       int64  `json:"min_amount"`
+	MaxAmount       int64  `json:"max_amount"`
+	SuggestedAmount int64  `json:"suggested_amount"`
+	Currency        string `json:"currency"`
+}
+
+// FindRewardTier 查找危害等级对应的奖金范围
+func (p *Project) FindRewardTier(severity string) *ProjectRewardTier {
+	for i := range p.RewardTable {
+		if p.RewardTable[i].Severity == severity {
+			return &p.RewardTable[i]
+		}
+	}
+	return nil
 }
 
 // TableName 指定表名
@@ -51,10 +86,11 @@ type ProjectRepository interface {
 
 // ProjectUpdateInput 更新项目输入
 type ProjectUpdateInput struct {
-	Name        string
-	Description string
-	Note        string
-	Status      string
+	Name             string
+	Description      string
+	Note             string
+	Status           string
+	ScopeEnforcement string
 }
 
 // ProjectService 项目服务接口
@@ -67,4 +103,6 @@ type ProjectService interface {
 	UpdateProject(id uint, input *ProjectUpdateInput) (*Project, error)
 	DeleteProject(id uint) error  // 软删除
 	RestoreProject(id uint) error // 恢复已删除的项目
+	// UpdateRewardTable 更新项目奖励表（admin/vendor）
+	UpdateRewardTable(id uint, tiers []ProjectRewardTier) (*Project, error)
 }
@@ -32,4 +32,40 @@ type ProjectAssignmentRepository interface {
 	FindByUserID(userID uint) ([]ProjectAssignment, error)
 	Delete(id uint) error
 	DeleteByProjectAndUser(projectID, userID uint) error
+	CreateBatch(assignments []ProjectAssignment) error
+	DeleteByProjectAndUsers(projectID uint, userIDs []uint) (int64, error)
+}
+
+// AssignmentTarget 批量指派/取消指派的目标用户
+// 三种方式可以组合使用，结果取并集，只有白帽子会被选中
+type AssignmentTarget struct {
+	UserIDs    []uint // 指定用户
+	OrgID      uint   // 指定组织下的所有白帽子
+	RankingTop int    // 排行榜前 N 名白帽子
+}
+
+// AssignmentResult 批量指派/取消指派的结果
+type AssignmentResult struct {
+	UserIDs []uint `json:"user_ids"` // 实际新增/移除指派的用户
+	Count   int    `json:"count"`
+	Skipped int    `json:"skipped"` // 已指派（指派时）或未指派（取消时）而跳过的用户数
+}
+
+// ProjectAssignee 项目指派用户及任务接受情况
+type ProjectAssignee struct {
+	UserID     uint       `json:"user_id"`
+	Username   string     `json:"username"`
+	Name       string     `json:"name"`
+	OrgID      uint       `json:"org_id"`
+	AssignedAt time.Time  `json:"assigned_at"`
+	Accepted   bool       `json:"accepted"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+}
+
+// ProjectAssignmentService 项目指派服务接口（仅管理员使用）
+type ProjectAssignmentService interface {
+	ListAssignees(projectID uint) ([]ProjectAssignee, error)
+	// AssignUsers 批量指派并通知新指派的用户
+	AssignUsers(projectID uint, target *AssignmentTarget) (*AssignmentResult, error)
+	UnassignUsers(projectID uint, target *AssignmentTarget) (*AssignmentResult, error)
 }
@@ -24,8 +24,19 @@ type ProjectAttachment struct {
 	// 文件类型（pdf, doc, image 等）
 	Type string `gorm:"size:50;comment:文件类型" json:"type"`
 
+	// 文件字节数、MIME 类型和 SHA-256（上传时记录）
+	FileSize int64  `gorm:"default:0;comment:文件字节数" json:"file_size"`
+	MimeType string `gorm:"size:100;comment:MIME类型" json:"mime_type"`
+	SHA256   string `gorm:"column:sha256;size:64;index;comment:文件SHA-256(十六进制)" json:"sha256"`
+
+	// 上传人
+	UploadedBy uint `gorm:"default:0;comment:上传人ID" json:"uploaded_by"`
+
 	// 排序顺序
 	SortOrder int `gorm:"default:0;comment:排序顺序" json:"sort_order"`
+
+	// 带签名的临时下载地址（不入库，返回给前端直接下载）
+	DownloadURL string `gorm:"-" json:"download_url,omitempty"`
 }
 
 // TableName 指定表名
@@ -36,7 +47,23 @@ func (ProjectAttachment) TableName() string {
 // ProjectAttachmentRepository 项目附件仓库接口
 type ProjectAttachmentRepository interface {
 	Create(attachment *ProjectAttachment) error
+	FindByID(id uint) (*ProjectAttachment, error)
+	FindByFilePath(filePath string) ([]ProjectAttachment, error) // 地址指向该上传文件的附件（用于下载鉴权）
 	FindByProjectID(projectID uint) ([]ProjectAttachment, error)
+	MaxSortOrder(projectID uint) (int, error)
+	UpdateSortOrders(projectID uint, orderedIDs []uint) error
 	Delete(id uint) error
 	DeleteByProjectID(projectID uint) error
 }
+
+// ProjectAttachmentService 项目附件服务接口
+type ProjectAttachmentService interface {
+	// ListAttachments 获取项目附件，白帽子需被指派到项目
+	ListAttachments(projectID uint, userID uint, userRole string) ([]ProjectAttachment, error)
+	// AddAttachment 添加附件，排在现有附件之后（仅admin/vendor）
+	AddAttachment(attachment *ProjectAttachment, userRole string) error
+	// ReorderAttachments 按给定顺序重排附件，必须包含项目的全部附件（仅admin/vendor）
+	ReorderAttachments(projectID uint, orderedIDs []uint, userRole string) ([]ProjectAttachment, error)
+	// DeleteAttachment 删除附件记录并返回被删除的附件（仅admin/vendor）
+	DeleteAttachment(projectID uint, attachmentID uint, userRole string) (*ProjectAttachment, error)
+}
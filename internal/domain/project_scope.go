@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"time"
+)
+
+// 范围资产类型
+const (
+	ScopeAssetDomain     = "domain"      // 域名，如 example.com
+	ScopeAssetWildcard   = "wildcard"    // 通配域名，如 *.example.com（匹配所有子域名）
+	ScopeAssetURLPrefix  = "url_prefix"  // URL 前缀，如 https://example.com/api/
+	ScopeAssetIPCIDR     = "ip_cidr"     // IP 或网段，如 10.0.0.1、10.0.0.0/24
+	ScopeAssetMobileApp  = "mobile_app"  // 移动应用包名/应用ID，如 com.example.app
+	ScopeAssetSourceRepo = "source_repo" // 源码仓库，如 https://github.com/example/app
+)
+
+// 项目范围校验模式
+const (
+	ScopeEnforcementOff    = "off"    // 不校验
+	ScopeEnforcementWarn   = "warn"   // 范围外的报告允许提交，但标记并提示
+	ScopeEnforcementReject = "reject" // 拒绝范围外的报告
+)
+
+// 报告的范围判定结果
+const (
+	ReportScopeInScope    = "in_scope"     // 漏洞链接命中范围内资产
+	ReportScopeOutOfScope = "out_of_scope" // 命中范围外资产或未命中任何资产
+	ReportScopeUnknown    = "unknown"      // 项目未配置范围或报告没有漏洞链接
+)
+
+// ProjectScope 项目范围资产
+type ProjectScope struct {
+	ID        uint      `gorm:"primaryKey;comment:资产ID" json:"id"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+
+	// 关联项目 - 不使用数据库外键
+	ProjectID uint `gorm:"not null;index;comment:关联项目ID" json:"project_id"`
+
+	// 资产类型和标识
+	AssetType  string `gorm:"size:20;not null;comment:资产类型(domain/wildcard/url_prefix/ip_cidr/mobile_app/source_repo)" json:"asset_type"`
+	Identifier string `gorm:"size:500;not null;comment:资产标识(域名/URL前缀/IP或网段/应用ID/仓库地址)" json:"identifier"`
+
+	// 资产说明
+	Description string `gorm:"type:text;comment:资产说明" json:"description"`
+
+	// 是否在测试范围内（范围外资产优先匹配）
+	InScope bool `gorm:"not null;default:true;comment:是否在范围内" json:"in_scope"`
+
+	// 是否有奖励
+	BountyEligible bool `gorm:"not null;default:true;comment:是否可获得奖励" json:"bounty_eligible"`
+
+	// 该资产可评定的最高危害等级（为空表示不限制）
+	MaxSeverity string `gorm:"size:20;comment:最高危害等级(Critical/High/Medium/Low，为空不限制)" json:"max_severity"`
+}
+
+// TableName 指定表名
+func (ProjectScope) TableName() string {
+	return "project_scopes"
+}
+
+// ScopeCheckResult 漏洞链接的范围校验结果
+type ScopeCheckResult struct {
+	Status  string        `json:"status"`            // in_scope / out_of_scope / unknown
+	Matched *ProjectScope `json:"matched,omitempty"` // 命中的资产
+	Message string        `json:"message,omitempty"`
+}
+
+// ProjectScopeRepository 项目范围仓库接口
+type ProjectScopeRepository interface {
+	Create(scope *ProjectScope) error
+	FindByID(id uint) (*ProjectScope, error)
+	ListByProject(projectID uint) ([]ProjectScope, error)
+	Update(scope *ProjectScope) error
+	Delete(id uint) error
+}
+
+// ProjectScopeService 项目范围服务接口
+type ProjectScopeService interface {
+	ListScopes(projectID uint) ([]ProjectScope, error)
+	GetScope(id uint) (*ProjectScope, error)
+	CreateScope(scope *ProjectScope) error
+	UpdateScope(projectID uint, scope *ProjectScope) (*ProjectScope, error)
+	DeleteScope(projectID uint, scopeID uint) error
+	// CheckURL 校验漏洞链接是否在项目范围内
+	CheckURL(projectID uint, rawURL string) (*ScopeCheckResult, error)
+}
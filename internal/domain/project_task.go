@@ -40,6 +40,7 @@ type ProjectTaskRepository interface {
 	FindByID(id uint) (*ProjectTask, error)
 	FindByProjectAndUser(projectID, userID uint) (*ProjectTask, error)
 	FindByUserID(userID uint) ([]ProjectTask, error)
+	FindByProjectID(projectID uint) ([]ProjectTask, error)
 	FindAcceptedByUserID(userID uint) ([]ProjectTask, error) // 获取用户已接受的任务
 	Update(task *ProjectTask) error
 	Delete(id uint) error
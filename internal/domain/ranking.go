@@ -10,6 +10,8 @@ type RankingItem struct {
 	VulnCount     int    `json:"vulns"`
 	CriticalCount int    `json:"critical"`
 	HighCount     int    `json:"high"`
+	// 被确认为重复的报告数量（计入原始报告作者的积分）
+	DuplicateCount int `json:"duplicates"`
 }
 
 // RankingStatistics 排行榜全局统计
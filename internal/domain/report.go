@@ -37,18 +37,35 @@ type Report struct {
 	// 漏洞详情
 	VulnerabilityDetail string `gorm:"type:text;comment:漏洞详情(文本输入，详细描述漏洞情况)" json:"vulnerability_detail"`
 
-	// 附件地址
-	AttachmentURL string `gorm:"size:500;comment:附件地址(文件上传后的URL，单个文件，后续可扩展为多个)" json:"attachment_url"`
+	// 附件（存储在 report_attachments 表，详情接口返回）
+	Attachments []ReportAttachment `gorm:"-" json:"attachments,omitempty"`
+
+	// CVSS 向量（服务端解析校验并计算分数，支持 3.1 和 4.0）
+	CVSSVector  string   `gorm:"size:255;comment:CVSS向量(CVSS:3.1/... 或 CVSS:4.0/...)" json:"cvss_vector"`
+	CVSSVersion string   `gorm:"size:5;comment:CVSS版本(3.1/4.0)" json:"cvss_version,omitempty"`
+	CVSSScore   *float64 `gorm:"type:decimal(3,1);comment:CVSS分数(0.0-10.0)" json:"cvss_score"`
 
 	// 危害等级: Low, Medium, High, Critical
-	Severity string `gorm:"size:20;comment:危害等级(Critical:严重, High:高危, Medium:中危, Low:低危, None:无危害)" json:"severity"`
+	// 有 CVSS 分数时默认由分数推导，审核人员可显式覆盖
+	Severity           string `gorm:"size:20;comment:危害等级(Critical:严重, High:高危, Medium:中危, Low:低危, None:无危害)" json:"severity"`
+	SeverityOverridden bool   `gorm:"default:false;comment:危害等级是否由审核人员覆盖(不再跟随CVSS分数)" json:"severity_overridden"`
+
+	// 状态由工作流引擎控制，默认工作流见 DefaultReportWorkflow
+	Status string `gorm:"size:20;default:'Pending';index;comment:报告状态(Pending:待审核[默认], NeedsMoreInfo:需补充信息, Triaged:已确认, Resolved:已修复, Closed:已关闭, Rejected:驳回, Duplicate:重复, Informative:仅供参考, WontFix:不予修复)" json:"status"`
 
-	// 状态机: Pending(待审核) -> Audited(已审核) / Rejected(驳回)
-	Status string `gorm:"size:20;default:'Pending';index;comment:报告状态(Pending:待审核[默认], Audited:已审核, Rejected:驳回)" json:"status"`
+	// 范围判定（提交时根据漏洞链接和项目范围计算）
+	ScopeStatus string `gorm:"size:20;default:'unknown';comment:范围判定(in_scope/out_of_scope/unknown)" json:"scope_status"`
+	ScopeID     *uint  `gorm:"index;comment:命中的项目范围资产ID" json:"scope_id,omitempty"`
+
+	// 重复报告关联的原始报告ID（标记为重复时设置）- 不使用数据库外键
+	DuplicateOfID *uint `gorm:"index;comment:重复报告的原始报告ID" json:"duplicate_of_id,omitempty"`
 
 	// 提交者ID - 不使用数据库外键
 	AuthorID uint `gorm:"index;comment:提交者ID" json:"author_id"`
 	Author   User `gorm:"-" json:"author,omitempty"` // 不创建外键，手动加载
+
+	// 建议奖金（审核人员设置危害等级时根据项目奖励表计算，不入库）
+	SuggestedBounty *BountySuggestion `gorm:"-" json:"suggested_bounty,omitempty"`
 }
 
 // TableName 指定表名
@@ -57,14 +74,16 @@ func (Report) TableName() string {
 }
 
 // ReportRepository 接口定义
+// 写操作可附带审计事件，与报告变更在同一事务中写入 report_events
 type ReportRepository interface {
-	Create(report *Report) error
+	Create(report *Report, events ...ReportEvent) error
 	FindByID(id uint) (*Report, error)
 	FindByIDWithDeleted(id uint) (*Report, error) // 包含已删除的报告
 	List(page, pageSize int, authorID *uint, keyword string) ([]Report, int64, error)
-	Update(report *Report) error
-	Delete(id uint) error  // 软删除
-	Restore(id uint) error // 恢复已删除的报告
+	ListByProject(projectID uint, limit int) ([]Report, error) // 同项目最近的报告（不加载关联，用于重复检测）
+	Update(report *Report, events ...ReportEvent) error
+	Delete(id uint, events ...ReportEvent) error  // 软删除
+	Restore(id uint, events ...ReportEvent) error // 恢复已删除的报告
 }
 
 // ReportUpdateInput 更新报告输入
@@ -76,9 +95,10 @@ type ReportUpdateInput struct {
 	SelfAssessmentID    *uint
 	VulnerabilityURL    string
 	VulnerabilityDetail string
-	AttachmentURL       string
-	Severity            string
+	CVSSVector          string // CVSS 向量（设置后重新计算分数）
+	Severity            string // 与 CVSS 推导结果不一致时视为覆盖（仅admin/vendor）
 	Status              string
+	Reason              string // 状态流转原因（工作流要求时必填）
 }
 
 // ReportService 业务逻辑接口定义
@@ -89,4 +109,6 @@ type ReportService interface {
 	UpdateReport(id uint, userID uint, userRole string, input *ReportUpdateInput) (*Report, error)
 	DeleteReport(id uint, userID uint, userRole string) error  // 软删除
 	RestoreReport(id uint, userID uint, userRole string) error // 恢复已删除的报告
+	ListDuplicateCandidates(id uint, userRole string) ([]ReportSimilarity, error)
+	MarkDuplicate(id uint, originalID uint, userID uint, userRole string, reason string) (*Report, error)
 }
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MaxReportAttachments 单份报告最多附件数
+const MaxReportAttachments = 20
+
+// 附件恶意软件扫描状态
+const (
+	ScanStatusPending  = "pending"  // 待扫描（扫描器暂不可用时保持该状态，稍后重新扫描）
+	ScanStatusClean    = "clean"    // 未发现恶意内容
+	ScanStatusInfected = "infected" // 发现恶意内容，文件已隔离
+)
+
+// ReportAttachment 报告附件实体（截图、PoC 脚本、HAR 等）
+type ReportAttachment struct {
+	ID        uint      `gorm:"primaryKey;comment:附件ID" json:"id"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+
+	// 关联报告 - 不使用数据库外键
+	ReportID uint `gorm:"not null;index;comment:报告ID" json:"report_id"`
+
+	// 原始文件名
+	Filename string `gorm:"size:255;not null;comment:原始文件名" json:"filename"`
+
+	// 文件访问地址（受控下载地址）
+	URL string `gorm:"size:500;not null;comment:文件地址" json:"url"`
+
+	// 文件字节数、MIME 类型和 SHA-256（上传时记录，历史附件迁移时可能为空）
+	FileSize int64  `gorm:"default:0;comment:文件字节数" json:"file_size"`
+	MimeType string `gorm:"size:100;comment:MIME类型" json:"mime_type"`
+	SHA256   string `gorm:"column:sha256;size:64;index;comment:文件SHA-256(十六进制)" json:"sha256"`
+
+	// 上传人
+	UploadedBy uint `gorm:"index;comment:上传人ID" json:"uploaded_by"`
+
+	// 恶意软件扫描状态，只有 clean 的附件可以下载
+	ScanStatus    string     `gorm:"size:20;default:'pending';index;comment:扫描状态(pending/clean/infected)" json:"scan_status"`
+	ScanSignature string     `gorm:"size:255;comment:命中的病毒特征" json:"scan_signature,omitempty"`
+	ScannedAt     *time.Time `gorm:"comment:扫描时间" json:"scanned_at,omitempty"`
+
+	// 带签名的临时下载地址（不入库，返回给前端直接下载）
+	DownloadURL string `gorm:"-" json:"download_url,omitempty"`
+}
+
+// TableName 指定表名
+func (ReportAttachment) TableName() string {
+	return "report_attachments"
+}
+
+// ReportAttachmentRepository 报告附件仓库接口
+// 写操作可附带审计事件，与附件变更在同一事务中写入 report_events
+type ReportAttachmentRepository interface {
+	Create(attachment *ReportAttachment, events ...ReportEvent) error
+	FindByID(id uint) (*ReportAttachment, error)
+	FindByReportID(reportID uint) ([]ReportAttachment, error)
+	FindByFilePath(filePath string) ([]ReportAttachment, error) // 地址指向该上传文件的附件（用于下载鉴权）
+	CountByReportID(reportID uint) (int64, error)
+	Delete(attachment *ReportAttachment, events ...ReportEvent) error
+	ListByScanStatus(status string, afterID uint, limit int) ([]ReportAttachment, error) // 按ID分批获取（afterID 为上一批最后一条）
+	// UpdateScanStatus 更新附件的扫描状态、病毒特征和扫描时间
+	UpdateScanStatus(attachment *ReportAttachment, events ...ReportEvent) error
+}
+
+// ReportAttachmentService 报告附件服务接口
+// 报告作者、管理员和厂商可以查看和管理报告附件
+type ReportAttachmentService interface {
+	ListAttachments(reportID uint, userID uint, userRole string) ([]ReportAttachment, error)
+	// CheckCanAttach 检查用户能否为报告添加附件（报告权限和附件数量）
+	CheckCanAttach(reportID uint, userID uint, userRole string) error
+	// AddAttachment 为报告添加附件，附件数量不超过 MaxReportAttachments
+	AddAttachment(attachment *ReportAttachment, userRole string) error
+	// DeleteAttachment 删除附件记录并返回被删除的附件
+	DeleteAttachment(reportID uint, attachmentID uint, userID uint, userRole string) (*ReportAttachment, error)
+}
+
+// AttachmentScanService 报告附件恶意软件扫描服务接口
+// 文件关联到报告前必须经过扫描；扫描器不可用时附件保持待扫描状态，由后台任务重新扫描
+type AttachmentScanService interface {
+	// ScanFile 扫描上传文件，返回扫描状态和命中的病毒特征
+	// 发现恶意内容时隔离文件，并将已引用该文件的附件标记为 infected；扫描器不可用时返回 pending
+	ScanFile(ctx context.Context, filePath string) (status string, signature string, err error)
+	// RescanPending 重新扫描待扫描的附件，返回完成扫描的附件数
+	RescanPending(ctx context.Context) (int, error)
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"bug-bounty-lite/pkg/types"
+)
+
+// 报告事件类型
+const (
+	ReportEventCreated            = "created"             // 提交报告
+	ReportEventUpdated            = "updated"             // 字段变更
+	ReportEventStatusChanged      = "status_changed"      // 状态流转
+	ReportEventDeleted            = "deleted"             // 软删除
+	ReportEventRestored           = "restored"            // 恢复
+	ReportEventAttachmentAdded    = "attachment_added"    // 添加附件
+	ReportEventAttachmentRemoved  = "attachment_removed"  // 删除附件
+	ReportEventAttachmentInfected = "attachment_infected" // 附件被扫描出恶意内容
+)
+
+// ReportEvent 报告审计事件
+// 每次报告变更都会写入一条或多条事件（每个变更字段一条），只追加不修改
+type ReportEvent struct {
+	ID        uint           `gorm:"primaryKey;comment:事件ID" json:"id"`
+	CreatedAt types.DateTime `gorm:"index;comment:事件时间" json:"created_at"`
+
+	// 关联报告
+	ReportID uint `gorm:"not null;index;comment:关联报告ID" json:"report_id"`
+
+	// 操作人（0 表示系统或命令行工具）
+	ActorID   uint   `gorm:"index;comment:操作人ID(0:系统)" json:"actor_id"`
+	ActorRole string `gorm:"size:20;comment:操作人角色" json:"actor_role"`
+	Actor     *User  `gorm:"-" json:"actor,omitempty"` // 手动加载
+
+	// 事件类型
+	EventType string `gorm:"size:30;not null;index;comment:事件类型(created/updated/status_changed/deleted/restored)" json:"event_type"`
+
+	// 变更字段及前后值
+	Field    string `gorm:"size:50;comment:变更字段" json:"field,omitempty"`
+	OldValue string `gorm:"type:text;comment:变更前的值" json:"old_value,omitempty"`
+	NewValue string `gorm:"type:text;comment:变更后的值" json:"new_value,omitempty"`
+
+	// 变更原因
+	Reason string `gorm:"type:text;comment:变更原因" json:"reason,omitempty"`
+}
+
+// TableName 指定表名
+func (ReportEvent) TableName() string {
+	return "report_events"
+}
+
+// ReportTimelineItem 报告时间线条目（事件或评论）
+type ReportTimelineItem struct {
+	Type      string         `json:"type"` // event / comment
+	CreatedAt types.DateTime `json:"created_at"`
+	Event     *ReportEvent   `json:"event,omitempty"`
+	Comment   *ReportComment `json:"comment,omitempty"`
+}
+
+// ReportEventRepository 报告事件仓库接口
+type ReportEventRepository interface {
+	FindByReportID(reportID uint) ([]ReportEvent, error)
+}
+
+// ReportTimelineService 报告时间线服务接口
+type ReportTimelineService interface {
+	// GetTimeline 获取报告的事件与评论，按时间先后合并
+	GetTimeline(reportID uint, userID uint, userRole string) ([]ReportTimelineItem, error)
+}
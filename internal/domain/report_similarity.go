@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"time"
+)
+
+// 重复检测参数
+const (
+	DuplicateScoreThreshold = 0.5 // 相似度达到该值才视为疑似重复
+	DuplicateMaxCandidates  = 5   // 每份报告最多保留的疑似重复数量
+	DuplicateScanLimit      = 500 // 提交时最多比对同项目最近的报告数量
+)
+
+// ReportSimilarity 报告疑似重复记录
+// 在报告提交时计算，供审核人员判断是否标记为重复
+type ReportSimilarity struct {
+	ID        uint      `gorm:"primaryKey;comment:记录ID" json:"id"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+
+	// 新提交的报告
+	ReportID uint `gorm:"not null;index;uniqueIndex:idx_similarity_pair;comment:报告ID" json:"report_id"`
+
+	// 疑似重复的已有报告
+	CandidateID uint    `gorm:"not null;index;uniqueIndex:idx_similarity_pair;comment:疑似重复的报告ID" json:"candidate_id"`
+	Candidate   *Report `gorm:"-" json:"candidate,omitempty"` // 手动加载
+
+	// 综合相似度 (0-1)
+	Score float64 `gorm:"not null;comment:综合相似度(0-1)" json:"score"`
+
+	// 各项得分明细
+	URLMatch  bool    `gorm:"comment:漏洞链接是否一致" json:"url_match"`
+	TypeMatch bool    `gorm:"comment:漏洞类型是否一致" json:"type_match"`
+	TextScore float64 `gorm:"comment:名称与详情的文本相似度(0-1)" json:"text_score"`
+}
+
+// TableName 指定表名
+func (ReportSimilarity) TableName() string {
+	return "report_similarities"
+}
+
+// ReportSimilarityRepository 疑似重复记录仓库接口
+type ReportSimilarityRepository interface {
+	Create(similarities []ReportSimilarity) error
+	FindByReportID(reportID uint) ([]ReportSimilarity, error)
+}
@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"time"
+)
+
+// 报告状态
+const (
+	ReportStatusPending       = "Pending"       // 待审核
+	ReportStatusNeedsMoreInfo = "NeedsMoreInfo" // 需补充信息
+	ReportStatusTriaged       = "Triaged"       // 已确认
+	ReportStatusResolved      = "Resolved"      // 已修复
+	ReportStatusClosed        = "Closed"        // 已关闭
+	ReportStatusRejected      = "Rejected"      // 已驳回
+	ReportStatusDuplicate     = "Duplicate"     // 重复
+	ReportStatusInformative   = "Informative"   // 仅供参考
+	ReportStatusWontFix       = "WontFix"       // 不予修复
+)
+
+// 状态流转时可要求必填的字段
+const (
+	WorkflowFieldSeverity    = "severity"     // 危害等级
+	WorkflowFieldReason      = "reason"       // 流转原因
+	WorkflowFieldDuplicateOf = "duplicate_of" // 重复报告的原始报告
+)
+
+// WorkflowState 工作流中的一个状态
+type WorkflowState struct {
+	Status   string `json:"status"`   // 状态值，如 Pending
+	Name     string `json:"name"`     // 显示名称，如 待审核
+	Terminal bool   `json:"terminal"` // 是否为终态（终态不允许再流转）
+}
+
+// WorkflowTransition 工作流中的一条流转规则
+type WorkflowTransition struct {
+	From           string   `json:"from"`                      // 源状态
+	To             string   `json:"to"`                        // 目标状态
+	Roles          []string `json:"roles"`                     // 允许执行该流转的角色
+	RequiredFields []string `json:"required_fields,omitempty"` // 进入目标状态时必填的字段
+}
+
+// ReportWorkflow 报告状态工作流定义
+// ProjectID 为 0 表示全局默认工作流，其余为项目自定义工作流
+type ReportWorkflow struct {
+	ID        uint      `gorm:"primaryKey;comment:工作流ID" json:"id"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+
+	// 关联项目（0 表示全局默认）
+	ProjectID uint `gorm:"uniqueIndex;not null;default:0;comment:关联项目ID(0:全局默认)" json:"project_id"`
+
+	// 新提交报告的初始状态
+	InitialStatus string `gorm:"size:20;not null;comment:初始状态" json:"initial_status"`
+
+	// 状态列表
+	States []WorkflowState `gorm:"type:json;serializer:json;comment:状态列表(JSON)" json:"states"`
+
+	// 流转规则
+	Transitions []WorkflowTransition `gorm:"type:json;serializer:json;comment:流转规则(JSON)" json:"transitions"`
+}
+
+// TableName 指定表名
+func (ReportWorkflow) TableName() string {
+	return "report_workflows"
+}
+
+// FindState 查找状态定义
+func (w *ReportWorkflow) FindState(status string) *WorkflowState {
+	for i := range w.States {
+		if w.States[i].Status == status {
+			return &w.States[i]
+		}
+	}
+	return nil
+}
+
+// FindTransition 查找流转规则
+func (w *ReportWorkflow) FindTransition(from, to string) *WorkflowTransition {
+	for i := range w.Transitions {
+		if w.Transitions[i].From == from && w.Transitions[i].To == to {
+			return &w.Transitions[i]
+		}
+	}
+	return nil
+}
+
+// AllowsRole 判断角色是否允许执行该流转
+func (t *WorkflowTransition) AllowsRole(role string) bool {
+	for _, r := range t.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorEditable 报告作者在该状态下能否修改 CVSS 向量和危害等级
+// 初始状态，或白帽子可以从该状态发起流转（如需补充信息）时视为仍由作者编辑，其余状态已进入审核
+func (w *ReportWorkflow) AuthorEditable(status string) bool {
+	if status == w.InitialStatus {
+		return true
+	}
+	for i := range w.Transitions {
+		if w.Transitions[i].From == status && w.Transitions[i].AllowsRole("whitehat") {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultReportWorkflow 内置默认工作流
+// 当项目和全局均未配置工作流时使用
+func DefaultReportWorkflow() *ReportWorkflow {
+	staff := []string{"admin", "vendor"}
+	reason := []string{WorkflowFieldReason}
+	duplicateOf := []string{WorkflowFieldDuplicateOf}
+
+	return &ReportWorkflow{
+		InitialStatus: ReportStatusPending,
+		States: []WorkflowState{
+			{Status: ReportStatusPending, Name: "待审核"},
+			{Status: ReportStatusNeedsMoreInfo, Name: "需补充信息"},
+			{Status: ReportStatusTriaged, Name: "已确认"},
+			{Status: ReportStatusResolved, Name: "已修复"},
+			{Status: ReportStatusClosed, Name: "已关闭", Terminal: true},
+			{Status: ReportStatusRejected, Name: "已驳回", Terminal: true},
+			{Status: ReportStatusDuplicate, Name: "重复", Terminal: true},
+			{Status: ReportStatusInformative, Name: "仅供参考", Terminal: true},
+			{Status: ReportStatusWontFix, Name: "不予修复", Terminal: true},
+		},
+		Transitions: []WorkflowTransition{
+			{From: ReportStatusPending, To: ReportStatusTriaged, Roles: staff, RequiredFields: []string{WorkflowFieldSeverity}},
+			{From: ReportStatusPending, To: ReportStatusNeedsMoreInfo, Roles: staff, RequiredFields: reason},
+			{From: ReportStatusPending, To: ReportStatusRejected, Roles: staff, RequiredFields: reason},
+			{From: ReportStatusPending, To: ReportStatusDuplicate, Roles: staff, RequiredFields: duplicateOf},
+			{From: ReportStatusPending, To: ReportStatusInformative, Roles: staff, RequiredFields: reason},
+			{From: ReportStatusPending, To: ReportStatusClosed, Roles: staff},
+			{From: ReportStatusNeedsMoreInfo, To: ReportStatusPending, Roles: []string{"whitehat", "admin", "vendor"}},
+			{From: ReportStatusNeedsMoreInfo, To: ReportStatusRejected, Roles: staff, RequiredFields: reason},
+			{From: ReportStatusTriaged, To: ReportStatusResolved, Roles: staff},
+			{From: ReportStatusTriaged, To: ReportStatusWontFix, Roles: staff, RequiredFields: reason},
+			{From: ReportStatusTriaged, To: ReportStatusDuplicate, Roles: staff, RequiredFields: duplicateOf},
+			{From: ReportStatusTriaged, To: ReportStatusClosed, Roles: staff},
+			{From: ReportStatusResolved, To: ReportStatusClosed, Roles: staff},
+		},
+	}
+}
+
+// ReportWorkflowRepository 工作流仓库接口
+type ReportWorkflowRepository interface {
+	FindByProjectID(projectID uint) (*ReportWorkflow, error)
+	Save(workflow *ReportWorkflow) error
+	DeleteByProjectID(projectID uint) error
+}
+
+// ReportWorkflowService 工作流服务接口
+type ReportWorkflowService interface {
+	// GetWorkflow 获取项目生效的工作流（项目 -> 全局 -> 内置默认）
+	GetWorkflow(projectID uint) (*ReportWorkflow, error)
+	SaveWorkflow(projectID uint, workflow *ReportWorkflow) (*ReportWorkflow, error)
+	ResetWorkflow(projectID uint) error
+	// ValidateTransition 校验报告能否由当前角色流转到目标状态
+	// report 应为已应用本次字段更新后的报告，input 用于读取原因等附加字段
+	ValidateTransition(report *Report, to string, role string, input *ReportUpdateInput) error
+	// AvailableTransitions 获取当前角色对报告可执行的流转
+	AvailableTransitions(report *Report, role string) ([]WorkflowTransition, error)
+}
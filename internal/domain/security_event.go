@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// 安全事件类型
+const (
+	SecurityEventLoginLockout = "login_lockout" // 登录失败次数过多被锁定
+	SecurityEventLoginUnlock  = "login_unlock"  // 管理员解除登录锁定
+)
+
+// LoginThrottledError 用户名或 IP 因登录失败次数过多处于退避或锁定状态
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	seconds := int(math.Ceil(e.RetryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return fmt.Sprintf("登录失败次数过多，请 %d 秒后再试", seconds)
+}
+
+// SecurityEvent 安全事件日志（只追加不修改）
+type SecurityEvent struct {
+	ID        uint      `gorm:"primaryKey;comment:事件ID" json:"id"`
+	CreatedAt time.Time `gorm:"index;comment:发生时间" json:"created_at"`
+	Type      string    `gorm:"size:32;not null;index;comment:事件类型(login_lockout/login_unlock)" json:"type"`
+
+	// UserID 相关用户，用户名不存在或按 IP 锁定时为 0
+	UserID   uint   `gorm:"default:0;index;comment:相关用户ID" json:"user_id"`
+	Username string `gorm:"size:64;index;comment:相关用户名" json:"username"`
+	IP       string `gorm:"size:64;index;comment:客户端IP" json:"ip"`
+	Detail   string `gorm:"size:255;comment:事件详情" json:"detail"`
+
+	// OperatorID 执行操作的管理员，系统自动产生的事件为 0
+	OperatorID uint `gorm:"default:0;comment:操作人ID" json:"operator_id"`
+}
+
+// TableName 指定表名
+func (SecurityEvent) TableName() string {
+	return "security_events"
+}
+
+// SecurityEventFilter 安全事件查询条件（为空表示不限）
+type SecurityEventFilter struct {
+	Type     string
+	Username string
+	IP       string
+}
+
+// SecurityEventRepository 安全事件仓库接口
+type SecurityEventRepository interface {
+	Create(event *SecurityEvent) error
+	List(filter SecurityEventFilter, page, pageSize int) ([]SecurityEvent, int64, error)
+}
+
+// LoginGuardService 登录防暴力破解服务接口
+type LoginGuardService interface {
+	// Check 校验密码前检查用户名和 IP 是否处于退避或锁定状态，是时返回 *LoginThrottledError
+	Check(username, ip string) error
+	// RecordFailure 记录一次登录失败（userID 为 0 表示用户名不存在），达到上限时锁定并记录安全事件
+	RecordFailure(username, ip string, userID uint)
+	// RecordSuccess 登录成功后清除用户名的失败计数（IP 计数不清除，防止用自己的账号重置计数）
+	RecordSuccess(username string)
+	// Unlock 管理员解除用户名和/或 IP 的锁定
+	Unlock(username, ip string, adminID uint) error
+	// ListEvents 分页查询安全事件
+	ListEvents(filter SecurityEventFilter, page, pageSize int) ([]SecurityEvent, int64, error)
+}
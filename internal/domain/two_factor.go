@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// 登录挑战用途
+const (
+	ChallengeTwoFactorVerify = "verify" // 已启用两步验证，需要输入验证码
+	ChallengeTwoFactorSetup  = "setup"  // 角色要求两步验证但尚未启用，需要先完成绑定
+)
+
+// SettingTwoFactorRequiredRoles 安全设置键：必须启用两步验证的角色（JSON 数组）
+const SettingTwoFactorRequiredRoles = "two_factor_required_roles"
+
+// 两步验证相关错误
+var (
+	ErrTwoFactorCodeInvalid          = errors.New("验证码错误")
+	ErrTwoFactorNotEnabled           = errors.New("未启用两步验证")
+	ErrTwoFactorAlreadyEnabled       = errors.New("已启用两步验证")
+	ErrTwoFactorSetupRequired        = errors.New("请先获取两步验证密钥")
+	ErrTwoFactorRequiredByRole       = errors.New("当前角色必须启用两步验证，不能关闭")
+	ErrLoginChallengeInvalid         = errors.New("登录挑战令牌无效或已过期，请重新登录")
+	ErrLoginChallengeTooManyAttempts = errors.New("验证码错误次数过多，请重新登录")
+)
+
+// UserTwoFactor 用户的 TOTP 两步验证配置
+type UserTwoFactor struct {
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false;comment:用户ID" json:"user_id"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+
+	// Secret Base32 编码的 TOTP 密钥，绑定确认前 Enabled 为 false
+	Secret    string     `gorm:"size:64;not null;comment:TOTP密钥(Base32)" json:"-"`
+	Enabled   bool       `gorm:"default:false;comment:是否已启用" json:"enabled"`
+	EnabledAt *time.Time `gorm:"comment:启用时间" json:"enabled_at"`
+
+	// LastUsedStep 最近一次验证成功的时间步，不大于它的验证码不再接受（防止重放）
+	LastUsedStep int64 `gorm:"default:0;comment:最近一次使用的时间步" json:"-"`
+}
+
+// TableName 指定表名
+func (UserTwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+// RecoveryCode 两步验证恢复码（只保存 SHA-256 哈希，每个只能使用一次）
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey;comment:恢复码ID" json:"id"`
+	CreatedAt time.Time  `gorm:"comment:生成时间" json:"created_at"`
+	UserID    uint       `gorm:"not null;index;comment:用户ID" json:"user_id"`
+	CodeHash  string     `gorm:"size:64;not null;comment:恢复码SHA-256哈希" json:"-"`
+	UsedAt    *time.Time `gorm:"comment:使用时间" json:"used_at"`
+}
+
+// TableName 指定表名
+func (RecoveryCode) TableName() string {
+	return "two_factor_recovery_codes"
+}
+
+// LoginChallenge 密码验证通过后等待第二步验证的登录挑战（只保存挑战令牌的 SHA-256 哈希）
+type LoginChallenge struct {
+	ID        string    `gorm:"primaryKey;size:64;comment:挑战令牌SHA-256哈希" json:"-"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UserID    uint      `gorm:"not null;index;comment:用户ID" json:"user_id"`
+	Purpose   string    `gorm:"size:20;not null;comment:用途(verify:输入验证码/setup:绑定两步验证)" json:"purpose"`
+	Attempts  int       `gorm:"default:0;comment:已提交验证码的次数" json:"attempts"`
+	ExpiresAt time.Time `gorm:"not null;index;comment:过期时间" json:"expires_at"`
+}
+
+// TableName 指定表名
+func (LoginChallenge) TableName() string {
+	return "login_challenges"
+}
+
+// SecuritySetting 管理员可在运行时修改的安全设置（键值对）
+type SecuritySetting struct {
+	Key       string    `gorm:"primaryKey;size:64;comment:设置键" json:"key"`
+	Value     string    `gorm:"type:text;comment:设置值(JSON)" json:"value"`
+	UpdatedBy uint      `gorm:"default:0;comment:最后修改人ID" json:"updated_by"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (SecuritySetting) TableName() string {
+	return "security_settings"
+}
+
+// LoginResult 登录结果：直接签发令牌，或返回挑战令牌等待第二步验证
+type LoginResult struct {
+	User                   *User
+	Tokens                 *TokenPair
+	ChallengeToken         string
+	ChallengeExpiresIn     int
+	TwoFactorRequired      bool // 需要输入验证码（POST /auth/login/2fa）
+	TwoFactorSetupRequired bool // 需要先绑定两步验证（POST /auth/2fa/setup、/auth/2fa/enable）
+}
+
+// TwoFactorSetup 绑定两步验证时返回的密钥和二维码内容
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth:// 地址，前端渲染为二维码
+}
+
+// TwoFactorStatus 两步验证状态
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	Required               bool       `json:"required"` // 当前角色是否必须启用
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorRepository 两步验证仓库接口
+type TwoFactorRepository interface {
+	FindByUserID(userID uint) (*UserTwoFactor, error)
+	Save(tf *UserTwoFactor) error
+	Delete(userID uint) error
+	// UseStep 以时间步大于已使用的时间步作为条件记录本次使用，返回是否记录成功（防止并发重放）
+	UseStep(userID uint, step int64) (bool, error)
+
+	// ReplaceRecoveryCodes 删除旧恢复码并保存新恢复码
+	ReplaceRecoveryCodes(userID uint, codes []RecoveryCode) error
+	// UseRecoveryCode 将未使用的恢复码标记为已使用，返回是否成功
+	UseRecoveryCode(userID uint, codeHash string) (bool, error)
+	CountRecoveryCodes(userID uint) (int64, error)
+
+	CreateChallenge(challenge *LoginChallenge) error
+	FindChallenge(id string) (*LoginChallenge, error)
+	// ConsumeChallengeAttempt 提交次数未达到 maxAttempts 时原子地加一，返回是否成功
+	ConsumeChallengeAttempt(id string, maxAttempts int) (bool, error)
+	DeleteChallenge(id string) error
+	DeleteExpiredChallenges(before time.Time) (int64, error)
+
+	GetSetting(key string) (*SecuritySetting, error)
+	SaveSetting(setting *SecuritySetting) error
+}
+
+// TwoFactorService 两步验证服务接口
+type TwoFactorService interface {
+	// LoginChallenge 密码验证通过后检查是否需要第二步验证，需要时返回挑战令牌
+	LoginChallenge(user *User) (*LoginResult, error)
+	// VerifyLogin 登录第二步：校验 TOTP 验证码或恢复码，通过后签发令牌
+	VerifyLogin(challengeToken, code string, client ClientInfo) (*User, *TokenPair, error)
+	// SetupWithChallenge 角色要求两步验证但尚未启用时，使用挑战令牌获取绑定密钥
+	SetupWithChallenge(challengeToken string) (*TwoFactorSetup, error)
+	// EnableWithChallenge 使用挑战令牌确认绑定，返回恢复码并签发令牌
+	EnableWithChallenge(challengeToken, code string, client ClientInfo) (*User, *TokenPair, []string, error)
+
+	GetStatus(userID uint, role string) (*TwoFactorStatus, error)
+	// Setup 生成新的密钥（未启用状态），已启用时返回错误
+	Setup(userID uint) (*TwoFactorSetup, error)
+	// Enable 校验验证码后启用两步验证，返回一次性展示的恢复码
+	Enable(userID uint, code string) ([]string, error)
+	// Disable 校验密码和验证码后关闭两步验证
+	Disable(userID uint, role, password, code string) error
+	// RegenerateRecoveryCodes 校验验证码后重新生成恢复码（旧恢复码全部失效）
+	RegenerateRecoveryCodes(userID uint, code string) ([]string, error)
+	// Reset 管理员重置用户的两步验证（用户丢失设备且恢复码用尽时）
+	Reset(userID uint) error
+
+	// GetRequiredRoles 获取必须启用两步验证的角色
+	GetRequiredRoles() ([]string, error)
+	// SetRequiredRoles 设置必须启用两步验证的角色（whitehat/vendor/admin）
+	SetRequiredRoles(roles []string, adminID uint) ([]string, error)
+	// CleanupExpired 清理过期的登录挑战
+	CleanupExpired() (int64, error)
+}
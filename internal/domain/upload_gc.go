@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// 孤儿文件处理方式
+const (
+	UploadGCActionQuarantine = "quarantine" // 移动到 orphaned/ 目录，确认无误后可手动删除
+	UploadGCActionDelete     = "delete"     // 直接删除
+)
+
+// UploadReferences 数据库中对上传文件的全部引用
+type UploadReferences struct {
+	URLs     []string // 地址字段（报告附件、项目附件、头像）
+	Texts    []string // 可能内嵌文件地址的正文（报告详情和链接、项目描述和备注、评论、文章、系统配置扩展数据）
+	Keys     []string // 直接记录存储键的字段（已合并的分片上传）
+	Sessions []string // 未过期的上传会话ID，其分片不回收
+}
+
+// UploadReferenceRepository 上传文件引用查询接口
+type UploadReferenceRepository interface {
+	// ListReferences 获取所有引用，deletedBefore 之前软删除的报告和项目不再视为引用
+	ListReferences(deletedBefore time.Time) (*UploadReferences, error)
+}
+
+// UploadGCOptions 孤儿文件回收参数
+type UploadGCOptions struct {
+	OlderThan time.Duration // 只回收修改时间早于该时长的文件（同时作为软删除报告和项目的保留期）
+	Action    string        // quarantine（默认）/ delete
+	DryRun    bool          // 只列出孤儿文件，不做任何修改
+}
+
+// OrphanFile 未被任何记录引用的上传文件
+type OrphanFile struct {
+	Key     string    `json:"key"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// UploadGCResult 回收结果
+type UploadGCResult struct {
+	Scanned         int          `json:"scanned"`          // 遍历的文件数
+	Orphans         []OrphanFile `json:"orphans"`          // 孤儿文件
+	OrphanBytes     int64        `json:"orphan_bytes"`     // 孤儿文件总字节数
+	Removed         int          `json:"removed"`          // 已删除或隔离的文件数
+	Failed          int          `json:"failed"`           // 处理失败的文件数
+	ExpiredSessions int          `json:"expired_sessions"` // 清理的过期上传会话数
+}
+
+// UploadGCService 孤儿上传文件回收服务接口
+type UploadGCService interface {
+	// Run 遍历存储后端，回收未被任何记录引用且超过保留期的文件
+	Run(ctx context.Context, opts UploadGCOptions) (*UploadGCResult, error)
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// 上传会话状态
+const (
+	UploadSessionPending   = "pending"   // 上传中
+	UploadSessionCompleted = "completed" // 已完成合并
+)
+
+// ErrUploadOffsetMismatch 分片偏移量与服务端已接收的字节数不一致（客户端应按返回的 offset 续传）
+var ErrUploadOffsetMismatch = errors.New("分片偏移量与已上传进度不一致")
+
+// UploadSession 大文件分片上传会话
+// 客户端创建会话后按顺序上传固定大小的分片，断线后可查询已接收的字节数继续上传，全部上传后合并为一个文件
+type UploadSession struct {
+	ID        string    `gorm:"primaryKey;size:32;comment:会话ID(随机生成)" json:"id"`
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+
+	// 上传人
+	UserID uint `gorm:"not null;index;comment:上传人ID" json:"user_id"`
+
+	// 文件信息
+	Filename  string `gorm:"size:255;not null;comment:原始文件名" json:"filename"`
+	TotalSize int64  `gorm:"not null;comment:文件总字节数" json:"total_size"`
+	ChunkSize int64  `gorm:"not null;comment:分片字节数(除最后一片外每片大小相同)" json:"chunk_size"`
+	MimeType  string `gorm:"size:100;comment:MIME类型(收到第一个分片时检测)" json:"mime_type"`
+
+	// 上传进度：已接收字节数，以及已接收内容的 SHA-256 中间状态（用于断点续传时继续计算）
+	ReceivedSize int64  `gorm:"default:0;comment:已接收字节数" json:"offset"`
+	HashState    []byte `gorm:"type:blob;comment:SHA-256中间状态" json:"-"`
+
+	// 完成后的文件信息
+	SHA256   string `gorm:"column:sha256;size:64;comment:文件SHA-256(十六进制)" json:"sha256,omitempty"`
+	FilePath string `gorm:"size:255;comment:合并后的文件存储路径" json:"-"`
+	// 图片合并后去除元数据，文件大小可能小于 TotalSize
+	FileSize      int64  `gorm:"default:0;comment:合并后的文件字节数" json:"file_size,omitempty"`
+	ThumbnailPath string `gorm:"size:255;comment:缩略图存储路径(仅图片)" json:"-"`
+
+	Status    string    `gorm:"size:20;default:'pending';index;comment:状态(pending/completed)" json:"status"`
+	ExpiresAt time.Time `gorm:"index;comment:过期时间(每次上传分片后顺延)" json:"expires_at"`
+}
+
+// TableName 指定表名
+func (UploadSession) TableName() string {
+	return "upload_sessions"
+}
+
+// UploadSessionRepository 上传会话仓库接口
+type UploadSessionRepository interface {
+	Create(session *UploadSession) error
+	FindByID(id string) (*UploadSession, error)
+	// AdvanceOffset 已接收字节数仍为 fromOffset 时更新进度，返回是否更新成功（防止并发写入同一分片）
+	AdvanceOffset(session *UploadSession, fromOffset int64) (bool, error)
+	Update(session *UploadSession) error
+	Delete(id string) error
+	ListExpired(before time.Time, limit int) ([]UploadSession, error)
+}
+
+// UploadSessionService 分片上传服务接口
+type UploadSessionService interface {
+	// CreateSession 创建上传会话，文件大小受角色上限限制
+	CreateSession(userID uint, userRole string, filename string, totalSize int64) (*UploadSession, error)
+	// GetSession 获取会话（仅上传人），用于断线后查询续传位置
+	GetSession(id string, userID uint) (*UploadSession, error)
+	// WriteChunk 写入从 offset 开始的分片，offset 必须等于已接收字节数
+	WriteChunk(ctx context.Context, id string, userID uint, offset int64, body io.Reader, length int64) (*UploadSession, error)
+	// CompleteSession 合并全部分片为一个文件并校验 SHA-256
+	CompleteSession(ctx context.Context, id string, userID uint) (*UploadSession, error)
+	// AbortSession 取消上传并删除已上传的分片
+	AbortSession(ctx context.Context, id string, userID uint) error
+	// CleanupExpired 清理过期的会话及其分片，返回清理的会话数
+	CleanupExpired(ctx context.Context) (int, error)
+}
@@ -8,7 +8,7 @@ import (
 // 登录(Login) 和 注册(Register) 是业务行为，不是单纯的 CRUD
 type UserService interface {
 	Register(user *User) error
-	Login(username, password string) (*User, string, error)
+	Login(username, password string, client ClientInfo) (*LoginResult, error)
 	GetUser(id uint) (*User, error)
 	UpdateProfile(userID uint, name string, bio string, phone string, email string) error // 更新基本信息与简介
 	ChangePassword(userID uint, oldPassword, newPassword string) error                    // 修改密码
@@ -45,6 +45,9 @@ type User struct {
 	Email string `gorm:"size:100;comment:邮箱" json:"email"`
 	Name  string `gorm:"size:50;comment:姓名" json:"name"`
 
+	// 邮箱验证时间，为空表示当前邮箱未验证（修改邮箱后清空）
+	EmailVerifiedAt *time.Time `gorm:"comment:邮箱验证时间" json:"email_verified_at"`
+
 	// --- 新增字段 ---
 	Bio   string        `gorm:"type:text;comment:个人简介" json:"bio"`
 	OrgID uint          `gorm:"index;comment:所属组织ID" json:"org_id"`
@@ -55,6 +58,9 @@ type User struct {
 	Avatar   *Avatar `gorm:"-" json:"avatar,omitempty"` // 手动加载头像信息
 
 	LastLoginAt *time.Time `gorm:"comment:最后登录时间" json:"last_login_at"`
+
+	// 令牌版本：退出全部会话时递增，签发时版本不一致的访问令牌全部失效
+	TokenVersion int `gorm:"default:0;comment:令牌版本(退出全部会话时递增)" json:"-"`
 }
 
 // Organization 组织实体
@@ -82,8 +88,17 @@ type UserRepository interface {
 	UpdateLastLoginAt(userID uint, loginTime time.Time) error
 	UpdateProfileFields(userID uint, name, bio, phone, email string) error
 	UpdateAvatarID(userID uint, avatarID uint) error
+	GetTokenVersion(userID uint) (int, error)
+	IncrementTokenVersion(userID uint) error
+	// MarkEmailVerified 以邮箱未变更为条件记录邮箱验证时间，返回是否成功
+	MarkEmailVerified(userID uint, email string, verifiedAt time.Time) (bool, error)
+	// ClearEmailVerified 清空邮箱验证时间（邮箱变更后需要重新验证）
+	ClearEmailVerified(userID uint) error
 	FindByUsername(username string) (*User, error)
+	FindByEmail(email string) ([]User, error)
 	FindByID(id uint) (*User, error)
+	FindByIDs(ids []uint) ([]User, error)
+	FindByOrgID(orgID uint) ([]User, error)
 }
 
 // OrganizationRepository 组织仓库接口
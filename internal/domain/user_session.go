@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrSessionNotFound 会话不存在或不属于该用户
+var ErrSessionNotFound = errors.New("会话不存在")
+
+// ClientInfo 登录或刷新时的客户端信息
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// UserSession 登录会话
+// 每次登录创建一个会话，会话内的访问令牌和刷新令牌带有相同的会话ID，会话被吊销后其令牌全部失效
+type UserSession struct {
+	ID        string    `gorm:"primaryKey;size:32;comment:会话ID(与令牌中的sid一致)" json:"id"`
+	CreatedAt time.Time `gorm:"comment:登录时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+
+	UserID uint `gorm:"not null;index;comment:用户ID" json:"user_id"`
+
+	// 客户端信息
+	Device    string `gorm:"size:100;comment:设备(由User-Agent解析，如 Chrome / Windows)" json:"device"`
+	UserAgent string `gorm:"size:512;comment:User-Agent" json:"user_agent"`
+	IP        string `gorm:"size:64;comment:最近一次访问的IP" json:"ip"`
+	LoginIP   string `gorm:"size:64;comment:登录IP" json:"login_ip"`
+
+	LastSeenAt time.Time  `gorm:"comment:最近活动时间" json:"last_seen_at"`
+	ExpiresAt  time.Time  `gorm:"index;comment:过期时间(刷新令牌过期时间，每次刷新顺延)" json:"expires_at"`
+	RevokedAt  *time.Time `gorm:"comment:注销时间" json:"revoked_at,omitempty"`
+
+	// Current 是否为发起请求的会话（不存储）
+	Current bool `gorm:"-" json:"current"`
+}
+
+// TableName 指定表名
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountHandler 邮箱验证与找回密码处理器
+type AccountHandler struct {
+	Service domain.AccountService
+}
+
+// NewAccountHandler 创建邮箱验证与找回密码处理器实例
+func NewAccountHandler(s domain.AccountService) *AccountHandler {
+	return &AccountHandler{Service: s}
+}
+
+// forgotPasswordRequest 找回密码请求体
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// resetPasswordRequest 重置密码请求体
+type resetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// verifyEmailRequest 验证邮箱请求体
+type verifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ForgotPassword [POST] /api/v1/auth/forgot-password - 发送重置密码邮件（邮箱是否存在都返回相同结果）
+func (h *AccountHandler) ForgotPassword(c *gin.Context) {
+	var req forgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.RequestPasswordReset(req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send password reset email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "如果该邮箱已注册，重置密码邮件已发送，请查收"})
+}
+
+// ResetPassword [POST] /api/v1/auth/reset-password - 使用邮件中的令牌设置新密码
+func (h *AccountHandler) ResetPassword(c *gin.Context) {
+	var req resetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.ResetPassword(req.Token, req.Password); err != nil {
+		respondPasswordError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "密码已重置，请使用新密码登录"})
+}
+
+// VerifyEmail [POST] /api/v1/auth/verify-email - 使用邮件中的令牌完成邮箱验证
+func (h *AccountHandler) VerifyEmail(c *gin.Context) {
+	var req verifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.VerifyEmail(req.Token); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "邮箱验证成功"})
+}
+
+// SendVerificationEmail [POST] /api/v1/user/verify-email - 重新发送邮箱验证邮件
+func (h *AccountHandler) SendVerificationEmail(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	if err := h.Service.SendVerificationEmail(userID.(uint)); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmailNotSet):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrEmailAlreadyVerified):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrAccountTokenTooFrequent):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{"error": "验证邮件发送失败，请稍后重试"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "验证邮件已发送，请查收"})
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler 个人 API Key 处理器
+type APIKeyHandler struct {
+	Service domain.APIKeyService
+}
+
+// NewAPIKeyHandler 创建 API Key 处理器实例
+func NewAPIKeyHandler(s domain.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{Service: s}
+}
+
+// createAPIKeyRequest 创建 API Key 请求体
+type createAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes" binding:"required"`
+	ExpiresInDays int      `json:"expires_in_days"` // 有效天数，0 或不传时使用默认有效期
+}
+
+// List [GET] /api/v1/user/api-keys - 我的 API Key（不返回 Key 明文）
+func (h *APIKeyHandler) List(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	keys, err := h.Service.List(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list API keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": keys,
+	})
+}
+
+// Create [POST] /api/v1/user/api-keys - 创建 API Key，Key 明文只在本次返回
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req createAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name 和 scopes 不能为空"})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	created, err := h.Service.Create(userID.(uint), req.Name, req.Scopes, req.ExpiresInDays)
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "API Key 已创建，请立即保存，之后将无法再次查看",
+		"key":     created.Key,
+		"api_key": created.APIKey,
+	})
+}
+
+// Revoke [DELETE] /api/v1/user/api-keys/:id - 吊销我的 API Key
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的 API Key ID"})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.Service.Revoke(userID.(uint), uint(id)); err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API Key 已吊销"})
+}
+
+// AdminList [GET] /api/v1/admin/users/:id/api-keys - 管理员查看指定用户的 API Key
+func (h *APIKeyHandler) AdminList(c *gin.Context) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有管理员可以查看其他用户的 API Key"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户ID"})
+		return
+	}
+
+	keys, err := h.Service.List(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list API keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": keys,
+	})
+}
+
+// AdminRevoke [DELETE] /api/v1/admin/users/:id/api-keys/:kid - 管理员吊销指定用户的 API Key
+func (h *APIKeyHandler) AdminRevoke(c *gin.Context) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有管理员可以吊销其他用户的 API Key"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户ID"})
+		return
+	}
+	keyID, err := strconv.ParseUint(c.Param("kid"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的 API Key ID"})
+		return
+	}
+
+	if err := h.Service.Revoke(uint(id), uint(keyID)); err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API Key 已吊销"})
+}
+
+// respondAPIKeyError 将 API Key 错误映射为 HTTP 状态码
+func respondAPIKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrAPIKeyNameInvalid),
+		errors.Is(err, domain.ErrAPIKeyScopeInvalid),
+		errors.Is(err, domain.ErrAPIKeyExpiryInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrAPIKeyLimitExceeded):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/jwt"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthHandler 令牌刷新与注销处理器
+type AuthHandler struct {
+	Service domain.AuthTokenService
+}
+
+// NewAuthHandler 创建令牌处理器实例
+func NewAuthHandler(s domain.AuthTokenService) *AuthHandler {
+	return &AuthHandler{Service: s}
+}
+
+// refreshRequest 刷新令牌请求体
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Refresh [POST] /api/v1/auth/refresh - 使用刷新令牌换取新的访问令牌和刷新令牌
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	client := domain.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+	user, tokens, err := h.Service.Refresh(req.RefreshToken, client)
+	if err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenInvalid) || errors.Is(err, domain.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "Token refreshed",
+		"token":              tokens.AccessToken,
+		"refresh_token":      tokens.RefreshToken,
+		"token_type":         tokens.TokenType,
+		"expires_in":         tokens.ExpiresIn,
+		"refresh_expires_in": tokens.RefreshExpiresIn,
+		"user":               user,
+	})
+}
+
+// Logout [POST] /api/v1/auth/logout - 注销当前会话（当前访问令牌和该会话的刷新令牌立即失效）
+func (h *AuthHandler) Logout(c *gin.Context) {
+	value, _ := c.Get("claims")
+	claims, ok := value.(*jwt.Claims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.Service.Logout(claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logout successful"})
+}
+
+// LogoutAll [POST] /api/v1/user/logout-all - 退出当前用户的全部会话并吊销其 API Key
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	if err := h.Service.LogoutAll(userID.(uint)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已退出全部会话，请重新登录"})
+}
+
+// AdminLogoutAll [POST] /api/v1/admin/users/:id/logout-all - 管理员强制注销指定用户的全部会话并吊销其 API Key
+func (h *AuthHandler) AdminLogoutAll(c *gin.Context) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有管理员可以注销其他用户的会话"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户ID"})
+		return
+	}
+
+	if err := h.Service.LogoutAll(uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已注销该用户的全部会话"})
+}
+
+// ListSessions [GET] /api/v1/user/sessions - 获取当前用户的活跃会话（设备、IP、最近活动时间）
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	sessions, err := h.Service.ListSessions(userID.(uint), currentSessionID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": sessions,
+	})
+}
+
+// RevokeSession [DELETE] /api/v1/user/sessions/:id - 注销当前用户的指定会话
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	if err := h.Service.RevokeSession(userID.(uint), c.Param("id")); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "会话已注销"})
+}
+
+// AdminListSessions [GET] /api/v1/admin/users/:id/sessions - 管理员查看指定用户的活跃会话
+func (h *AuthHandler) AdminListSessions(c *gin.Context) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有管理员可以查看其他用户的会话"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户ID"})
+		return
+	}
+
+	sessions, err := h.Service.ListSessions(uint(id), currentSessionID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": sessions,
+	})
+}
+
+// AdminRevokeSession [DELETE] /api/v1/admin/users/:id/sessions/:sid - 管理员注销指定用户的某个会话
+func (h *AuthHandler) AdminRevokeSession(c *gin.Context) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有管理员可以注销其他用户的会话"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户ID"})
+		return
+	}
+
+	if err := h.Service.RevokeSession(uint(id), c.Param("sid")); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "会话已注销"})
+}
+
+// currentSessionID 发起请求的访问令牌所属的会话ID
+func currentSessionID(c *gin.Context) string {
+	value, _ := c.Get("claims")
+	if claims, ok := value.(*jwt.Claims); ok {
+		return claims.SessionID
+	}
+	return ""
+}
@@ -13,12 +13,13 @@ import (
 
 // AvatarHandler 头像管理处理器
 type AvatarHandler struct {
-	service domain.AvatarService
+	service  domain.AvatarService
+	uploader *upload.Uploader
 }
 
 // NewAvatarHandler 创建头像处理器实例
-func NewAvatarHandler(s domain.AvatarService) *AvatarHandler {
-	return &AvatarHandler{service: s}
+func NewAvatarHandler(s domain.AvatarService, uploader *upload.Uploader) *AvatarHandler {
+	return &AvatarHandler{service: s, uploader: uploader}
 }
 
 // UploadAvatarHandler 管理员上传头像到平台头像库
@@ -52,14 +53,14 @@ func (h *AvatarHandler) UploadAvatarHandler(c *gin.Context) {
 	baseURL := fmt.Sprintf("%s://%s", scheme, c.Request.Host)
 
 	// 上传文件到 avatars 子目录
-	result, err := upload.UploadFileToDir(file, baseURL, "avatars")
+	result, err := h.uploader.UploadAvatar(c.Request.Context(), file, baseURL)
 	if err != nil {
 		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// 保存头像记录到数据库
-	avatar, err := h.service.UploadAvatar(name, result.URL)
+	avatar, err := h.service.UploadAvatar(name, result.URL, result.ThumbnailURL)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "保存头像信息失败")
 		return
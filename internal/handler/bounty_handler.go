@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/response"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BountyHandler 奖励处理器
+type BountyHandler struct {
+	Service domain.BountyService
+}
+
+// NewBountyHandler 创建奖励处理器实例
+func NewBountyHandler(s domain.BountyService) *BountyHandler {
+	return &BountyHandler{Service: s}
+}
+
+// AwardBountyRequest 发放奖励请求 DTO
+type AwardBountyRequest struct {
+	Type     string `json:"type" binding:"omitempty,oneof=award bonus"`
+	Amount   int64  `json:"amount" binding:"required,min=1"` // 最小货币单位（如分）
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+	Note     string `json:"note" binding:"omitempty,max=500"`
+}
+
+// UpdateRewardStatusRequest 变更奖励状态请求 DTO
+type UpdateRewardStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved cancelled"`
+	Note   string `json:"note" binding:"omitempty,max=500"`
+}
+
+// CreatePayoutBatchRequest 创建发放批次请求 DTO
+type CreatePayoutBatchRequest struct {
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+	Note     string `json:"note" binding:"omitempty,max=500"`
+}
+
+// AwardBounty 为报告发放奖励（仅admin/vendor）
+// POST /api/v1/reports/:id/bounties
+func (h *BountyHandler) AwardBounty(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的报告ID")
+		return
+	}
+
+	var req AwardBountyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+
+	reward, err := h.Service.AwardBounty(uint(id), userID.(uint), userRole, &domain.BountyAwardInput{
+		Type:     req.Type,
+		Amount:   req.Amount,
+		Currency: req.Currency,
+		Note:     req.Note,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrBountyAlreadyAwarded) {
+			response.Error(c, http.StatusConflict, err.Error())
+			return
+		}
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "奖励已发放，等待审批", reward)
+}
+
+// ListReportBounties 获取报告的奖励及账本
+// GET /api/v1/reports/:id/bounties
+func (h *BountyHandler) ListReportBounties(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的报告ID")
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+
+	rewards, ledger, err := h.Service.ListReportBounties(uint(id), userID.(uint), userRole)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":   rewards,
+		"ledger": ledger,
+	})
+}
+
+// UpdateRewardStatus 变更奖励发放状态（审批/取消）
+// PUT /api/v1/bounties/:id/status
+func (h *BountyHandler) UpdateRewardStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的奖励ID")
+		return
+	}
+
+	var req UpdateRewardStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+
+	reward, err := h.Service.UpdateRewardStatus(uint(id), req.Status, userID.(uint), userRole, req.Note)
+	if err != nil {
+		if errors.Is(err, domain.ErrBountyRewardConflict) {
+			response.Error(c, http.StatusConflict, err.Error())
+			return
+		}
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, reward)
+}
+
+// GetEarnings 获取当前用户的收益汇总
+// GET /api/v1/user/earnings
+func (h *BountyHandler) GetEarnings(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	earnings, err := h.Service.GetUserEarnings(userID.(uint))
+	if err != nil {
+		response.InternalError(c, "获取收益失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, earnings)
+}
+
+// CreatePayoutBatch 创建发放批次（仅admin）
+// POST /api/v1/admin/payouts
+func (h *BountyHandler) CreatePayoutBatch(c *gin.Context) {
+	var req CreatePayoutBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+	if userRole != "admin" {
+		response.Forbidden(c, "只有管理员可以创建发放批次")
+		return
+	}
+
+	batch, err := h.Service.CreatePayoutBatch(req.Currency, userID.(uint), userRole, req.Note)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, batch)
+}
+
+// ListPayoutBatches 获取发放批次列表（仅admin）
+// GET /api/v1/admin/payouts
+func (h *BountyHandler) ListPayoutBatches(c *gin.Context) {
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+	if userRole != "admin" {
+		response.Forbidden(c, "只有管理员可以查看发放批次")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	batches, total, err := h.Service.ListPayoutBatches(page, pageSize, userRole)
+	if err != nil {
+		response.InternalError(c, "获取发放批次失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":      batches,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// ExportPayoutBatch 导出发放批次明细为 CSV（仅admin）
+// GET /api/v1/admin/payouts/:id/export
+func (h *BountyHandler) ExportPayoutBatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的批次ID")
+		return
+	}
+
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+	if userRole != "admin" {
+		response.Forbidden(c, "只有管理员可以导出发放批次")
+		return
+	}
+
+	batch, rewards, err := h.Service.ExportPayoutBatch(uint(id), userRole)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"batch_id", "reward_id", "report_id", "type", "author_id", "username", "name", "email", "phone", "amount", "currency", "status"})
+	for _, reward := range rewards {
+		var username, name, email, phone string
+		if reward.Author != nil {
+			username = reward.Author.Username
+			name = reward.Author.Name
+			email = reward.Author.Email
+			phone = reward.Author.Phone
+		}
+		_ = w.Write([]string{
+			strconv.FormatUint(uint64(batch.ID), 10),
+			strconv.FormatUint(uint64(reward.ID), 10),
+			strconv.FormatUint(uint64(reward.ReportID), 10),
+			reward.Type,
+			strconv.FormatUint(uint64(reward.AuthorID), 10),
+			csvSafe(username),
+			csvSafe(name),
+			csvSafe(email),
+			csvSafe(phone),
+			strconv.FormatInt(reward.Amount, 10),
+			reward.Currency,
+			reward.Status,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		response.InternalError(c, "导出失败: "+err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=payout-batch-%d.csv", batch.ID))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// MarkPayoutBatchPaid 确认发放批次已打款（仅admin）
+// POST /api/v1/admin/payouts/:id/paid
+func (h *BountyHandler) MarkPayoutBatchPaid(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的批次ID")
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+	if userRole != "admin" {
+		response.Forbidden(c, "只有管理员可以确认打款")
+		return
+	}
+
+	batch, err := h.Service.MarkPayoutBatchPaid(uint(id), userID.(uint), userRole)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "批次已确认打款", batch)
+}
+
+// csvSafe 防止 CSV 公式注入：以 = + - @ 制表符或回车开头的单元格前加单引号，表格软件按文本显示
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,31 @@
+package handler
+
+import "testing"
+
+func TestCSVSafe(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"alice", "alice"},
+		{"", ""},
+		{"50000", "50000"},
+		{"=HYPERLINK(\"http://evil.example.com\",\"点击\")", "'=HYPERLINK(\"http://evil.example.com\",\"点击\")"},
+		{"+1+cmd|' /C calc'!A0", "'+1+cmd|' /C calc'!A0"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"\t=1+1", "'\t=1+1"},
+		{"\r=1+1", "'\r=1+1"},
+		// 只检查首字符
+		{"张三=1", "张三=1"},
+		{"a@example.com", "a@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := csvSafe(tt.value); got != tt.want {
+				t.Errorf("csvSafe(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
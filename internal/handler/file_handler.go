@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/response"
+	"bug-bounty-lite/pkg/upload"
+	"errors"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FileHandler 上传文件受控下载处理器
+type FileHandler struct {
+	Service domain.FileAccessService
+	Storage upload.Storage
+	Signer  *upload.URLSigner
+}
+
+// NewFileHandler 创建文件下载处理器实例
+func NewFileHandler(s domain.FileAccessService, storage upload.Storage, signer *upload.URLSigner) *FileHandler {
+	return &FileHandler{Service: s, Storage: storage, Signer: signer}
+}
+
+// SignFileRequest 获取签名下载链接请求 DTO
+type SignFileRequest struct {
+	URL string `json:"url" binding:"required,max=500"`
+}
+
+// Download 下载上传文件
+// 携带有效签名（expires、signature 参数）时直接下载，否则需登录并通过文件归属校验
+// GET /api/v1/files/*filepath
+func (h *FileHandler) Download(c *gin.Context) {
+	filePath, ok := upload.CleanFilePath(c.Param("filepath"))
+	if !ok {
+		response.NotFound(c, "文件不存在")
+		return
+	}
+
+	if signature := c.Query("signature"); signature != "" {
+		if !h.Signer.Verify(filePath, c.Query("expires"), signature) {
+			response.Forbidden(c, "下载链接无效或已过期")
+			return
+		}
+	} else {
+		userID, exists := c.Get("userID")
+		if !exists {
+			response.Unauthorized(c, "用户未认证")
+			return
+		}
+		role, _ := c.Get("role")
+		userRole, _ := role.(string)
+
+		if err := h.Service.CheckAccess(filePath, userID.(uint), userRole); err != nil {
+			response.Forbidden(c, err.Error())
+			return
+		}
+	}
+
+	// 报告附件通过恶意软件扫描后才能下载
+	if err := h.Service.CheckScanStatus(filePath); err != nil {
+		response.Forbidden(c, err.Error())
+		return
+	}
+
+	h.serve(c, filePath, "private, no-store")
+}
+
+// Avatar 公开访问头像文件（无需登录）
+// GET /uploads/avatars/*filepath
+func (h *FileHandler) Avatar(c *gin.Context) {
+	filename, ok := upload.CleanFilePath(c.Param("filepath"))
+	if !ok {
+		response.NotFound(c, "文件不存在")
+		return
+	}
+	h.serve(c, path.Join(upload.AvatarDir, filename), "public, max-age=86400")
+}
+
+// serve 从存储后端读取文件并返回给客户端
+func (h *FileHandler) serve(c *gin.Context, key string, cacheControl string) {
+	reader, info, err := h.Storage.Open(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, upload.ErrObjectNotFound) {
+			response.NotFound(c, "文件不存在")
+		} else {
+			response.InternalError(c, "读取文件失败")
+		}
+		return
+	}
+	defer reader.Close()
+
+	headers := map[string]string{
+		"Cache-Control":          cacheControl,
+		"X-Content-Type-Options": "nosniff",
+	}
+	// 只有栅格图片可以在浏览器中直接显示，其他类型强制下载，避免上传的内容被当作页面渲染
+	if !upload.InlineContentTypes[info.ContentType] {
+		headers["Content-Disposition"] = "attachment"
+	}
+	c.DataFromReader(http.StatusOK, info.Size, info.ContentType, reader, headers)
+}
+
+// SignURL 为有权访问的文件生成短期有效的签名下载链接（用于浏览器直接打开）
+// POST /api/v1/files/sign
+func (h *FileHandler) SignURL(c *gin.Context) {
+	var req SignFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	filePath, ok := upload.FilePathFromURL(req.URL)
+	if !ok {
+		response.BadRequest(c, "无效的文件地址")
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+
+	if err := h.Service.CheckAccess(filePath, userID.(uint), userRole); err != nil {
+		response.Forbidden(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"url":        h.Signer.SignURL(upload.FileRoutePrefix + filePath),
+		"expires_in": int(h.Signer.TTL().Seconds()),
+	})
+}
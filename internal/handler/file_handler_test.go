@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"bug-bounty-lite/pkg/upload"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFileHandlerServeHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	storage := upload.NewLocalStorage(t.TempDir())
+	handler := NewFileHandler(nil, storage, nil)
+	router := gin.New()
+	router.GET(upload.AvatarRoutePrefix+"*filepath", handler.Avatar)
+
+	tests := []struct {
+		name            string
+		filename        string
+		wantContentType string
+		wantAttachment  bool
+	}{
+		{name: "PNG 直接显示", filename: "avatar.png", wantContentType: "image/png"},
+		{name: "WebP 直接显示", filename: "avatar.webp", wantContentType: "image/webp"},
+		{name: "PDF 强制下载", filename: "doc.pdf", wantContentType: "application/pdf", wantAttachment: true},
+		{name: "HTML 强制下载", filename: "xss.html", wantContentType: upload.DefaultContentType, wantAttachment: true},
+		{name: "SVG 强制下载", filename: "xss.svg", wantContentType: upload.DefaultContentType, wantAttachment: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := "<svg onload=alert(1)>"
+			if err := storage.Put(context.Background(), upload.AvatarDir+"/"+tt.filename, strings.NewReader(content), int64(len(content))); err != nil {
+				t.Fatal(err)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, upload.AvatarRoutePrefix+tt.filename, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+				t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+			}
+			if got := w.Header().Get("Content-Disposition"); (got == "attachment") != tt.wantAttachment {
+				t.Errorf("Content-Disposition = %q, want attachment = %v", got, tt.wantAttachment)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler 站内通知处理器
+type NotificationHandler struct {
+	Service domain.NotificationService
+}
+
+// NewNotificationHandler 创建通知处理器实例
+func NewNotificationHandler(s domain.NotificationService) *NotificationHandler {
+	return &NotificationHandler{Service: s}
+}
+
+// ListNotifications 获取当前用户的通知列表
+// GET /api/v1/notifications?page=1&page_size=20&unread=true
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, total, err := h.Service.ListNotifications(userID.(uint), unreadOnly, page, pageSize)
+	if err != nil {
+		response.InternalError(c, "获取通知失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":      notifications,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// GetUnreadCount 获取当前用户的未读通知数
+// GET /api/v1/notifications/unread-count
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	count, err := h.Service.CountUnread(userID.(uint))
+	if err != nil {
+		response.InternalError(c, "获取未读通知数失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{"count": count})
+}
+
+// MarkRead 将通知标记为已读
+// PUT /api/v1/notifications/:id/read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的通知ID")
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.Service.MarkRead(userID.(uint), uint(id)); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "已标记为已读", nil)
+}
+
+// MarkAllRead 将当前用户的所有通知标记为已读
+// PUT /api/v1/notifications/read-all
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	count, err := h.Service.MarkAllRead(userID.(uint))
+	if err != nil {
+		response.InternalError(c, "标记已读失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{"count": count})
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// oidcStateCookie 保存 state 哈希的 Cookie，回调时比对，把 state 绑定到发起登录的浏览器
+	oidcStateCookie = "oidc_state"
+	// oidcCookiePath Cookie 只在单点登录接口下发送
+	oidcCookiePath = "/api/v1/auth/oidc"
+)
+
+// OIDCHandler 单点登录处理器
+type OIDCHandler struct {
+	Service domain.OIDCService
+}
+
+// NewOIDCHandler 创建单点登录处理器实例
+func NewOIDCHandler(s domain.OIDCService) *OIDCHandler {
+	return &OIDCHandler{Service: s}
+}
+
+// oidcCallbackRequest 身份提供方回调参数（前端回调页面从地址栏取出后提交）
+type oidcCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// Providers [GET] /api/v1/auth/oidc/providers - 登录页展示的身份提供方
+func (h *OIDCHandler) Providers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": h.Service.Providers(),
+	})
+}
+
+// Authorize [GET] /api/v1/auth/oidc/:provider/authorize - 获取身份提供方的授权地址，前端保存 state 后跳转
+// 同时下发 HttpOnly Cookie 保存 state 哈希，回调只接受同一浏览器发起的登录
+func (h *OIDCHandler) Authorize(c *gin.Context) {
+	authorization, err := h.Service.Authorize(c.Param("provider"))
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+	setOIDCStateCookie(c, hashOIDCState(authorization.State), authorization.ExpiresIn)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": authorization,
+	})
+}
+
+// Callback [POST] /api/v1/auth/oidc/:provider/callback - 提交授权码完成登录，返回与密码登录相同的结果
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	var req oidcCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code 和 state 不能为空"})
+		return
+	}
+
+	// state 必须与本浏览器发起登录时下发的 Cookie 一致，防止攻击者让受害者登录到攻击者的账号
+	cookie, err := c.Cookie(oidcStateCookie)
+	setOIDCStateCookie(c, "", -1)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie), []byte(hashOIDCState(req.State))) != 1 {
+		respondOIDCError(c, domain.ErrOIDCStateInvalid)
+		return
+	}
+
+	client := domain.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+	result, err := h.Service.Callback(c.Param("provider"), req.Code, req.State, client)
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+
+	respondLoginResult(c, result)
+}
+
+// hashOIDCState 计算 state 的 SHA-256，Cookie 中不保存 state 明文
+func hashOIDCState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+// setOIDCStateCookie 设置或清除（maxAge < 0）state Cookie：HttpOnly、SameSite=Lax，HTTPS 请求时加 Secure
+func setOIDCStateCookie(c *gin.Context, value string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, value, maxAge, oidcCookiePath, "", secure, true)
+}
+
+// respondOIDCError 将单点登录错误映射为 HTTP 状态码
+func respondOIDCError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrOIDCProviderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrOIDCStateInvalid),
+		errors.Is(err, domain.ErrOIDCVerifyFailed):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrOIDCAccessDenied),
+		errors.Is(err, domain.ErrOIDCAccountNotFound),
+		errors.Is(err, domain.ErrOIDCEmailConflict):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrOIDCUnavailable):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeOIDCService 每次授权返回新的 state，回调时记录调用
+type fakeOIDCService struct {
+	domain.OIDCService
+	states    int
+	callbacks int
+}
+
+func (s *fakeOIDCService) Authorize(provider string) (*domain.OIDCAuthorization, error) {
+	s.states++
+	return &domain.OIDCAuthorization{AuthorizationURL: "https://idp.example.com/authorize", State: "state-" + strconv.Itoa(s.states), ExpiresIn: 600}, nil
+}
+
+func (s *fakeOIDCService) Callback(provider, code, state string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	s.callbacks++
+	return &domain.LoginResult{User: &domain.User{ID: 1}, Tokens: &domain.TokenPair{AccessToken: "access"}}, nil
+}
+
+func TestOIDCHandlerStateCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// authorize 发起一次登录，返回下发的 state Cookie
+	authorize := func(t *testing.T, router *gin.Engine) *http.Cookie {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/mock/authorize", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("authorize status = %d", w.Code)
+		}
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == oidcStateCookie {
+				return cookie
+			}
+		}
+		t.Fatal("authorize 没有下发 state Cookie")
+		return nil
+	}
+
+	tests := []struct {
+		name string
+		// cookie 回调请求携带的 Cookie（第一次登录为 state-1，第二次为 state-2）
+		cookie       func(first, second *http.Cookie) *http.Cookie
+		state        string
+		wantStatus   int
+		wantCallback bool
+	}{
+		{name: "同一浏览器回调", cookie: func(first, second *http.Cookie) *http.Cookie { return first }, state: "state-1", wantStatus: http.StatusOK, wantCallback: true},
+		{name: "后发起的登录", cookie: func(first, second *http.Cookie) *http.Cookie { return second }, state: "state-2", wantStatus: http.StatusOK, wantCallback: true},
+		{name: "没有 Cookie", cookie: func(first, second *http.Cookie) *http.Cookie { return nil }, state: "state-1", wantStatus: http.StatusUnauthorized},
+		{name: "Cookie 属于另一次登录", cookie: func(first, second *http.Cookie) *http.Cookie { return second }, state: "state-1", wantStatus: http.StatusUnauthorized},
+		{name: "Cookie 为 state 明文", cookie: func(first, second *http.Cookie) *http.Cookie {
+			return &http.Cookie{Name: oidcStateCookie, Value: "state-1"}
+		}, state: "state-1", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeOIDCService{}
+			handler := NewOIDCHandler(service)
+			router := gin.New()
+			router.GET("/api/v1/auth/oidc/:provider/authorize", handler.Authorize)
+			router.POST("/api/v1/auth/oidc/:provider/callback", handler.Callback)
+
+			first := authorize(t, router)
+			second := authorize(t, router)
+			if !first.HttpOnly || first.Path != oidcCookiePath || first.SameSite != http.SameSiteLaxMode || first.MaxAge != 600 {
+				t.Errorf("state Cookie = %+v, want HttpOnly, SameSite=Lax, Path=%s, MaxAge=600", first, oidcCookiePath)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/oidc/mock/callback", strings.NewReader(`{"code":"code","state":"`+tt.state+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if cookie := tt.cookie(first, second); cookie != nil {
+				req.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("callback status = %d, want %d, body = %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if (service.callbacks == 1) != tt.wantCallback {
+				t.Errorf("service Callback called %d times, want called = %v", service.callbacks, tt.wantCallback)
+			}
+			// 无论成功与否都清除 Cookie
+			cleared := false
+			for _, cookie := range w.Result().Cookies() {
+				cleared = cleared || (cookie.Name == oidcStateCookie && cookie.MaxAge < 0)
+			}
+			if !cleared {
+				t.Error("callback 没有清除 state Cookie")
+			}
+		})
+	}
+}
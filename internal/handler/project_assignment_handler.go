@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProjectAssignmentHandler 项目指派处理器（管理员）
+type ProjectAssignmentHandler struct {
+	Service domain.ProjectAssignmentService
+}
+
+// NewProjectAssignmentHandler 创建项目指派处理器实例
+func NewProjectAssignmentHandler(s domain.ProjectAssignmentService) *ProjectAssignmentHandler {
+	return &ProjectAssignmentHandler{Service: s}
+}
+
+// AssignmentTargetRequest 批量指派/取消指派请求 DTO
+// user_ids、organization_id、ranking_top 至少填写一项，结果取并集
+type AssignmentTargetRequest struct {
+	UserIDs        []uint `json:"user_ids" binding:"omitempty,max=1000"`
+	OrganizationID uint   `json:"organization_id"`
+	RankingTop     int    `json:"ranking_top" binding:"omitempty,min=1,max=1000"`
+}
+
+// bindAssignmentRequest 校验管理员权限并解析项目ID和请求体
+func bindAssignmentRequest(c *gin.Context) (uint, *domain.AssignmentTarget, bool) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		response.Forbidden(c, "只有管理员可以管理项目指派")
+		return 0, nil, false
+	}
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的项目ID")
+		return 0, nil, false
+	}
+
+	var req AssignmentTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return 0, nil, false
+	}
+
+	return uint(projectID), &domain.AssignmentTarget{
+		UserIDs:    req.UserIDs,
+		OrgID:      req.OrganizationID,
+		RankingTop: req.RankingTop,
+	}, true
+}
+
+// ListAssignees 获取项目的指派用户及接受情况（仅admin）
+// GET /api/v1/admin/projects/:id/assignments
+func (h *ProjectAssignmentHandler) ListAssignees(c *gin.Context) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		response.Forbidden(c, "只有管理员可以查看项目指派")
+		return
+	}
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的项目ID")
+		return
+	}
+
+	assignees, err := h.Service.ListAssignees(uint(projectID))
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	accepted := 0
+	for _, assignee := range assignees {
+		if assignee.Accepted {
+			accepted++
+		}
+	}
+
+	response.Success(c, gin.H{
+		"list":     assignees,
+		"total":    len(assignees),
+		"accepted": accepted,
+	})
+}
+
+// AssignUsers 批量指派用户到项目（仅admin）
+// POST /api/v1/admin/projects/:id/assignments
+func (h *ProjectAssignmentHandler) AssignUsers(c *gin.Context) {
+	projectID, target, ok := bindAssignmentRequest(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.Service.AssignUsers(projectID, target)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "指派成功", result)
+}
+
+// UnassignUsers 批量取消用户的项目指派（仅admin）
+// DELETE /api/v1/admin/projects/:id/assignments
+func (h *ProjectAssignmentHandler) UnassignUsers(c *gin.Context) {
+	projectID, target, ok := bindAssignmentRequest(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.Service.UnassignUsers(projectID, target)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "已取消指派", result)
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/response"
+	"bug-bounty-lite/pkg/upload"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProjectAttachmentHandler 项目附件处理器
+type ProjectAttachmentHandler struct {
+	Service  domain.ProjectAttachmentService
+	Uploader *upload.Uploader
+	Signer   *upload.URLSigner
+}
+
+// NewProjectAttachmentHandler 创建项目附件处理器实例
+func NewProjectAttachmentHandler(s domain.ProjectAttachmentService, uploader *upload.Uploader, signer *upload.URLSigner) *ProjectAttachmentHandler {
+	return &ProjectAttachmentHandler{Service: s, Uploader: uploader, Signer: signer}
+}
+
+// signAttachments 为附件填充签名下载地址
+func signAttachments(signer *upload.URLSigner, attachments []domain.ProjectAttachment) {
+	for i := range attachments {
+		attachments[i].DownloadURL = signer.SignURL(attachments[i].URL)
+	}
+}
+
+// ReorderAttachmentsRequest 附件排序请求 DTO
+type ReorderAttachmentsRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1"` // 按新顺序排列的全部附件ID
+}
+
+// ListAttachments 获取项目附件列表（白帽子需被指派到项目）
+// GET /api/v1/projects/:id/attachments
+func (h *ProjectAttachmentHandler) ListAttachments(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的项目ID")
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+
+	attachments, err := h.Service.ListAttachments(uint(projectID), userID.(uint), userRole)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	signAttachments(h.Signer, attachments)
+
+	response.Success(c, gin.H{
+		"list":  attachments,
+		"total": len(attachments),
+	})
+}
+
+// UploadAttachment 上传项目附件（仅admin/vendor）
+// POST /api/v1/projects/:id/attachments (multipart/form-data: file, name)
+func (h *ProjectAttachmentHandler) UploadAttachment(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+	if userRole != "admin" && userRole != "vendor" {
+		response.Forbidden(c, "只有管理员和厂商可以上传项目附件")
+		return
+	}
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的项目ID")
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "请选择要上传的文件")
+		return
+	}
+
+	// 获取 base URL（用于生成文件访问 URL）
+	scheme := "http"
+	if c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	baseURL := fmt.Sprintf("%s://%s", scheme, c.Request.Host)
+
+	result, err := h.Uploader.UploadFileTo(c.Request.Context(), file, baseURL, upload.ProjectAttachmentDir)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	// 附件名称默认使用原始文件名
+	name := strings.TrimSpace(c.PostForm("name"))
+	if name == "" {
+		name = result.Filename
+	}
+
+	attachment := &domain.ProjectAttachment{
+		ProjectID:  uint(projectID),
+		Name:       name,
+		URL:        result.URL,
+		Size:       upload.FormatSize(result.Size),
+		Type:       upload.FileTypeFromMime(result.MimeType),
+		FileSize:   result.Size,
+		MimeType:   result.MimeType,
+		SHA256:     result.SHA256,
+		UploadedBy: userID.(uint),
+	}
+	if err := h.Service.AddAttachment(attachment, userRole); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	attachment.DownloadURL = h.Signer.SignURL(attachment.URL)
+
+	response.SuccessWithMessage(c, "附件上传成功", attachment)
+}
+
+// ReorderAttachments 调整项目附件顺序（仅admin/vendor）
+// PUT /api/v1/projects/:id/attachments/order
+func (h *ProjectAttachmentHandler) ReorderAttachments(c *gin.Context) {
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+	if userRole != "admin" && userRole != "vendor" {
+		response.Forbidden(c, "只有管理员和厂商可以调整附件顺序")
+		return
+	}
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的项目ID")
+		return
+	}
+
+	var req ReorderAttachmentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	attachments, err := h.Service.ReorderAttachments(uint(projectID), req.IDs, userRole)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	signAttachments(h.Signer, attachments)
+
+	response.Success(c, gin.H{
+		"list":  attachments,
+		"total": len(attachments),
+	})
+}
+
+// DeleteAttachment 删除项目附件（仅admin/vendor）
+// DELETE /api/v1/projects/:id/attachments/:attachmentId
+func (h *ProjectAttachmentHandler) DeleteAttachment(c *gin.Context) {
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+	if userRole != "admin" && userRole != "vendor" {
+		response.Forbidden(c, "只有管理员和厂商可以删除项目附件")
+		return
+	}
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的项目ID")
+		return
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachmentId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的附件ID")
+		return
+	}
+
+	// 文件按内容寻址，可能被其他附件共享，这里只删除记录
+	if _, err := h.Service.DeleteAttachment(uint(projectID), uint(attachmentID), userRole); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "附件已删除", nil)
+}
@@ -30,6 +30,21 @@ type UpdateProjectRequest struct {
 	Description string `json:"description"`
 	Note        string `json:"note"`
 	Status      string `json:"status" binding:"omitempty,oneof=active inactive"`
+	// 范围校验模式
+	ScopeEnforcement string `json:"scope_enforcement" binding:"omitempty,oneof=off warn reject"`
+}
+
+// UpdateRewardTableRequest 更新项目奖励表请求 DTO
+type UpdateRewardTableRequest struct {
+	Tiers []RewardTierRequest `json:"tiers" binding:"dive"`
+}
+
+// RewardTierRequest 奖励表中某个危害等级的奖金范围
+type RewardTierRequest struct {
+	SeverityLevelID uint   `json:"severity_level_id" binding:"required"`
+	MinAmount       int64  `json:"min_amount" binding:"min=0"`
+	MaxAmount       int64  `json:"max_amount" binding:"min=0"`
+	Currency        string `json:"currency" binding:"omitempty,len=3"`
 }
 
 // CreateHandler 创建项目
@@ -137,10 +152,11 @@ func (h *ProjectHandler) UpdateHandler(c *gin.Context) {
 
 	// 调用 Service 更新
 	project, err := h.Service.UpdateProject(uint(id), &domain.ProjectUpdateInput{
-		Name:        req.Name,
-		Description: req.Description,
-		Note:        req.Note,
-		Status:      req.Status,
+		Name:             req.Name,
+		Description:      req.Description,
+		Note:             req.Note,
+		Status:           req.Status,
+		ScopeEnforcement: req.ScopeEnforcement,
 	})
 
 	if err != nil {
@@ -200,3 +216,45 @@ func (h *ProjectHandler) RestoreHandler(c *gin.Context) {
 
 	response.SuccessWithMessage(c, "项目恢复成功", nil)
 }
+
+// UpdateRewardTableHandler 更新项目奖励表
+// PUT /api/v1/projects/:id/reward-table
+func (h *ProjectHandler) UpdateRewardTableHandler(c *gin.Context) {
+	// 权限检查：admin 和 vendor 可以维护奖励表
+	role, exists := c.Get("role")
+	if !exists || (role.(string) != "admin" && role.(string) != "vendor") {
+		response.Error(c, http.StatusForbidden, "只有管理员和厂商可以修改奖励表")
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "无效的项目ID")
+		return
+	}
+
+	var req UpdateRewardTableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "请求参数错误: "+err.Error())
+		return
+	}
+
+	tiers := make([]domain.ProjectRewardTier, 0, len(req.Tiers))
+	for _, t := range req.Tiers {
+		tiers = append(tiers, domain.ProjectRewardTier{
+			SeverityLevelID: t.SeverityLevelID,
+			MinAmount:       t.MinAmount,
+			MaxAmount:       t.MaxAmount,
+			Currency:        t.Currency,
+		})
+	}
+
+	project, err := h.Service.UpdateRewardTable(uint(id), tiers)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(c, project)
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProjectScopeHandler 项目范围处理器
+type ProjectScopeHandler struct {
+	Service domain.ProjectScopeService
+}
+
+// NewProjectScopeHandler 创建项目范围处理器实例
+func NewProjectScopeHandler(s domain.ProjectScopeService) *ProjectScopeHandler {
+	return &ProjectScopeHandler{Service: s}
+}
+
+// ProjectScopeRequest 新增/更新范围资产请求 DTO
+type ProjectScopeRequest struct {
+	AssetType      string `json:"asset_type" binding:"required,oneof=domain wildcard url_prefix ip_cidr mobile_app source_repo"`
+	Identifier     string `json:"identifier" binding:"required,max=500"`
+	Description    string `json:"description"`
+	InScope        *bool  `json:"in_scope"`
+	BountyEligible *bool  `json:"bounty_eligible"`
+	MaxSeverity    string `json:"max_severity" binding:"omitempty,oneof=Critical High Medium Low"`
+}
+
+// CheckScopeRequest 校验链接请求 DTO
+type CheckScopeRequest struct {
+	URL string `json:"url" binding:"required"`
+}
+
+// toScope 将请求转换为范围资产，in_scope 和 bounty_eligible 未传时默认为 true
+func (req *ProjectScopeRequest) toScope(projectID uint) *domain.ProjectScope {
+	scope := &domain.ProjectScope{
+		ProjectID:      projectID,
+		AssetType:      req.AssetType,
+		Identifier:     req.Identifier,
+		Description:    req.Description,
+		InScope:        true,
+		BountyEligible: true,
+		MaxSeverity:    req.MaxSeverity,
+	}
+	if req.InScope != nil {
+		scope.InScope = *req.InScope
+	}
+	if req.BountyEligible != nil {
+		scope.BountyEligible = *req.BountyEligible
+	}
+	return scope
+}
+
+// canManageScope 只有管理员和厂商可以维护项目范围
+func canManageScope(c *gin.Context) bool {
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+	return userRole == "admin" || userRole == "vendor"
+}
+
+// ListScopes 获取项目范围资产列表
+// GET /api/v1/projects/:id/scopes
+func (h *ProjectScopeHandler) ListScopes(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的项目ID")
+		return
+	}
+
+	scopes, err := h.Service.ListScopes(uint(projectID))
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  scopes,
+		"total": len(scopes),
+	})
+}
+
+// CreateScope 新增范围资产（仅admin/vendor）
+// POST /api/v1/projects/:id/scopes
+func (h *ProjectScopeHandler) CreateScope(c *gin.Context) {
+	if !canManageScope(c) {
+		response.Forbidden(c, "只有管理员和厂商可以维护项目范围")
+		return
+	}
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的项目ID")
+		return
+	}
+
+	var req ProjectScopeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	scope := req.toScope(uint(projectID))
+	if err := h.Service.CreateScope(scope); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "范围资产已添加", scope)
+}
+
+// UpdateScope 更新范围资产（仅admin/vendor）
+// PUT /api/v1/projects/:id/scopes/:scopeId
+func (h *ProjectScopeHandler) UpdateScope(c *gin.Context) {
+	if !canManageScope(c) {
+		response.Forbidden(c, "只有管理员和厂商可以维护项目范围")
+		return
+	}
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的项目ID")
+		return
+	}
+	scopeID, err := strconv.ParseUint(c.Param("scopeId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的资产ID")
+		return
+	}
+
+	var req ProjectScopeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	scope := req.toScope(uint(projectID))
+	scope.ID = uint(scopeID)
+	updated, err := h.Service.UpdateScope(uint(projectID), scope)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "范围资产已更新", updated)
+}
+
+// DeleteScope 删除范围资产（仅admin/vendor）
+// DELETE /api/v1/projects/:id/scopes/:scopeId
+func (h *ProjectScopeHandler) DeleteScope(c *gin.Context) {
+	if !canManageScope(c) {
+		response.Forbidden(c, "只有管理员和厂商可以维护项目范围")
+		return
+	}
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的项目ID")
+		return
+	}
+	scopeID, err := strconv.ParseUint(c.Param("scopeId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的资产ID")
+		return
+	}
+
+	if err := h.Service.DeleteScope(uint(projectID), uint(scopeID)); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "范围资产已删除", nil)
+}
+
+// CheckURL 校验链接是否在项目范围内，供提交报告前预检
+// POST /api/v1/projects/:id/scopes/check
+func (h *ProjectScopeHandler) CheckURL(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的项目ID")
+		return
+	}
+
+	var req CheckScopeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.Service.CheckURL(uint(projectID), req.URL)
+	if err != nil {
+		response.InternalError(c, "范围校验失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"bug-bounty-lite/internal/domain"
 	"bug-bounty-lite/pkg/response"
+	"bug-bounty-lite/pkg/upload"
 	"net/http"
 	"strconv"
 
@@ -16,6 +17,7 @@ type ProjectTaskHandler struct {
 	AssignmentRepo domain.ProjectAssignmentRepository
 	ProjectRepo    domain.ProjectRepository
 	AttachmentRepo domain.ProjectAttachmentRepository
+	Signer         *upload.URLSigner
 }
 
 // NewProjectTaskHandler 创建项目任务处理器
@@ -25,6 +27,7 @@ func NewProjectTaskHandler(
 	assignmentRepo domain.ProjectAssignmentRepository,
 	projectRepo domain.ProjectRepository,
 	attachmentRepo domain.ProjectAttachmentRepository,
+	signer *upload.URLSigner,
 ) *ProjectTaskHandler {
 	return &ProjectTaskHandler{
 		TaskService:    taskService,
@@ -32,6 +35,7 @@ func NewProjectTaskHandler(
 		AssignmentRepo: assignmentRepo,
 		ProjectRepo:    projectRepo,
 		AttachmentRepo: attachmentRepo,
+		Signer:         signer,
 	}
 }
 
@@ -149,17 +153,19 @@ func (h *ProjectTaskHandler) GetProjectDetail(c *gin.Context) {
 
 	// 获取项目附件
 	attachments, _ := h.AttachmentRepo.FindByProjectID(uint(projectID))
+	signAttachments(h.Signer, attachments)
 
 	response.Success(c, gin.H{
-		"id":          project.ID,
-		"name":        project.Name,
-		"description": project.Description,
-		"difficulty":  project.Difficulty,
-		"deadline":    deadlineStr,
-		"status":      project.Status,
-		"created_at":  project.CreatedAt,
-		"accepted":    accepted,
-		"attachments": attachments,
+		"id":           project.ID,
+		"name":         project.Name,
+		"description":  project.Description,
+		"difficulty":   project.Difficulty,
+		"deadline":     deadlineStr,
+		"status":       project.Status,
+		"created_at":   project.CreatedAt,
+		"accepted":     accepted,
+		"attachments":  attachments,
+		"reward_table": project.RewardTable,
 	})
 }
 
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/response"
+	"bug-bounty-lite/pkg/upload"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportAttachmentHandler 报告附件处理器
+type ReportAttachmentHandler struct {
+	Service        domain.ReportAttachmentService
+	UploadSessions domain.UploadSessionService
+	Scanner        domain.AttachmentScanService
+	Uploader       *upload.Uploader
+	Signer         *upload.URLSigner
+}
+
+// NewReportAttachmentHandler 创建报告附件处理器实例
+func NewReportAttachmentHandler(
+	s domain.ReportAttachmentService,
+	uploadSessions domain.UploadSessionService,
+	scanner domain.AttachmentScanService,
+	uploader *upload.Uploader,
+	signer *upload.URLSigner,
+) *ReportAttachmentHandler {
+	return &ReportAttachmentHandler{Service: s, UploadSessions: uploadSessions, Scanner: scanner, Uploader: uploader, Signer: signer}
+}
+
+// AttachUploadRequest 使用分片上传完成的文件作为附件
+type AttachUploadRequest struct {
+	UploadID string `json:"upload_id" form:"upload_id"`
+}
+
+// signReportAttachments 为通过扫描的报告附件填充签名下载地址
+func signReportAttachments(signer *upload.URLSigner, attachments []domain.ReportAttachment) {
+	for i := range attachments {
+		if attachments[i].ScanStatus != domain.ScanStatusClean {
+			continue
+		}
+		attachments[i].DownloadURL = signer.SignURL(attachments[i].URL)
+	}
+}
+
+// ListAttachments 获取报告附件列表（报告作者/admin/vendor）
+// GET /api/v1/reports/:id/attachments
+func (h *ReportAttachmentHandler) ListAttachments(c *gin.Context) {
+	reportID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的报告ID")
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+
+	attachments, err := h.Service.ListAttachments(uint(reportID), userID.(uint), userRole)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	signReportAttachments(h.Signer, attachments)
+
+	response.Success(c, gin.H{
+		"list":  attachments,
+		"total": len(attachments),
+	})
+}
+
+// UploadAttachment 上传报告附件（报告作者/admin/vendor）
+// POST /api/v1/reports/:id/attachments (multipart/form-data: file，或 upload_id 引用已完成的分片上传)
+func (h *ReportAttachmentHandler) UploadAttachment(c *gin.Context) {
+	reportID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的报告ID")
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+
+	// 获取 base URL（用于生成文件访问 URL）
+	scheme := "http"
+	if c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	baseURL := fmt.Sprintf("%s://%s", scheme, c.Request.Host)
+
+	// 先检查报告权限和附件数量，再写入和扫描文件
+	if err := h.Service.CheckCanAttach(uint(reportID), userID.(uint), userRole); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	var req AttachUploadRequest
+	_ = c.ShouldBind(&req)
+
+	var result *upload.UploadResult
+	if req.UploadID != "" {
+		// 大文件通过分片上传完成后再关联到报告
+		session, err := h.UploadSessions.GetSession(req.UploadID, userID.(uint))
+		if err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		if session.Status != domain.UploadSessionCompleted {
+			response.BadRequest(c, "分片上传尚未完成")
+			return
+		}
+		result = uploadResultFromSession(session, baseURL)
+	} else {
+		file, err := c.FormFile("file")
+		if err != nil {
+			response.BadRequest(c, "请选择要上传的文件")
+			return
+		}
+		result, err = h.Uploader.UploadFile(c.Request.Context(), file, baseURL)
+		if err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	// 关联到报告前扫描文件，发现恶意内容时文件已被隔离，拒绝添加
+	filePath, _ := upload.FilePathFromURL(result.URL)
+	scanStatus, signature, err := h.Scanner.ScanFile(c.Request.Context(), filePath)
+	if err != nil {
+		response.BadRequest(c, "文件扫描失败: "+err.Error())
+		return
+	}
+	if scanStatus == domain.ScanStatusInfected {
+		response.BadRequest(c, fmt.Sprintf("文件未通过安全扫描（%s），已被隔离", signature))
+		return
+	}
+
+	attachment := &domain.ReportAttachment{
+		ReportID:   uint(reportID),
+		Filename:   result.Filename,
+		URL:        result.URL,
+		FileSize:   result.Size,
+		MimeType:   result.MimeType,
+		SHA256:     result.SHA256,
+		UploadedBy: userID.(uint),
+		ScanStatus: scanStatus,
+	}
+	if scanStatus == domain.ScanStatusClean {
+		now := time.Now()
+		attachment.ScannedAt = &now
+	}
+	if err := h.Service.AddAttachment(attachment, userRole); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	if attachment.ScanStatus == domain.ScanStatusClean {
+		attachment.DownloadURL = h.Signer.SignURL(attachment.URL)
+	}
+
+	response.SuccessWithMessage(c, "附件上传成功", attachment)
+}
+
+// DeleteAttachment 删除报告附件（报告作者/admin/vendor）
+// DELETE /api/v1/reports/:id/attachments/:attachmentId
+func (h *ReportAttachmentHandler) DeleteAttachment(c *gin.Context) {
+	reportID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的报告ID")
+		return
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachmentId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的附件ID")
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+
+	// 文件按内容寻址，可能被其他附件共享，这里只删除记录
+	if _, err := h.Service.DeleteAttachment(uint(reportID), uint(attachmentID), userID.(uint), userRole); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "附件已删除", nil)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"bug-bounty-lite/internal/domain"
 	"bug-bounty-lite/pkg/response"
+	"bug-bounty-lite/pkg/upload"
 	"net/http"
 	"strconv"
 
@@ -11,10 +12,11 @@ import (
 
 type ReportHandler struct {
 	Service domain.ReportService
+	Signer  *upload.URLSigner
 }
 
-func NewReportHandler(s domain.ReportService) *ReportHandler {
-	return &ReportHandler{Service: s}
+func NewReportHandler(s domain.ReportService, signer *upload.URLSigner) *ReportHandler {
+	return &ReportHandler{Service: s, Signer: signer}
 }
 
 // CreateReportRequest 创建报告请求 DTO
@@ -26,7 +28,7 @@ type CreateReportRequest struct {
 	SelfAssessmentID    *uint  `json:"self_assessment_id"`
 	VulnerabilityURL    string `json:"vulnerability_url" binding:"omitempty,url"`
 	VulnerabilityDetail string `json:"vulnerability_detail"`
-	AttachmentURL       string `json:"attachment_url" binding:"omitempty,url"`
+	CVSSVector          string `json:"cvss_vector" binding:"omitempty,max=255"`
 	Severity            string `json:"severity" binding:"omitempty,oneof=Low Medium High Critical"`
 }
 
@@ -39,9 +41,10 @@ type UpdateReportRequest struct {
 	SelfAssessmentID    *uint  `json:"self_assessment_id"`
 	VulnerabilityURL    string `json:"vulnerability_url" binding:"omitempty,url"`
 	VulnerabilityDetail string `json:"vulnerability_detail"`
-	AttachmentURL       string `json:"attachment_url" binding:"omitempty,url"`
-	Severity            string `json:"severity" binding:"omitempty,oneof=Low Medium High Critical"`
-	Status              string `json:"status" binding:"omitempty,oneof=Pending Triaged Resolved Closed"`
+	CVSSVector          string `json:"cvss_vector" binding:"omitempty,max=255"` // 服务端解析并计算分数
+	Severity            string `json:"severity" binding:"omitempty,oneof=Low Medium High Critical None"`
+	Status              string `json:"status" binding:"omitempty,max=20"`  // 可选状态由项目工作流决定
+	Reason              string `json:"reason" binding:"omitempty,max=500"` // 状态流转原因
 }
 
 // CreateHandler 提交漏洞
@@ -68,7 +71,7 @@ func (h *ReportHandler) CreateHandler(c *gin.Context) {
 		SelfAssessmentID:    req.SelfAssessmentID,
 		VulnerabilityURL:    req.VulnerabilityURL,
 		VulnerabilityDetail: req.VulnerabilityDetail,
-		AttachmentURL:       req.AttachmentURL,
+		CVSSVector:          req.CVSSVector,
 		Severity:            req.Severity,
 		AuthorID:            userID.(uint),
 	}
@@ -81,6 +84,10 @@ func (h *ReportHandler) CreateHandler(c *gin.Context) {
 	}
 
 	// 成功时只返回提示语，不返回报告数据（统一使用 200 状态码）
+	if report.ScopeStatus == domain.ReportScopeOutOfScope {
+		response.SuccessWithMessage(c, "漏洞报告提交成功，但漏洞链接不在项目范围内，可能不会被受理", nil)
+		return
+	}
 	response.SuccessWithMessage(c, "漏洞报告提交成功", nil)
 }
 
@@ -136,6 +143,7 @@ func (h *ReportHandler) GetHandler(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
 		return
 	}
+	signReportAttachments(h.Signer, report.Attachments)
 	c.JSON(http.StatusOK, gin.H{"data": report})
 }
 
@@ -167,9 +175,10 @@ func (h *ReportHandler) UpdateHandler(c *gin.Context) {
 		SelfAssessmentID:    req.SelfAssessmentID,
 		VulnerabilityURL:    req.VulnerabilityURL,
 		VulnerabilityDetail: req.VulnerabilityDetail,
-		AttachmentURL:       req.AttachmentURL,
+		CVSSVector:          req.CVSSVector,
 		Severity:            req.Severity,
 		Status:              req.Status,
+		Reason:              req.Reason,
 	})
 
 	if err != nil {
@@ -239,3 +248,69 @@ func (h *ReportHandler) RestoreHandler(c *gin.Context) {
 
 	response.SuccessWithMessage(c, "报告恢复成功", nil)
 }
+
+// MarkDuplicateRequest 标记重复请求 DTO
+type MarkDuplicateRequest struct {
+	OriginalID uint   `json:"original_id" binding:"required"`
+	Reason     string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// ListDuplicatesHandler 获取报告的疑似重复列表（仅admin/vendor）
+// GET /api/v1/reports/:id/duplicates
+func (h *ReportHandler) ListDuplicatesHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的报告ID")
+		return
+	}
+
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+	if userRole != "admin" && userRole != "vendor" {
+		response.Forbidden(c, "只有管理员和厂商可以查看疑似重复报告")
+		return
+	}
+
+	similarities, err := h.Service.ListDuplicateCandidates(uint(id), userRole)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  similarities,
+		"total": len(similarities),
+	})
+}
+
+// MarkDuplicateHandler 将报告标记为另一份报告的重复（仅admin/vendor）
+// POST /api/v1/reports/:id/duplicate
+func (h *ReportHandler) MarkDuplicateHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的报告ID")
+		return
+	}
+
+	var req MarkDuplicateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+	if userRole != "admin" && userRole != "vendor" {
+		response.Forbidden(c, "只有管理员和厂商可以标记重复报告")
+		return
+	}
+
+	report, err := h.Service.MarkDuplicate(uint(id), req.OriginalID, userID.(uint), userRole, req.Reason)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "已标记为重复报告", report)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportTimelineHandler 报告时间线处理器
+type ReportTimelineHandler struct {
+	Service domain.ReportTimelineService
+}
+
+// NewReportTimelineHandler 创建报告时间线处理器实例
+func NewReportTimelineHandler(s domain.ReportTimelineService) *ReportTimelineHandler {
+	return &ReportTimelineHandler{Service: s}
+}
+
+// GetTimeline 获取报告时间线（状态变更、字段修改与评论按时间合并）
+// GET /api/v1/reports/:id/timeline
+func (h *ReportTimelineHandler) GetTimeline(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的报告ID")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+	role, _ := c.Get("role")
+	userRole := "whitehat"
+	if role != nil {
+		userRole = role.(string)
+	}
+
+	items, err := h.Service.GetTimeline(uint(id), userID.(uint), userRole)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  items,
+		"total": len(items),
+	})
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportWorkflowHandler 报告工作流处理器
+type ReportWorkflowHandler struct {
+	Service       domain.ReportWorkflowService
+	ReportService domain.ReportService
+}
+
+// NewReportWorkflowHandler 创建工作流处理器实例
+func NewReportWorkflowHandler(s domain.ReportWorkflowService, reportService domain.ReportService) *ReportWorkflowHandler {
+	return &ReportWorkflowHandler{Service: s, ReportService: reportService}
+}
+
+// SaveWorkflowRequest 保存工作流请求 DTO
+type SaveWorkflowRequest struct {
+	InitialStatus string                      `json:"initial_status" binding:"required,max=20"`
+	States        []domain.WorkflowState      `json:"states" binding:"required,min=1"`
+	Transitions   []domain.WorkflowTransition `json:"transitions"`
+}
+
+// GetProjectWorkflow 获取项目生效的工作流
+// GET /api/v1/projects/:id/workflow
+func (h *ReportWorkflowHandler) GetProjectWorkflow(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的项目ID")
+		return
+	}
+
+	workflow, err := h.Service.GetWorkflow(uint(id))
+	if err != nil {
+		response.InternalError(c, "获取工作流失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, workflow)
+}
+
+// SaveProjectWorkflow 保存项目自定义工作流（仅admin）
+// PUT /api/v1/projects/:id/workflow
+func (h *ReportWorkflowHandler) SaveProjectWorkflow(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "无效的项目ID")
+		return
+	}
+
+	h.saveWorkflow(c, uint(id))
+}
+
+// ResetProjectWorkflow 删除项目自定义工作流，恢复为全局工作流（仅admin）
+// DELETE /api/v1/projects/:id/workflow
+func (h *ReportWorkflowHandler) ResetProjectWorkflow(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "无效的项目ID")
+		return
+	}
+
+	h.resetWorkflow(c, uint(id))
+}
+
+// GetGlobalWorkflow 获取全局默认工作流
+// GET /api/v1/admin/report-workflow
+func (h *ReportWorkflowHandler) GetGlobalWorkflow(c *gin.Context) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		response.Forbidden(c, "只有管理员可以查看全局工作流")
+		return
+	}
+
+	workflow, err := h.Service.GetWorkflow(0)
+	if err != nil {
+		response.InternalError(c, "获取工作流失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, workflow)
+}
+
+// SaveGlobalWorkflow 保存全局默认工作流（仅admin）
+// PUT /api/v1/admin/report-workflow
+func (h *ReportWorkflowHandler) SaveGlobalWorkflow(c *gin.Context) {
+	h.saveWorkflow(c, 0)
+}
+
+// ResetGlobalWorkflow 删除全局工作流配置，恢复为内置默认工作流（仅admin）
+// DELETE /api/v1/admin/report-workflow
+func (h *ReportWorkflowHandler) ResetGlobalWorkflow(c *gin.Context) {
+	h.resetWorkflow(c, 0)
+}
+
+// ListReportTransitions 获取当前用户对报告可执行的状态流转
+// GET /api/v1/reports/:id/transitions
+func (h *ReportWorkflowHandler) ListReportTransitions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的报告ID")
+		return
+	}
+
+	report, err := h.ReportService.GetReport(uint(id))
+	if err != nil {
+		response.NotFound(c, "报告不存在")
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+
+	// 白帽子只能查看自己报告的流转
+	if userRole == "whitehat" && report.AuthorID != userID.(uint) {
+		response.Forbidden(c, "无权查看此报告")
+		return
+	}
+
+	transitions, err := h.Service.AvailableTransitions(report, userRole)
+	if err != nil {
+		response.InternalError(c, "获取可用流转失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"status": report.Status,
+		"list":   transitions,
+		"total":  len(transitions),
+	})
+}
+
+// saveWorkflow 保存工作流的公共逻辑
+func (h *ReportWorkflowHandler) saveWorkflow(c *gin.Context, projectID uint) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		response.Forbidden(c, "只有管理员可以修改工作流")
+		return
+	}
+
+	var req SaveWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	workflow, err := h.Service.SaveWorkflow(projectID, &domain.ReportWorkflow{
+		InitialStatus: req.InitialStatus,
+		States:        req.States,
+		Transitions:   req.Transitions,
+	})
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, workflow)
+}
+
+// resetWorkflow 重置工作流的公共逻辑
+func (h *ReportWorkflowHandler) resetWorkflow(c *gin.Context, projectID uint) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		response.Forbidden(c, "只有管理员可以修改工作流")
+		return
+	}
+
+	if err := h.Service.ResetWorkflow(projectID); err != nil {
+		response.InternalError(c, "重置工作流失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "工作流已重置", nil)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityHandler 登录锁定与安全事件处理器
+type SecurityHandler struct {
+	Service domain.LoginGuardService
+}
+
+// NewSecurityHandler 创建安全处理器实例
+func NewSecurityHandler(s domain.LoginGuardService) *SecurityHandler {
+	return &SecurityHandler{Service: s}
+}
+
+// unlockRequest 解除登录锁定请求体（用户名和 IP 至少填写一个）
+type unlockRequest struct {
+	Username string `json:"username"`
+	IP       string `json:"ip"`
+}
+
+// Unlock [POST] /api/v1/admin/security/unlock - 管理员解除用户名和/或 IP 的登录锁定
+func (h *SecurityHandler) Unlock(c *gin.Context) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有管理员可以解除登录锁定"})
+		return
+	}
+
+	var req unlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.Service.Unlock(req.Username, req.IP, userID.(uint)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已解除登录锁定"})
+}
+
+// ListEvents [GET] /api/v1/admin/security/events - 管理员查询安全事件（支持 type/username/ip 筛选）
+func (h *SecurityHandler) ListEvents(c *gin.Context) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有管理员可以查看安全事件"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	filter := domain.SecurityEventFilter{
+		Type:     c.Query("type"),
+		Username: c.Query("username"),
+		IP:       c.Query("ip"),
+	}
+
+	events, total, err := h.Service.ListEvents(filter, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list security events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"list":      events,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
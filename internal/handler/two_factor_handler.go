@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler 两步验证处理器
+type TwoFactorHandler struct {
+	Service domain.TwoFactorService
+}
+
+// NewTwoFactorHandler 创建两步验证处理器实例
+func NewTwoFactorHandler(s domain.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{Service: s}
+}
+
+// challengeRequest 登录第二步请求体（code 可以是 6 位验证码或恢复码）
+type challengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code"`
+}
+
+// codeRequest 验证码请求体
+type codeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// disableTwoFactorRequest 关闭两步验证请求体
+type disableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// twoFactorPolicyRequest 两步验证策略请求体
+type twoFactorPolicyRequest struct {
+	RequiredRoles []string `json:"required_roles"`
+}
+
+// VerifyLogin [POST] /api/v1/auth/login/2fa - 登录第二步：提交验证码或恢复码换取令牌
+func (h *TwoFactorHandler) VerifyLogin(c *gin.Context) {
+	var req challengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "challenge_token 和 code 不能为空"})
+		return
+	}
+
+	client := domain.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+	user, tokens, err := h.Service.VerifyLogin(req.ChallengeToken, req.Code, client)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "Login successful",
+		"token":              tokens.AccessToken,
+		"refresh_token":      tokens.RefreshToken,
+		"token_type":         tokens.TokenType,
+		"expires_in":         tokens.ExpiresIn,
+		"refresh_expires_in": tokens.RefreshExpiresIn,
+		"user":               user,
+	})
+}
+
+// SetupWithChallenge [POST] /api/v1/auth/2fa/setup - 角色要求两步验证但尚未绑定时，使用挑战令牌获取密钥
+func (h *TwoFactorHandler) SetupWithChallenge(c *gin.Context) {
+	var req challengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	setup, err := h.Service.SetupWithChallenge(req.ChallengeToken)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": setup,
+	})
+}
+
+// EnableWithChallenge [POST] /api/v1/auth/2fa/enable - 使用挑战令牌确认绑定，返回恢复码并完成登录
+func (h *TwoFactorHandler) EnableWithChallenge(c *gin.Context) {
+	var req challengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "challenge_token 和 code 不能为空"})
+		return
+	}
+
+	client := domain.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+	user, tokens, codes, err := h.Service.EnableWithChallenge(req.ChallengeToken, req.Code, client)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "Two-factor authentication enabled",
+		"recovery_codes":     codes,
+		"token":              tokens.AccessToken,
+		"refresh_token":      tokens.RefreshToken,
+		"token_type":         tokens.TokenType,
+		"expires_in":         tokens.ExpiresIn,
+		"refresh_expires_in": tokens.RefreshExpiresIn,
+		"user":               user,
+	})
+}
+
+// GetStatus [GET] /api/v1/user/2fa - 获取当前用户的两步验证状态
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	status, err := h.Service.GetStatus(userID.(uint), role.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get two-factor status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": status,
+	})
+}
+
+// Setup [POST] /api/v1/user/2fa/setup - 生成 TOTP 密钥和二维码地址（确认前不生效）
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	setup, err := h.Service.Setup(userID.(uint))
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": setup,
+	})
+}
+
+// Enable [POST] /api/v1/user/2fa/enable - 提交验证码确认绑定，返回一次性展示的恢复码
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	var req codeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	codes, err := h.Service.Enable(userID.(uint), req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "两步验证已启用，请妥善保存恢复码",
+		"recovery_codes": codes,
+	})
+}
+
+// Disable [POST] /api/v1/user/2fa/disable - 校验密码和验证码后关闭两步验证
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req disableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	if err := h.Service.Disable(userID.(uint), role.(string), req.Password, req.Code); err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "两步验证已关闭"})
+}
+
+// RegenerateRecoveryCodes [POST] /api/v1/user/2fa/recovery-codes - 重新生成恢复码（旧恢复码全部失效）
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req codeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	codes, err := h.Service.RegenerateRecoveryCodes(userID.(uint), req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "恢复码已重新生成，旧恢复码已失效",
+		"recovery_codes": codes,
+	})
+}
+
+// GetPolicy [GET] /api/v1/admin/security/2fa-policy - 获取必须启用两步验证的角色
+func (h *TwoFactorHandler) GetPolicy(c *gin.Context) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有管理员可以查看两步验证策略"})
+		return
+	}
+
+	roles, err := h.Service.GetRequiredRoles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{"required_roles": roles},
+	})
+}
+
+// UpdatePolicy [PUT] /api/v1/admin/security/2fa-policy - 设置必须启用两步验证的角色
+func (h *TwoFactorHandler) UpdatePolicy(c *gin.Context) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有管理员可以修改两步验证策略"})
+		return
+	}
+
+	var req twoFactorPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	roles, err := h.Service.SetRequiredRoles(req.RequiredRoles, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "两步验证策略已更新",
+		"data":    gin.H{"required_roles": roles},
+	})
+}
+
+// AdminReset [DELETE] /api/v1/admin/users/:id/2fa - 管理员重置指定用户的两步验证
+func (h *TwoFactorHandler) AdminReset(c *gin.Context) {
+	role, exists := c.Get("role")
+	if !exists || role.(string) != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有管理员可以重置两步验证"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户ID"})
+		return
+	}
+
+	if err := h.Service.Reset(uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已重置该用户的两步验证"})
+}
+
+// respondTwoFactorError 将两步验证错误映射为 HTTP 状态码
+func respondTwoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrLoginChallengeInvalid),
+		errors.Is(err, domain.ErrLoginChallengeTooManyAttempts),
+		errors.Is(err, domain.ErrTwoFactorCodeInvalid):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrTwoFactorRequiredByRole):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrTwoFactorNotEnabled),
+		errors.Is(err, domain.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, domain.ErrTwoFactorSetupRequired):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
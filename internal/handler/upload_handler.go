@@ -1,19 +1,24 @@
 package handler
 
 import (
-	"fmt"
+	"bug-bounty-lite/internal/domain"
 	"bug-bounty-lite/pkg/response"
 	"bug-bounty-lite/pkg/upload"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 // UploadHandler 文件上传处理器
-type UploadHandler struct{}
+type UploadHandler struct {
+	Uploader *upload.Uploader
+	Scanner  domain.AttachmentScanService
+	Signer   *upload.URLSigner
+}
 
-func NewUploadHandler() *UploadHandler {
-	return &UploadHandler{}
+func NewUploadHandler(uploader *upload.Uploader, scanner domain.AttachmentScanService, signer *upload.URLSigner) *UploadHandler {
+	return &UploadHandler{Uploader: uploader, Scanner: scanner, Signer: signer}
 }
 
 // UploadFileHandler 上传单个文件
@@ -34,12 +39,39 @@ func (h *UploadHandler) UploadFileHandler(c *gin.Context) {
 	baseURL := fmt.Sprintf("%s://%s", scheme, c.Request.Host)
 
 	// 上传文件
-	result, err := upload.UploadFile(file, baseURL)
+	result, err := h.Uploader.UploadFile(c.Request.Context(), file, baseURL)
 	if err != nil {
 		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	if !signScannedUpload(c, h.Scanner, h.Signer, result) {
+		return
+	}
 
 	response.Success(c, result)
 }
 
+// signScannedUpload 扫描刚上传的文件，通过扫描后才返回签名下载地址
+// 发现恶意内容时文件已被隔离，返回 400 并返回 false；扫描器暂不可用时保持 pending，不返回签名地址
+func signScannedUpload(c *gin.Context, scanner domain.AttachmentScanService, signer *upload.URLSigner, result *upload.UploadResult) bool {
+	filePath, _ := upload.FilePathFromURL(result.URL)
+	scanStatus, signature, err := scanner.ScanFile(c.Request.Context(), filePath)
+	if err != nil {
+		response.BadRequest(c, "文件扫描失败: "+err.Error())
+		return false
+	}
+	if scanStatus == domain.ScanStatusInfected {
+		response.BadRequest(c, fmt.Sprintf("文件未通过安全扫描（%s），已被隔离", signature))
+		return false
+	}
+
+	result.ScanStatus = scanStatus
+	if scanStatus == domain.ScanStatusClean {
+		result.SignedURL = signer.SignURL(result.URL)
+		if result.ThumbnailURL != "" {
+			result.SignedThumbnailURL = signer.SignURL(result.ThumbnailURL)
+		}
+	}
+	return true
+}
+
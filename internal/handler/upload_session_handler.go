@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/response"
+	"bug-bounty-lite/pkg/upload"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadSessionHandler 大文件分片上传处理器
+type UploadSessionHandler struct {
+	Service domain.UploadSessionService
+	Scanner domain.AttachmentScanService
+	Signer  *upload.URLSigner
+}
+
+// NewUploadSessionHandler 创建分片上传处理器实例
+func NewUploadSessionHandler(s domain.UploadSessionService, scanner domain.AttachmentScanService, signer *upload.URLSigner) *UploadSessionHandler {
+	return &UploadSessionHandler{Service: s, Scanner: scanner, Signer: signer}
+}
+
+// CreateUploadSessionRequest 创建上传会话请求 DTO
+type CreateUploadSessionRequest struct {
+	Filename string `json:"filename" binding:"required,max=255"`
+	Size     int64  `json:"size" binding:"required,min=1"`
+}
+
+// uploadOffsetHeader 分片偏移量请求/响应头
+const uploadOffsetHeader = "Upload-Offset"
+
+// CreateSession 创建上传会话
+// POST /api/v1/upload/sessions
+func (h *UploadSessionHandler) CreateSession(c *gin.Context) {
+	var req CreateUploadSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	userRole, _ := role.(string)
+
+	session, err := h.Service.CreateSession(userID.(uint), userRole, req.Filename, req.Size)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	response.Success(c, session)
+}
+
+// GetSession 查询上传进度（断线后按返回的 offset 继续上传）
+// GET /api/v1/upload/sessions/:id
+func (h *UploadSessionHandler) GetSession(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	session, err := h.Service.GetSession(c.Param("id"), userID.(uint))
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+	c.Header(uploadOffsetHeader, strconv.FormatInt(session.ReceivedSize, 10))
+	response.Success(c, session)
+}
+
+// UploadChunk 上传分片，请求体为分片的原始字节，Upload-Offset 请求头为分片在文件中的起始位置
+// PUT /api/v1/upload/sessions/:id
+func (h *UploadSessionHandler) UploadChunk(c *gin.Context) {
+	offset, err := strconv.ParseInt(c.GetHeader(uploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		response.BadRequest(c, "缺少或无效的 Upload-Offset 请求头")
+		return
+	}
+	if c.Request.ContentLength <= 0 {
+		response.BadRequest(c, "缺少 Content-Length 请求头")
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	session, err := h.Service.WriteChunk(c.Request.Context(), c.Param("id"), userID.(uint), offset, c.Request.Body, c.Request.ContentLength)
+	if err != nil {
+		if errors.Is(err, domain.ErrUploadOffsetMismatch) {
+			// 返回服务端已接收的字节数，客户端从该位置续传
+			c.Header(uploadOffsetHeader, strconv.FormatInt(session.ReceivedSize, 10))
+			c.JSON(http.StatusConflict, response.Response{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+				Data:    gin.H{"offset": session.ReceivedSize},
+			})
+			return
+		}
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	c.Header(uploadOffsetHeader, strconv.FormatInt(session.ReceivedSize, 10))
+	response.Success(c, gin.H{
+		"offset":     session.ReceivedSize,
+		"total_size": session.TotalSize,
+	})
+}
+
+// CompleteSession 合并分片，返回与普通上传相同的上传结果
+// POST /api/v1/upload/sessions/:id/complete
+func (h *UploadSessionHandler) CompleteSession(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	session, err := h.Service.CompleteSession(c.Request.Context(), c.Param("id"), userID.(uint))
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	// 获取 base URL（用于生成文件访问 URL）
+	scheme := "http"
+	if c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	baseURL := fmt.Sprintf("%s://%s", scheme, c.Request.Host)
+
+	result := uploadResultFromSession(session, baseURL)
+	if !signScannedUpload(c, h.Scanner, h.Signer, result) {
+		return
+	}
+	response.Success(c, gin.H{
+		"upload_id": session.ID,
+		"file":      result,
+	})
+}
+
+// AbortSession 取消上传并删除已上传的分片
+// DELETE /api/v1/upload/sessions/:id
+func (h *UploadSessionHandler) AbortSession(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	if err := h.Service.AbortSession(c.Request.Context(), c.Param("id"), userID.(uint)); err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "上传已取消", nil)
+}
+
+// uploadResultFromSession 根据已完成的上传会话生成上传结果
+func uploadResultFromSession(session *domain.UploadSession, baseURL string) *upload.UploadResult {
+	fileURL := strings.TrimSuffix(baseURL, "/") + upload.FileRoutePrefix
+	result := &upload.UploadResult{
+		URL:      fileURL + session.FilePath,
+		Filename: session.Filename,
+		Size:     session.FileSize,
+		MimeType: session.MimeType,
+		SHA256:   session.SHA256,
+	}
+	if result.Size == 0 {
+		result.Size = session.TotalSize
+	}
+	if session.ThumbnailPath != "" {
+		result.ThumbnailURL = fileURL + session.ThumbnailPath
+	}
+	return result
+}
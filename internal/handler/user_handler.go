@@ -2,7 +2,10 @@ package handler
 
 import (
 	"bug-bounty-lite/internal/domain"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,7 +21,8 @@ func NewUserHandler(s domain.UserService) *UserHandler {
 // DTO: 专门用于接收注册参数
 type registerRequest struct {
 	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required,min=6"` // 密码最少6位
+	Password string `json:"password" binding:"required"`     // 密码规则见 auth.password 配置
+	Email    string `json:"email" binding:"omitempty,email"` // 可选，填写后发送验证邮件
 }
 
 // Register 处理注册请求
@@ -34,11 +38,12 @@ func (h *UserHandler) Register(c *gin.Context) {
 	user := &domain.User{
 		Username: req.Username,
 		Password: req.Password,
+		Email:    req.Email,
 	}
 
 	// 3. 调用 Service
 	if err := h.Service.Register(user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondPasswordError(c, err)
 		return
 	}
 
@@ -60,17 +65,52 @@ func (h *UserHandler) Login(c *gin.Context) {
 	}
 
 	// 调用 Service 进行登录
-	user, token, err := h.Service.Login(req.Username, req.Password)
+	client := domain.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+	result, err := h.Service.Login(req.Username, req.Password, client)
 	if err != nil {
+		// 失败次数过多返回 429，Retry-After 为需要等待的秒数
+		var throttled *domain.LoginThrottledError
+		if errors.As(err, &throttled) {
+			retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": throttled.Error(), "retry_after": retryAfter})
+			return
+		}
 		// 登录失败返回 401 Unauthorized
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
+	respondLoginResult(c, result)
+}
+
+// respondLoginResult 返回登录结果（密码登录和单点登录共用）
+func respondLoginResult(c *gin.Context, result *domain.LoginResult) {
+	// 需要两步验证：只返回挑战令牌，客户端完成第二步后才能拿到访问令牌
+	if result.Tokens == nil {
+		message := "Two-factor authentication required"
+		if result.TwoFactorSetupRequired {
+			message = "Two-factor authentication setup required"
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message":                   message,
+			"two_factor_required":       result.TwoFactorRequired,
+			"two_factor_setup_required": result.TwoFactorSetupRequired,
+			"challenge_token":           result.ChallengeToken,
+			"expires_in":                result.ChallengeExpiresIn,
+		})
+		return
+	}
+
+	tokens := result.Tokens
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-		"token":   token,
-		"user":    user, // 注意：User 里的 Password 字段有 `json:"-"`，所以不会返回
+		"message":            "Login successful",
+		"token":              tokens.AccessToken,
+		"refresh_token":      tokens.RefreshToken,
+		"token_type":         tokens.TokenType,
+		"expires_in":         tokens.ExpiresIn,
+		"refresh_expires_in": tokens.RefreshExpiresIn,
+		"user":               result.User, // 注意：User 里的 Password 字段有 `json:"-"`，所以不会返回
 	})
 }
 
@@ -139,7 +179,7 @@ func (h *UserHandler) BindOrganization(c *gin.Context) {
 // ChangePasswordRequest 修改密码请求体
 type ChangePasswordRequest struct {
 	OldPassword string `json:"oldPassword" binding:"required"`
-	NewPassword string `json:"newPassword" binding:"required,min=6"`
+	NewPassword string `json:"newPassword" binding:"required"`
 }
 
 // ChangePassword [POST] /api/v1/user/change-password
@@ -152,13 +192,23 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	}
 
 	if err := h.Service.ChangePassword(userID.(uint), req.OldPassword, req.NewPassword); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondPasswordError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "密码修改成功"})
 }
 
+// respondPasswordError 返回 400，新密码不符合密码策略时同时返回未通过的规则
+func respondPasswordError(c *gin.Context, err error) {
+	var policyErr *domain.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": policyErr.Message, "rule": policyErr.Rule})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
 // UpdateAvatarRequest 更新头像请求体
 type UpdateAvatarRequest struct {
 	AvatarID uint `json:"avatar_id" binding:"required"`
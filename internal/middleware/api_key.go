@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http"
+
+	"bug-bounty-lite/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader 携带个人 API Key 的请求头
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyValidator 校验个人 API Key（存在、未吊销、未过期），返回 Key 和所属用户，并记录最近使用
+type APIKeyValidator interface {
+	ValidateAPIKey(key, clientIP string) (*domain.APIKey, *domain.User, error)
+}
+
+// apiKeyRoute API Key 可访问的接口（请求方法 + 路由模板）及所需授权范围
+type apiKeyRoute struct {
+	method string
+	path   string
+	scope  string
+}
+
+// apiKeyRoutes API Key 可访问的接口，按路由模板精确匹配
+// 未列出的接口一律拒绝，包括账号设置、管理后台，以及删除/恢复报告、标记重复、发放奖励等不适合脚本调用的操作
+var apiKeyRoutes = []apiKeyRoute{
+	// 报告
+	{http.MethodGet, "/api/v1/reports", domain.APIKeyScopeReportsRead},
+	{http.MethodGet, "/api/v1/reports/:id", domain.APIKeyScopeReportsRead},
+	{http.MethodGet, "/api/v1/reports/:id/duplicates", domain.APIKeyScopeReportsRead},
+	{http.MethodGet, "/api/v1/reports/:id/attachments", domain.APIKeyScopeReportsRead},
+	{http.MethodGet, "/api/v1/reports/:id/transitions", domain.APIKeyScopeReportsRead},
+	{http.MethodGet, "/api/v1/reports/:id/timeline", domain.APIKeyScopeReportsRead},
+	{http.MethodGet, "/api/v1/reports/:id/comments", domain.APIKeyScopeReportsRead},
+	{http.MethodGet, "/api/v1/reports/:id/bounties", domain.APIKeyScopeReportsRead},
+	{http.MethodPost, "/api/v1/reports", domain.APIKeyScopeReportsWrite},
+	{http.MethodPut, "/api/v1/reports/:id", domain.APIKeyScopeReportsWrite},
+	{http.MethodPost, "/api/v1/reports/:id/attachments", domain.APIKeyScopeReportsWrite},
+	{http.MethodDelete, "/api/v1/reports/:id/attachments/:attachmentId", domain.APIKeyScopeReportsWrite},
+	{http.MethodPost, "/api/v1/reports/:id/comments", domain.APIKeyScopeReportsWrite},
+	{http.MethodDelete, "/api/v1/reports/:id/comments/:commentId", domain.APIKeyScopeReportsWrite},
+
+	// 上传文件和分片上传
+	{http.MethodPost, "/api/v1/upload", domain.APIKeyScopeReportsWrite},
+	{http.MethodPost, "/api/v1/upload/sessions", domain.APIKeyScopeReportsWrite},
+	{http.MethodGet, "/api/v1/upload/sessions/:id", domain.APIKeyScopeReportsWrite},
+	{http.MethodPut, "/api/v1/upload/sessions/:id", domain.APIKeyScopeReportsWrite},
+	{http.MethodPost, "/api/v1/upload/sessions/:id/complete", domain.APIKeyScopeReportsWrite},
+	{http.MethodDelete, "/api/v1/upload/sessions/:id", domain.APIKeyScopeReportsWrite},
+	{http.MethodPost, "/api/v1/files/sign", domain.APIKeyScopeReportsRead}, // 获取附件签名下载链接
+
+	// 提交报告所需的漏洞类型、危害等级等
+	{http.MethodGet, "/api/v1/configs/:type", domain.APIKeyScopeReportsRead},
+	{http.MethodGet, "/api/v1/configs/:type/:id", domain.APIKeyScopeReportsRead},
+
+	// 项目
+	{http.MethodGet, "/api/v1/projects", domain.APIKeyScopeProjectsRead},
+	{http.MethodGet, "/api/v1/projects/available", domain.APIKeyScopeProjectsRead},
+	{http.MethodGet, "/api/v1/projects/accepted", domain.APIKeyScopeProjectsRead},
+	{http.MethodGet, "/api/v1/projects/available/:id", domain.APIKeyScopeProjectsRead},
+	{http.MethodGet, "/api/v1/projects/:id", domain.APIKeyScopeProjectsRead},
+	{http.MethodGet, "/api/v1/projects/:id/attachments", domain.APIKeyScopeProjectsRead},
+	{http.MethodGet, "/api/v1/projects/:id/scopes", domain.APIKeyScopeProjectsRead},
+	{http.MethodPost, "/api/v1/projects/:id/scopes/check", domain.APIKeyScopeProjectsRead}, // 只读：校验链接是否在范围内
+	{http.MethodGet, "/api/v1/projects/:id/workflow", domain.APIKeyScopeProjectsRead},
+}
+
+// apiKeyRequiredScope 根据请求方法和路由模板返回所需授权范围，不允许 API Key 访问时返回空字符串
+func apiKeyRequiredScope(method, fullPath string) string {
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	for _, route := range apiKeyRoutes {
+		if route.method == method && route.path == fullPath {
+			return route.scope
+		}
+	}
+	return ""
+}
+
+// authenticateAPIKey 使用 API Key 认证并检查授权范围，失败时返回 false（已写入响应）
+func authenticateAPIKey(c *gin.Context, validator APIKeyValidator, key string) bool {
+	apiKey, user, err := validator.ValidateAPIKey(key, c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		c.Abort()
+		return false
+	}
+
+	scope := apiKeyRequiredScope(c.Request.Method, c.FullPath())
+	if scope == "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API Key 无权访问该接口"})
+		c.Abort()
+		return false
+	}
+	if !apiKey.HasScope(scope) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API Key 缺少授权范围 " + scope, "required_scope": scope})
+		c.Abort()
+		return false
+	}
+
+	// 角色以用户当前角色为准（不存入 claims，依赖会话的接口不可用）
+	c.Set("userID", user.ID)
+	c.Set("username", user.Username)
+	c.Set("role", user.Role)
+	c.Set("apiKeyID", apiKey.ID)
+	return true
+}
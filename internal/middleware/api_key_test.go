@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIKeyRequiredScope(t *testing.T) {
+	tests := []struct {
+		method   string
+		fullPath string
+		want     string
+	}{
+		{http.MethodGet, "/api/v1/reports", domain.APIKeyScopeReportsRead},
+		{http.MethodHead, "/api/v1/reports/:id", domain.APIKeyScopeReportsRead},
+		{http.MethodGet, "/api/v1/reports/:id/attachments", domain.APIKeyScopeReportsRead},
+		{http.MethodGet, "/api/v1/reports/:id/bounties", domain.APIKeyScopeReportsRead},
+		{http.MethodPost, "/api/v1/reports", domain.APIKeyScopeReportsWrite},
+		{http.MethodPut, "/api/v1/reports/:id", domain.APIKeyScopeReportsWrite},
+		{http.MethodDelete, "/api/v1/reports/:id/attachments/:attachmentId", domain.APIKeyScopeReportsWrite},
+		{http.MethodPost, "/api/v1/reports/:id/comments", domain.APIKeyScopeReportsWrite},
+		{http.MethodPost, "/api/v1/upload", domain.APIKeyScopeReportsWrite},
+		{http.MethodGet, "/api/v1/upload/sessions/:id", domain.APIKeyScopeReportsWrite},
+		{http.MethodPut, "/api/v1/upload/sessions/:id", domain.APIKeyScopeReportsWrite},
+		{http.MethodPost, "/api/v1/files/sign", domain.APIKeyScopeReportsRead},
+		{http.MethodGet, "/api/v1/configs/:type", domain.APIKeyScopeReportsRead},
+		{http.MethodGet, "/api/v1/projects/:id", domain.APIKeyScopeProjectsRead},
+		{http.MethodPost, "/api/v1/projects/:id/scopes/check", domain.APIKeyScopeProjectsRead},
+		// 报告下的高风险操作不开放
+		{http.MethodDelete, "/api/v1/reports/:id", ""},
+		{http.MethodPost, "/api/v1/reports/:id/restore", ""},
+		{http.MethodPost, "/api/v1/reports/:id/duplicate", ""},
+		{http.MethodPost, "/api/v1/reports/:id/bounties", ""},
+		// 方法和路由模板都必须匹配
+		{http.MethodPost, "/api/v1/configs/:type", ""},
+		{http.MethodPut, "/api/v1/projects/:id", ""},
+		{http.MethodPost, "/api/v1/projects/:id/accept", ""},
+		{http.MethodGet, "/api/v1/reportsx", ""},
+		{http.MethodGet, "/api/v1/reports/:id/unknown", ""},
+		// 账号设置和管理后台一律拒绝
+		{http.MethodGet, "/api/v1/user/profile", ""},
+		{http.MethodPost, "/api/v1/user/api-keys", ""},
+		{http.MethodDelete, "/api/v1/user/sessions/:id", ""},
+		{http.MethodGet, "/api/v1/admin/users/:id/api-keys", ""},
+		{http.MethodPut, "/api/v1/bounties/:id/status", ""},
+		{http.MethodGet, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.fullPath, func(t *testing.T) {
+			if got := apiKeyRequiredScope(tt.method, tt.fullPath); got != tt.want {
+				t.Errorf("apiKeyRequiredScope(%s, %s) = %q, want %q", tt.method, tt.fullPath, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeAPIKeyValidator 固定 Key 的校验器
+type fakeAPIKeyValidator struct {
+	keys map[string]*domain.APIKey
+	user *domain.User
+}
+
+func (v *fakeAPIKeyValidator) ValidateAPIKey(key, clientIP string) (*domain.APIKey, *domain.User, error) {
+	apiKey, ok := v.keys[key]
+	if !ok {
+		return nil, nil, errors.New("API Key 无效")
+	}
+	return apiKey, v.user, nil
+}
+
+func TestAuthMiddlewareAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator := &fakeAPIKeyValidator{
+		keys: map[string]*domain.APIKey{
+			"bbl_valid":  {ID: 7, Scopes: domain.APIKeyScopeReportsRead},
+			"bbl_writer": {ID: 8, Scopes: domain.APIKeyScopeReportsRead + "," + domain.APIKeyScopeReportsWrite},
+		},
+		user: &domain.User{ID: 1, Username: "alice", Role: "whitehat"},
+	}
+	router := gin.New()
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetUint("userID"), "api_key_id": c.GetUint("apiKeyID")})
+	}
+	api := router.Group("/api/v1", AuthMiddleware(nil, nil, validator))
+	api.GET("/reports/:id", handler)
+	api.POST("/reports", handler)
+	api.POST("/user/api-keys", handler)
+	api.DELETE("/reports/:id", handler)
+	api.POST("/reports/:id/bounties", handler)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		key        string
+		wantStatus int
+	}{
+		{name: "授权范围内", method: http.MethodGet, path: "/api/v1/reports/1", key: "bbl_valid", wantStatus: http.StatusOK},
+		{name: "缺少授权范围", method: http.MethodPost, path: "/api/v1/reports", key: "bbl_valid", wantStatus: http.StatusForbidden},
+		{name: "不允许 API Key 访问的接口", method: http.MethodPost, path: "/api/v1/user/api-keys", key: "bbl_valid", wantStatus: http.StatusForbidden},
+		{name: "写权限也不能删除报告", method: http.MethodDelete, path: "/api/v1/reports/1", key: "bbl_writer", wantStatus: http.StatusForbidden},
+		{name: "写权限也不能发放奖励", method: http.MethodPost, path: "/api/v1/reports/1/bounties", key: "bbl_writer", wantStatus: http.StatusForbidden},
+		{name: "写权限可以提交报告", method: http.MethodPost, path: "/api/v1/reports", key: "bbl_writer", wantStatus: http.StatusOK},
+		{name: "无效 Key", method: http.MethodGet, path: "/api/v1/reports/1", key: "bbl_invalid", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(APIKeyHeader, tt.key)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK && w.Body.String() != fmt.Sprintf(`{"api_key_id":%d,"user_id":1}`, validator.keys[tt.key].ID) {
+				t.Errorf("body = %s, want user and API Key in context", w.Body.String())
+			}
+		})
+	}
+}
@@ -9,9 +9,23 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// TokenValidator 检查已通过签名校验的访问令牌是否已被吊销（会话已注销、退出全部会话），并记录会话最近活动
+type TokenValidator interface {
+	ValidateAccessToken(claims *jwt.Claims, clientIP string) error
+}
+
 // AuthMiddleware JWT 认证中间件
-func AuthMiddleware(jwtManager *jwt.JWTManager) gin.HandlerFunc {
+// 请求携带 X-API-Key 时改用个人 API Key 认证，并按接口检查 Key 的授权范围
+func AuthMiddleware(jwtManager *jwt.JWTManager, validator TokenValidator, apiKeys APIKeyValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// 0. 个人 API Key
+		if key := c.GetHeader(APIKeyHeader); key != "" {
+			if authenticateAPIKey(c, apiKeys, key) {
+				c.Next()
+			}
+			return
+		}
+
 		// 1. 从 Header 获取 Token
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -38,18 +52,26 @@ func AuthMiddleware(jwtManager *jwt.JWTManager) gin.HandlerFunc {
 			return
 		}
 
-		// 4. 将用户信息存入 Context
+		// 4. 检查 Token 是否已被吊销
+		if err := validator.ValidateAccessToken(claims, c.ClientIP()); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		// 5. 将用户信息存入 Context
 		c.Set("userID", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("claims", claims)
 
 		c.Next()
 	}
 }
 
 // OptionalAuthMiddleware 可选认证中间件
-// 尝试解析 Token，如果成功则设置用户信息，失败则继续（不阻断请求）
-func OptionalAuthMiddleware(jwtManager *jwt.JWTManager) gin.HandlerFunc {
+// 尝试解析 Token，如果成功则设置用户信息，失败或已被吊销则继续（不阻断请求）
+func OptionalAuthMiddleware(jwtManager *jwt.JWTManager, validator TokenValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -65,7 +87,7 @@ func OptionalAuthMiddleware(jwtManager *jwt.JWTManager) gin.HandlerFunc {
 
 		tokenString := parts[1]
 		claims, err := jwtManager.ParseToken(tokenString)
-		if err != nil {
+		if err != nil || validator.ValidateAccessToken(claims, c.ClientIP()) != nil {
 			c.Next()
 			return
 		}
@@ -73,6 +95,7 @@ func OptionalAuthMiddleware(jwtManager *jwt.JWTManager) gin.HandlerFunc {
 		c.Set("userID", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("claims", claims)
 		c.Next()
 	}
 }
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailVerificationChecker 检查用户当前邮箱是否已验证
+type EmailVerificationChecker interface {
+	IsEmailVerified(userID uint) (bool, error)
+}
+
+// RequireVerifiedEmail 邮箱未验证的账号返回 403（需在 AuthMiddleware 之后使用）
+func RequireVerifiedEmail(checker EmailVerificationChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			c.Abort()
+			return
+		}
+
+		verified, err := checker.IsEmailVerified(userID.(uint))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+		if !verified {
+			c.JSON(http.StatusForbidden, gin.H{"error": "请先验证邮箱后再进行此操作", "email_verification_required": true})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type accountTokenRepo struct {
+	db *gorm.DB
+}
+
+// NewAccountTokenRepo 创建账号令牌仓库实例
+func NewAccountTokenRepo(db *gorm.DB) domain.AccountTokenRepository {
+	return &accountTokenRepo{db: db}
+}
+
+// Create 保存新令牌，同时作废该用户同用途的未使用令牌（同一时间只有最新的链接有效）
+func (r *accountTokenRepo) Create(token *domain.AccountToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.AccountToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Update("expires_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+// FindByHash 根据令牌哈希查找
+func (r *accountTokenRepo) FindByHash(tokenHash string) (*domain.AccountToken, error) {
+	var token domain.AccountToken
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindLatest 获取用户同用途最近创建的令牌，不存在时返回 nil
+func (r *accountTokenRepo) FindLatest(userID uint, purpose string) (*domain.AccountToken, error) {
+	var token domain.AccountToken
+	err := r.db.Where("user_id = ? AND purpose = ?", userID, purpose).Order("id DESC").First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed 以未使用为条件标记令牌已使用
+func (r *accountTokenRepo) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&domain.AccountToken{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteExpired 删除已过期的令牌（包括已使用的）
+func (r *accountTokenRepo) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&domain.AccountToken{})
+	return result.RowsAffected, result.Error
+}
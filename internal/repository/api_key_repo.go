@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type apiKeyRepo struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepo 创建 API Key 仓库实例
+func NewAPIKeyRepo(db *gorm.DB) domain.APIKeyRepository {
+	return &apiKeyRepo{db: db}
+}
+
+// Create 保存 API Key
+func (r *apiKeyRepo) Create(key *domain.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+// FindByHash 根据 Key 哈希查找
+func (r *apiKeyRepo) FindByHash(keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := r.db.Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListByUser 获取用户全部未吊销的 Key（按创建时间倒序）
+func (r *apiKeyRepo) ListByUser(userID uint) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL", userID).Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// CountActive 统计用户未吊销且未过期的 Key
+func (r *apiKeyRepo) CountActive(userID uint, now time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Count(&count).Error
+	return count, err
+}
+
+// Revoke 吊销用户的 Key
+func (r *apiKeyRepo) Revoke(userID, id uint, revokedAt time.Time) (bool, error) {
+	result := r.db.Model(&domain.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RevokeAllByUser 吊销用户全部未吊销的 Key
+func (r *apiKeyRepo) RevokeAllByUser(userID uint, revokedAt time.Time) (int64, error) {
+	result := r.db.Model(&domain.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt)
+	return result.RowsAffected, result.Error
+}
+
+// TouchLastUsed 记录最近使用时间和 IP
+func (r *apiKeyRepo) TouchLastUsed(id uint, ip string, usedAt time.Time) error {
+	return r.db.Model(&domain.APIKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_used_at": usedAt,
+		"last_used_ip": ip,
+	}).Error
+}
+
+// DeleteExpired 删除过期或吊销时间早于 before 的 Key
+func (r *apiKeyRepo) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ? OR revoked_at < ?", before, before).Delete(&domain.APIKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type authTokenRepo struct {
+	db *gorm.DB
+}
+
+// NewAuthTokenRepo 创建令牌和登录会话仓库实例
+func NewAuthTokenRepo(db *gorm.DB) domain.AuthTokenRepository {
+	return &authTokenRepo{db: db}
+}
+
+// CreateSession 保存新会话及其第一个刷新令牌
+func (r *authTokenRepo) CreateSession(session *domain.UserSession, token *domain.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+// FindSession 根据ID查找会话
+func (r *authTokenRepo) FindSession(id string) (*domain.UserSession, error) {
+	var session domain.UserSession
+	if err := r.db.Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActiveSessions 获取用户未注销且未过期的会话
+func (r *authTokenRepo) ListActiveSessions(userID uint, now time.Time) ([]domain.UserSession, error) {
+	var sessions []domain.UserSession
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// TouchSession 更新会话最近活动时间和IP
+func (r *authTokenRepo) TouchSession(id string, ip string, seenAt time.Time) error {
+	return r.db.Model(&domain.UserSession{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_seen_at": seenAt, "ip": ip}).Error
+}
+
+// FindRefreshTokenByHash 根据令牌哈希查找刷新令牌
+func (r *authTokenRepo) FindRefreshTokenByHash(hash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RotateRefreshToken 以旧令牌未被吊销作为条件吊销旧令牌，保存新令牌并顺延会话
+func (r *authTokenRepo) RotateRefreshToken(old *domain.RefreshToken, next *domain.RefreshToken, ip string) (bool, error) {
+	rotated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", old.ID).
+			Update("revoked_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.RefreshToken{}).Where("id = ?", old.ID).
+			Update("replaced_by_id", next.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.UserSession{}).Where("id = ?", old.SessionID).
+			Updates(map[string]interface{}{"last_seen_at": now, "ip": ip, "expires_at": next.ExpiresAt}).Error; err != nil {
+			return err
+		}
+		old.RevokedAt = &now
+		old.ReplacedByID = next.ID
+		rotated = true
+		return nil
+	})
+	return rotated, err
+}
+
+// RevokeSession 注销会话并吊销会话下所有未吊销的刷新令牌
+func (r *authTokenRepo) RevokeSession(userID uint, sessionID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&domain.UserSession{}).
+			Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.RefreshToken{}).
+			Where("user_id = ? AND session_id = ? AND revoked_at IS NULL", userID, sessionID).
+			Update("revoked_at", now).Error
+	})
+}
+
+// RevokeUserSessions 注销用户所有会话并吊销所有未吊销的刷新令牌
+func (r *authTokenRepo) RevokeUserSessions(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&domain.UserSession{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now).Error
+	})
+}
+
+// RevokeAccessToken 记录已吊销的访问令牌（重复吊销忽略）
+func (r *authTokenRepo) RevokeAccessToken(token *domain.RevokedToken) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+// IsAccessTokenRevoked 检查访问令牌是否已被吊销
+func (r *authTokenRepo) IsAccessTokenRevoked(tokenID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.RevokedToken{}).Where("token_id = ?", tokenID).Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteExpired 删除已过期的会话、刷新令牌和吊销记录
+func (r *authTokenRepo) DeleteExpired(before time.Time) (int64, error) {
+	var deleted int64
+	for _, model := range []interface{}{&domain.UserSession{}, &domain.RefreshToken{}, &domain.RevokedToken{}} {
+		result := r.db.Where("expires_at < ?", before).Delete(model)
+		if result.Error != nil {
+			return deleted, result.Error
+		}
+		deleted += result.RowsAffected
+	}
+	return deleted, nil
+}
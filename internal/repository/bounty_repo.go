@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type bountyRepo struct {
+	db *gorm.DB
+}
+
+// NewBountyRepo 创建奖励仓库实例
+func NewBountyRepo(db *gorm.DB) domain.BountyRepository {
+	return &bountyRepo{db: db}
+}
+
+// CreateReward 创建奖励，同时追加账本条目
+// 创建奖金时锁定报告行再检查已有奖金，避免并发发放产生多笔有效奖金
+func (r *bountyRepo) CreateReward(reward *domain.BountyReward, entry *domain.BountyLedgerEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if reward.Type == domain.BountyTypeAward {
+			var report domain.Report
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id").
+				First(&report, reward.ReportID).Error
+			if err != nil {
+				return err
+			}
+			var count int64
+			err = tx.Model(&domain.BountyReward{}).
+				Where("report_id = ? AND type = ? AND status <> ?", reward.ReportID, domain.BountyTypeAward, domain.BountyStatusCancelled).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return domain.ErrBountyAlreadyAwarded
+			}
+		}
+		if err := tx.Create(reward).Error; err != nil {
+			return err
+		}
+		entry.RewardID = reward.ID
+		return tx.Create(entry).Error
+	})
+}
+
+// UpdateRewardStatus 更新奖励状态，同时追加账本条目
+// 以读取时的状态和未加入发放批次为条件更新，避免覆盖并发创建批次写入的 payout_batch_id
+func (r *bountyRepo) UpdateRewardStatus(reward *domain.BountyReward, fromStatus string, entry *domain.BountyLedgerEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		reward.UpdatedAt = time.Now()
+		result := tx.Model(&domain.BountyReward{}).
+			Where("id = ? AND status = ? AND payout_batch_id IS NULL", reward.ID, fromStatus).
+			Updates(map[string]interface{}{
+				"status":     reward.Status,
+				"updated_at": reward.UpdatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrBountyRewardConflict
+		}
+		entry.RewardID = reward.ID
+		return tx.Create(entry).Error
+	})
+}
+
+// FindRewardByID 根据ID查找奖励
+func (r *bountyRepo) FindRewardByID(id uint) (*domain.BountyReward, error) {
+	var reward domain.BountyReward
+	if err := r.db.First(&reward, id).Error; err != nil {
+		return nil, err
+	}
+	return &reward, nil
+}
+
+// ListRewardsByReport 获取报告的所有奖励
+func (r *bountyRepo) ListRewardsByReport(reportID uint) ([]domain.BountyReward, error) {
+	var rewards []domain.BountyReward
+	err := r.db.Where("report_id = ?", reportID).Order("id ASC").Find(&rewards).Error
+	return rewards, err
+}
+
+// ListRewardsByAuthor 获取白帽子的所有奖励（加载关联报告）
+func (r *bountyRepo) ListRewardsByAuthor(authorID uint) ([]domain.BountyReward, error) {
+	var rewards []domain.BountyReward
+	if err := r.db.Where("author_id = ?", authorID).Order("id DESC").Find(&rewards).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range rewards {
+		var report domain.Report
+		if err := r.db.Select("id", "vulnerability_name", "project_id", "severity", "status").First(&report, rewards[i].ReportID).Error; err == nil {
+			rewards[i].Report = &report
+		}
+	}
+
+	return rewards, nil
+}
+
+// ListLedgerByReport 获取报告的奖励账本（按时间正序）
+func (r *bountyRepo) ListLedgerByReport(reportID uint) ([]domain.BountyLedgerEntry, error) {
+	var entries []domain.BountyLedgerEntry
+	err := r.db.Where("report_id = ?", reportID).Order("created_at ASC, id ASC").Find(&entries).Error
+	return entries, err
+}
+
+// CreateBatch 将指定币种所有未打包的已审批奖励放入新批次
+func (r *bountyRepo) CreateBatch(batch *domain.PayoutBatch) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 锁定待打包的奖励，避免并发创建批次时重复打包
+		var rewards []domain.BountyReward
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("status = ? AND currency = ? AND payout_batch_id IS NULL", domain.BountyStatusApproved, batch.Currency).
+			Order("id ASC").
+			Find(&rewards).Error
+		if err != nil {
+			return err
+		}
+		if len(rewards) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		batch.ItemCount = len(rewards)
+		batch.TotalAmount = 0
+		for _, reward := range rewards {
+			batch.TotalAmount += reward.Amount
+		}
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+
+		ids := make([]uint, 0, len(rewards))
+		entries := make([]domain.BountyLedgerEntry, 0, len(rewards))
+		for _, reward := range rewards {
+			ids = append(ids, reward.ID)
+			entries = append(entries, domain.BountyLedgerEntry{
+				RewardID:      reward.ID,
+				ReportID:      reward.ReportID,
+				AuthorID:      reward.AuthorID,
+				EntryType:     domain.BountyEntryBatched,
+				Amount:        reward.Amount,
+				Currency:      reward.Currency,
+				FromStatus:    reward.Status,
+				ToStatus:      reward.Status,
+				PayoutBatchID: &batch.ID,
+				ActorID:       batch.CreatedBy,
+			})
+		}
+
+		if err := tx.Model(&domain.BountyReward{}).Where("id IN ?", ids).Update("payout_batch_id", batch.ID).Error; err != nil {
+			return err
+		}
+		return tx.Create(&entries).Error
+	})
+}
+
+// FindBatchByID 根据ID查找发放批次
+func (r *bountyRepo) FindBatchByID(id uint) (*domain.PayoutBatch, error) {
+	var batch domain.PayoutBatch
+	if err := r.db.First(&batch, id).Error; err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// ListBatches 分页获取发放批次
+func (r *bountyRepo) ListBatches(page, pageSize int) ([]domain.PayoutBatch, int64, error) {
+	var batches []domain.PayoutBatch
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	if err := r.db.Model(&domain.PayoutBatch{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.db.Order("id DESC").Offset(offset).Limit(pageSize).Find(&batches).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return batches, total, nil
+}
+
+// ListRewardsByBatch 获取批次中的奖励（加载获奖白帽子信息，用于导出）
+func (r *bountyRepo) ListRewardsByBatch(batchID uint) ([]domain.BountyReward, error) {
+	var rewards []domain.BountyReward
+	if err := r.db.Where("payout_batch_id = ?", batchID).Order("author_id ASC, id ASC").Find(&rewards).Error; err != nil {
+		return nil, err
+	}
+
+	authors := make(map[uint]*domain.User)
+	for i := range rewards {
+		authorID := rewards[i].AuthorID
+		if _, ok := authors[authorID]; !ok {
+			var user domain.User
+			if err := r.db.First(&user, authorID).Error; err == nil {
+				authors[authorID] = &user
+			} else {
+				authors[authorID] = nil
+			}
+		}
+		rewards[i].Author = authors[authorID]
+	}
+
+	return rewards, nil
+}
+
+// MarkBatchPaid 将批次及其中仍为已审批状态的奖励标记为已发放
+func (r *bountyRepo) MarkBatchPaid(batch *domain.PayoutBatch, actorID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var rewards []domain.BountyReward
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("payout_batch_id = ? AND status = ?", batch.ID, domain.BountyStatusApproved).
+			Find(&rewards).Error
+		if err != nil {
+			return err
+		}
+
+		if len(rewards) > 0 {
+			ids := make([]uint, 0, len(rewards))
+			entries := make([]domain.BountyLedgerEntry, 0, len(rewards))
+			for _, reward := range rewards {
+				ids = append(ids, reward.ID)
+				entries = append(entries, domain.BountyLedgerEntry{
+					RewardID:      reward.ID,
+					ReportID:      reward.ReportID,
+					AuthorID:      reward.AuthorID,
+					EntryType:     domain.BountyEntryStatusChanged,
+					Amount:        reward.Amount,
+					Currency:      reward.Currency,
+					FromStatus:    reward.Status,
+					ToStatus:      domain.BountyStatusPaid,
+					PayoutBatchID: &batch.ID,
+					ActorID:       actorID,
+				})
+			}
+
+			err := tx.Model(&domain.BountyReward{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+				"status":  domain.BountyStatusPaid,
+				"paid_at": now,
+			}).Error
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(&entries).Error; err != nil {
+				return err
+			}
+		}
+
+		batch.Status = domain.PayoutBatchPaid
+		batch.PaidBy = actorID
+		batch.PaidAt = &now
+		return tx.Save(batch).Error
+	})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"bug-bounty-lite/internal/domain"
+	"math"
 	"time"
 
 	"gorm.io/gorm"
@@ -22,7 +23,7 @@ func (r *dashboardRepo) CountBySeverity(authorID *uint) (*domain.SeverityStatist
 	stats := &domain.SeverityStatistics{}
 
 	// 基础查询：已审核的报告（status 不为 Pending）
-	baseQuery := r.db.Model(&domain.Report{}).Where("status != ?", "Pending")
+	baseQuery := r.db.Model(&domain.Report{}).Where("status != ?", domain.ReportStatusPending)
 
 	// 如果指定了用户ID，只查询该用户的报告
 	if authorID != nil && *authorID > 0 {
@@ -35,7 +36,7 @@ func (r *dashboardRepo) CountBySeverity(authorID *uint) (*domain.SeverityStatist
 		Count    int64
 	}
 
-	err := baseQuery.Select("severity, COUNT(*) as count").
+	err := baseQuery.Session(&gorm.Session{}).Select("severity, COUNT(*) as count").
 		Where("severity != '' AND severity IS NOT NULL").
 		Group("severity").
 		Scan(&results).Error
@@ -63,6 +64,20 @@ func (r *dashboardRepo) CountBySeverity(authorID *uint) (*domain.SeverityStatist
 	// 计算总数
 	stats.Total = stats.Critical + stats.High + stats.Medium + stats.Low + stats.None
 
+	// 统计 CVSS 评分
+	var scoreResult struct {
+		Scored   int64
+		AvgScore float64
+	}
+	err = baseQuery.Session(&gorm.Session{}).
+		Select("COUNT(cvss_score) as scored, COALESCE(AVG(cvss_score), 0) as avg_score").
+		Scan(&scoreResult).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.Scored = scoreResult.Scored
+	stats.AvgCVSSScore = math.Round(scoreResult.AvgScore*10) / 10
+
 	return stats, nil
 }
 
@@ -72,7 +87,7 @@ func (r *dashboardRepo) GetTrend(period string, authorID *uint) ([]domain.TrendI
 	var results []domain.TrendItem
 
 	// 基础查询：已审核的报告（status 不为 Pending），按 created_at 分组
-	baseQuery := r.db.Model(&domain.Report{}).Where("status != ?", "Pending")
+	baseQuery := r.db.Model(&domain.Report{}).Where("status != ?", domain.ReportStatusPending)
 
 	// 如果指定了用户ID，只查询该用户的报告
 	if authorID != nil && *authorID > 0 {
@@ -205,9 +220,9 @@ func (r *dashboardRepo) ListByStatus(isPending bool, limit int, authorID *uint)
 	query := r.db.Model(&domain.Report{})
 
 	if isPending {
-		query = query.Where("status = ?", "Pending")
+		query = query.Where("status = ?", domain.ReportStatusPending)
 	} else {
-		query = query.Where("status != ?", "Pending")
+		query = query.Where("status != ?", domain.ReportStatusPending)
 	}
 
 	// 如果指定了用户ID，只查询该用户的报告
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// notificationBatchSize 批量写入通知时每批的条数
+const notificationBatchSize = 200
+
+type notificationRepo struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepo 创建通知仓库实例
+func NewNotificationRepo(db *gorm.DB) domain.NotificationRepository {
+	return &notificationRepo{db: db}
+}
+
+// CreateBatch 批量创建通知
+func (r *notificationRepo) CreateBatch(notifications []domain.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(notifications, notificationBatchSize).Error
+}
+
+// ListByUser 分页获取用户的通知（按时间倒序）
+func (r *notificationRepo) ListByUser(userID uint, unreadOnly bool, page, pageSize int) ([]domain.Notification, int64, error) {
+	var notifications []domain.Notification
+	var total int64
+
+	query := r.db.Model(&domain.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&notifications).Error
+	return notifications, total, err
+}
+
+// CountUnread 统计用户的未读通知数
+func (r *notificationRepo) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// MarkRead 将用户的某条通知标记为已读
+func (r *notificationRepo) MarkRead(userID uint, id uint) error {
+	var notification domain.Notification
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		return err
+	}
+	if notification.ReadAt != nil {
+		return nil
+	}
+	return r.db.Model(&notification).Update("read_at", time.Now()).Error
+}
+
+// MarkAllRead 将用户的所有未读通知标记为已读，返回标记的条数
+func (r *notificationRepo) MarkAllRead(userID uint) (int64, error) {
+	result := r.db.Model(&domain.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
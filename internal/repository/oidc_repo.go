@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type oidcRepo struct {
+	db *gorm.DB
+}
+
+// NewOIDCRepo 创建单点登录仓库实例
+func NewOIDCRepo(db *gorm.DB) domain.OIDCRepository {
+	return &oidcRepo{db: db}
+}
+
+// CreateState 保存登录状态
+func (r *oidcRepo) CreateState(state *domain.OIDCLoginState) error {
+	return r.db.Create(state).Error
+}
+
+// ConsumeState 查找并删除登录状态，并发回调时只有删除成功的一方可以继续
+func (r *oidcRepo) ConsumeState(stateHash string) (*domain.OIDCLoginState, error) {
+	var state domain.OIDCLoginState
+	if err := r.db.Where("state_hash = ?", stateHash).First(&state).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	result := r.db.Delete(&domain.OIDCLoginState{}, state.ID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// DeleteExpiredStates 删除已过期的登录状态
+func (r *oidcRepo) DeleteExpiredStates(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&domain.OIDCLoginState{})
+	return result.RowsAffected, result.Error
+}
+
+// FindIdentity 根据身份提供方和 subject 查找身份，不存在时返回 nil
+func (r *oidcRepo) FindIdentity(provider, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	if err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// CreateIdentity 关联外部身份
+func (r *oidcRepo) CreateIdentity(identity *domain.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// UpdateIdentityLogin 记录身份的最近登录时间和邮箱
+func (r *oidcRepo) UpdateIdentityLogin(id uint, email string, loginAt time.Time) error {
+	return r.db.Model(&domain.UserIdentity{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email":         email,
+		"last_login_at": loginAt,
+	}).Error
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type passwordHistoryRepo struct {
+	db *gorm.DB
+}
+
+// NewPasswordHistoryRepo 创建历史密码仓库实例
+func NewPasswordHistoryRepo(db *gorm.DB) domain.PasswordHistoryRepository {
+	return &passwordHistoryRepo{db: db}
+}
+
+// Create 保存历史密码
+func (r *passwordHistoryRepo) Create(history *domain.PasswordHistory) error {
+	return r.db.Create(history).Error
+}
+
+// ListRecent 获取用户最近 limit 条历史密码
+func (r *passwordHistoryRepo) ListRecent(userID uint, limit int) ([]domain.PasswordHistory, error) {
+	var histories []domain.PasswordHistory
+	err := r.db.Where("user_id = ?", userID).Order("id DESC").Limit(limit).Find(&histories).Error
+	return histories, err
+}
+
+// Prune 只保留用户最近 keep 条历史密码
+func (r *passwordHistoryRepo) Prune(userID uint, keep int) error {
+	query := r.db.Where("user_id = ?", userID)
+	if keep > 0 {
+		// 第 keep 条记录的 ID，更早的记录删除（MySQL 不支持在 IN 子查询中使用 LIMIT）
+		var ids []uint
+		if err := r.db.Model(&domain.PasswordHistory{}).Where("user_id = ?", userID).
+			Order("id DESC").Offset(keep-1).Limit(1).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		query = query.Where("id < ?", ids[0])
+	}
+	return query.Delete(&domain.PasswordHistory{}).Error
+}
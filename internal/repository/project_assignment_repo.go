@@ -53,3 +53,20 @@ func (r *projectAssignmentRepo) Delete(id uint) error {
 func (r *projectAssignmentRepo) DeleteByProjectAndUser(projectID, userID uint) error {
 	return r.db.Where("project_id = ? AND user_id = ?", projectID, userID).Delete(&domain.ProjectAssignment{}).Error
 }
+
+// CreateBatch 批量创建指派记录
+func (r *projectAssignmentRepo) CreateBatch(assignments []domain.ProjectAssignment) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(assignments, 200).Error
+}
+
+// DeleteByProjectAndUsers 批量删除项目中指定用户的指派记录，返回删除条数
+func (r *projectAssignmentRepo) DeleteByProjectAndUsers(projectID uint, userIDs []uint) (int64, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	result := r.db.Where("project_id = ? AND user_id IN ?", projectID, userIDs).Delete(&domain.ProjectAssignment{})
+	return result.RowsAffected, result.Error
+}
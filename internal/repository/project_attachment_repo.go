@@ -20,6 +20,22 @@ func (r *projectAttachmentRepo) Create(attachment *domain.ProjectAttachment) err
 	return r.db.Create(attachment).Error
 }
 
+// FindByID 根据ID查找附件
+func (r *projectAttachmentRepo) FindByID(id uint) (*domain.ProjectAttachment, error) {
+	var attachment domain.ProjectAttachment
+	if err := r.db.First(&attachment, id).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// FindByFilePath 查找地址指向指定上传文件的附件（按 URL 后缀匹配）
+func (r *projectAttachmentRepo) FindByFilePath(filePath string) ([]domain.ProjectAttachment, error) {
+	var attachments []domain.ProjectAttachment
+	err := r.db.Where("url LIKE ?", "%/"+escapeLike(filePath)).Find(&attachments).Error
+	return attachments, err
+}
+
 // FindByProjectID 根据项目ID查找所有附件
 func (r *projectAttachmentRepo) FindByProjectID(projectID uint) ([]domain.ProjectAttachment, error) {
 	var attachments []domain.ProjectAttachment
@@ -27,6 +43,30 @@ func (r *projectAttachmentRepo) FindByProjectID(projectID uint) ([]domain.Projec
 	return attachments, err
 }
 
+// MaxSortOrder 获取项目附件的最大排序值，没有附件时返回 0
+func (r *projectAttachmentRepo) MaxSortOrder(projectID uint) (int, error) {
+	var maxOrder int
+	err := r.db.Model(&domain.ProjectAttachment{}).
+		Where("project_id = ?", projectID).
+		Select("COALESCE(MAX(sort_order), 0)").
+		Scan(&maxOrder).Error
+	return maxOrder, err
+}
+
+// UpdateSortOrders 按给定顺序更新附件的排序值（从 1 开始）
+func (r *projectAttachmentRepo) UpdateSortOrders(projectID uint, orderedIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range orderedIDs {
+			if err := tx.Model(&domain.ProjectAttachment{}).
+				Where("id = ? AND project_id = ?", id, projectID).
+				Update("sort_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // Delete 删除附件记录
 func (r *projectAttachmentRepo) Delete(id uint) error {
 	return r.db.Delete(&domain.ProjectAttachment{}, id).Error
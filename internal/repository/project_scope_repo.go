@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type projectScopeRepo struct {
+	db *gorm.DB
+}
+
+// NewProjectScopeRepo 创建项目范围仓库实例
+func NewProjectScopeRepo(db *gorm.DB) domain.ProjectScopeRepository {
+	return &projectScopeRepo{db: db}
+}
+
+// Create 创建范围资产
+func (r *projectScopeRepo) Create(scope *domain.ProjectScope) error {
+	return r.db.Create(scope).Error
+}
+
+// FindByID 根据ID查找范围资产
+func (r *projectScopeRepo) FindByID(id uint) (*domain.ProjectScope, error) {
+	var scope domain.ProjectScope
+	if err := r.db.First(&scope, id).Error; err != nil {
+		return nil, err
+	}
+	return &scope, nil
+}
+
+// ListByProject 获取项目的所有范围资产（范围内在前）
+func (r *projectScopeRepo) ListByProject(projectID uint) ([]domain.ProjectScope, error) {
+	var scopes []domain.ProjectScope
+	err := r.db.Where("project_id = ?", projectID).Order("in_scope DESC, id ASC").Find(&scopes).Error
+	return scopes, err
+}
+
+// Update 更新范围资产
+func (r *projectScopeRepo) Update(scope *domain.ProjectScope) error {
+	return r.db.Save(scope).Error
+}
+
+// Delete 删除范围资产
+func (r *projectScopeRepo) Delete(id uint) error {
+	return r.db.Delete(&domain.ProjectScope{}, id).Error
+}
@@ -74,6 +74,13 @@ func (r *projectTaskRepo) FindByUserID(userID uint) ([]domain.ProjectTask, error
 	return tasks, nil
 }
 
+// FindByProjectID 根据项目ID查找所有任务
+func (r *projectTaskRepo) FindByProjectID(projectID uint) ([]domain.ProjectTask, error) {
+	var tasks []domain.ProjectTask
+	err := r.db.Where("project_id = ?", projectID).Find(&tasks).Error
+	return tasks, err
+}
+
 // FindAcceptedByUserID 获取用户已接受的任务（状态为 accepted）
 func (r *projectTaskRepo) FindAcceptedByUserID(userID uint) ([]domain.ProjectTask, error) {
 	var tasks []domain.ProjectTask
@@ -6,6 +6,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// duplicateBonusPoints 原始报告每被确认一份重复报告获得的额外积分
+const duplicateBonusPoints = 5
+
 type rankingRepo struct {
 	db *gorm.DB
 }
@@ -18,23 +21,33 @@ func (r *rankingRepo) GetGlobalRanking(limit int) ([]domain.RankingItem, error)
 	var items []domain.RankingItem
 
 	// 积分规则：严重40、高危30、中危20、低危10
+	// 有 CVSS 分数且危害等级未被覆盖时，按分数计分（分数 x 4，满分 40）
+	// 原始报告每被确认一份重复报告，额外加 duplicateBonusPoints 分
 	sql := `
 		SELECT 
 			u.id as user_id,
 			u.name as user_name,
 			a.url as avatar_url,
 			COALESCE(SUM(CASE 
+				WHEN r.cvss_score IS NOT NULL AND r.severity_overridden = FALSE THEN ROUND(r.cvss_score * 4)
 				WHEN LOWER(r.severity) = 'critical' THEN 40
 				WHEN LOWER(r.severity) = 'high' THEN 30
 				WHEN LOWER(r.severity) = 'medium' THEN 20
 				WHEN LOWER(r.severity) = 'low' THEN 10
 				ELSE 0 
-			END), 0) as points,
+			END), 0) + COALESCE(SUM(d.dup_count), 0) * ? as points,
 			COUNT(r.id) as vuln_count,
 			COALESCE(SUM(CASE WHEN LOWER(r.severity) = 'critical' THEN 1 ELSE 0 END), 0) as critical_count,
-			COALESCE(SUM(CASE WHEN LOWER(r.severity) = 'high' THEN 1 ELSE 0 END), 0) as high_count
+			COALESCE(SUM(CASE WHEN LOWER(r.severity) = 'high' THEN 1 ELSE 0 END), 0) as high_count,
+			COALESCE(SUM(d.dup_count), 0) as duplicate_count
 		FROM users u
 		LEFT JOIN reports r ON u.id = r.author_id AND LOWER(r.status) IN ('audited', 'triaged', 'resolved', 'closed') AND r.deleted_at IS NULL
+		LEFT JOIN (
+			SELECT duplicate_of_id, COUNT(*) as dup_count
+			FROM reports
+			WHERE status = ? AND duplicate_of_id IS NOT NULL AND deleted_at IS NULL
+			GROUP BY duplicate_of_id
+		) d ON d.duplicate_of_id = r.id
 		LEFT JOIN avatars a ON u.avatar_id = a.id
 		WHERE u.role = 'whitehat'
 		GROUP BY u.id
@@ -42,7 +55,7 @@ func (r *rankingRepo) GetGlobalRanking(limit int) ([]domain.RankingItem, error)
 		LIMIT ?
 	`
 
-	rows, err := r.db.Raw(sql, limit).Rows()
+	rows, err := r.db.Raw(sql, duplicateBonusPoints, domain.ReportStatusDuplicate, limit).Rows()
 	if err != nil {
 		return nil, err
 	}
@@ -60,6 +73,7 @@ func (r *rankingRepo) GetGlobalRanking(limit int) ([]domain.RankingItem, error)
 			&item.VulnCount,
 			&item.CriticalCount,
 			&item.HighCount,
+			&item.DuplicateCount,
 		)
 		if err != nil {
 			return nil, err
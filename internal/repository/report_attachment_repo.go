@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type reportAttachmentRepo struct {
+	db *gorm.DB
+}
+
+// NewReportAttachmentRepo 创建报告附件仓库实例
+func NewReportAttachmentRepo(db *gorm.DB) domain.ReportAttachmentRepository {
+	return &reportAttachmentRepo{db: db}
+}
+
+// Create 创建附件记录（同时写入审计事件）
+func (r *reportAttachmentRepo) Create(attachment *domain.ReportAttachment, events ...domain.ReportEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attachment).Error; err != nil {
+			return err
+		}
+		return createReportEvents(tx, attachment.ReportID, events)
+	})
+}
+
+// FindByID 根据ID查找附件
+func (r *reportAttachmentRepo) FindByID(id uint) (*domain.ReportAttachment, error) {
+	var attachment domain.ReportAttachment
+	if err := r.db.First(&attachment, id).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// FindByReportID 获取报告的全部附件（按上传先后排序）
+func (r *reportAttachmentRepo) FindByReportID(reportID uint) ([]domain.ReportAttachment, error) {
+	var attachments []domain.ReportAttachment
+	err := r.db.Where("report_id = ?", reportID).Order("id ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+// FindByFilePath 查找地址指向指定上传文件的附件（按 URL 后缀匹配）
+func (r *reportAttachmentRepo) FindByFilePath(filePath string) ([]domain.ReportAttachment, error) {
+	var attachments []domain.ReportAttachment
+	err := r.db.Where("url LIKE ?", "%/"+escapeLike(filePath)).Find(&attachments).Error
+	return attachments, err
+}
+
+// CountByReportID 统计报告的附件数量
+func (r *reportAttachmentRepo) CountByReportID(reportID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.ReportAttachment{}).Where("report_id = ?", reportID).Count(&count).Error
+	return count, err
+}
+
+// Delete 删除附件记录（同时写入审计事件）
+func (r *reportAttachmentRepo) Delete(attachment *domain.ReportAttachment, events ...domain.ReportEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&domain.ReportAttachment{}, attachment.ID).Error; err != nil {
+			return err
+		}
+		return createReportEvents(tx, attachment.ReportID, events)
+	})
+}
+
+// ListByScanStatus 获取指定扫描状态的附件（按ID升序）
+func (r *reportAttachmentRepo) ListByScanStatus(status string, afterID uint, limit int) ([]domain.ReportAttachment, error) {
+	var attachments []domain.ReportAttachment
+	err := r.db.Where("scan_status = ? AND id > ?", status, afterID).Order("id ASC").Limit(limit).Find(&attachments).Error
+	return attachments, err
+}
+
+// UpdateScanStatus 更新附件扫描结果（同时写入审计事件）
+func (r *reportAttachmentRepo) UpdateScanStatus(attachment *domain.ReportAttachment, events ...domain.ReportEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.ReportAttachment{}).Where("id = ?", attachment.ID).
+			Updates(map[string]interface{}{
+				"scan_status":    attachment.ScanStatus,
+				"scan_signature": attachment.ScanSignature,
+				"scanned_at":     attachment.ScannedAt,
+			}).Error; err != nil {
+			return err
+		}
+		return createReportEvents(tx, attachment.ReportID, events)
+	})
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type reportEventRepo struct {
+	db *gorm.DB
+}
+
+// NewReportEventRepo 创建报告事件仓库实例
+// 事件的写入由 reportRepo 在报告变更事务中完成，这里只负责查询
+func NewReportEventRepo(db *gorm.DB) domain.ReportEventRepository {
+	return &reportEventRepo{db: db}
+}
+
+// FindByReportID 获取报告的所有事件（按时间正序）
+func (r *reportEventRepo) FindByReportID(reportID uint) ([]domain.ReportEvent, error) {
+	var events []domain.ReportEvent
+	if err := r.db.Where("report_id = ?", reportID).Order("created_at ASC, id ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	// 手动加载操作人信息（同一操作人只查询一次）
+	actors := make(map[uint]*domain.User)
+	for i := range events {
+		actorID := events[i].ActorID
+		if actorID == 0 {
+			continue
+		}
+		if _, ok := actors[actorID]; !ok {
+			var user domain.User
+			if err := r.db.First(&user, actorID).Error; err == nil {
+				actors[actorID] = &user
+			} else {
+				actors[actorID] = nil
+			}
+		}
+		events[i].Actor = actors[actorID]
+	}
+
+	return events, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"bug-bounty-lite/internal/domain"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -14,9 +15,33 @@ func NewReportRepo(db *gorm.DB) domain.ReportRepository {
 	return &reportRepo{db: db}
 }
 
-// Create 创建报告
-func (r *reportRepo) Create(report *domain.Report) error {
-	return r.db.Create(report).Error
+// Create 创建报告（同时写入审计事件）
+func (r *reportRepo) Create(report *domain.Report, events ...domain.ReportEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(report).Error; err != nil {
+			return err
+		}
+		return createReportEvents(tx, report.ID, events)
+	})
+}
+
+// createReportEvents 在事务中写入报告审计事件
+func createReportEvents(tx *gorm.DB, reportID uint, events []domain.ReportEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	for i := range events {
+		events[i].ReportID = reportID
+	}
+	return tx.Create(&events).Error
+}
+
+// loadAttachments 加载报告附件（仅详情查询加载，列表不加载）
+func (r *reportRepo) loadAttachments(report *domain.Report) {
+	var attachments []domain.ReportAttachment
+	if err := r.db.Where("report_id = ?", report.ID).Order("id ASC").Find(&attachments).Error; err == nil {
+		report.Attachments = attachments
+	}
 }
 
 // loadAssociations 手动加载关联数据（因为移除了外键）
@@ -64,6 +89,7 @@ func (r *reportRepo) FindByID(id uint) (*domain.Report, error) {
 	}
 	// 手动加载关联数据
 	r.loadAssociations(&report)
+	r.loadAttachments(&report)
 	return &report, nil
 }
 
@@ -75,6 +101,7 @@ func (r *reportRepo) FindByIDWithDeleted(id uint) (*domain.Report, error) {
 	}
 	// 手动加载关联数据
 	r.loadAssociations(&report)
+	r.loadAttachments(&report)
 	return &report, nil
 }
 
@@ -113,17 +140,45 @@ func (r *reportRepo) List(page, pageSize int, authorID *uint, keyword string) ([
 	return reports, total, nil
 }
 
-// Update 更新报告
-func (r *reportRepo) Update(report *domain.Report) error {
-	return r.db.Save(report).Error
+// ListByProject 获取项目下最近的报告
+// 仅查询报告本身，不加载关联数据，用于重复检测比对
+func (r *reportRepo) ListByProject(projectID uint, limit int) ([]domain.Report, error) {
+	var reports []domain.Report
+	err := r.db.Where("project_id = ?", projectID).Order("id desc").Limit(limit).Find(&reports).Error
+	return reports, err
+}
+
+// Update 更新报告（同时写入审计事件）
+func (r *reportRepo) Update(report *domain.Report, events ...domain.ReportEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(report).Error; err != nil {
+			return err
+		}
+		return createReportEvents(tx, report.ID, events)
+	})
 }
 
-// Delete 软删除报告
-func (r *reportRepo) Delete(id uint) error {
-	return r.db.Delete(&domain.Report{}, id).Error
+// Delete 软删除报告（同时写入审计事件）
+func (r *reportRepo) Delete(id uint, events ...domain.ReportEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&domain.Report{}, id).Error; err != nil {
+			return err
+		}
+		return createReportEvents(tx, id, events)
+	})
+}
+
+// Restore 恢复已删除的报告（同时写入审计事件）
+func (r *reportRepo) Restore(id uint, events ...domain.ReportEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&domain.Report{}).Where("id = ?", id).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return createReportEvents(tx, id, events)
+	})
 }
 
-// Restore 恢复已删除的报告
-func (r *reportRepo) Restore(id uint) error {
-	return r.db.Unscoped().Model(&domain.Report{}).Where("id = ?", id).Update("deleted_at", nil).Error
+// escapeLike 转义 LIKE 模式中的通配符（MySQL 默认转义字符为反斜杠）
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
 }
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type reportSimilarityRepo struct {
+	db *gorm.DB
+}
+
+// NewReportSimilarityRepo 创建疑似重复记录仓库实例
+func NewReportSimilarityRepo(db *gorm.DB) domain.ReportSimilarityRepository {
+	return &reportSimilarityRepo{db: db}
+}
+
+// Create 批量保存疑似重复记录
+func (r *reportSimilarityRepo) Create(similarities []domain.ReportSimilarity) error {
+	if len(similarities) == 0 {
+		return nil
+	}
+	return r.db.Create(&similarities).Error
+}
+
+// FindByReportID 获取报告的疑似重复记录（按相似度降序）
+// 已被删除的候选报告不返回
+func (r *reportSimilarityRepo) FindByReportID(reportID uint) ([]domain.ReportSimilarity, error) {
+	var similarities []domain.ReportSimilarity
+	if err := r.db.Where("report_id = ?", reportID).Order("score DESC, id ASC").Find(&similarities).Error; err != nil {
+		return nil, err
+	}
+
+	if len(similarities) == 0 {
+		return similarities, nil
+	}
+
+	// 一次查询加载全部候选报告
+	candidateIDs := make([]uint, 0, len(similarities))
+	for _, similarity := range similarities {
+		candidateIDs = append(candidateIDs, similarity.CandidateID)
+	}
+	var candidates []domain.Report
+	if err := r.db.Where("id IN ?", candidateIDs).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	candidateMap := make(map[uint]*domain.Report, len(candidates))
+	for i := range candidates {
+		candidateMap[candidates[i].ID] = &candidates[i]
+	}
+
+	result := make([]domain.ReportSimilarity, 0, len(similarities))
+	for i := range similarities {
+		candidate, ok := candidateMap[similarities[i].CandidateID]
+		if !ok {
+			continue
+		}
+		similarities[i].Candidate = candidate
+		result = append(result, similarities[i])
+	}
+
+	return result, nil
+}
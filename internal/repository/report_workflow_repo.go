@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type reportWorkflowRepo struct {
+	db *gorm.DB
+}
+
+// NewReportWorkflowRepo 创建工作流仓库实例
+func NewReportWorkflowRepo(db *gorm.DB) domain.ReportWorkflowRepository {
+	return &reportWorkflowRepo{db: db}
+}
+
+// FindByProjectID 根据项目ID查找工作流（0 为全局默认），不存在时返回 nil
+func (r *reportWorkflowRepo) FindByProjectID(projectID uint) (*domain.ReportWorkflow, error) {
+	var workflow domain.ReportWorkflow
+	if err := r.db.Where("project_id = ?", projectID).First(&workflow).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+// Save 创建或更新工作流
+func (r *reportWorkflowRepo) Save(workflow *domain.ReportWorkflow) error {
+	return r.db.Save(workflow).Error
+}
+
+// DeleteByProjectID 删除项目的自定义工作流
+func (r *reportWorkflowRepo) DeleteByProjectID(projectID uint) error {
+	return r.db.Where("project_id = ?", projectID).Delete(&domain.ReportWorkflow{}).Error
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type securityEventRepo struct {
+	db *gorm.DB
+}
+
+// NewSecurityEventRepo 创建安全事件仓库实例
+func NewSecurityEventRepo(db *gorm.DB) domain.SecurityEventRepository {
+	return &securityEventRepo{db: db}
+}
+
+// Create 记录安全事件
+func (r *securityEventRepo) Create(event *domain.SecurityEvent) error {
+	return r.db.Create(event).Error
+}
+
+// List 分页查询安全事件（按时间倒序）
+func (r *securityEventRepo) List(filter domain.SecurityEventFilter, page, pageSize int) ([]domain.SecurityEvent, int64, error) {
+	var events []domain.SecurityEvent
+	var total int64
+
+	query := r.db.Model(&domain.SecurityEvent{})
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Username != "" {
+		query = query.Where("username = ?", filter.Username)
+	}
+	if filter.IP != "" {
+		query = query.Where("ip = ?", filter.IP)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&events).Error
+	return events, total, err
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type twoFactorRepo struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepo 创建两步验证仓库实例
+func NewTwoFactorRepo(db *gorm.DB) domain.TwoFactorRepository {
+	return &twoFactorRepo{db: db}
+}
+
+// FindByUserID 获取用户的两步验证配置，未配置时返回 nil
+func (r *twoFactorRepo) FindByUserID(userID uint) (*domain.UserTwoFactor, error) {
+	var tf domain.UserTwoFactor
+	if err := r.db.Where("user_id = ?", userID).First(&tf).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tf, nil
+}
+
+// Save 保存两步验证配置
+func (r *twoFactorRepo) Save(tf *domain.UserTwoFactor) error {
+	return r.db.Save(tf).Error
+}
+
+// Delete 删除两步验证配置及恢复码
+func (r *twoFactorRepo) Delete(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&domain.UserTwoFactor{}).Error
+	})
+}
+
+// UseStep 记录验证成功的时间步
+func (r *twoFactorRepo) UseStep(userID uint, step int64) (bool, error) {
+	result := r.db.Model(&domain.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReplaceRecoveryCodes 删除旧恢复码并保存新恢复码
+func (r *twoFactorRepo) ReplaceRecoveryCodes(userID uint, codes []domain.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode 将未使用的恢复码标记为已使用
+func (r *twoFactorRepo) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	result := r.db.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountRecoveryCodes 统计未使用的恢复码数量
+func (r *twoFactorRepo) CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// CreateChallenge 保存登录挑战
+func (r *twoFactorRepo) CreateChallenge(challenge *domain.LoginChallenge) error {
+	return r.db.Create(challenge).Error
+}
+
+// FindChallenge 根据挑战令牌哈希查找登录挑战
+func (r *twoFactorRepo) FindChallenge(id string) (*domain.LoginChallenge, error) {
+	var challenge domain.LoginChallenge
+	if err := r.db.Where("id = ?", id).First(&challenge).Error; err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// ConsumeChallengeAttempt 以提交次数未达上限为条件加一，并发请求中只有前 maxAttempts 个能成功
+func (r *twoFactorRepo) ConsumeChallengeAttempt(id string, maxAttempts int) (bool, error) {
+	result := r.db.Model(&domain.LoginChallenge{}).
+		Where("id = ? AND attempts < ?", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteChallenge 删除登录挑战
+func (r *twoFactorRepo) DeleteChallenge(id string) error {
+	return r.db.Where("id = ?", id).Delete(&domain.LoginChallenge{}).Error
+}
+
+// DeleteExpiredChallenges 删除已过期的登录挑战
+func (r *twoFactorRepo) DeleteExpiredChallenges(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&domain.LoginChallenge{})
+	return result.RowsAffected, result.Error
+}
+
+// GetSetting 获取安全设置，未设置时返回 nil
+func (r *twoFactorRepo) GetSetting(key string) (*domain.SecuritySetting, error) {
+	var setting domain.SecuritySetting
+	if err := r.db.Where("`key` = ?", key).First(&setting).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// SaveSetting 保存安全设置
+func (r *twoFactorRepo) SaveSetting(setting *domain.SecuritySetting) error {
+	return r.db.Save(setting).Error
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 上传文件地址特征（正文中只需查询包含这些片段的记录）
+var uploadURLPatterns = []string{"%/api/v1/files/%", "%/uploads/%"}
+
+type uploadReferenceRepo struct {
+	db *gorm.DB
+}
+
+// NewUploadReferenceRepo 创建上传文件引用查询仓库实例
+func NewUploadReferenceRepo(db *gorm.DB) domain.UploadReferenceRepository {
+	return &uploadReferenceRepo{db: db}
+}
+
+// ListReferences 获取所有引用上传文件的字段值
+func (r *uploadReferenceRepo) ListReferences(deletedBefore time.Time) (*domain.UploadReferences, error) {
+	refs := &domain.UploadReferences{}
+
+	// 1. 报告附件和项目附件（所属报告/项目软删除超过保留期的不算引用）
+	var urls []string
+	if err := r.db.Table("report_attachments").
+		Joins("JOIN reports ON reports.id = report_attachments.report_id").
+		Where("reports.deleted_at IS NULL OR reports.deleted_at > ?", deletedBefore).
+		Pluck("report_attachments.url", &urls).Error; err != nil {
+		return nil, err
+	}
+	refs.URLs = append(refs.URLs, urls...)
+
+	urls = nil
+	if err := r.db.Table("project_attachments").
+		Joins("JOIN projects ON projects.id = project_attachments.project_id").
+		Where("projects.deleted_at IS NULL OR projects.deleted_at > ?", deletedBefore).
+		Pluck("project_attachments.url", &urls).Error; err != nil {
+		return nil, err
+	}
+	refs.URLs = append(refs.URLs, urls...)
+
+	// 2. 头像及其缩略图
+	for _, column := range []string{"url", "thumbnail_url"} {
+		urls = nil
+		if err := r.db.Model(&domain.Avatar{}).Where(column+" <> ''").Pluck(column, &urls).Error; err != nil {
+			return nil, err
+		}
+		refs.URLs = append(refs.URLs, urls...)
+	}
+
+	// 3. 正文中引用的文件（通过 POST /api/v1/upload 上传后插入正文或填入链接）
+	for _, column := range []string{"vulnerability_detail", "vulnerability_impact", "vulnerability_url"} {
+		reports := r.db.Unscoped().Model(&domain.Report{}).
+			Where("deleted_at IS NULL OR deleted_at > ?", deletedBefore)
+		texts, err := r.pluckTexts(reports, column)
+		if err != nil {
+			return nil, err
+		}
+		refs.Texts = append(refs.Texts, texts...)
+	}
+	for _, column := range []string{"description", "note"} {
+		projects := r.db.Unscoped().Model(&domain.Project{}).
+			Where("deleted_at IS NULL OR deleted_at > ?", deletedBefore)
+		texts, err := r.pluckTexts(projects, column)
+		if err != nil {
+			return nil, err
+		}
+		refs.Texts = append(refs.Texts, texts...)
+	}
+	for _, model := range []interface{}{&domain.ReportComment{}, &domain.Article{}, &domain.ArticleComment{}} {
+		texts, err := r.pluckTexts(r.db.Model(model), "content")
+		if err != nil {
+			return nil, err
+		}
+		refs.Texts = append(refs.Texts, texts...)
+	}
+	// 系统配置的扩展数据（如图标地址）
+	texts, err := r.pluckTexts(r.db.Model(&domain.SystemConfig{}), "extra_data")
+	if err != nil {
+		return nil, err
+	}
+	refs.Texts = append(refs.Texts, texts...)
+
+	// 4. 上传会话：已合并的文件在会话过期前可关联为附件，未完成会话的分片仍在上传
+	var sessions []domain.UploadSession
+	if err := r.db.Select("id", "status", "file_path").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		refs.Sessions = append(refs.Sessions, session.ID)
+		if session.FilePath != "" {
+			refs.Keys = append(refs.Keys, session.FilePath)
+		}
+	}
+
+	return refs, nil
+}
+
+// pluckTexts 取出包含上传文件地址的正文
+func (r *uploadReferenceRepo) pluckTexts(query *gorm.DB, column string) ([]string, error) {
+	var texts []string
+	err := query.Where("("+column+" LIKE ? OR "+column+" LIKE ?)", uploadURLPatterns[0], uploadURLPatterns[1]).
+		Pluck(column, &texts).Error
+	return texts, err
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type uploadSessionRepo struct {
+	db *gorm.DB
+}
+
+// NewUploadSessionRepo 创建上传会话仓库实例
+func NewUploadSessionRepo(db *gorm.DB) domain.UploadSessionRepository {
+	return &uploadSessionRepo{db: db}
+}
+
+// Create 创建上传会话
+func (r *uploadSessionRepo) Create(session *domain.UploadSession) error {
+	return r.db.Create(session).Error
+}
+
+// FindByID 根据ID查找上传会话
+func (r *uploadSessionRepo) FindByID(id string) (*domain.UploadSession, error) {
+	var session domain.UploadSession
+	if err := r.db.Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// AdvanceOffset 以已接收字节数作为条件更新上传进度
+func (r *uploadSessionRepo) AdvanceOffset(session *domain.UploadSession, fromOffset int64) (bool, error) {
+	result := r.db.Model(&domain.UploadSession{}).
+		Where("id = ? AND received_size = ? AND status = ?", session.ID, fromOffset, domain.UploadSessionPending).
+		Updates(map[string]interface{}{
+			"received_size": session.ReceivedSize,
+			"hash_state":    session.HashState,
+			"mime_type":     session.MimeType,
+			"expires_at":    session.ExpiresAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Update 更新上传会话
+func (r *uploadSessionRepo) Update(session *domain.UploadSession) error {
+	return r.db.Save(session).Error
+}
+
+// Delete 删除上传会话
+func (r *uploadSessionRepo) Delete(id string) error {
+	return r.db.Where("id = ?", id).Delete(&domain.UploadSession{}).Error
+}
+
+// ListExpired 获取已过期的上传会话
+func (r *uploadSessionRepo) ListExpired(before time.Time, limit int) ([]domain.UploadSession, error) {
+	var sessions []domain.UploadSession
+	err := r.db.Where("expires_at < ?", before).Order("expires_at ASC").Limit(limit).Find(&sessions).Error
+	return sessions, err
+}
@@ -80,6 +80,23 @@ func (r *userRepo) FindByID(id uint) (*domain.User, error) {
 	return &user, nil
 }
 
+// FindByIDs 根据ID批量查找（不加载组织和头像）
+func (r *userRepo) FindByIDs(ids []uint) ([]domain.User, error) {
+	var users []domain.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("id IN ?", ids).Order("id ASC").Find(&users).Error
+	return users, err
+}
+
+// FindByOrgID 查找组织下的所有用户（不加载组织和头像）
+func (r *userRepo) FindByOrgID(orgID uint) ([]domain.User, error) {
+	var users []domain.User
+	err := r.db.Where("org_id = ?", orgID).Order("id ASC").Find(&users).Error
+	return users, err
+}
+
 func (r *userRepo) Update(user *domain.User) error {
 	// 使用 Save 进行全量更新
 	return r.db.Save(user).Error
@@ -115,3 +132,40 @@ func (r *userRepo) UpdateProfileFields(userID uint, name, bio, phone, email stri
 func (r *userRepo) UpdateAvatarID(userID uint, avatarID uint) error {
 	return r.db.Model(&domain.User{}).Where("id = ?", userID).Update("avatar_id", avatarID).Error
 }
+
+// MarkEmailVerified 以邮箱未变更为条件记录邮箱验证时间
+func (r *userRepo) MarkEmailVerified(userID uint, email string, verifiedAt time.Time) (bool, error) {
+	result := r.db.Model(&domain.User{}).Where("id = ? AND email = ?", userID, email).
+		Update("email_verified_at", verifiedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClearEmailVerified 清空邮箱验证时间
+func (r *userRepo) ClearEmailVerified(userID uint) error {
+	return r.db.Model(&domain.User{}).Where("id = ?", userID).Update("email_verified_at", nil).Error
+}
+
+// FindByEmail 根据邮箱查找（同一邮箱可能绑定多个账号，不加载组织和头像）
+func (r *userRepo) FindByEmail(email string) ([]domain.User, error) {
+	var users []domain.User
+	err := r.db.Where("email = ?", email).Order("id ASC").Find(&users).Error
+	return users, err
+}
+
+// GetTokenVersion 获取用户当前的令牌版本
+func (r *userRepo) GetTokenVersion(userID uint) (int, error) {
+	var user domain.User
+	if err := r.db.Select("id", "token_version").First(&user, userID).Error; err != nil {
+		return 0, err
+	}
+	return user.TokenVersion, nil
+}
+
+// IncrementTokenVersion 令牌版本加一，使已签发的访问令牌全部失效
+func (r *userRepo) IncrementTokenVersion(userID uint) error {
+	return r.db.Model(&domain.User{}).Where("id = ?", userID).
+		Update("token_version", gorm.Expr("token_version + 1")).Error
+}
@@ -5,14 +5,20 @@ import (
 	"bug-bounty-lite/internal/middleware"
 	"bug-bounty-lite/internal/repository"
 	"bug-bounty-lite/internal/service"
+	"bug-bounty-lite/pkg/breached"
 	"bug-bounty-lite/pkg/config"
 	"bug-bounty-lite/pkg/jwt"
+	"bug-bounty-lite/pkg/lockout"
+	"bug-bounty-lite/pkg/mailer"
+	"bug-bounty-lite/pkg/oidc"
+	"bug-bounty-lite/pkg/upload"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
+func SetupRouter(db *gorm.DB, cfg *config.Config, storage upload.Storage, scanner upload.Scanner, mail mailer.Mailer, attempts lockout.Store, breachedList breached.Source, oidcProviders []*oidc.Provider) *gin.Engine {
 	// 设置 Gin 模式
 	if cfg.Server.Mode == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -25,12 +31,7 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	r.SetTrustedProxies(nil)
 
 	// ===========================
-	// 1. 静态文件服务（用于访问上传的文件）
-	// ===========================
-	r.Static("/uploads", "./uploads")
-
-	// ===========================
-	// 2. 全局中间件
+	// 1. 全局中间件
 	// ===========================
 	r.Use(gin.Recovery())
 	r.Use(middleware.CORSMiddleware())
@@ -42,23 +43,81 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	}
 
 	// ===========================
-	// 3. 初始化 JWT 管理器
+	// 2. 初始化 JWT 管理器
 	// ===========================
 	jwtManager := jwt.NewJWTManager(cfg.JWT.Secret, cfg.JWT.Expire)
 
+	// 上传文件下载链接签名器（未单独配置密钥时使用 JWT 密钥）
+	signingSecret := cfg.Upload.SigningSecret
+	if signingSecret == "" {
+		signingSecret = cfg.JWT.Secret
+	}
+	urlSigner := upload.NewURLSigner(signingSecret, time.Duration(cfg.Upload.SignedURLTTL)*time.Second)
+	uploader := upload.NewUploader(storage)
+
+	// 大文件分片上传（后台定时清理过期会话）
+	uploadSessionRepo := repository.NewUploadSessionRepo(db)
+	uploadSessionService := service.NewUploadSessionService(uploadSessionRepo, storage, cfg.Upload.Chunked)
+	service.StartUploadSessionCleanup(uploadSessionService, time.Hour)
+
+	// 孤儿上传文件定时回收（可选）
+	if cfg.Upload.GC.Enabled {
+		uploadGCService := service.NewUploadGCService(repository.NewUploadReferenceRepo(db), uploadSessionService, storage)
+		service.StartUploadGC(uploadGCService, cfg.Upload.GC)
+	}
+
 	// ===========================
-	// 4. 依赖注入 (组装层)
+	// 3. 依赖注入 (组装层)
 	// ===========================
 
 	// User 模块
 	userRepo := repository.NewUserRepo(db)
 	orgRepo := repository.NewOrganizationRepo(db)
 	userUpdateLogRepo := repository.NewUserUpdateLogRepo(db)
+	apiKeyRepo := repository.NewAPIKeyRepo(db)
 
 	organizationService := service.NewOrganizationService(orgRepo)
 	organizationHandler := handler.NewOrganizationHandler(organizationService)
 
-	userService := service.NewUserService(userRepo, orgRepo, userUpdateLogRepo, jwtManager)
+	// 访问令牌 + 轮换刷新令牌（后台定时清理过期的令牌记录，退出全部会话时一并吊销 API Key）
+	authTokenService := service.NewAuthTokenService(repository.NewAuthTokenRepo(db), apiKeyRepo, userRepo, jwtManager, cfg.JWT.RefreshExpire)
+	service.StartAuthTokenCleanup(authTokenService, time.Hour)
+	authHandler := handler.NewAuthHandler(authTokenService)
+
+	// 登录防暴力破解（按用户名和 IP 统计失败次数，锁定时记录安全事件）
+	loginGuardService := service.NewLoginGuardService(attempts, repository.NewSecurityEventRepo(db), cfg.Auth.Lockout)
+	securityHandler := handler.NewSecurityHandler(loginGuardService)
+
+	// TOTP 两步验证（后台定时清理过期的登录挑战，验证码错误计入登录失败次数）
+	twoFactorService := service.NewTwoFactorService(repository.NewTwoFactorRepo(db), userRepo, authTokenService, loginGuardService, cfg.Auth.TwoFactor)
+	service.StartTwoFactorCleanup(twoFactorService, time.Hour)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorService)
+
+	// 密码策略（长度、字符类别、历史密码、泄露密码库）
+	passwordPolicyService := service.NewPasswordPolicyService(repository.NewPasswordHistoryRepo(db), cfg.Auth.Password, breachedList)
+
+	// 邮箱验证与找回密码（后台定时清理过期的令牌）
+	accountService := service.NewAccountService(repository.NewAccountTokenRepo(db), userRepo, authTokenService, mail, passwordPolicyService, cfg.Auth.Email)
+	service.StartAccountTokenCleanup(accountService, time.Hour)
+	accountHandler := handler.NewAccountHandler(accountService)
+
+	// OIDC 单点登录（后台定时清理过期的登录状态）
+	oidcService := service.NewOIDCService(repository.NewOIDCRepo(db), userRepo, authTokenService, twoFactorService, oidcProviders, cfg.Auth.OIDC)
+	service.StartOIDCStateCleanup(oidcService, time.Hour)
+	oidcHandler := handler.NewOIDCHandler(oidcService)
+
+	// 个人 API Key（脚本通过 X-API-Key 调用，后台定时清理过期和已吊销的 Key）
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, cfg.Auth.APIKeys)
+	service.StartAPIKeyCleanup(apiKeyService, time.Hour)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+
+	// 未验证邮箱的账号不能提交报告（auth.email.require_verified）
+	requireVerifiedEmail := func(c *gin.Context) { c.Next() }
+	if cfg.Auth.Email.RequireVerified {
+		requireVerifiedEmail = middleware.RequireVerifiedEmail(accountService)
+	}
+
+	userService := service.NewUserService(userRepo, orgRepo, userUpdateLogRepo, authTokenService, twoFactorService, accountService, loginGuardService, passwordPolicyService)
 	userHandler := handler.NewUserHandler(userService)
 
 	// SystemConfig 模块（需要在 Report 之前初始化，因为 Report 依赖它）
@@ -66,10 +125,33 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	systemConfigService := service.NewSystemConfigService(systemConfigRepo)
 	systemConfigHandler := handler.NewSystemConfigHandler(systemConfigService)
 
+	// Project 仓库（Report 工作流需要校验项目）
+	projectRepo := repository.NewProjectRepo(db)
+
+	// ProjectScope 模块（提交报告时校验漏洞链接是否在范围内）
+	projectScopeRepo := repository.NewProjectScopeRepo(db)
+	projectScopeService := service.NewProjectScopeService(projectScopeRepo, projectRepo)
+	projectScopeHandler := handler.NewProjectScopeHandler(projectScopeService)
+
 	// Report 模块
+	reportWorkflowRepo := repository.NewReportWorkflowRepo(db)
+	reportWorkflowService := service.NewReportWorkflowService(reportWorkflowRepo, projectRepo)
 	reportRepo := repository.NewReportRepo(db)
-	reportService := service.NewReportService(reportRepo, systemConfigRepo)
-	reportHandler := handler.NewReportHandler(reportService)
+	reportSimilarityRepo := repository.NewReportSimilarityRepo(db)
+	reportService := service.NewReportService(reportRepo, systemConfigRepo, reportWorkflowService, reportSimilarityRepo, projectRepo, projectScopeService)
+	reportHandler := handler.NewReportHandler(reportService, urlSigner)
+	reportAttachmentRepo := repository.NewReportAttachmentRepo(db)
+	reportAttachmentService := service.NewReportAttachmentService(reportAttachmentRepo, reportRepo)
+	attachmentScanService := service.NewAttachmentScanService(reportAttachmentRepo, storage, scanner)
+	reportAttachmentHandler := handler.NewReportAttachmentHandler(reportAttachmentService, uploadSessionService, attachmentScanService, uploader, urlSigner)
+
+	// 扫描器不可用时附件保持待扫描状态，后台定时重新扫描
+	rescanPeriod := time.Duration(cfg.Upload.Scanner.RescanPeriod) * time.Second
+	if rescanPeriod <= 0 {
+		rescanPeriod = 5 * time.Minute
+	}
+	service.StartAttachmentRescan(attachmentScanService, rescanPeriod)
+	reportWorkflowHandler := handler.NewReportWorkflowHandler(reportWorkflowService, reportService)
 
 	// UserInfoChange 模块
 	userInfoChangeRepo := repository.NewUserInfoChangeRepo(db)
@@ -77,23 +159,33 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	userInfoChangeHandler := handler.NewUserInfoChangeHandler(userInfoChangeService)
 
 	// Project 模块
-	projectRepo := repository.NewProjectRepo(db)
-	projectService := service.NewProjectService(projectRepo)
+	projectService := service.NewProjectService(projectRepo, systemConfigRepo)
 	projectHandler := handler.NewProjectHandler(projectService)
 
 	// Upload 模块
-	uploadHandler := handler.NewUploadHandler()
+	uploadHandler := handler.NewUploadHandler(uploader, attachmentScanService, urlSigner)
+	uploadSessionHandler := handler.NewUploadSessionHandler(uploadSessionService, attachmentScanService, urlSigner)
 
 	// Avatar 模块
 	avatarRepo := repository.NewAvatarRepo(db)
 	avatarService := service.NewAvatarService(avatarRepo)
-	avatarHandler := handler.NewAvatarHandler(avatarService)
+	avatarHandler := handler.NewAvatarHandler(avatarService, uploader)
 
 	// Comment 模块
 	commentRepo := repository.NewCommentRepo(db)
 	commentService := service.NewCommentService(commentRepo, reportRepo)
 	commentHandler := handler.NewCommentHandler(commentService)
 
+	// 报告时间线（审计事件 + 评论）
+	reportEventRepo := repository.NewReportEventRepo(db)
+	reportTimelineService := service.NewReportTimelineService(reportEventRepo, commentRepo, reportRepo)
+	reportTimelineHandler := handler.NewReportTimelineHandler(reportTimelineService)
+
+	// Bounty 模块（奖励账本与发放批次）
+	bountyRepo := repository.NewBountyRepo(db)
+	bountyService := service.NewBountyService(bountyRepo, reportRepo, projectScopeRepo)
+	bountyHandler := handler.NewBountyHandler(bountyService)
+
 	// Article 模块
 	articleRepo := repository.NewArticleRepo(db)
 	articleViewRepo := repository.NewArticleViewRepo(db)
@@ -111,7 +203,13 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	projectTaskRepo := repository.NewProjectTaskRepository(db)
 	projectAttachmentRepo := repository.NewProjectAttachmentRepository(db)
 	projectTaskService := service.NewProjectTaskService(projectTaskRepo, projectAssignmentRepo, projectRepo)
-	projectTaskHandler := handler.NewProjectTaskHandler(projectTaskService, projectTaskRepo, projectAssignmentRepo, projectRepo, projectAttachmentRepo)
+	projectTaskHandler := handler.NewProjectTaskHandler(projectTaskService, projectTaskRepo, projectAssignmentRepo, projectRepo, projectAttachmentRepo, urlSigner)
+	projectAttachmentService := service.NewProjectAttachmentService(projectAttachmentRepo, projectRepo, projectAssignmentRepo)
+	projectAttachmentHandler := handler.NewProjectAttachmentHandler(projectAttachmentService, uploader, urlSigner)
+
+	// File 模块（上传文件受控下载）
+	fileAccessService := service.NewFileAccessService(reportRepo, reportAttachmentRepo, projectAttachmentRepo, projectAssignmentRepo)
+	fileHandler := handler.NewFileHandler(fileAccessService, storage, urlSigner)
 
 	// Dashboard 模块（仪表盘/首页统计）
 	dashboardRepo := repository.NewDashboardRepo(db)
@@ -123,10 +221,22 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	rankingService := service.NewRankingService(rankingRepo)
 	rankingHandler := handler.NewRankingHandler(rankingService)
 
+	// Notification 模块（站内通知）
+	notificationRepo := repository.NewNotificationRepo(db)
+	notificationService := service.NewNotificationService(notificationRepo)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+
+	// ProjectAssignment 模块（管理员批量指派项目，依赖排行榜和通知）
+	projectAssignmentService := service.NewProjectAssignmentService(projectAssignmentRepo, projectTaskRepo, projectRepo, userRepo, rankingRepo, notificationService)
+	projectAssignmentHandler := handler.NewProjectAssignmentHandler(projectAssignmentService)
+
 	// ===========================
-	// 5. 注册路由
+	// 4. 注册路由
 	// ===========================
 
+	// 公开头像（从存储后端读取，报告和项目附件通过 /api/v1/files 受控下载）
+	r.GET("/uploads/avatars/*filepath", fileHandler.Avatar)
+
 	api := r.Group("/api/v1")
 	{
 		// 公开路由 - 排行榜
@@ -136,22 +246,50 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 		{
 			auth.POST("/register", userHandler.Register)
 			auth.POST("/login", userHandler.Login)
+			auth.POST("/login/2fa", twoFactorHandler.VerifyLogin)                                                            // 登录第二步：验证码或恢复码
+			auth.POST("/2fa/setup", twoFactorHandler.SetupWithChallenge)                                                     // 角色要求两步验证时获取绑定密钥
+			auth.POST("/2fa/enable", twoFactorHandler.EnableWithChallenge)                                                   // 确认绑定并完成登录
+			auth.POST("/forgot-password", accountHandler.ForgotPassword)                                                     // 发送重置密码邮件
+			auth.POST("/reset-password", accountHandler.ResetPassword)                                                       // 使用邮件令牌重置密码
+			auth.POST("/verify-email", accountHandler.VerifyEmail)                                                           // 使用邮件令牌验证邮箱
+			auth.POST("/refresh", authHandler.Refresh)                                                                       // 刷新令牌
+			auth.GET("/oidc/providers", oidcHandler.Providers)                                                               // 单点登录身份提供方列表
+			auth.GET("/oidc/:provider/authorize", oidcHandler.Authorize)                                                     // 获取身份提供方授权地址
+			auth.POST("/oidc/:provider/callback", oidcHandler.Callback)                                                      // 提交授权码完成单点登录
+			auth.POST("/logout", middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService), authHandler.Logout) // 注销当前会话
 		}
 
 		// 用户个人管理路由
 		user := api.Group("/user")
-		user.Use(middleware.AuthMiddleware(jwtManager))
+		user.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
 		{
 			user.GET("/profile", userHandler.GetProfile)
 			user.POST("/profile", userHandler.UpdateProfile)
 			user.POST("/bind-org", userHandler.BindOrganization)
 			user.POST("/change-password", userHandler.ChangePassword)
-			user.POST("/avatar", userHandler.UpdateAvatar) // 用户选择头像
+			user.POST("/avatar", userHandler.UpdateAvatar)                   // 用户选择头像
+			user.GET("/earnings", bountyHandler.GetEarnings)                 // 我的收益
+			user.POST("/logout-all", authHandler.LogoutAll)                  // 退出全部会话并吊销 API Key
+			user.GET("/sessions", authHandler.ListSessions)                  // 我的登录会话
+			user.DELETE("/sessions/:id", authHandler.RevokeSession)          // 注销指定会话
+			user.POST("/verify-email", accountHandler.SendVerificationEmail) // 重新发送邮箱验证邮件
+
+			// 个人 API Key
+			user.GET("/api-keys", apiKeyHandler.List)          // 我的 API Key
+			user.POST("/api-keys", apiKeyHandler.Create)       // 创建 API Key（明文只返回一次）
+			user.DELETE("/api-keys/:id", apiKeyHandler.Revoke) // 吊销 API Key
+
+			// 两步验证
+			user.GET("/2fa", twoFactorHandler.GetStatus)                               // 两步验证状态
+			user.POST("/2fa/setup", twoFactorHandler.Setup)                            // 生成密钥和二维码地址
+			user.POST("/2fa/enable", twoFactorHandler.Enable)                          // 确认绑定并获取恢复码
+			user.POST("/2fa/disable", twoFactorHandler.Disable)                        // 关闭两步验证
+			user.POST("/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes) // 重新生成恢复码
 		}
 
 		// 组织管理路由（限管理员可用逻辑待后续细化，目前先挂载）
 		orgs := api.Group("/organizations")
-		orgs.Use(middleware.AuthMiddleware(jwtManager))
+		orgs.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
 		{
 			orgs.POST("", organizationHandler.Create)
 			orgs.GET("", organizationHandler.List)
@@ -161,24 +299,56 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 
 		// 需要认证的路由 - Reports
 		reports := api.Group("/reports")
-		reports.Use(middleware.AuthMiddleware(jwtManager))
+		reports.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
 		{
-			reports.POST("", reportHandler.CreateHandler)              // 提交
-			reports.GET("", reportHandler.ListHandler)                 // 列表
-			reports.GET("/:id", reportHandler.GetHandler)              // 详情
-			reports.PUT("/:id", reportHandler.UpdateHandler)           // 更新
-			reports.DELETE("/:id", reportHandler.DeleteHandler)        // 软删除
-			reports.POST("/:id/restore", reportHandler.RestoreHandler) // 恢复已删除
+			reports.POST("", requireVerifiedEmail, reportHandler.CreateHandler) // 提交
+			reports.GET("", reportHandler.ListHandler)                          // 列表
+			reports.GET("/:id", reportHandler.GetHandler)                       // 详情
+			reports.PUT("/:id", reportHandler.UpdateHandler)                    // 更新
+			reports.DELETE("/:id", reportHandler.DeleteHandler)                 // 软删除
+			reports.POST("/:id/restore", reportHandler.RestoreHandler)          // 恢复已删除
+			reports.GET("/:id/duplicates", reportHandler.ListDuplicatesHandler) // 疑似重复列表
+			reports.POST("/:id/duplicate", reportHandler.MarkDuplicateHandler)  // 标记为重复
+
+			// 报告附件（报告作者/admin/vendor）
+			reports.GET("/:id/attachments", reportAttachmentHandler.ListAttachments)                   // 获取附件列表
+			reports.POST("/:id/attachments", reportAttachmentHandler.UploadAttachment)                 // 上传附件
+			reports.DELETE("/:id/attachments/:attachmentId", reportAttachmentHandler.DeleteAttachment) // 删除附件
+
+			// 状态工作流
+			reports.GET("/:id/transitions", reportWorkflowHandler.ListReportTransitions) // 获取可执行的状态流转
+			reports.GET("/:id/timeline", reportTimelineHandler.GetTimeline)              // 获取报告时间线
 
 			// 评论相关路由
 			reports.GET("/:id/comments", commentHandler.ListComments)                // 获取评论列表
 			reports.POST("/:id/comments", commentHandler.CreateComment)              // 创建评论
 			reports.DELETE("/:id/comments/:commentId", commentHandler.DeleteComment) // 删除评论
+
+			// 奖励相关路由
+			reports.GET("/:id/bounties", bountyHandler.ListReportBounties) // 获取报告奖励及账本
+			reports.POST("/:id/bounties", bountyHandler.AwardBounty)       // 发放奖励（admin/vendor）
+		}
+
+		// 需要认证的路由 - Notifications
+		notifications := api.Group("/notifications")
+		notifications.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
+		{
+			notifications.GET("", notificationHandler.ListNotifications)           // 通知列表
+			notifications.GET("/unread-count", notificationHandler.GetUnreadCount) // 未读通知数
+			notifications.PUT("/read-all", notificationHandler.MarkAllRead)        // 全部标记为已读
+			notifications.PUT("/:id/read", notificationHandler.MarkRead)           // 标记为已读
+		}
+
+		// 需要认证的路由 - Bounty
+		bounties := api.Group("/bounties")
+		bounties.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
+		{
+			bounties.PUT("/:id/status", bountyHandler.UpdateRewardStatus) // 审批/取消奖励
 		}
 
 		// 需要认证的路由 - User Info Change
 		userInfo := api.Group("/user/info")
-		userInfo.Use(middleware.AuthMiddleware(jwtManager))
+		userInfo.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
 		{
 			userInfo.POST("/change", userInfoChangeHandler.SubmitChangeRequest)   // 提交变更申请
 			userInfo.GET("/changes", userInfoChangeHandler.GetUserChangeRequests) // 获取变更申请列表
@@ -187,23 +357,42 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 
 		// 需要认证的路由 - Projects
 		projects := api.Group("/projects")
-		projects.Use(middleware.AuthMiddleware(jwtManager))
+		projects.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
 		{
-			projects.POST("", projectHandler.CreateHandler)                      // 创建项目（仅admin）
-			projects.GET("", projectHandler.ListHandler)                         // 获取项目列表
-			projects.GET("/available", projectTaskHandler.ListAvailableProjects) // 获取用户可见的项目列表
-			projects.GET("/accepted", projectTaskHandler.ListAcceptedProjects)   // 获取用户已接受的项目列表
-			projects.GET("/:id", projectHandler.GetHandler)                      // 获取项目详情
-			projects.GET("/available/:id", projectTaskHandler.GetProjectDetail)  // 获取项目详情（可见性检查）
-			projects.POST("/:id/accept", projectTaskHandler.AcceptTask)          // 接受项目任务
-			projects.PUT("/:id", projectHandler.UpdateHandler)                   // 更新项目（仅admin）
-			projects.DELETE("/:id", projectHandler.DeleteHandler)                // 软删除项目（仅admin）
-			projects.POST("/:id/restore", projectHandler.RestoreHandler)         // 恢复已删除项目（仅admin）
+			projects.POST("", projectHandler.CreateHandler)                            // 创建项目（仅admin）
+			projects.GET("", projectHandler.ListHandler)                               // 获取项目列表
+			projects.GET("/available", projectTaskHandler.ListAvailableProjects)       // 获取用户可见的项目列表
+			projects.GET("/accepted", projectTaskHandler.ListAcceptedProjects)         // 获取用户已接受的项目列表
+			projects.GET("/:id", projectHandler.GetHandler)                            // 获取项目详情
+			projects.GET("/available/:id", projectTaskHandler.GetProjectDetail)        // 获取项目详情（可见性检查）
+			projects.POST("/:id/accept", projectTaskHandler.AcceptTask)                // 接受项目任务
+			projects.PUT("/:id", projectHandler.UpdateHandler)                         // 更新项目（仅admin）
+			projects.DELETE("/:id", projectHandler.DeleteHandler)                      // 软删除项目（仅admin）
+			projects.POST("/:id/restore", projectHandler.RestoreHandler)               // 恢复已删除项目（仅admin）
+			projects.PUT("/:id/reward-table", projectHandler.UpdateRewardTableHandler) // 更新奖励表（admin/vendor）
+
+			// 项目附件
+			projects.GET("/:id/attachments", projectAttachmentHandler.ListAttachments)                   // 获取附件列表（白帽子需被指派）
+			projects.POST("/:id/attachments", projectAttachmentHandler.UploadAttachment)                 // 上传附件（admin/vendor）
+			projects.PUT("/:id/attachments/order", projectAttachmentHandler.ReorderAttachments)          // 调整附件顺序（admin/vendor）
+			projects.DELETE("/:id/attachments/:attachmentId", projectAttachmentHandler.DeleteAttachment) // 删除附件（admin/vendor）
+
+			// 项目范围
+			projects.GET("/:id/scopes", projectScopeHandler.ListScopes)              // 获取范围资产列表
+			projects.POST("/:id/scopes", projectScopeHandler.CreateScope)            // 新增范围资产（admin/vendor）
+			projects.PUT("/:id/scopes/:scopeId", projectScopeHandler.UpdateScope)    // 更新范围资产（admin/vendor）
+			projects.DELETE("/:id/scopes/:scopeId", projectScopeHandler.DeleteScope) // 删除范围资产（admin/vendor）
+			projects.POST("/:id/scopes/check", projectScopeHandler.CheckURL)         // 校验链接是否在范围内
+
+			// 项目报告工作流
+			projects.GET("/:id/workflow", reportWorkflowHandler.GetProjectWorkflow)      // 获取项目生效的工作流
+			projects.PUT("/:id/workflow", reportWorkflowHandler.SaveProjectWorkflow)     // 保存项目自定义工作流（仅admin）
+			projects.DELETE("/:id/workflow", reportWorkflowHandler.ResetProjectWorkflow) // 重置为全局工作流（仅admin）
 		}
 
 		// 需要认证的路由 - Articles
 		articles := api.Group("/articles")
-		articles.Use(middleware.AuthMiddleware(jwtManager))
+		articles.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
 		{
 			articles.POST("", articleHandler.CreateArticle)       // 创建文章
 			articles.GET("", articleHandler.GetMyArticles)        // 获取我的文章列表
@@ -219,17 +408,51 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 
 		// 管理员路由 - 文章管理
 		admin := api.Group("/admin")
-		admin.Use(middleware.AuthMiddleware(jwtManager))
+		admin.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
 		{
 			admin.PUT("/articles/:id/review", articleHandler.ReviewArticle) // 审核文章
 			admin.PUT("/articles/:id/featured", articleHandler.SetFeatured) // 设置精选
+
+			// 全局报告工作流
+			admin.GET("/report-workflow", reportWorkflowHandler.GetGlobalWorkflow)      // 获取全局工作流
+			admin.PUT("/report-workflow", reportWorkflowHandler.SaveGlobalWorkflow)     // 保存全局工作流
+			admin.DELETE("/report-workflow", reportWorkflowHandler.ResetGlobalWorkflow) // 重置为内置默认工作流
+
+			// 项目指派
+			admin.GET("/projects/:id/assignments", projectAssignmentHandler.ListAssignees)    // 指派用户及接受情况
+			admin.POST("/projects/:id/assignments", projectAssignmentHandler.AssignUsers)     // 批量指派
+			admin.DELETE("/projects/:id/assignments", projectAssignmentHandler.UnassignUsers) // 批量取消指派
+
+			// 奖励发放批次
+			admin.GET("/payouts", bountyHandler.ListPayoutBatches)             // 发放批次列表
+			admin.POST("/payouts", bountyHandler.CreatePayoutBatch)            // 打包已审批奖励为发放批次
+			admin.GET("/payouts/:id/export", bountyHandler.ExportPayoutBatch)  // 导出批次明细(CSV)
+			admin.POST("/payouts/:id/paid", bountyHandler.MarkPayoutBatchPaid) // 确认批次已打款
+
+			// 用户会话
+			admin.GET("/users/:id/sessions", authHandler.AdminListSessions)          // 查看用户的登录会话
+			admin.DELETE("/users/:id/sessions/:sid", authHandler.AdminRevokeSession) // 注销用户的指定会话
+			admin.POST("/users/:id/logout-all", authHandler.AdminLogoutAll)          // 强制注销用户的全部会话并吊销 API Key
+
+			// 个人 API Key
+			admin.GET("/users/:id/api-keys", apiKeyHandler.AdminList)           // 查看用户的 API Key
+			admin.DELETE("/users/:id/api-keys/:kid", apiKeyHandler.AdminRevoke) // 吊销用户的 API Key
+
+			// 两步验证
+			admin.GET("/security/2fa-policy", twoFactorHandler.GetPolicy)    // 获取必须启用两步验证的角色
+			admin.PUT("/security/2fa-policy", twoFactorHandler.UpdatePolicy) // 设置必须启用两步验证的角色
+			admin.DELETE("/users/:id/2fa", twoFactorHandler.AdminReset)      // 重置用户的两步验证
+
+			// 登录锁定与安全事件
+			admin.POST("/security/unlock", securityHandler.Unlock)    // 解除用户名/IP 的登录锁定
+			admin.GET("/security/events", securityHandler.ListEvents) // 安全事件日志
 		}
 
 		// 文章点赞评论路由
-		api.GET("/articles/:id/like", middleware.OptionalAuthMiddleware(jwtManager), articleLikeCommentHandler.GetLikeStatus) // 获取点赞状态
-		api.GET("/articles/:id/comments", articleLikeCommentHandler.GetComments)                                              // 获取评论列表
+		api.GET("/articles/:id/like", middleware.OptionalAuthMiddleware(jwtManager, authTokenService), articleLikeCommentHandler.GetLikeStatus) // 获取点赞状态
+		api.GET("/articles/:id/comments", articleLikeCommentHandler.GetComments)                                                                // 获取评论列表
 		articlesAuth := api.Group("/articles")
-		articlesAuth.Use(middleware.AuthMiddleware(jwtManager))
+		articlesAuth.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
 		{
 			articlesAuth.POST("/:id/like", articleLikeCommentHandler.ToggleLike)                     // 切换点赞
 			articlesAuth.POST("/:id/comments", articleLikeCommentHandler.AddComment)                 // 发表评论
@@ -238,7 +461,7 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 
 		// 需要认证的路由 - System Configs
 		configs := api.Group("/configs")
-		configs.Use(middleware.AuthMiddleware(jwtManager))
+		configs.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
 		{
 			configs.GET("/:type", systemConfigHandler.GetConfigsByTypeHandler)    // 获取配置列表
 			configs.GET("/:type/:id", systemConfigHandler.GetConfigHandler)       // 获取配置详情
@@ -248,15 +471,26 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 		}
 
 		// 需要认证的路由 - Upload
-		upload := api.Group("/upload")
-		upload.Use(middleware.AuthMiddleware(jwtManager))
+		uploadGroup := api.Group("/upload")
+		uploadGroup.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
 		{
-			upload.POST("", uploadHandler.UploadFileHandler) // 上传文件
+			uploadGroup.POST("", uploadHandler.UploadFileHandler) // 上传文件
+
+			// 大文件分片上传：创建会话 -> 按 Upload-Offset 依次上传分片 -> 合并
+			uploadGroup.POST("/sessions", uploadSessionHandler.CreateSession)                // 创建上传会话
+			uploadGroup.GET("/sessions/:id", uploadSessionHandler.GetSession)                // 查询上传进度（断点续传）
+			uploadGroup.PUT("/sessions/:id", uploadSessionHandler.UploadChunk)               // 上传分片
+			uploadGroup.POST("/sessions/:id/complete", uploadSessionHandler.CompleteSession) // 合并分片
+			uploadGroup.DELETE("/sessions/:id", uploadSessionHandler.AbortSession)           // 取消上传
 		}
 
+		// 上传文件下载 - 携带有效签名时无需登录，否则按文件归属鉴权
+		api.GET("/files/*filepath", middleware.OptionalAuthMiddleware(jwtManager, authTokenService), fileHandler.Download)
+		api.POST("/files/sign", middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService), fileHandler.SignURL) // 获取签名下载链接
+
 		// 需要认证的路由 - Avatars
 		avatars := api.Group("/avatars")
-		avatars.Use(middleware.AuthMiddleware(jwtManager))
+		avatars.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
 		{
 			avatars.GET("/active", avatarHandler.ListActiveAvatarsHandler) // 获取启用的头像（用户选择用）
 			avatars.GET("", avatarHandler.ListAvatarsHandler)              // 获取所有头像（管理员）
@@ -267,7 +501,7 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 
 		// 需要认证的路由 - Dashboard（仪表盘/首页统计）
 		dashboard := api.Group("/dashboard")
-		dashboard.Use(middleware.AuthMiddleware(jwtManager))
+		dashboard.Use(middleware.AuthMiddleware(jwtManager, authTokenService, apiKeyService))
 		{
 			dashboard.GET("/statistics", dashboardHandler.GetStatistics) // 获取统计数据
 			dashboard.GET("/trend", dashboardHandler.GetTrend)           // 获取趋势数据
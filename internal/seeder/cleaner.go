@@ -25,6 +25,18 @@ func (c *Cleaner) CleanAll() error {
 	if err := c.CleanReportComments(); err != nil {
 		return err
 	}
+	if err := c.CleanReportEvents(); err != nil {
+		return err
+	}
+	if err := c.CleanReportSimilarities(); err != nil {
+		return err
+	}
+	if err := c.CleanReportAttachments(); err != nil {
+		return err
+	}
+	if err := c.CleanBounties(); err != nil {
+		return err
+	}
 	if err := c.CleanReports(); err != nil {
 		return err
 	}
@@ -40,6 +52,9 @@ func (c *Cleaner) CleanAll() error {
 	if err := c.CleanArticles(); err != nil {
 		return err
 	}
+	if err := c.CleanProjectScopes(); err != nil {
+		return err
+	}
 	if err := c.CleanProjectAttachments(); err != nil {
 		return err
 	}
@@ -52,12 +67,39 @@ func (c *Cleaner) CleanAll() error {
 	if err := c.CleanProjects(); err != nil {
 		return err
 	}
+	if err := c.CleanNotifications(); err != nil {
+		return err
+	}
 	if err := c.CleanUserUpdateLogs(); err != nil {
 		return err
 	}
 	if err := c.CleanUserInfoChanges(); err != nil {
 		return err
 	}
+	if err := c.CleanUploadSessions(); err != nil {
+		return err
+	}
+	if err := c.CleanAuthTokens(); err != nil {
+		return err
+	}
+	if err := c.CleanTwoFactor(); err != nil {
+		return err
+	}
+	if err := c.CleanAccountTokens(); err != nil {
+		return err
+	}
+	if err := c.CleanSecurityEvents(); err != nil {
+		return err
+	}
+	if err := c.CleanPasswordHistories(); err != nil {
+		return err
+	}
+	if err := c.CleanUserIdentities(); err != nil {
+		return err
+	}
+	if err := c.CleanAPIKeys(); err != nil {
+		return err
+	}
 	if err := c.CleanUsers(); err != nil {
 		return err
 	}
@@ -149,6 +191,88 @@ func (c *Cleaner) CleanReportComments() error {
 	return nil
 }
 
+// CleanReportEvents 清理报告审计事件数据
+func (c *Cleaner) CleanReportEvents() error {
+	var count int64
+	c.db.Model(&domain.ReportEvent{}).Count(&count)
+
+	if count == 0 {
+		fmt.Println("[INFO] No report events to clean")
+		return nil
+	}
+
+	result := c.db.Where("1 = 1").Delete(&domain.ReportEvent{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean report events: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d report events\n", result.RowsAffected)
+	return nil
+}
+
+// CleanReportSimilarities 清理报告疑似重复记录
+func (c *Cleaner) CleanReportSimilarities() error {
+	var count int64
+	c.db.Model(&domain.ReportSimilarity{}).Count(&count)
+
+	if count == 0 {
+		fmt.Println("[INFO] No report similarities to clean")
+		return nil
+	}
+
+	result := c.db.Where("1 = 1").Delete(&domain.ReportSimilarity{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean report similarities: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d report similarities\n", result.RowsAffected)
+	return nil
+}
+
+// CleanReportAttachments 清理报告附件记录（不删除存储中的文件）
+func (c *Cleaner) CleanReportAttachments() error {
+	var count int64
+	c.db.Model(&domain.ReportAttachment{}).Count(&count)
+
+	if count == 0 {
+		fmt.Println("[INFO] No report attachments to clean")
+		return nil
+	}
+
+	result := c.db.Where("1 = 1").Delete(&domain.ReportAttachment{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean report attachments: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d report attachments\n", result.RowsAffected)
+	return nil
+}
+
+// CleanBounties 清理奖励、奖励账本和发放批次数据
+func (c *Cleaner) CleanBounties() error {
+	var count int64
+	c.db.Model(&domain.BountyReward{}).Count(&count)
+
+	if count == 0 {
+		fmt.Println("[INFO] No bounties to clean")
+		return nil
+	}
+
+	if err := c.db.Where("1 = 1").Delete(&domain.BountyLedgerEntry{}).Error; err != nil {
+		return fmt.Errorf("failed to clean bounty ledger: %w", err)
+	}
+	if err := c.db.Where("1 = 1").Delete(&domain.PayoutBatch{}).Error; err != nil {
+		return fmt.Errorf("failed to clean payout batches: %w", err)
+	}
+	result := c.db.Where("1 = 1").Delete(&domain.BountyReward{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean bounties: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d bounties\n", result.RowsAffected)
+	return nil
+}
+
 // CleanAvatars 清理头像数据
 func (c *Cleaner) CleanAvatars() error {
 	var count int64
@@ -168,6 +292,194 @@ func (c *Cleaner) CleanAvatars() error {
 	return nil
 }
 
+// CleanUploadSessions 清理分片上传会话（只删除记录，存储中的分片由 gc-uploads 回收）
+func (c *Cleaner) CleanUploadSessions() error {
+	var count int64
+	c.db.Model(&domain.UploadSession{}).Count(&count)
+
+	if count == 0 {
+		fmt.Println("[INFO] No upload sessions to clean")
+		return nil
+	}
+
+	result := c.db.Where("1 = 1").Delete(&domain.UploadSession{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean upload sessions: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d upload sessions\n", result.RowsAffected)
+	return nil
+}
+
+// CleanAuthTokens 清理登录会话、刷新令牌和访问令牌吊销记录
+func (c *Cleaner) CleanAuthTokens() error {
+	var sessionCount, refreshCount, revokedCount int64
+	c.db.Model(&domain.UserSession{}).Count(&sessionCount)
+	c.db.Model(&domain.RefreshToken{}).Count(&refreshCount)
+	c.db.Model(&domain.RevokedToken{}).Count(&revokedCount)
+
+	if sessionCount == 0 && refreshCount == 0 && revokedCount == 0 {
+		fmt.Println("[INFO] No auth tokens to clean")
+		return nil
+	}
+
+	result := c.db.Where("1 = 1").Delete(&domain.UserSession{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean user sessions: %w", result.Error)
+	}
+	sessionCount = result.RowsAffected
+
+	result = c.db.Where("1 = 1").Delete(&domain.RefreshToken{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean refresh tokens: %w", result.Error)
+	}
+	refreshCount = result.RowsAffected
+
+	result = c.db.Where("1 = 1").Delete(&domain.RevokedToken{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean revoked tokens: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d sessions, %d refresh tokens and %d revoked tokens\n", sessionCount, refreshCount, result.RowsAffected)
+	return nil
+}
+
+// CleanTwoFactor 清理两步验证配置、恢复码和登录挑战
+func (c *Cleaner) CleanTwoFactor() error {
+	var twoFactorCount, codeCount, challengeCount int64
+	c.db.Model(&domain.UserTwoFactor{}).Count(&twoFactorCount)
+	c.db.Model(&domain.RecoveryCode{}).Count(&codeCount)
+	c.db.Model(&domain.LoginChallenge{}).Count(&challengeCount)
+
+	if twoFactorCount == 0 && codeCount == 0 && challengeCount == 0 {
+		fmt.Println("[INFO] No two-factor records to clean")
+		return nil
+	}
+
+	result := c.db.Where("1 = 1").Delete(&domain.RecoveryCode{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean recovery codes: %w", result.Error)
+	}
+	codeCount = result.RowsAffected
+
+	result = c.db.Where("1 = 1").Delete(&domain.LoginChallenge{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean login challenges: %w", result.Error)
+	}
+	challengeCount = result.RowsAffected
+
+	result = c.db.Where("1 = 1").Delete(&domain.UserTwoFactor{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean two-factor settings: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d two-factor settings, %d recovery codes and %d login challenges\n", result.RowsAffected, codeCount, challengeCount)
+	return nil
+}
+
+// CleanAccountTokens 清理邮箱验证和重置密码令牌
+func (c *Cleaner) CleanAccountTokens() error {
+	var count int64
+	c.db.Model(&domain.AccountToken{}).Count(&count)
+
+	if count == 0 {
+		fmt.Println("[INFO] No account tokens to clean")
+		return nil
+	}
+
+	result := c.db.Where("1 = 1").Delete(&domain.AccountToken{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean account tokens: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d account tokens\n", result.RowsAffected)
+	return nil
+}
+
+// CleanSecurityEvents 清理安全事件
+func (c *Cleaner) CleanSecurityEvents() error {
+	var count int64
+	c.db.Model(&domain.SecurityEvent{}).Count(&count)
+
+	if count == 0 {
+		fmt.Println("[INFO] No security events to clean")
+		return nil
+	}
+
+	result := c.db.Where("1 = 1").Delete(&domain.SecurityEvent{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean security events: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d security events\n", result.RowsAffected)
+	return nil
+}
+
+// CleanPasswordHistories 清理历史密码
+func (c *Cleaner) CleanPasswordHistories() error {
+	var count int64
+	c.db.Model(&domain.PasswordHistory{}).Count(&count)
+
+	if count == 0 {
+		fmt.Println("[INFO] No password histories to clean")
+		return nil
+	}
+
+	result := c.db.Where("1 = 1").Delete(&domain.PasswordHistory{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean password histories: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d password histories\n", result.RowsAffected)
+	return nil
+}
+
+// CleanUserIdentities 清理外部身份关联和单点登录状态
+func (c *Cleaner) CleanUserIdentities() error {
+	var stateCount int64
+	c.db.Model(&domain.OIDCLoginState{}).Count(&stateCount)
+	if stateCount > 0 {
+		if err := c.db.Where("1 = 1").Delete(&domain.OIDCLoginState{}).Error; err != nil {
+			return fmt.Errorf("failed to clean oidc login states: %w", err)
+		}
+	}
+
+	var count int64
+	c.db.Model(&domain.UserIdentity{}).Count(&count)
+
+	if count == 0 && stateCount == 0 {
+		fmt.Println("[INFO] No user identities to clean")
+		return nil
+	}
+
+	result := c.db.Where("1 = 1").Delete(&domain.UserIdentity{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean user identities: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d user identities and %d oidc login states\n", result.RowsAffected, stateCount)
+	return nil
+}
+
+// CleanAPIKeys 清理个人 API Key
+func (c *Cleaner) CleanAPIKeys() error {
+	var count int64
+	c.db.Model(&domain.APIKey{}).Count(&count)
+
+	if count == 0 {
+		fmt.Println("[INFO] No API keys to clean")
+		return nil
+	}
+
+	result := c.db.Where("1 = 1").Delete(&domain.APIKey{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean API keys: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d API keys\n", result.RowsAffected)
+	return nil
+}
+
 // CleanOrganizations 清理组织数据
 func (c *Cleaner) CleanOrganizations() error {
 	var count int64
@@ -358,6 +670,44 @@ func (c *Cleaner) CleanProjectAttachments() error {
 	return nil
 }
 
+// CleanProjectScopes 清理项目范围资产
+func (c *Cleaner) CleanProjectScopes() error {
+	var count int64
+	c.db.Model(&domain.ProjectScope{}).Count(&count)
+
+	if count == 0 {
+		fmt.Println("[INFO] No project scopes to clean")
+		return nil
+	}
+
+	result := c.db.Where("1 = 1").Delete(&domain.ProjectScope{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean project scopes: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d project scopes\n", result.RowsAffected)
+	return nil
+}
+
+// CleanNotifications 清理站内通知
+func (c *Cleaner) CleanNotifications() error {
+	var count int64
+	c.db.Model(&domain.Notification{}).Count(&count)
+
+	if count == 0 {
+		fmt.Println("[INFO] No notifications to clean")
+		return nil
+	}
+
+	result := c.db.Where("1 = 1").Delete(&domain.Notification{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clean notifications: %w", result.Error)
+	}
+
+	fmt.Printf("[OK] Cleaned %d notifications\n", result.RowsAffected)
+	return nil
+}
+
 // PrintStatistics 打印当前数据统计
 func (c *Cleaner) PrintStatistics() {
 	fmt.Println("\n========== 数据统计 ==========")
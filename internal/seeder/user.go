@@ -84,6 +84,8 @@ func (s *UserSeeder) Seed(force bool) error {
 		// 随机模拟一个过去 24 小时内的登录时间
 		lastLogin := time.Now().Add(-time.Duration(rand.Intn(24)) * time.Hour)
 		user.LastLoginAt = &lastLogin
+		// 测试账号的邮箱视为已验证，可以直接提交报告
+		user.EmailVerifiedAt = &lastLogin
 
 		// 随机分配一个组织 (如果存在组织数据)
 		if len(orgIDs) > 0 {
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/config"
+	"bug-bounty-lite/pkg/mailer"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// defaultResetTokenTTL 未配置 auth.email.reset_token_ttl 时重置密码令牌的有效期
+	defaultResetTokenTTL = 30 * time.Minute
+	// defaultVerifyTokenTTL 未配置 auth.email.verify_token_ttl 时邮箱验证令牌的有效期
+	defaultVerifyTokenTTL = 24 * time.Hour
+	// accountTokenResendInterval 同一用户同用途邮件的最小发送间隔
+	accountTokenResendInterval = time.Minute
+	// mailSendTimeout 单封邮件的发送超时
+	mailSendTimeout = time.Minute
+)
+
+type accountService struct {
+	repo      domain.AccountTokenRepository
+	userRepo  domain.UserRepository
+	tokens    domain.AuthTokenService
+	mailer    mailer.Mailer
+	passwords domain.PasswordPolicyService
+	baseURL   string
+	resetTTL  time.Duration
+	verifyTTL time.Duration
+}
+
+// NewAccountService 创建邮箱验证与找回密码服务实例
+func NewAccountService(repo domain.AccountTokenRepository, userRepo domain.UserRepository, tokens domain.AuthTokenService, m mailer.Mailer, passwords domain.PasswordPolicyService, cfg config.AccountEmailConfig) domain.AccountService {
+	resetTTL := time.Duration(cfg.ResetTokenTTL) * time.Second
+	if resetTTL <= 0 {
+		resetTTL = defaultResetTokenTTL
+	}
+	verifyTTL := time.Duration(cfg.VerifyTokenTTL) * time.Second
+	if verifyTTL <= 0 {
+		verifyTTL = defaultVerifyTokenTTL
+	}
+	return &accountService{
+		repo:      repo,
+		userRepo:  userRepo,
+		tokens:    tokens,
+		mailer:    m,
+		passwords: passwords,
+		baseURL:   strings.TrimRight(cfg.BaseURL, "/"),
+		resetTTL:  resetTTL,
+		verifyTTL: verifyTTL,
+	}
+}
+
+// RequestPasswordReset 向该邮箱对应的账号发送重置密码邮件
+// 邮箱不存在、发送过于频繁或发送失败时同样返回成功，并在后台发送，避免通过响应内容或耗时枚举邮箱
+func (s *accountService) RequestPasswordReset(email string) error {
+	email = strings.TrimSpace(email)
+	users, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	for i := range users {
+		user := &users[i]
+		token, err := s.issueToken(user, domain.AccountTokenPasswordReset, s.resetTTL)
+		if err != nil {
+			if !errors.Is(err, domain.ErrAccountTokenTooFrequent) {
+				log.Printf("[WARN] Failed to create password reset token for user %d: %v", user.ID, err)
+			}
+			continue
+		}
+
+		msg := &mailer.Message{
+			To:      user.Email,
+			Subject: "重置密码",
+			Body: fmt.Sprintf("%s，你好：\n\n我们收到了重置账号 %s 密码的请求。请在 %s 内打开以下链接设置新密码：\n\n%s\n\n链接只能使用一次。如果这不是你本人的操作，请忽略本邮件，你的密码不会改变。\n",
+				displayName(user), user.Username, formatTTL(s.resetTTL), s.link("/reset-password", token)),
+		}
+		go s.deliver(msg)
+	}
+	return nil
+}
+
+// ResetPassword 使用重置令牌设置新密码，并注销该用户的全部会话
+func (s *accountService) ResetPassword(token, newPassword string) error {
+	record, err := s.findToken(token, domain.AccountTokenPasswordReset)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(record.UserID)
+	if err != nil || user == nil {
+		return domain.ErrAccountTokenInvalid
+	}
+	// 令牌发出后邮箱已变更，原邮箱不应再能重置密码
+	if record.Email != user.Email {
+		return domain.ErrAccountTokenInvalid
+	}
+	if err := s.passwords.Validate(user, newPassword); err != nil {
+		return err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("密码加密失败")
+	}
+
+	used, err := s.repo.MarkUsed(record.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return domain.ErrAccountTokenInvalid
+	}
+
+	user.Password = string(hashedPassword)
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+	if err := s.passwords.Remember(user.ID, user.Password); err != nil {
+		log.Printf("[WARN] Failed to record password history for user %d: %v", user.ID, err)
+	}
+	// 密码可能已泄露，注销所有已登录的会话
+	return s.tokens.LogoutAll(user.ID)
+}
+
+// SendVerificationEmail 向用户当前邮箱发送验证邮件
+func (s *accountService) SendVerificationEmail(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		return errors.New("user not found")
+	}
+	if user.Email == "" {
+		return domain.ErrEmailNotSet
+	}
+	if user.EmailVerifiedAt != nil {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	token, err := s.issueToken(user, domain.AccountTokenEmailVerify, s.verifyTTL)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+	defer cancel()
+	return s.mailer.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "验证邮箱",
+		Body: fmt.Sprintf("%s，你好：\n\n请在 %s 内打开以下链接，验证账号 %s 的邮箱：\n\n%s\n\n如果这不是你本人的操作，请忽略本邮件。\n",
+			displayName(user), formatTTL(s.verifyTTL), user.Username, s.link("/verify-email", token)),
+	})
+}
+
+// VerifyEmail 使用验证令牌完成邮箱验证（令牌发出后邮箱已变更则失效）
+func (s *accountService) VerifyEmail(token string) error {
+	record, err := s.findToken(token, domain.AccountTokenEmailVerify)
+	if err != nil {
+		return err
+	}
+
+	used, err := s.repo.MarkUsed(record.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return domain.ErrAccountTokenInvalid
+	}
+
+	verified, err := s.userRepo.MarkEmailVerified(record.UserID, record.Email, time.Now())
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return domain.ErrAccountTokenInvalid
+	}
+	return nil
+}
+
+// IsEmailVerified 用户当前邮箱是否已验证
+func (s *accountService) IsEmailVerified(userID uint) (bool, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		return false, errors.New("user not found")
+	}
+	return user.Email != "" && user.EmailVerifiedAt != nil, nil
+}
+
+// CleanupExpired 清理过期的令牌
+func (s *accountService) CleanupExpired() (int64, error) {
+	return s.repo.DeleteExpired(time.Now())
+}
+
+// issueToken 生成一次性令牌并保存哈希，同一用户同用途的邮件一分钟内只能发送一次
+func (s *accountService) issueToken(user *domain.User, purpose string, ttl time.Duration) (string, error) {
+	latest, err := s.repo.FindLatest(user.ID, purpose)
+	if err != nil {
+		return "", err
+	}
+	if latest != nil && time.Since(latest.CreatedAt) < accountTokenResendInterval {
+		return "", domain.ErrAccountTokenTooFrequent
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	if err := s.repo.Create(&domain.AccountToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// findToken 查找并校验令牌（存在、用途一致、未使用、未过期）
+func (s *accountService) findToken(token, purpose string) (*domain.AccountToken, error) {
+	if token == "" {
+		return nil, domain.ErrAccountTokenInvalid
+	}
+	record, err := s.repo.FindByHash(hashToken(token))
+	if err != nil || record.Purpose != purpose || record.UsedAt != nil || time.Now().After(record.ExpiresAt) {
+		return nil, domain.ErrAccountTokenInvalid
+	}
+	return record, nil
+}
+
+// link 生成邮件中的链接，未配置前端地址时只返回令牌
+func (s *accountService) link(path, token string) string {
+	if s.baseURL == "" {
+		return "令牌: " + token
+	}
+	return s.baseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// deliver 在后台发送邮件，失败时只记录日志
+func (s *accountService) deliver(msg *mailer.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+	defer cancel()
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("[WARN] Failed to send mail to %s: %v", msg.To, err)
+	}
+}
+
+// displayName 邮件称呼，未填写姓名时使用用户名
+func displayName(user *domain.User) string {
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.Username
+}
+
+// formatTTL 将有效期格式化为 "30 分钟"、"24 小时"
+func formatTTL(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		return fmt.Sprintf("%d 小时", int(ttl.Hours()))
+	}
+	return fmt.Sprintf("%d 分钟", int(ttl.Minutes()))
+}
+
+// StartAccountTokenCleanup 启动后台定时清理过期的邮箱验证和重置密码令牌
+func StartAccountTokenCleanup(s domain.AccountService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if count, err := s.CleanupExpired(); err != nil {
+				log.Printf("[WARN] Failed to clean up expired account tokens: %v", err)
+			} else if count > 0 {
+				log.Printf("[INFO] Cleaned up %d expired account token(s)", count)
+			}
+		}
+	}()
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeAccountTokenRepo 内存账号令牌仓库
+type fakeAccountTokenRepo struct {
+	domain.AccountTokenRepository
+	tokens map[string]*domain.AccountToken
+}
+
+func (r *fakeAccountTokenRepo) FindByHash(tokenHash string) (*domain.AccountToken, error) {
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (r *fakeAccountTokenRepo) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	for _, token := range r.tokens {
+		if token.ID == id && token.UsedAt == nil {
+			token.UsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeLogoutRecorder 记录被注销全部会话的用户
+type fakeLogoutRecorder struct {
+	domain.AuthTokenService
+	loggedOut []uint
+}
+
+func (s *fakeLogoutRecorder) LogoutAll(userID uint) error {
+	s.loggedOut = append(s.loggedOut, userID)
+	return nil
+}
+
+// fakePasswordPolicy 接受任意新密码
+type fakePasswordPolicy struct{}
+
+func (fakePasswordPolicy) Validate(user *domain.User, password string) error { return nil }
+
+func (fakePasswordPolicy) Remember(userID uint, passwordHash string) error { return nil }
+
+func TestAccountResetPassword(t *testing.T) {
+	const token = "reset-token"
+	usedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		record  domain.AccountToken
+		email   string // 用户当前邮箱
+		wantErr bool
+	}{
+		{
+			name:   "有效令牌",
+			record: domain.AccountToken{Purpose: domain.AccountTokenPasswordReset, Email: "alice@example.com", ExpiresAt: time.Now().Add(time.Minute)},
+			email:  "alice@example.com",
+		},
+		{
+			name:    "令牌发出后邮箱已变更",
+			record:  domain.AccountToken{Purpose: domain.AccountTokenPasswordReset, Email: "old@example.com", ExpiresAt: time.Now().Add(time.Minute)},
+			email:   "alice@example.com",
+			wantErr: true,
+		},
+		{
+			name:    "令牌已过期",
+			record:  domain.AccountToken{Purpose: domain.AccountTokenPasswordReset, Email: "alice@example.com", ExpiresAt: time.Now().Add(-time.Second)},
+			email:   "alice@example.com",
+			wantErr: true,
+		},
+		{
+			name:    "令牌已使用",
+			record:  domain.AccountToken{Purpose: domain.AccountTokenPasswordReset, Email: "alice@example.com", ExpiresAt: time.Now().Add(time.Minute), UsedAt: &usedAt},
+			email:   "alice@example.com",
+			wantErr: true,
+		},
+		{
+			name:    "邮箱验证令牌",
+			record:  domain.AccountToken{Purpose: domain.AccountTokenEmailVerify, Email: "alice@example.com", ExpiresAt: time.Now().Add(time.Minute)},
+			email:   "alice@example.com",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := tt.record
+			record.ID, record.UserID = 1, 1
+			repo := &fakeAccountTokenRepo{tokens: map[string]*domain.AccountToken{hashToken(token): &record}}
+			users := &fakeUserRepo{users: map[uint]*domain.User{1: {ID: 1, Username: "alice", Email: tt.email, Password: "old-hash"}}}
+			tokens := &fakeLogoutRecorder{}
+			s := &accountService{repo: repo, userRepo: users, tokens: tokens, passwords: fakePasswordPolicy{}}
+
+			err := s.ResetPassword(token, "N3w-Passw0rd!")
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrAccountTokenInvalid) {
+					t.Fatalf("ResetPassword() error = %v, want ErrAccountTokenInvalid", err)
+				}
+				if users.users[1].Password != "old-hash" || len(tokens.loggedOut) != 0 {
+					t.Error("令牌无效时不应修改密码或注销会话")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResetPassword() error = %v", err)
+			}
+			if bcrypt.CompareHashAndPassword([]byte(users.users[1].Password), []byte("N3w-Passw0rd!")) != nil {
+				t.Error("密码未更新")
+			}
+			if len(tokens.loggedOut) != 1 || tokens.loggedOut[0] != 1 {
+				t.Errorf("LogoutAll called for %v, want [1]", tokens.loggedOut)
+			}
+			if repo.tokens[hashToken(token)].UsedAt == nil {
+				t.Error("令牌未标记为已使用")
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/config"
+	"crypto/rand"
+	"encoding/base64"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// apiKeyPrefix API Key 明文前缀，便于识别和密钥扫描
+	apiKeyPrefix = "bbl_"
+	// apiKeyDisplayLength 列表中展示的 Key 前缀长度
+	apiKeyDisplayLength = 12
+	// apiKeyTouchInterval 最近使用时间的最小更新间隔，避免每个请求都写库
+	apiKeyTouchInterval = time.Minute
+	// apiKeyRetention 过期或吊销的 Key 保留多久后清理
+	apiKeyRetention = 30 * 24 * time.Hour
+
+	defaultAPIKeyMaxPerUser = 10
+	defaultAPIKeyTTLDays    = 90
+	defaultAPIKeyMaxTTLDays = 365
+)
+
+type apiKeyService struct {
+	repo       domain.APIKeyRepository
+	userRepo   domain.UserRepository
+	maxPerUser int
+	defaultTTL int
+	maxTTL     int
+}
+
+// NewAPIKeyService 创建 API Key 服务实例
+func NewAPIKeyService(repo domain.APIKeyRepository, userRepo domain.UserRepository, cfg config.APIKeyConfig) domain.APIKeyService {
+	s := &apiKeyService{
+		repo:       repo,
+		userRepo:   userRepo,
+		maxPerUser: cfg.MaxPerUser,
+		defaultTTL: cfg.DefaultTTLDays,
+		maxTTL:     cfg.MaxTTLDays,
+	}
+	if s.maxPerUser <= 0 {
+		s.maxPerUser = defaultAPIKeyMaxPerUser
+	}
+	if s.maxTTL <= 0 {
+		s.maxTTL = defaultAPIKeyMaxTTLDays
+	}
+	if s.defaultTTL <= 0 {
+		s.defaultTTL = defaultAPIKeyTTLDays
+	}
+	if s.defaultTTL > s.maxTTL {
+		s.defaultTTL = s.maxTTL
+	}
+	return s
+}
+
+// Create 创建 API Key
+// 1. 校验名称、授权范围（去重）和有效期，Key 必须有过期时间
+// 2. 检查用户有效 Key 数量上限
+// 3. 生成随机 Key，只保存哈希，明文只在本次返回
+func (s *apiKeyService) Create(userID uint, name string, scopes []string, expiresInDays int) (*domain.APIKeyCreated, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 64 {
+		return nil, domain.ErrAPIKeyNameInvalid
+	}
+
+	scopeList := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !domain.IsValidAPIKeyScope(scope) {
+			return nil, domain.ErrAPIKeyScopeInvalid
+		}
+		if !slices.Contains(scopeList, scope) {
+			scopeList = append(scopeList, scope)
+		}
+	}
+	if len(scopeList) == 0 {
+		return nil, domain.ErrAPIKeyScopeInvalid
+	}
+
+	if expiresInDays == 0 {
+		expiresInDays = s.defaultTTL
+	}
+	if expiresInDays < 0 || expiresInDays > s.maxTTL {
+		return nil, domain.ErrAPIKeyExpiryInvalid
+	}
+
+	now := time.Now()
+	count, err := s.repo.CountActive(userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if count >= int64(s.maxPerUser) {
+		return nil, domain.ErrAPIKeyLimitExceeded
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	record := &domain.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    key[:apiKeyDisplayLength],
+		KeyHash:   hashToken(key),
+		Scopes:    strings.Join(scopeList, ","),
+		ExpiresAt: now.AddDate(0, 0, expiresInDays),
+	}
+	if err := s.repo.Create(record); err != nil {
+		return nil, err
+	}
+	record.ScopeList = scopeList
+	return &domain.APIKeyCreated{Key: key, APIKey: record}, nil
+}
+
+// List 获取用户未吊销的 Key（包括已过期的，便于用户知道需要轮换）
+func (s *apiKeyService) List(userID uint) ([]domain.APIKey, error) {
+	keys, err := s.repo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		keys[i].ScopeList = strings.Split(keys[i].Scopes, ",")
+	}
+	return keys, nil
+}
+
+// Revoke 吊销用户的 Key，立即失效
+func (s *apiKeyService) Revoke(userID, id uint) error {
+	ok, err := s.repo.Revoke(userID, id, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// ValidateAPIKey 校验 Key（存在、未吊销、未过期）并返回所属用户，角色以用户当前角色为准
+func (s *apiKeyService) ValidateAPIKey(key, clientIP string) (*domain.APIKey, *domain.User, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, nil, domain.ErrAPIKeyInvalid
+	}
+	record, err := s.repo.FindByHash(hashToken(key))
+	if err != nil {
+		return nil, nil, domain.ErrAPIKeyInvalid
+	}
+	now := time.Now()
+	if record.RevokedAt != nil || !now.Before(record.ExpiresAt) {
+		return nil, nil, domain.ErrAPIKeyInvalid
+	}
+	user, err := s.userRepo.FindByID(record.UserID)
+	if err != nil {
+		return nil, nil, domain.ErrAPIKeyInvalid
+	}
+
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) >= apiKeyTouchInterval || record.LastUsedIP != clientIP {
+		if err := s.repo.TouchLastUsed(record.ID, clientIP, now); err != nil {
+			log.Printf("[WARN] Failed to update API key %d last used time: %v", record.ID, err)
+		}
+	}
+	record.ScopeList = strings.Split(record.Scopes, ",")
+	return record, user, nil
+}
+
+// CleanupExpired 清理过期或吊销超过保留期的 Key
+func (s *apiKeyService) CleanupExpired() (int64, error) {
+	return s.repo.DeleteExpired(time.Now().Add(-apiKeyRetention))
+}
+
+// StartAPIKeyCleanup 启动后台定时清理过期和已吊销的 API Key
+func StartAPIKeyCleanup(s domain.APIKeyService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if count, err := s.CleanupExpired(); err != nil {
+				log.Printf("[WARN] Failed to clean up expired API keys: %v", err)
+			} else if count > 0 {
+				log.Printf("[INFO] Cleaned up %d expired API key(s)", count)
+			}
+		}
+	}()
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/upload"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// attachmentRescanBatchSize 每批重新扫描的附件数
+const attachmentRescanBatchSize = 100
+
+type attachmentScanService struct {
+	repo    domain.ReportAttachmentRepository
+	storage upload.Storage
+	scanner upload.Scanner
+}
+
+// NewAttachmentScanService 创建附件扫描服务实例
+func NewAttachmentScanService(repo domain.ReportAttachmentRepository, storage upload.Storage, scanner upload.Scanner) domain.AttachmentScanService {
+	return &attachmentScanService{repo: repo, storage: storage, scanner: scanner}
+}
+
+// ScanFile 扫描上传文件
+func (s *attachmentScanService) ScanFile(ctx context.Context, filePath string) (string, string, error) {
+	result, err := upload.ScanObject(ctx, s.storage, s.scanner, filePath)
+	if errors.Is(err, upload.ErrObjectNotFound) {
+		return "", "", err
+	}
+	if err != nil {
+		// 扫描器不可用时不阻塞上传，附件保持待扫描状态
+		log.Printf("[WARN] Failed to scan %s, will retry later: %v", filePath, err)
+		return domain.ScanStatusPending, "", nil
+	}
+	if !result.Infected {
+		return domain.ScanStatusClean, "", nil
+	}
+
+	log.Printf("[WARN] Malware %s found in %s, quarantining", result.Signature, filePath)
+	if err := upload.Quarantine(ctx, s.storage, filePath); err != nil {
+		return "", "", err
+	}
+
+	// 文件按内容寻址，已引用该文件的附件同样被感染
+	attachments, err := s.repo.FindByFilePath(filePath)
+	if err != nil {
+		return "", "", err
+	}
+	for i := range attachments {
+		if attachments[i].ScanStatus == domain.ScanStatusInfected {
+			continue
+		}
+		if err := s.markInfected(&attachments[i], result.Signature); err != nil {
+			return "", "", err
+		}
+	}
+	return domain.ScanStatusInfected, result.Signature, nil
+}
+
+// RescanPending 重新扫描待扫描的附件
+// 多个附件引用同一文件时只扫描一次；扫描器仍不可用时结束本轮，等待下次重试
+func (s *attachmentScanService) RescanPending(ctx context.Context) (int, error) {
+	scanned := 0
+	var afterID uint
+	for {
+		attachments, err := s.repo.ListByScanStatus(domain.ScanStatusPending, afterID, attachmentRescanBatchSize)
+		if err != nil {
+			return scanned, err
+		}
+
+		results := make(map[string]string) // 文件路径 -> 本轮扫描状态
+		for i := range attachments {
+			attachment := &attachments[i]
+			afterID = attachment.ID
+
+			filePath, ok := upload.FilePathFromURL(attachment.URL)
+			if !ok {
+				continue
+			}
+			status, scannedBefore := results[filePath]
+			if !scannedBefore {
+				status, _, err = s.ScanFile(ctx, filePath)
+				if err != nil {
+					log.Printf("[WARN] Failed to rescan attachment %d: %v", attachment.ID, err)
+					continue
+				}
+				results[filePath] = status
+			}
+
+			switch status {
+			case domain.ScanStatusPending:
+				return scanned, nil
+			case domain.ScanStatusClean:
+				now := time.Now()
+				attachment.ScanStatus = domain.ScanStatusClean
+				attachment.ScannedAt = &now
+				if err := s.repo.UpdateScanStatus(attachment); err != nil {
+					return scanned, err
+				}
+			}
+			// infected 的附件已在 ScanFile 中更新
+			scanned++
+		}
+
+		if len(attachments) < attachmentRescanBatchSize {
+			return scanned, nil
+		}
+	}
+}
+
+// markInfected 将附件标记为感染，并写入报告时间线
+func (s *attachmentScanService) markInfected(attachment *domain.ReportAttachment, signature string) error {
+	now := time.Now()
+	attachment.ScanStatus = domain.ScanStatusInfected
+	attachment.ScanSignature = signature
+	attachment.ScannedAt = &now
+	return s.repo.UpdateScanStatus(attachment, domain.ReportEvent{
+		ActorRole: "system",
+		EventType: domain.ReportEventAttachmentInfected,
+		Field:     "attachments",
+		NewValue:  fmt.Sprintf("%s (%s)", attachment.Filename, signature),
+	})
+}
+
+// StartAttachmentRescan 启动后台定时重新扫描待扫描的附件
+func StartAttachmentRescan(s domain.AttachmentScanService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if count, err := s.RescanPending(context.Background()); err != nil {
+				log.Printf("[WARN] Failed to rescan pending attachments: %v", err)
+			} else if count > 0 {
+				log.Printf("[INFO] Rescanned %d pending attachment(s)", count)
+			}
+		}
+	}()
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/jwt"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultRefreshTokenTTL 未配置 jwt.refresh_expire 时刷新令牌的有效期
+	defaultRefreshTokenTTL = 7 * 24 * time.Hour
+	// sessionTouchInterval 会话最近活动时间的最小更新间隔，避免每个请求都写库
+	sessionTouchInterval = time.Minute
+	// maxUserAgentLength User-Agent 最大保存长度
+	maxUserAgentLength = 512
+)
+
+type authTokenService struct {
+	repo       domain.AuthTokenRepository
+	apiKeyRepo domain.APIKeyRepository
+	userRepo   domain.UserRepository
+	jwtManager *jwt.JWTManager
+	refreshTTL time.Duration
+}
+
+// NewAuthTokenService 创建令牌服务实例
+func NewAuthTokenService(repo domain.AuthTokenRepository, apiKeyRepo domain.APIKeyRepository, userRepo domain.UserRepository, jwtManager *jwt.JWTManager, refreshExpireSeconds int) domain.AuthTokenService {
+	refreshTTL := time.Duration(refreshExpireSeconds) * time.Second
+	if refreshTTL <= 0 {
+		refreshTTL = defaultRefreshTokenTTL
+	}
+	return &authTokenService{
+		repo:       repo,
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		jwtManager: jwtManager,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// IssueTokens 记录登录会话（设备、User-Agent、IP）并签发令牌
+func (s *authTokenService) IssueTokens(user *domain.User, client domain.ClientInfo) (*domain.TokenPair, error) {
+	sessionID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, record, err := s.newRefreshToken(user.ID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	session := &domain.UserSession{
+		ID:         sessionID,
+		UserID:     user.ID,
+		Device:     describeDevice(client.UserAgent),
+		UserAgent:  userAgent,
+		IP:         client.IP,
+		LoginIP:    client.IP,
+		LastSeenAt: time.Now(),
+		ExpiresAt:  record.ExpiresAt,
+	}
+	if err := s.repo.CreateSession(session, record); err != nil {
+		return nil, err
+	}
+	return s.tokenPair(user, sessionID, refreshToken)
+}
+
+// Refresh 轮换刷新令牌
+// 1. 刷新令牌不存在或已过期：拒绝
+// 2. 刷新令牌已被轮换过：说明令牌被重放（可能已泄露），注销整个会话
+// 3. 否则吊销旧令牌，在同一会话下签发新的访问令牌和刷新令牌
+func (s *authTokenService) Refresh(refreshToken string, client domain.ClientInfo) (*domain.User, *domain.TokenPair, error) {
+	old, err := s.repo.FindRefreshTokenByHash(hashToken(refreshToken))
+	if err != nil {
+		return nil, nil, domain.ErrRefreshTokenInvalid
+	}
+	if old.RevokedAt != nil {
+		if old.ReplacedByID == 0 {
+			// 会话已注销
+			return nil, nil, domain.ErrRefreshTokenInvalid
+		}
+		s.revokeReusedSession(old)
+		return nil, nil, domain.ErrRefreshTokenReused
+	}
+	if time.Now().After(old.ExpiresAt) {
+		return nil, nil, domain.ErrRefreshTokenInvalid
+	}
+
+	user, err := s.userRepo.FindByID(old.UserID)
+	if err != nil || user == nil {
+		return nil, nil, domain.ErrRefreshTokenInvalid
+	}
+
+	newToken, next, err := s.newRefreshToken(user.ID, old.SessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rotated, err := s.repo.RotateRefreshToken(old, next, client.IP)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !rotated {
+		// 同一令牌被并发使用，按重放处理
+		s.revokeReusedSession(old)
+		return nil, nil, domain.ErrRefreshTokenReused
+	}
+
+	pair, err := s.tokenPair(user, old.SessionID, newToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, pair, nil
+}
+
+// Logout 吊销当前访问令牌，并吊销其所属会话的刷新令牌
+func (s *authTokenService) Logout(claims *jwt.Claims) error {
+	if claims.ID != "" {
+		expiresAt := time.Now().Add(s.jwtManager.Expire())
+		if claims.ExpiresAt != nil {
+			expiresAt = claims.ExpiresAt.Time
+		}
+		if err := s.repo.RevokeAccessToken(&domain.RevokedToken{
+			TokenID:   claims.ID,
+			UserID:    claims.UserID,
+			ExpiresAt: expiresAt,
+		}); err != nil {
+			return err
+		}
+	}
+	if claims.SessionID != "" {
+		return s.repo.RevokeSession(claims.UserID, claims.SessionID)
+	}
+	return nil
+}
+
+// LogoutAll 令牌版本加一使所有访问令牌失效，注销所有会话并吊销所有 API Key
+// 重置密码、管理员强制注销都通过这里收回凭据，API Key 不依赖会话，需要单独吊销
+func (s *authTokenService) LogoutAll(userID uint) error {
+	if _, err := s.userRepo.GetTokenVersion(userID); err != nil {
+		return errors.New("user not found")
+	}
+	if err := s.userRepo.IncrementTokenVersion(userID); err != nil {
+		return err
+	}
+	if err := s.repo.RevokeUserSessions(userID); err != nil {
+		return err
+	}
+	if count, err := s.apiKeyRepo.RevokeAllByUser(userID, time.Now()); err != nil {
+		return err
+	} else if count > 0 {
+		log.Printf("[INFO] Revoked %d API key(s) of user %d", count, userID)
+	}
+	return nil
+}
+
+// ListSessions 获取用户的活跃会话
+func (s *authTokenService) ListSessions(userID uint, currentSessionID string) ([]domain.UserSession, error) {
+	sessions, err := s.repo.ListActiveSessions(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession 注销用户的指定会话（会话必须属于该用户）
+func (s *authTokenService) RevokeSession(userID uint, sessionID string) error {
+	session, err := s.repo.FindSession(sessionID)
+	if err != nil || session.UserID != userID {
+		return domain.ErrSessionNotFound
+	}
+	return s.repo.RevokeSession(userID, sessionID)
+}
+
+// ValidateAccessToken 检查令牌版本、所属会话和吊销列表，通过后按间隔记录会话最近活动
+func (s *authTokenService) ValidateAccessToken(claims *jwt.Claims, clientIP string) error {
+	version, err := s.userRepo.GetTokenVersion(claims.UserID)
+	if err != nil || version != claims.Version {
+		return domain.ErrTokenRevoked
+	}
+	if claims.ID != "" {
+		revoked, err := s.repo.IsAccessTokenRevoked(claims.ID)
+		if err != nil || revoked {
+			return domain.ErrTokenRevoked
+		}
+	}
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	session, err := s.repo.FindSession(claims.SessionID)
+	if err != nil || session.UserID != claims.UserID || session.RevokedAt != nil {
+		return domain.ErrTokenRevoked
+	}
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval || session.IP != clientIP {
+		if err := s.repo.TouchSession(session.ID, clientIP, now); err != nil {
+			log.Printf("[WARN] Failed to update session %s activity: %v", session.ID, err)
+		}
+	}
+	return nil
+}
+
+// CleanupExpired 清理过期的会话、刷新令牌和吊销记录
+func (s *authTokenService) CleanupExpired() (int64, error) {
+	return s.repo.DeleteExpired(time.Now())
+}
+
+// tokenPair 签发访问令牌并组装返回结果
+func (s *authTokenService) tokenPair(user *domain.User, sessionID string, refreshToken string) (*domain.TokenPair, error) {
+	accessToken, err := s.jwtManager.GenerateToken(user.ID, user.Username, user.Role, sessionID, user.TokenVersion)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+	return &domain.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(s.jwtManager.Expire().Seconds()),
+		RefreshExpiresIn: int(s.refreshTTL.Seconds()),
+	}, nil
+}
+
+// newRefreshToken 生成随机刷新令牌，返回明文（只返回给客户端一次）和待保存的记录
+func (s *authTokenService) newRefreshToken(userID uint, sessionID string) (string, *domain.RefreshToken, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, &domain.RefreshToken{
+		UserID:    userID,
+		SessionID: sessionID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}, nil
+}
+
+// revokeReusedSession 刷新令牌被重放时注销整个会话
+func (s *authTokenService) revokeReusedSession(token *domain.RefreshToken) {
+	log.Printf("[WARN] Refresh token reuse detected: user %d, session %s", token.UserID, token.SessionID)
+	if err := s.repo.RevokeSession(token.UserID, token.SessionID); err != nil {
+		log.Printf("[WARN] Failed to revoke session %s: %v", token.SessionID, err)
+	}
+}
+
+// StartAuthTokenCleanup 启动后台定时清理过期的会话、刷新令牌和吊销记录
+func StartAuthTokenCleanup(s domain.AuthTokenService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if count, err := s.CleanupExpired(); err != nil {
+				log.Printf("[WARN] Failed to clean up expired tokens: %v", err)
+			} else if count > 0 {
+				log.Printf("[INFO] Cleaned up %d expired token record(s)", count)
+			}
+		}
+	}()
+}
+
+// hashToken 计算令牌的 SHA-256 哈希（十六进制）
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomHex 生成 n 字节的随机十六进制字符串
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// describeDevice 从 User-Agent 中粗略识别浏览器和操作系统，如 "Chrome / Windows"
+func describeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "未知设备"
+	}
+
+	browser := ""
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"), strings.Contains(userAgent, "Opera"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"), strings.Contains(userAgent, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	case strings.HasPrefix(userAgent, "curl/"):
+		browser = "curl"
+	case strings.Contains(userAgent, "python-requests"), strings.Contains(userAgent, "Python"):
+		browser = "Python"
+	case strings.HasPrefix(userAgent, "Go-http-client"):
+		browser = "Go"
+	}
+
+	platform := ""
+	switch {
+	case strings.Contains(userAgent, "Windows"):
+		platform = "Windows"
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"):
+		platform = "iOS"
+	case strings.Contains(userAgent, "Android"):
+		platform = "Android"
+	case strings.Contains(userAgent, "Mac OS X"), strings.Contains(userAgent, "Macintosh"):
+		platform = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		platform = "Linux"
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " / " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	default:
+		return "未知设备"
+	}
+}
@@ -0,0 +1,525 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/jwt"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeUserRepo 内存用户仓库，只实现测试用到的方法
+type fakeUserRepo struct {
+	domain.UserRepository
+	users map[uint]*domain.User
+}
+
+func (r *fakeUserRepo) FindByID(id uint) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) FindByUsername(username string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Username == username {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeUserRepo) Update(user *domain.User) error {
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+func (r *fakeUserRepo) UpdateLastLoginAt(userID uint, loginTime time.Time) error {
+	return nil
+}
+
+func (r *fakeUserRepo) GetTokenVersion(userID uint) (int, error) {
+	user, ok := r.users[userID]
+	if !ok {
+		return 0, errors.New("record not found")
+	}
+	return user.TokenVersion, nil
+}
+
+func (r *fakeUserRepo) IncrementTokenVersion(userID uint) error {
+	r.users[userID].TokenVersion++
+	return nil
+}
+
+// fakeAuthTokenRepo 内存令牌仓库，返回记录的副本（与数据库行为一致）
+type fakeAuthTokenRepo struct {
+	domain.AuthTokenRepository
+	sessions map[string]*domain.UserSession
+	tokens   map[uint]*domain.RefreshToken
+	revoked  map[string]bool // 已吊销的访问令牌ID
+	nextID   uint
+	// rotateConflict 模拟并发刷新：RotateRefreshToken 返回 false
+	rotateConflict bool
+}
+
+func newFakeAuthTokenRepo() *fakeAuthTokenRepo {
+	return &fakeAuthTokenRepo{
+		sessions: make(map[string]*domain.UserSession),
+		tokens:   make(map[uint]*domain.RefreshToken),
+		revoked:  make(map[string]bool),
+	}
+}
+
+func (r *fakeAuthTokenRepo) saveToken(token *domain.RefreshToken) {
+	r.nextID++
+	token.ID = r.nextID
+	copied := *token
+	r.tokens[token.ID] = &copied
+}
+
+func (r *fakeAuthTokenRepo) CreateSession(session *domain.UserSession, token *domain.RefreshToken) error {
+	copied := *session
+	r.sessions[session.ID] = &copied
+	r.saveToken(token)
+	return nil
+}
+
+func (r *fakeAuthTokenRepo) FindSession(id string) (*domain.UserSession, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *fakeAuthTokenRepo) ListActiveSessions(userID uint, now time.Time) ([]domain.UserSession, error) {
+	var sessions []domain.UserSession
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			sessions = append(sessions, *session)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *fakeAuthTokenRepo) TouchSession(id string, ip string, seenAt time.Time) error {
+	r.sessions[id].IP = ip
+	r.sessions[id].LastSeenAt = seenAt
+	return nil
+}
+
+func (r *fakeAuthTokenRepo) FindRefreshTokenByHash(hash string) (*domain.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeAuthTokenRepo) RotateRefreshToken(old *domain.RefreshToken, next *domain.RefreshToken, ip string) (bool, error) {
+	stored := r.tokens[old.ID]
+	if r.rotateConflict || stored.RevokedAt != nil {
+		return false, nil
+	}
+	r.saveToken(next)
+	now := time.Now()
+	stored.RevokedAt = &now
+	stored.ReplacedByID = next.ID
+	return true, nil
+}
+
+func (r *fakeAuthTokenRepo) RevokeSession(userID uint, sessionID string) error {
+	now := time.Now()
+	if session, ok := r.sessions[sessionID]; ok && session.UserID == userID {
+		session.RevokedAt = &now
+	}
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.SessionID == sessionID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeAuthTokenRepo) RevokeUserSessions(userID uint) error {
+	now := time.Now()
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+		}
+	}
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeAuthTokenRepo) RevokeAccessToken(token *domain.RevokedToken) error {
+	r.revoked[token.TokenID] = true
+	return nil
+}
+
+func (r *fakeAuthTokenRepo) IsAccessTokenRevoked(tokenID string) (bool, error) {
+	return r.revoked[tokenID], nil
+}
+
+// fakeAPIKeyRepo 记录每个用户未吊销的 API Key 数量
+type fakeAPIKeyRepo struct {
+	domain.APIKeyRepository
+	active map[uint]int64
+}
+
+func (r *fakeAPIKeyRepo) RevokeAllByUser(userID uint, revokedAt time.Time) (int64, error) {
+	count := r.active[userID]
+	r.active[userID] = 0
+	return count, nil
+}
+
+// activeTokens 未吊销的刷新令牌数
+func (r *fakeAuthTokenRepo) activeTokens() int {
+	count := 0
+	for _, token := range r.tokens {
+		if token.RevokedAt == nil {
+			count++
+		}
+	}
+	return count
+}
+
+func newTestAuthTokenService(user *domain.User) (domain.AuthTokenService, *fakeAuthTokenRepo, *jwt.JWTManager) {
+	repo := newFakeAuthTokenRepo()
+	manager := jwt.NewJWTManager("test-secret", 900)
+	userRepo := &fakeUserRepo{users: map[uint]*domain.User{user.ID: user}}
+	return NewAuthTokenService(repo, &fakeAPIKeyRepo{active: map[uint]int64{}}, userRepo, manager, 3600), repo, manager
+}
+
+func TestAuthTokenServiceRefresh(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup 登录并返回要提交的刷新令牌
+		setup   func(t *testing.T, s domain.AuthTokenService, repo *fakeAuthTokenRepo) string
+		wantErr error
+		// wantActive 刷新后仍有效的刷新令牌数
+		wantActive int
+	}{
+		{
+			name: "正常轮换",
+			setup: func(t *testing.T, s domain.AuthTokenService, repo *fakeAuthTokenRepo) string {
+				return login(t, s).RefreshToken
+			},
+			wantActive: 1,
+		},
+		{
+			name: "重放已轮换的令牌注销会话",
+			setup: func(t *testing.T, s domain.AuthTokenService, repo *fakeAuthTokenRepo) string {
+				first := login(t, s).RefreshToken
+				if _, _, err := s.Refresh(first, testClient); err != nil {
+					t.Fatalf("first Refresh() error = %v", err)
+				}
+				return first
+			},
+			wantErr: domain.ErrRefreshTokenReused,
+		},
+		{
+			name: "并发刷新按重放处理",
+			setup: func(t *testing.T, s domain.AuthTokenService, repo *fakeAuthTokenRepo) string {
+				token := login(t, s).RefreshToken
+				repo.rotateConflict = true
+				return token
+			},
+			wantErr: domain.ErrRefreshTokenReused,
+		},
+		{
+			name: "重放只注销所属会话",
+			setup: func(t *testing.T, s domain.AuthTokenService, repo *fakeAuthTokenRepo) string {
+				login(t, s) // 另一台设备
+				first := login(t, s).RefreshToken
+				if _, _, err := s.Refresh(first, testClient); err != nil {
+					t.Fatalf("first Refresh() error = %v", err)
+				}
+				return first
+			},
+			wantErr:    domain.ErrRefreshTokenReused,
+			wantActive: 1,
+		},
+		{
+			name: "已退出会话的令牌",
+			setup: func(t *testing.T, s domain.AuthTokenService, repo *fakeAuthTokenRepo) string {
+				token := login(t, s).RefreshToken
+				repo.RevokeUserSessions(1)
+				return token
+			},
+			wantErr: domain.ErrRefreshTokenInvalid,
+		},
+		{
+			name: "已过期的令牌",
+			setup: func(t *testing.T, s domain.AuthTokenService, repo *fakeAuthTokenRepo) string {
+				token := login(t, s).RefreshToken
+				for _, record := range repo.tokens {
+					record.ExpiresAt = time.Now().Add(-time.Second)
+				}
+				return token
+			},
+			wantErr:    domain.ErrRefreshTokenInvalid,
+			wantActive: 1,
+		},
+		{
+			name: "未知令牌",
+			setup: func(t *testing.T, s domain.AuthTokenService, repo *fakeAuthTokenRepo) string {
+				login(t, s)
+				return "unknown-token"
+			},
+			wantErr:    domain.ErrRefreshTokenInvalid,
+			wantActive: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo, _ := newTestAuthTokenService(&domain.User{ID: 1, Username: "alice", Role: "whitehat"})
+
+			token := tt.setup(t, s, repo)
+			_, pair, err := s.Refresh(token, testClient)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Refresh() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := repo.activeTokens(); got != tt.wantActive {
+				t.Errorf("active refresh tokens = %d, want %d", got, tt.wantActive)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+			if pair.RefreshToken == "" || pair.RefreshToken == token || pair.AccessToken == "" {
+				t.Fatalf("Refresh() pair = %+v, want a new token pair", pair)
+			}
+			// 新令牌可以继续轮换
+			if _, _, err := s.Refresh(pair.RefreshToken, testClient); err != nil {
+				t.Errorf("Refresh(new token) error = %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthTokenServiceReuseRevokesRotatedTokens(t *testing.T) {
+	s, _, _ := newTestAuthTokenService(&domain.User{ID: 1, Username: "alice", Role: "whitehat"})
+
+	stolen := login(t, s).RefreshToken
+	_, pair, err := s.Refresh(stolen, testClient)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	// 攻击者重放旧令牌：会话注销，合法用户手中的新令牌同样失效
+	if _, _, err := s.Refresh(stolen, testClient); !errors.Is(err, domain.ErrRefreshTokenReused) {
+		t.Fatalf("Refresh(stolen) error = %v, want ErrRefreshTokenReused", err)
+	}
+	if _, _, err := s.Refresh(pair.RefreshToken, testClient); !errors.Is(err, domain.ErrRefreshTokenInvalid) {
+		t.Errorf("Refresh(rotated) error = %v, want ErrRefreshTokenInvalid", err)
+	}
+}
+
+func TestAuthTokenServiceLogout(t *testing.T) {
+	tests := []struct {
+		name   string
+		logout func(s domain.AuthTokenService, claims *jwt.Claims) error
+		// 另一台设备上的会话和用户的 API Key 是否仍然有效
+		wantOtherValid bool
+	}{
+		{name: "退出当前会话", logout: func(s domain.AuthTokenService, claims *jwt.Claims) error { return s.Logout(claims) }, wantOtherValid: true},
+		{name: "退出全部会话", logout: func(s domain.AuthTokenService, claims *jwt.Claims) error { return s.LogoutAll(claims.UserID) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := jwt.NewJWTManager("test-secret", 900)
+			apiKeys := &fakeAPIKeyRepo{active: map[uint]int64{1: 2, 2: 1}}
+			userRepo := &fakeUserRepo{users: map[uint]*domain.User{1: {ID: 1, Username: "alice", Role: "whitehat"}}}
+			s := NewAuthTokenService(newFakeAuthTokenRepo(), apiKeys, userRepo, manager, 3600)
+			current, other := login(t, s), login(t, s)
+			claims, err := manager.ParseToken(current.AccessToken)
+			if err != nil {
+				t.Fatal(err)
+			}
+			otherClaims, err := manager.ParseToken(other.AccessToken)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.ValidateAccessToken(claims, testClient.IP); err != nil {
+				t.Fatalf("ValidateAccessToken() before logout error = %v", err)
+			}
+
+			if err := tt.logout(s, claims); err != nil {
+				t.Fatalf("logout error = %v", err)
+			}
+
+			if err := s.ValidateAccessToken(claims, testClient.IP); !errors.Is(err, domain.ErrTokenRevoked) {
+				t.Errorf("ValidateAccessToken(current) error = %v, want ErrTokenRevoked", err)
+			}
+			if _, _, err := s.Refresh(current.RefreshToken, testClient); !errors.Is(err, domain.ErrRefreshTokenInvalid) {
+				t.Errorf("Refresh(current) error = %v, want ErrRefreshTokenInvalid", err)
+			}
+			if err := s.ValidateAccessToken(otherClaims, testClient.IP); (err == nil) != tt.wantOtherValid {
+				t.Errorf("ValidateAccessToken(other) error = %v, want valid = %v", err, tt.wantOtherValid)
+			}
+			if _, _, err := s.Refresh(other.RefreshToken, testClient); (err == nil) != tt.wantOtherValid {
+				t.Errorf("Refresh(other) error = %v, want valid = %v", err, tt.wantOtherValid)
+			}
+			// API Key 不属于任何会话，退出全部会话（重置密码、管理员强制注销）时同样吊销
+			if got := apiKeys.active[1] > 0; got != tt.wantOtherValid {
+				t.Errorf("API Key 有效 = %v, want %v", got, tt.wantOtherValid)
+			}
+			if apiKeys.active[2] != 1 {
+				t.Error("其他用户的 API Key 不应被吊销")
+			}
+		})
+	}
+}
+
+func TestAuthTokenServiceSessions(t *testing.T) {
+	repo := newFakeAuthTokenRepo()
+	manager := jwt.NewJWTManager("test-secret", 900)
+	userRepo := &fakeUserRepo{users: map[uint]*domain.User{
+		1: {ID: 1, Username: "alice", Role: "whitehat"},
+		2: {ID: 2, Username: "bob", Role: "whitehat"},
+	}}
+	s := NewAuthTokenService(repo, &fakeAPIKeyRepo{active: map[uint]int64{}}, userRepo, manager, 3600)
+
+	alice := login(t, s)
+	aliceClaims, err := manager.ParseToken(alice.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	login(t, s) // alice 的另一台设备
+	bob, err := s.IssueTokens(&domain.User{ID: 2, Username: "bob", Role: "whitehat"}, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobClaims, err := manager.ParseToken(bob.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := s.ListSessions(1, aliceClaims.SessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := 0
+	for _, session := range sessions {
+		if session.UserID != 1 {
+			t.Errorf("ListSessions() returned session of user %d", session.UserID)
+		}
+		if session.Current {
+			current++
+			if session.ID != aliceClaims.SessionID {
+				t.Errorf("Current session = %s, want %s", session.ID, aliceClaims.SessionID)
+			}
+		}
+		if session.Device != "curl" || session.LoginIP != testClient.IP {
+			t.Errorf("session client = %q %q, want curl %s", session.Device, session.LoginIP, testClient.IP)
+		}
+	}
+	if len(sessions) != 2 || current != 1 {
+		t.Fatalf("ListSessions() = %d sessions (%d current), want 2 (1 current)", len(sessions), current)
+	}
+
+	// 不能注销其他用户的会话
+	if err := s.RevokeSession(1, bobClaims.SessionID); !errors.Is(err, domain.ErrSessionNotFound) {
+		t.Errorf("RevokeSession(other user) error = %v, want ErrSessionNotFound", err)
+	}
+	if err := s.RevokeSession(1, "missing"); !errors.Is(err, domain.ErrSessionNotFound) {
+		t.Errorf("RevokeSession(missing) error = %v, want ErrSessionNotFound", err)
+	}
+	if err := s.ValidateAccessToken(bobClaims, testClient.IP); err != nil {
+		t.Errorf("ValidateAccessToken(bob) error = %v", err)
+	}
+
+	// 注销会话后，该会话的访问令牌和刷新令牌立即失效
+	if err := s.RevokeSession(1, aliceClaims.SessionID); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+	if err := s.ValidateAccessToken(aliceClaims, testClient.IP); !errors.Is(err, domain.ErrTokenRevoked) {
+		t.Errorf("ValidateAccessToken(revoked session) error = %v, want ErrTokenRevoked", err)
+	}
+	if _, _, err := s.Refresh(alice.RefreshToken, testClient); !errors.Is(err, domain.ErrRefreshTokenInvalid) {
+		t.Errorf("Refresh(revoked session) error = %v, want ErrRefreshTokenInvalid", err)
+	}
+	if sessions, _ := s.ListSessions(1, ""); len(sessions) != 1 {
+		t.Errorf("ListSessions() after revoke = %d sessions, want 1", len(sessions))
+	}
+}
+
+func TestAuthTokenServiceTouchSession(t *testing.T) {
+	s, repo, manager := newTestAuthTokenService(&domain.User{ID: 1, Username: "alice", Role: "whitehat"})
+	claims, err := manager.ParseToken(login(t, s).AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := repo.sessions[claims.SessionID]
+	loginAt := session.LastSeenAt
+
+	// 间隔内同一 IP 的请求不写库
+	if err := s.ValidateAccessToken(claims, testClient.IP); err != nil {
+		t.Fatal(err)
+	}
+	if !session.LastSeenAt.Equal(loginAt) {
+		t.Error("LastSeenAt updated within touch interval")
+	}
+	// IP 变化立即记录
+	if err := s.ValidateAccessToken(claims, "10.0.0.8"); err != nil {
+		t.Fatal(err)
+	}
+	if session.IP != "10.0.0.8" || session.LoginIP != testClient.IP {
+		t.Errorf("session IP = %s (login %s), want 10.0.0.8 (login %s)", session.IP, session.LoginIP, testClient.IP)
+	}
+}
+
+func TestDescribeDevice(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "Chrome / Windows"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0", "Edge / Windows"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", "Safari / macOS"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0 Mobile/15E148 Safari/604.1", "Chrome / iOS"},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Mobile Safari/537.36", "Chrome / Android"},
+		{"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", "Firefox / Linux"},
+		{"curl/8.5.0", "curl"},
+		{"python-requests/2.31.0", "Python"},
+		{"Go-http-client/1.1", "Go"},
+		{"", "未知设备"},
+		{"Burp Suite", "未知设备"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := describeDevice(tt.userAgent); got != tt.want {
+				t.Errorf("describeDevice(%q) = %q, want %q", tt.userAgent, got, tt.want)
+			}
+		})
+	}
+}
+
+// testClient 测试用的客户端信息
+var testClient = domain.ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.5.0"}
+
+// login 为用户 1 登录并返回令牌
+func login(t *testing.T, s domain.AuthTokenService) *domain.TokenPair {
+	t.Helper()
+	pair, err := s.IssueTokens(&domain.User{ID: 1, Username: "alice", Role: "whitehat"}, testClient)
+	if err != nil {
+		t.Fatalf("IssueTokens() error = %v", err)
+	}
+	return pair
+}
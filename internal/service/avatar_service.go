@@ -14,11 +14,12 @@ func NewAvatarService(repo domain.AvatarRepository) domain.AvatarService {
 }
 
 // UploadAvatar 上传头像（记录到数据库）
-func (s *avatarService) UploadAvatar(name string, url string) (*domain.Avatar, error) {
+func (s *avatarService) UploadAvatar(name string, url string, thumbnailURL string) (*domain.Avatar, error) {
 	avatar := &domain.Avatar{
-		Name:     name,
-		URL:      url,
-		IsActive: true,
+		Name:         name,
+		URL:          url,
+		ThumbnailURL: thumbnailURL,
+		IsActive:     true,
 	}
 	if err := s.repo.Create(avatar); err != nil {
 		return nil, err
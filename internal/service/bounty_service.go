@@ -0,0 +1,332 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// currencyPattern 币种代码（ISO 4217，三位大写字母）
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// bountyAwardableStatuses 可以发放奖励的报告状态
+var bountyAwardableStatuses = map[string]bool{
+	domain.ReportStatusTriaged:  true,
+	domain.ReportStatusResolved: true,
+	domain.ReportStatusClosed:   true,
+}
+
+type bountyService struct {
+	repo       domain.BountyRepository
+	reportRepo domain.ReportRepository
+	scopeRepo  domain.ProjectScopeRepository
+}
+
+// NewBountyService 创建奖励服务实例
+func NewBountyService(repo domain.BountyRepository, reportRepo domain.ReportRepository, scopeRepo domain.ProjectScopeRepository) domain.BountyService {
+	return &bountyService{
+		repo:       repo,
+		reportRepo: reportRepo,
+		scopeRepo:  scopeRepo,
+	}
+}
+
+// AwardBounty 为报告发放奖金或额外奖励（仅admin/vendor）
+// 奖励创建后为待审批状态，每份报告最多一笔未取消的奖金，额外奖励不限
+func (s *bountyService) AwardBounty(reportID uint, actorID uint, actorRole string, input *domain.BountyAwardInput) (*domain.BountyReward, error) {
+	if actorRole != "admin" && actorRole != "vendor" {
+		return nil, errors.New("只有管理员和厂商可以发放奖励")
+	}
+
+	report, err := s.reportRepo.FindByID(reportID)
+	if err != nil {
+		return nil, errors.New("报告不存在")
+	}
+	if !bountyAwardableStatuses[report.Status] {
+		return nil, errors.New("只有已确认的报告才能发放奖励")
+	}
+	if report.ScopeID != nil {
+		if scope, err := s.scopeRepo.FindByID(*report.ScopeID); err == nil && (!scope.InScope || !scope.BountyEligible) {
+			return nil, errors.New("漏洞所属资产不在奖励范围内")
+		}
+	}
+
+	rewardType := input.Type
+	if rewardType == "" {
+		rewardType = domain.BountyTypeAward
+	}
+	if rewardType != domain.BountyTypeAward && rewardType != domain.BountyTypeBonus {
+		return nil, errors.New("无效的奖励类型")
+	}
+	if input.Amount <= 0 {
+		return nil, errors.New("奖励金额必须大于0")
+	}
+	currency, err := normalizeCurrency(input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	reward := &domain.BountyReward{
+		ReportID:  report.ID,
+		AuthorID:  report.AuthorID,
+		Type:      rewardType,
+		Amount:    input.Amount,
+		Currency:  currency,
+		Note:      input.Note,
+		Status:    domain.BountyStatusPending,
+		AwardedBy: actorID,
+	}
+	entry := &domain.BountyLedgerEntry{
+		ReportID:  reward.ReportID,
+		AuthorID:  reward.AuthorID,
+		EntryType: domain.BountyEntryCreated,
+		Amount:    reward.Amount,
+		Currency:  reward.Currency,
+		ToStatus:  reward.Status,
+		ActorID:   actorID,
+		Note:      input.Note,
+	}
+
+	// 已有奖金的检查在仓库事务内完成，见 BountyRepository.CreateReward
+	if err := s.repo.CreateReward(reward, entry); err != nil {
+		return nil, err
+	}
+	return reward, nil
+}
+
+// UpdateRewardStatus 变更奖励发放状态
+// pending -> approved（仅admin）
+// pending -> cancelled（admin/vendor）
+// approved -> cancelled（仅admin，且未加入发放批次）
+// 已发放状态只能通过发放批次确认
+func (s *bountyService) UpdateRewardStatus(rewardID uint, status string, actorID uint, actorRole string, note string) (*domain.BountyReward, error) {
+	if actorRole != "admin" && actorRole != "vendor" {
+		return nil, errors.New("permission denied")
+	}
+
+	reward, err := s.repo.FindRewardByID(rewardID)
+	if err != nil {
+		return nil, errors.New("奖励不存在")
+	}
+
+	from := reward.Status
+	switch {
+	case from == domain.BountyStatusPending && status == domain.BountyStatusApproved:
+		if actorRole != "admin" {
+			return nil, errors.New("只有管理员可以审批奖励")
+		}
+	case from == domain.BountyStatusPending && status == domain.BountyStatusCancelled:
+	case from == domain.BountyStatusApproved && status == domain.BountyStatusCancelled:
+		if actorRole != "admin" {
+			return nil, errors.New("只有管理员可以取消已审批的奖励")
+		}
+		if reward.PayoutBatchID != nil {
+			return nil, errors.New("奖励已加入发放批次，无法取消")
+		}
+	case status == domain.BountyStatusPaid:
+		return nil, errors.New("奖励只能通过发放批次标记为已发放")
+	default:
+		return nil, errors.New("不允许从 " + from + " 变更为 " + status)
+	}
+
+	reward.Status = status
+	entry := &domain.BountyLedgerEntry{
+		ReportID:   reward.ReportID,
+		AuthorID:   reward.AuthorID,
+		EntryType:  domain.BountyEntryStatusChanged,
+		Amount:     reward.Amount,
+		Currency:   reward.Currency,
+		FromStatus: from,
+		ToStatus:   status,
+		ActorID:    actorID,
+		Note:       note,
+	}
+
+	if err := s.repo.UpdateRewardStatus(reward, from, entry); err != nil {
+		return nil, err
+	}
+	return reward, nil
+}
+
+// ListReportBounties 获取报告的奖励及账本
+// 白帽子只能查看自己报告的奖励
+func (s *bountyService) ListReportBounties(reportID uint, userID uint, userRole string) ([]domain.BountyReward, []domain.BountyLedgerEntry, error) {
+	report, err := s.reportRepo.FindByID(reportID)
+	if err != nil {
+		return nil, nil, errors.New("报告不存在")
+	}
+	if userRole != "admin" && userRole != "vendor" && report.AuthorID != userID {
+		return nil, nil, errors.New("permission denied")
+	}
+
+	rewards, err := s.repo.ListRewardsByReport(reportID)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := s.repo.ListLedgerByReport(reportID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rewards, entries, nil
+}
+
+// GetUserEarnings 获取白帽子的收益汇总（按币种统计，不含已取消的奖励）
+func (s *bountyService) GetUserEarnings(userID uint) (*domain.UserEarnings, error) {
+	rewards, err := s.repo.ListRewardsByAuthor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make([]domain.EarningsTotal, 0)
+	index := make(map[string]int)
+	for _, reward := range rewards {
+		if reward.Status == domain.BountyStatusCancelled {
+			continue
+		}
+		i, ok := index[reward.Currency]
+		if !ok {
+			totals = append(totals, domain.EarningsTotal{Currency: reward.Currency})
+			i = len(totals) - 1
+			index[reward.Currency] = i
+		}
+		switch reward.Status {
+		case domain.BountyStatusPending:
+			totals[i].Pending += reward.Amount
+		case domain.BountyStatusApproved:
+			totals[i].Approved += reward.Amount
+		case domain.BountyStatusPaid:
+			totals[i].Paid += reward.Amount
+		}
+	}
+
+	return &domain.UserEarnings{
+		Totals:  totals,
+		Rewards: rewards,
+	}, nil
+}
+
+// CreatePayoutBatch 将指定币种所有已审批且未打包的奖励创建为发放批次（仅admin）
+func (s *bountyService) CreatePayoutBatch(currency string, actorID uint, actorRole string, note string) (*domain.PayoutBatch, error) {
+	if actorRole != "admin" {
+		return nil, errors.New("只有管理员可以创建发放批次")
+	}
+
+	currency, err := normalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &domain.PayoutBatch{
+		Currency:  currency,
+		Status:    domain.PayoutBatchExported,
+		CreatedBy: actorID,
+		Note:      note,
+	}
+	if err := s.repo.CreateBatch(batch); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("没有可发放的已审批奖励")
+		}
+		return nil, err
+	}
+	return batch, nil
+}
+
+// ListPayoutBatches 分页获取发放批次（仅admin）
+func (s *bountyService) ListPayoutBatches(page, pageSize int, actorRole string) ([]domain.PayoutBatch, int64, error) {
+	if actorRole != "admin" {
+		return nil, 0, errors.New("permission denied")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+	return s.repo.ListBatches(page, pageSize)
+}
+
+// ExportPayoutBatch 获取批次及其中的奖励，用于导出（仅admin）
+func (s *bountyService) ExportPayoutBatch(batchID uint, actorRole string) (*domain.PayoutBatch, []domain.BountyReward, error) {
+	if actorRole != "admin" {
+		return nil, nil, errors.New("permission denied")
+	}
+
+	batch, err := s.repo.FindBatchByID(batchID)
+	if err != nil {
+		return nil, nil, errors.New("发放批次不存在")
+	}
+	rewards, err := s.repo.ListRewardsByBatch(batchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return batch, rewards, nil
+}
+
+// MarkPayoutBatchPaid 确认批次已线下打款，批次中的奖励全部标记为已发放（仅admin）
+func (s *bountyService) MarkPayoutBatchPaid(batchID uint, actorID uint, actorRole string) (*domain.PayoutBatch, error) {
+	if actorRole != "admin" {
+		return nil, errors.New("只有管理员可以确认打款")
+	}
+
+	batch, err := s.repo.FindBatchByID(batchID)
+	if err != nil {
+		return nil, errors.New("发放批次不存在")
+	}
+	if batch.Status == domain.PayoutBatchPaid {
+		return nil, errors.New("该批次已确认打款")
+	}
+
+	if err := s.repo.MarkBatchPaid(batch, actorID); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// severityScoreRanges 各危害等级对应的 CVSS 分数区间，用于在奖金范围内按分数插值
+var severityScoreRanges = map[string][2]float64{
+	"Critical": {9.0, 10.0},
+	"High":     {7.0, 8.9},
+	"Medium":   {4.0, 6.9},
+	"Low":      {0.1, 3.9},
+}
+
+// suggestBounty 根据项目奖励表计算报告的建议奖金
+// 有 CVSS 分数时按分数在等级区间内的位置插值，否则取范围中值
+func suggestBounty(project *domain.Project, report *domain.Report) *domain.BountySuggestion {
+	tier := project.FindRewardTier(report.Severity)
+	if tier == nil {
+		return nil
+	}
+
+	suggested := tier.MinAmount + (tier.MaxAmount-tier.MinAmount)/2
+	if scoreRange, ok := severityScoreRanges[report.Severity]; ok && report.CVSSScore != nil {
+		score := *report.CVSSScore
+		if score >= scoreRange[0] && score <= scoreRange[1] {
+			ratio := (score - scoreRange[0]) / (scoreRange[1] - scoreRange[0])
+			suggested = tier.MinAmount + int64(float64(tier.MaxAmount-tier.MinAmount)*ratio)
+		}
+	}
+
+	return &domain.BountySuggestion{
+		Severity:        tier.Severity,
+		MinAmount:       tier.MinAmount,
+		MaxAmount:       tier.MaxAmount,
+		SuggestedAmount: suggested,
+		Currency:        tier.Currency,
+	}
+}
+
+// normalizeCurrency 规范化币种代码，为空时使用默认币种
+func normalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return domain.DefaultBountyCurrency, nil
+	}
+	if !currencyPattern.MatchString(currency) {
+		return "", errors.New("无效的币种代码")
+	}
+	return currency, nil
+}
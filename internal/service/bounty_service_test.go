@@ -0,0 +1,292 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"testing"
+)
+
+// fakeBountyRepo 内存中的奖励和账本
+type fakeBountyRepo struct {
+	domain.BountyRepository
+	rewards map[uint]*domain.BountyReward
+	ledger  []domain.BountyLedgerEntry
+	// beforeUpdate 模拟读取奖励后、条件更新前的并发操作
+	beforeUpdate func()
+	// beforeCreate 模拟校验报告后、创建奖励前的并发操作
+	beforeCreate func()
+}
+
+func newFakeBountyRepo(rewards ...domain.BountyReward) *fakeBountyRepo {
+	r := &fakeBountyRepo{rewards: make(map[uint]*domain.BountyReward)}
+	for i := range rewards {
+		r.rewards[rewards[i].ID] = &rewards[i]
+	}
+	return r
+}
+
+func (r *fakeBountyRepo) CreateReward(reward *domain.BountyReward, entry *domain.BountyLedgerEntry) error {
+	if r.beforeCreate != nil {
+		r.beforeCreate()
+	}
+	if reward.Type == domain.BountyTypeAward {
+		for _, existing := range r.rewards {
+			if existing.ReportID == reward.ReportID && existing.Type == domain.BountyTypeAward && existing.Status != domain.BountyStatusCancelled {
+				return domain.ErrBountyAlreadyAwarded
+			}
+		}
+	}
+	reward.ID = uint(len(r.rewards) + 1)
+	r.rewards[reward.ID] = reward
+	entry.RewardID = reward.ID
+	r.ledger = append(r.ledger, *entry)
+	return nil
+}
+
+func (r *fakeBountyRepo) UpdateRewardStatus(reward *domain.BountyReward, fromStatus string, entry *domain.BountyLedgerEntry) error {
+	if r.beforeUpdate != nil {
+		r.beforeUpdate()
+	}
+	stored := r.rewards[reward.ID]
+	if stored.Status != fromStatus || stored.PayoutBatchID != nil {
+		return domain.ErrBountyRewardConflict
+	}
+	copied := *reward
+	r.rewards[reward.ID] = &copied
+	r.ledger = append(r.ledger, *entry)
+	return nil
+}
+
+func (r *fakeBountyRepo) FindRewardByID(id uint) (*domain.BountyReward, error) {
+	reward, ok := r.rewards[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	copied := *reward
+	return &copied, nil
+}
+
+func (r *fakeBountyRepo) ListRewardsByReport(reportID uint) ([]domain.BountyReward, error) {
+	var result []domain.BountyReward
+	for _, reward := range r.rewards {
+		if reward.ReportID == reportID {
+			result = append(result, *reward)
+		}
+	}
+	return result, nil
+}
+
+// fakeReportRepo 按ID查找报告
+type fakeReportRepo struct {
+	domain.ReportRepository
+	reports map[uint]*domain.Report
+}
+
+func (r *fakeReportRepo) FindByID(id uint) (*domain.Report, error) {
+	report, ok := r.reports[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return report, nil
+}
+
+func TestBountyUpdateRewardStatus(t *testing.T) {
+	batchID := uint(1)
+
+	tests := []struct {
+		name    string
+		reward  domain.BountyReward
+		status  string
+		role    string
+		wantErr bool
+	}{
+		{name: "管理员审批", reward: domain.BountyReward{Status: domain.BountyStatusPending}, status: domain.BountyStatusApproved, role: "admin"},
+		{name: "厂商不能审批", reward: domain.BountyReward{Status: domain.BountyStatusPending}, status: domain.BountyStatusApproved, role: "vendor", wantErr: true},
+		{name: "厂商取消待审批奖励", reward: domain.BountyReward{Status: domain.BountyStatusPending}, status: domain.BountyStatusCancelled, role: "vendor"},
+		{name: "管理员取消已审批奖励", reward: domain.BountyReward{Status: domain.BountyStatusApproved}, status: domain.BountyStatusCancelled, role: "admin"},
+		{name: "厂商不能取消已审批奖励", reward: domain.BountyReward{Status: domain.BountyStatusApproved}, status: domain.BountyStatusCancelled, role: "vendor", wantErr: true},
+		{name: "已加入批次不能取消", reward: domain.BountyReward{Status: domain.BountyStatusApproved, PayoutBatchID: &batchID}, status: domain.BountyStatusCancelled, role: "admin", wantErr: true},
+		{name: "不能直接标记已发放", reward: domain.BountyReward{Status: domain.BountyStatusApproved}, status: domain.BountyStatusPaid, role: "admin", wantErr: true},
+		{name: "已取消不能恢复", reward: domain.BountyReward{Status: domain.BountyStatusCancelled}, status: domain.BountyStatusPending, role: "admin", wantErr: true},
+		{name: "已发放不能取消", reward: domain.BountyReward{Status: domain.BountyStatusPaid}, status: domain.BountyStatusCancelled, role: "admin", wantErr: true},
+		{name: "白帽无权操作", reward: domain.BountyReward{Status: domain.BountyStatusPending}, status: domain.BountyStatusCancelled, role: "whitehat", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.reward.ID = 1
+			tt.reward.Amount = 50000
+			tt.reward.Currency = "CNY"
+			repo := newFakeBountyRepo(tt.reward)
+			service := NewBountyService(repo, nil, nil)
+
+			got, err := service.UpdateRewardStatus(1, tt.status, 9, tt.role, "备注")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateRewardStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if len(repo.ledger) != 0 || repo.rewards[1].Status != tt.reward.Status {
+					t.Errorf("失败的状态变更不应写入: status = %s, ledger = %+v", repo.rewards[1].Status, repo.ledger)
+				}
+				return
+			}
+			if got.Status != tt.status {
+				t.Errorf("Status = %s, want %s", got.Status, tt.status)
+			}
+			want := domain.BountyLedgerEntry{EntryType: domain.BountyEntryStatusChanged, Amount: 50000, Currency: "CNY", FromStatus: tt.reward.Status, ToStatus: tt.status, ActorID: 9, Note: "备注"}
+			if len(repo.ledger) != 1 || repo.ledger[0] != want {
+				t.Errorf("ledger = %+v, want %+v", repo.ledger, want)
+			}
+		})
+	}
+}
+
+func TestBountyUpdateRewardStatusConflict(t *testing.T) {
+	batchID := uint(3)
+	repo := newFakeBountyRepo(domain.BountyReward{ID: 1, Status: domain.BountyStatusApproved, Amount: 50000, Currency: "CNY"})
+	// 管理员取消奖励的同时，奖励被打包进发放批次
+	repo.beforeUpdate = func() { repo.rewards[1].PayoutBatchID = &batchID }
+	service := NewBountyService(repo, nil, nil)
+
+	if _, err := service.UpdateRewardStatus(1, domain.BountyStatusCancelled, 9, "admin", ""); !errors.Is(err, domain.ErrBountyRewardConflict) {
+		t.Fatalf("UpdateRewardStatus() error = %v, want ErrBountyRewardConflict", err)
+	}
+	if repo.rewards[1].Status != domain.BountyStatusApproved || len(repo.ledger) != 0 {
+		t.Errorf("冲突时不应写入: status = %s, ledger = %+v", repo.rewards[1].Status, repo.ledger)
+	}
+}
+
+func TestBountyAwardBounty(t *testing.T) {
+	noBountyScope, outOfScope := uint(2), uint(3)
+	reports := &fakeReportRepo{reports: map[uint]*domain.Report{
+		1: {ID: 1, AuthorID: 7, Status: domain.ReportStatusTriaged},
+		2: {ID: 2, AuthorID: 7, Status: domain.ReportStatusPending},
+		3: {ID: 3, AuthorID: 7, Status: domain.ReportStatusTriaged, ScopeID: &noBountyScope},
+		4: {ID: 4, AuthorID: 7, Status: domain.ReportStatusTriaged, ScopeID: &outOfScope},
+	}}
+	scopes := &fakeScopeRepo{scopes: []domain.ProjectScope{
+		{ID: 1, InScope: true, BountyEligible: true},
+		{ID: 2, InScope: true, BountyEligible: false},
+		{ID: 3, InScope: false, BountyEligible: true},
+	}}
+
+	tests := []struct {
+		name     string
+		existing []domain.BountyReward
+		reportID uint
+		role     string
+		input    domain.BountyAwardInput
+		wantErr  bool
+	}{
+		{name: "发放奖金", reportID: 1, role: "vendor", input: domain.BountyAwardInput{Amount: 100, Currency: " cny "}},
+		{name: "白帽不能发放", reportID: 1, role: "whitehat", input: domain.BountyAwardInput{Amount: 100}, wantErr: true},
+		{name: "报告未确认", reportID: 2, role: "admin", input: domain.BountyAwardInput{Amount: 100}, wantErr: true},
+		{name: "资产不在奖励范围内", reportID: 3, role: "admin", input: domain.BountyAwardInput{Amount: 100}, wantErr: true},
+		{name: "资产在范围外", reportID: 4, role: "admin", input: domain.BountyAwardInput{Amount: 100}, wantErr: true},
+		{name: "报告不存在", reportID: 5, role: "admin", input: domain.BountyAwardInput{Amount: 100}, wantErr: true},
+		{name: "金额为0", reportID: 1, role: "admin", input: domain.BountyAwardInput{Amount: 0}, wantErr: true},
+		{name: "币种无效", reportID: 1, role: "admin", input: domain.BountyAwardInput{Amount: 100, Currency: "RMB1"}, wantErr: true},
+		{name: "奖励类型无效", reportID: 1, role: "admin", input: domain.BountyAwardInput{Type: "gift", Amount: 100}, wantErr: true},
+		{
+			name:     "已有奖金",
+			existing: []domain.BountyReward{{ID: 1, ReportID: 1, Type: domain.BountyTypeAward, Status: domain.BountyStatusApproved}},
+			reportID: 1, role: "admin", input: domain.BountyAwardInput{Amount: 100}, wantErr: true,
+		},
+		{
+			name:     "已有奖金被取消后可以重新发放",
+			existing: []domain.BountyReward{{ID: 1, ReportID: 1, Type: domain.BountyTypeAward, Status: domain.BountyStatusCancelled}},
+			reportID: 1, role: "admin", input: domain.BountyAwardInput{Amount: 100},
+		},
+		{
+			name:     "已有奖金时可以发放额外奖励",
+			existing: []domain.BountyReward{{ID: 1, ReportID: 1, Type: domain.BountyTypeAward, Status: domain.BountyStatusPending}},
+			reportID: 1, role: "admin", input: domain.BountyAwardInput{Type: domain.BountyTypeBonus, Amount: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeBountyRepo(tt.existing...)
+			service := NewBountyService(repo, reports, scopes)
+
+			got, err := service.AwardBounty(tt.reportID, 9, tt.role, &tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AwardBounty() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if len(repo.ledger) != 0 {
+					t.Errorf("ledger = %+v, want empty", repo.ledger)
+				}
+				return
+			}
+			if got.AuthorID != 7 || got.Status != domain.BountyStatusPending || got.Currency != "CNY" || got.AwardedBy != 9 {
+				t.Errorf("AwardBounty() = %+v", got)
+			}
+			if len(repo.ledger) != 1 || repo.ledger[0].EntryType != domain.BountyEntryCreated || repo.ledger[0].ToStatus != domain.BountyStatusPending {
+				t.Errorf("ledger = %+v, want one created entry", repo.ledger)
+			}
+		})
+	}
+}
+
+func TestBountyAwardBountyConcurrent(t *testing.T) {
+	reports := &fakeReportRepo{reports: map[uint]*domain.Report{1: {ID: 1, AuthorID: 7, Status: domain.ReportStatusTriaged}}}
+	repo := newFakeBountyRepo()
+	// 管理员发放奖金的同时，厂商已为同一报告发放了奖金
+	repo.beforeCreate = func() {
+		repo.rewards[100] = &domain.BountyReward{ID: 100, ReportID: 1, Type: domain.BountyTypeAward, Status: domain.BountyStatusPending}
+	}
+	service := NewBountyService(repo, reports, nil)
+
+	if _, err := service.AwardBounty(1, 9, "admin", &domain.BountyAwardInput{Amount: 100}); !errors.Is(err, domain.ErrBountyAlreadyAwarded) {
+		t.Fatalf("AwardBounty() error = %v, want ErrBountyAlreadyAwarded", err)
+	}
+	if len(repo.rewards) != 1 || len(repo.ledger) != 0 {
+		t.Errorf("rewards = %d, ledger = %+v, want only the concurrent award", len(repo.rewards), repo.ledger)
+	}
+}
+
+func TestSuggestBounty(t *testing.T) {
+	project := &domain.Project{RewardTable: []domain.ProjectRewardTier{
+		{Severity: "High", MinAmount: 10000, MaxAmount: 30000, Currency: "CNY"},
+		{Severity: "Low", MinAmount: 500, MaxAmount: 500, Currency: "USD"},
+	}}
+	score := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name     string
+		severity string
+		score    *float64
+		want     int64
+		wantNil  bool
+	}{
+		{name: "没有 CVSS 分数取中值", severity: "High", want: 20000},
+		{name: "等级区间下限", severity: "High", score: score(7.0), want: 10000},
+		{name: "等级区间中间", severity: "High", score: score(7.95), want: 20000},
+		{name: "等级区间上限", severity: "High", score: score(8.9), want: 30000},
+		// 危害等级被审核人员覆盖，分数不在等级区间内
+		{name: "分数不在等级区间内取中值", severity: "High", score: score(9.8), want: 20000},
+		{name: "固定金额", severity: "Low", score: score(2.0), want: 500},
+		{name: "奖励表未设置该等级", severity: "Critical", wantNil: true},
+		{name: "没有危害等级", wantNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := suggestBounty(project, &domain.Report{Severity: tt.severity, CVSSScore: tt.score})
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("suggestBounty() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.SuggestedAmount != tt.want {
+				t.Fatalf("suggestBounty() = %+v, want suggested %d", got, tt.want)
+			}
+			tier := project.FindRewardTier(tt.severity)
+			if got.MinAmount != tier.MinAmount || got.MaxAmount != tier.MaxAmount || got.Currency != tier.Currency {
+				t.Errorf("suggestBounty() = %+v, want range of %+v", got, tier)
+			}
+		})
+	}
+}
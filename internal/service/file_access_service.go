@@ -0,0 +1,94 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/upload"
+	"errors"
+	"strings"
+)
+
+type fileAccessService struct {
+	reportRepo           domain.ReportRepository
+	reportAttachmentRepo domain.ReportAttachmentRepository
+	attachmentRepo       domain.ProjectAttachmentRepository
+	assignmentRepo       domain.ProjectAssignmentRepository
+}
+
+// NewFileAccessService 创建文件下载鉴权服务实例
+func NewFileAccessService(
+	reportRepo domain.ReportRepository,
+	reportAttachmentRepo domain.ReportAttachmentRepository,
+	attachmentRepo domain.ProjectAttachmentRepository,
+	assignmentRepo domain.ProjectAssignmentRepository,
+) domain.FileAccessService {
+	return &fileAccessService{
+		reportRepo:           reportRepo,
+		reportAttachmentRepo: reportAttachmentRepo,
+		attachmentRepo:       attachmentRepo,
+		assignmentRepo:       assignmentRepo,
+	}
+}
+
+// CheckAccess 校验用户能否下载文件（缩略图按原图校验）
+func (s *fileAccessService) CheckAccess(filePath string, userID uint, userRole string) error {
+	filePath = upload.SourceKey(filePath)
+	dir, _, _ := strings.Cut(filePath, "/")
+	if dir != domain.FileDirReports && dir != domain.FileDirProjects {
+		return errors.New("文件不存在")
+	}
+
+	if userRole == "admin" || userRole == "vendor" {
+		return nil
+	}
+
+	switch dir {
+	case domain.FileDirReports:
+		// 白帽子只能下载自己报告的附件
+		attachments, err := s.reportAttachmentRepo.FindByFilePath(filePath)
+		if err != nil {
+			return err
+		}
+		for _, attachment := range attachments {
+			if report, err := s.reportRepo.FindByID(attachment.ReportID); err == nil && report.AuthorID == userID {
+				return nil
+			}
+		}
+	case domain.FileDirProjects:
+		// 白帽子只能下载被指派项目的附件
+		attachments, err := s.attachmentRepo.FindByFilePath(filePath)
+		if err != nil {
+			return err
+		}
+		for _, attachment := range attachments {
+			if _, err := s.assignmentRepo.FindByProjectAndUser(attachment.ProjectID, userID); err == nil {
+				return nil
+			}
+		}
+	}
+
+	return errors.New("您无权下载该文件")
+}
+
+// CheckScanStatus 校验报告附件已通过恶意软件扫描
+// 未被报告附件引用的文件（如刚上传尚未关联、项目附件）不受限制
+func (s *fileAccessService) CheckScanStatus(filePath string) error {
+	filePath = upload.SourceKey(filePath)
+	dir, _, _ := strings.Cut(filePath, "/")
+	if dir != domain.FileDirReports {
+		return nil
+	}
+
+	attachments, err := s.reportAttachmentRepo.FindByFilePath(filePath)
+	if err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		switch attachment.ScanStatus {
+		case domain.ScanStatusInfected:
+			return errors.New("文件包含恶意内容，已被隔离")
+		case domain.ScanStatusPending:
+			return errors.New("文件正在进行安全扫描，请稍后下载")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"testing"
+)
+
+// fakeReportAttachmentRepo 按文件路径查找报告附件
+type fakeReportAttachmentRepo struct {
+	domain.ReportAttachmentRepository
+	byPath map[string][]domain.ReportAttachment
+}
+
+func (r *fakeReportAttachmentRepo) FindByFilePath(filePath string) ([]domain.ReportAttachment, error) {
+	return r.byPath[filePath], nil
+}
+
+// fakeProjectAttachmentRepo 按文件路径查找项目附件
+type fakeProjectAttachmentRepo struct {
+	domain.ProjectAttachmentRepository
+	byPath map[string][]domain.ProjectAttachment
+}
+
+func (r *fakeProjectAttachmentRepo) FindByFilePath(filePath string) ([]domain.ProjectAttachment, error) {
+	return r.byPath[filePath], nil
+}
+
+// fakeAssignmentRepo 项目指派关系
+type fakeAssignmentRepo struct {
+	domain.ProjectAssignmentRepository
+	assigned map[[2]uint]bool // {projectID, userID}
+}
+
+func (r *fakeAssignmentRepo) FindByProjectAndUser(projectID, userID uint) (*domain.ProjectAssignment, error) {
+	if !r.assigned[[2]uint{projectID, userID}] {
+		return nil, errors.New("record not found")
+	}
+	return &domain.ProjectAssignment{ProjectID: projectID, UserID: userID}, nil
+}
+
+func TestFileAccessCheckAccess(t *testing.T) {
+	service := NewFileAccessService(
+		&fakeReportRepo{reports: map[uint]*domain.Report{1: {ID: 1, AuthorID: 7}}},
+		&fakeReportAttachmentRepo{byPath: map[string][]domain.ReportAttachment{
+			"reports/a.pdf": {{ID: 1, ReportID: 1}},
+			// 附件所属报告已被删除
+			"reports/c.pdf": {{ID: 2, ReportID: 2}},
+		}},
+		&fakeProjectAttachmentRepo{byPath: map[string][]domain.ProjectAttachment{
+			"projects/spec.pdf": {{ID: 1, ProjectID: 3}},
+		}},
+		&fakeAssignmentRepo{assigned: map[[2]uint]bool{{3, 7}: true}},
+	)
+
+	tests := []struct {
+		name     string
+		filePath string
+		userID   uint
+		role     string
+		wantErr  bool
+	}{
+		{name: "白帽下载自己报告的附件", filePath: "reports/a.pdf", userID: 7, role: "whitehat"},
+		{name: "白帽下载他人报告的附件", filePath: "reports/a.pdf", userID: 8, role: "whitehat", wantErr: true},
+		{name: "白帽下载未关联报告的文件", filePath: "reports/b.pdf", userID: 7, role: "whitehat", wantErr: true},
+		{name: "白帽下载已删除报告的附件", filePath: "reports/c.pdf", userID: 7, role: "whitehat", wantErr: true},
+		{name: "白帽下载被指派项目的附件", filePath: "projects/spec.pdf", userID: 7, role: "whitehat"},
+		{name: "白帽下载未指派项目的附件", filePath: "projects/spec.pdf", userID: 8, role: "whitehat", wantErr: true},
+		{name: "厂商下载任意报告附件", filePath: "reports/b.pdf", userID: 2, role: "vendor"},
+		{name: "管理员下载任意项目附件", filePath: "projects/other.pdf", userID: 1, role: "admin"},
+		// 头像等其他目录不通过受控下载接口提供
+		{name: "管理员下载其他目录", filePath: "avatars/a.png", userID: 1, role: "admin", wantErr: true},
+		{name: "目录前缀不完整", filePath: "reportsx/a.pdf", userID: 1, role: "admin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.CheckAccess(tt.filePath, tt.userID, tt.role)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckAccess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
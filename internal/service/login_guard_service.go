@@ -0,0 +1,249 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/config"
+	"bug-bounty-lite/pkg/lockout"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxLoginAttempts   = 5
+	defaultIPMaxLoginAttempts = 20
+	defaultLoginBaseDelay     = time.Second
+	defaultLockoutDuration    = 15 * time.Minute
+	defaultMaxLockoutDuration = 24 * time.Hour
+	defaultLoginFailureWindow = time.Hour
+	// maxLoginBackoff 未达到锁定次数前单次退避的最长等待时间
+	maxLoginBackoff = 30 * time.Second
+	// lockoutStoreTimeout 单次访问失败计数存储的超时
+	lockoutStoreTimeout = 2 * time.Second
+)
+
+// loginGuardTarget 一个计数维度（用户名或 IP）
+type loginGuardTarget struct {
+	key         string
+	kind        string // 用户名 / IP
+	maxAttempts int
+}
+
+type loginGuardService struct {
+	store       lockout.Store
+	eventRepo   domain.SecurityEventRepository
+	maxAttempts int
+	ipMax       int
+	baseDelay   time.Duration
+	lockout     time.Duration
+	maxLockout  time.Duration
+	window      time.Duration
+}
+
+// NewLoginGuardService 创建登录防暴力破解服务实例
+func NewLoginGuardService(store lockout.Store, eventRepo domain.SecurityEventRepository, cfg config.LockoutConfig) domain.LoginGuardService {
+	s := &loginGuardService{
+		store:       store,
+		eventRepo:   eventRepo,
+		maxAttempts: cfg.MaxAttempts,
+		ipMax:       cfg.IPMaxAttempts,
+		baseDelay:   time.Duration(cfg.BaseDelay) * time.Second,
+		lockout:     time.Duration(cfg.LockoutDuration) * time.Second,
+		maxLockout:  time.Duration(cfg.MaxLockoutDuration) * time.Second,
+		window:      time.Duration(cfg.Window) * time.Second,
+	}
+	if s.maxAttempts <= 0 {
+		s.maxAttempts = defaultMaxLoginAttempts
+	}
+	if s.ipMax <= 0 {
+		s.ipMax = defaultIPMaxLoginAttempts
+	}
+	if s.baseDelay <= 0 {
+		s.baseDelay = defaultLoginBaseDelay
+	}
+	if s.lockout <= 0 {
+		s.lockout = defaultLockoutDuration
+	}
+	if s.maxLockout <= 0 {
+		s.maxLockout = defaultMaxLockoutDuration
+	}
+	if s.maxLockout < s.lockout {
+		s.maxLockout = s.lockout
+	}
+	if s.window <= 0 {
+		s.window = defaultLoginFailureWindow
+	}
+	return s
+}
+
+// Check 校验密码前检查用户名和 IP 是否处于退避或锁定状态
+// 计数存储不可用时放行并记录日志，避免存储故障导致所有用户无法登录
+func (s *loginGuardService) Check(username, ip string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), lockoutStoreTimeout)
+	defer cancel()
+
+	now := time.Now()
+	var retryAfter time.Duration
+	for _, target := range s.targets(username, ip) {
+		state, err := s.store.Get(ctx, target.key)
+		if err != nil {
+			log.Printf("[WARN] Failed to read login attempts for %s: %v", target.key, err)
+			continue
+		}
+		if state.Locked(now) && state.LockedUntil.Sub(now) > retryAfter {
+			retryAfter = state.LockedUntil.Sub(now)
+		}
+	}
+	if retryAfter > 0 {
+		return &domain.LoginThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RecordFailure 记录一次登录失败
+// 失败次数达到上限的一半后开始指数退避（base_delay、2 倍、4 倍……），达到上限后锁定并记录安全事件，之后每多失败一次锁定时间翻倍
+func (s *loginGuardService) RecordFailure(username, ip string, userID uint) {
+	ctx, cancel := context.WithTimeout(context.Background(), lockoutStoreTimeout)
+	defer cancel()
+
+	now := time.Now()
+	for _, target := range s.targets(username, ip) {
+		failures, err := s.store.AddFailure(ctx, target.key, s.window)
+		if err != nil {
+			log.Printf("[WARN] Failed to record login failure for %s: %v", target.key, err)
+			continue
+		}
+
+		delay, locked := s.delay(failures, target.maxAttempts)
+		if delay <= 0 {
+			continue
+		}
+		if err := s.store.LockUntil(ctx, target.key, now.Add(delay)); err != nil {
+			log.Printf("[WARN] Failed to lock %s: %v", target.key, err)
+			continue
+		}
+		if !locked {
+			continue
+		}
+
+		log.Printf("[WARN] Login locked: %s after %d failed attempt(s), for %s", target.key, failures, delay)
+		s.recordEvent(&domain.SecurityEvent{
+			Type:     domain.SecurityEventLoginLockout,
+			UserID:   userID,
+			Username: username,
+			IP:       ip,
+			Detail:   fmt.Sprintf("%s连续登录失败 %d 次，锁定 %s", target.kind, failures, delay),
+		})
+	}
+}
+
+// RecordSuccess 登录成功后清除用户名的失败计数
+func (s *loginGuardService) RecordSuccess(username string) {
+	ctx, cancel := context.WithTimeout(context.Background(), lockoutStoreTimeout)
+	defer cancel()
+
+	key := usernameKey(username)
+	if err := s.store.Reset(ctx, key); err != nil {
+		log.Printf("[WARN] Failed to reset login attempts for %s: %v", key, err)
+	}
+}
+
+// Unlock 管理员解除用户名和/或 IP 的锁定并清除失败计数
+func (s *loginGuardService) Unlock(username, ip string, adminID uint) error {
+	username = strings.TrimSpace(username)
+	ip = strings.TrimSpace(ip)
+	if username == "" && ip == "" {
+		return errors.New("用户名和IP不能同时为空")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lockoutStoreTimeout)
+	defer cancel()
+
+	for _, target := range s.targets(username, ip) {
+		if err := s.store.Reset(ctx, target.key); err != nil {
+			return fmt.Errorf("解除锁定失败: %v", err)
+		}
+	}
+
+	s.recordEvent(&domain.SecurityEvent{
+		Type:       domain.SecurityEventLoginUnlock,
+		Username:   username,
+		IP:         ip,
+		Detail:     "管理员解除登录锁定",
+		OperatorID: adminID,
+	})
+	return nil
+}
+
+// ListEvents 分页查询安全事件
+func (s *loginGuardService) ListEvents(filter domain.SecurityEventFilter, page, pageSize int) ([]domain.SecurityEvent, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.eventRepo.List(filter, page, pageSize)
+}
+
+// delay 第 failures 次失败后的等待时间，locked 表示已达到锁定次数
+func (s *loginGuardService) delay(failures, maxAttempts int) (time.Duration, bool) {
+	if failures >= maxAttempts {
+		return backoff(s.lockout, failures-maxAttempts, s.maxLockout), true
+	}
+	// 偶尔输错不等待；IP 的上限较高，同一出口 IP 下的其他用户不会因少量失败被拖慢
+	start := maxAttempts / 2
+	if start < 2 {
+		start = 2
+	}
+	if failures < start {
+		return 0, false
+	}
+	return backoff(s.baseDelay, failures-start, maxLoginBackoff), false
+}
+
+// targets 需要检查和计数的维度，用户名或 IP 为空时跳过
+func (s *loginGuardService) targets(username, ip string) []loginGuardTarget {
+	var targets []loginGuardTarget
+	if strings.TrimSpace(username) != "" {
+		targets = append(targets, loginGuardTarget{key: usernameKey(username), kind: "用户名", maxAttempts: s.maxAttempts})
+	}
+	if ip != "" {
+		targets = append(targets, loginGuardTarget{key: "ip:" + ip, kind: "IP", maxAttempts: s.ipMax})
+	}
+	return targets
+}
+
+// recordEvent 记录安全事件，失败时只记录日志
+func (s *loginGuardService) recordEvent(event *domain.SecurityEvent) {
+	if len(event.Username) > 64 {
+		event.Username = event.Username[:64]
+	}
+	if err := s.eventRepo.Create(event); err != nil {
+		log.Printf("[WARN] Failed to record security event %s: %v", event.Type, err)
+	}
+}
+
+// usernameKey 用户名计数键（忽略大小写和首尾空格，防止变换大小写绕过计数；用户名最长 64 个字符，超出部分不参与计数）
+func usernameKey(username string) string {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if len(username) > 64 {
+		username = username[:64]
+	}
+	return "user:" + username
+}
+
+// backoff 计算 base * 2^exp，不超过 limit
+func backoff(base time.Duration, exp int, limit time.Duration) time.Duration {
+	d := base
+	for i := 0; i < exp && d < limit; i++ {
+		d *= 2
+	}
+	if d > limit {
+		d = limit
+	}
+	return d
+}
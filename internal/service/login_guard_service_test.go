@@ -0,0 +1,214 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/config"
+	"bug-bounty-lite/pkg/jwt"
+	"bug-bounty-lite/pkg/lockout"
+	"bug-bounty-lite/pkg/totp"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeSecurityEventRepo 内存安全事件仓库
+type fakeSecurityEventRepo struct {
+	events []domain.SecurityEvent
+}
+
+func (r *fakeSecurityEventRepo) Create(event *domain.SecurityEvent) error {
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *fakeSecurityEventRepo) List(filter domain.SecurityEventFilter, page, pageSize int) ([]domain.SecurityEvent, int64, error) {
+	return r.events, int64(len(r.events)), nil
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name  string
+		base  time.Duration
+		exp   int
+		limit time.Duration
+		want  time.Duration
+	}{
+		{"初始等待", time.Second, 0, 30 * time.Second, time.Second},
+		{"翻倍三次", time.Second, 3, 30 * time.Second, 8 * time.Second},
+		{"不超过上限", time.Second, 10, 30 * time.Second, 30 * time.Second},
+		{"指数很大时不溢出", time.Second, 1000, 30 * time.Second, 30 * time.Second},
+		{"首次锁定", 15 * time.Minute, 0, 24 * time.Hour, 15 * time.Minute},
+		{"锁定翻倍", 15 * time.Minute, 2, 24 * time.Hour, time.Hour},
+		{"最长锁定", 15 * time.Minute, 10, 24 * time.Hour, 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backoff(tt.base, tt.exp, tt.limit); got != tt.want {
+				t.Errorf("backoff(%s, %d, %s) = %s, want %s", tt.base, tt.exp, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginGuardDelay(t *testing.T) {
+	s := NewLoginGuardService(lockout.NewMemoryStore(), &fakeSecurityEventRepo{}, config.LockoutConfig{}).(*loginGuardService)
+
+	tests := []struct {
+		name        string
+		failures    int
+		maxAttempts int
+		want        time.Duration
+		wantLocked  bool
+	}{
+		{"用户名第 1 次失败不等待", 1, 5, 0, false},
+		{"用户名达到一半开始退避", 2, 5, time.Second, false},
+		{"用户名退避翻倍", 4, 5, 4 * time.Second, false},
+		{"用户名达到上限锁定", 5, 5, 15 * time.Minute, true},
+		{"锁定后继续失败锁定翻倍", 6, 5, 30 * time.Minute, true},
+		{"锁定时长不超过上限", 20, 5, 24 * time.Hour, true},
+		{"IP 少量失败不等待", 9, 20, 0, false},
+		{"IP 达到一半开始退避", 10, 20, time.Second, false},
+		{"IP 退避不超过 30 秒", 19, 20, maxLoginBackoff, false},
+		{"IP 达到上限锁定", 20, 20, 15 * time.Minute, true},
+		{"上限很小时至少第 2 次才退避", 1, 2, 0, false},
+		{"上限很小时第 2 次直接锁定", 2, 2, 15 * time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, locked := s.delay(tt.failures, tt.maxAttempts)
+			if got != tt.want || locked != tt.wantLocked {
+				t.Errorf("delay(%d, %d) = %s, %v, want %s, %v", tt.failures, tt.maxAttempts, got, locked, tt.want, tt.wantLocked)
+			}
+		})
+	}
+}
+
+func TestLoginGuardLockout(t *testing.T) {
+	events := &fakeSecurityEventRepo{}
+	s := NewLoginGuardService(lockout.NewMemoryStore(), events, config.LockoutConfig{MaxAttempts: 3, IPMaxAttempts: 100})
+
+	// 用户名忽略大小写和首尾空格计数
+	for _, username := range []string{"alice", "ALICE", " Alice "} {
+		s.RecordFailure(username, "10.0.0.1", 1)
+	}
+
+	var throttled *domain.LoginThrottledError
+	if err := s.Check("alice", "10.0.0.2"); !errors.As(err, &throttled) {
+		t.Fatalf("Check() error = %v, want LoginThrottledError", err)
+	}
+	if throttled.RetryAfter <= 14*time.Minute || throttled.RetryAfter > 15*time.Minute {
+		t.Errorf("RetryAfter = %s, want about 15m", throttled.RetryAfter)
+	}
+	if len(events.events) != 1 || events.events[0].Type != domain.SecurityEventLoginLockout {
+		t.Errorf("events = %+v, want one lockout event", events.events)
+	}
+
+	// 其他用户不受影响（IP 未达到上限）
+	if err := s.Check("bob", "10.0.0.1"); err != nil {
+		t.Errorf("Check(bob) error = %v, want nil", err)
+	}
+
+	// 管理员解锁后恢复
+	if err := s.Unlock("alice", "", 99); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	if err := s.Check("alice", "10.0.0.1"); err != nil {
+		t.Errorf("Check() after unlock error = %v, want nil", err)
+	}
+	if err := s.Unlock("", "", 99); err == nil {
+		t.Error("Unlock() with empty username and IP error = nil, want error")
+	}
+}
+
+func TestLoginGuardRecordSuccessResetsCount(t *testing.T) {
+	s := NewLoginGuardService(lockout.NewMemoryStore(), &fakeSecurityEventRepo{}, config.LockoutConfig{MaxAttempts: 3, IPMaxAttempts: 100})
+
+	s.RecordFailure("alice", "10.0.0.1", 1)
+	s.RecordSuccess("Alice")
+	s.RecordFailure("alice", "10.0.0.1", 1)
+
+	// 成功登录后重新计数：第 1 次失败不等待
+	if err := s.Check("alice", ""); err != nil {
+		t.Errorf("Check() error = %v, want nil", err)
+	}
+}
+
+func TestLoginGuardTwoFactor(t *testing.T) {
+	const password = "Correct-Horse-42"
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := domain.ClientInfo{IP: "10.0.0.1"}
+
+	tests := []struct {
+		name string
+		// steps 依次执行的登录动作：password/wrong-password 提交密码，totp/wrong-totp/wrong-recovery 使用最近一次的挑战令牌
+		steps        []string
+		wantFailures int
+	}{
+		{name: "密码正确但未完成两步验证不清除失败次数", steps: []string{"wrong-password", "wrong-password", "password"}, wantFailures: 2},
+		{name: "验证码错误计入失败次数", steps: []string{"wrong-password", "password", "wrong-totp", "wrong-totp"}, wantFailures: 3},
+		{name: "恢复码错误计入失败次数", steps: []string{"password", "wrong-recovery", "password", "wrong-recovery"}, wantFailures: 2},
+		{name: "两步验证通过后清除失败次数", steps: []string{"wrong-password", "password", "wrong-totp", "totp"}, wantFailures: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := lockout.NewMemoryStore()
+			guard := NewLoginGuardService(store, &fakeSecurityEventRepo{}, config.LockoutConfig{MaxAttempts: 10, IPMaxAttempts: 100})
+			users := &fakeUserRepo{users: map[uint]*domain.User{1: {ID: 1, Username: "alice", Password: string(hash)}}}
+			tfRepo := &fakeTwoFactorRepo{challenges: make(map[string]*domain.LoginChallenge)}
+			tokens := NewAuthTokenService(newFakeAuthTokenRepo(), &fakeAPIKeyRepo{active: map[uint]int64{}}, users, jwt.NewJWTManager("test-secret", 900), 3600)
+			twoFactor := NewTwoFactorService(tfRepo, users, tokens, guard, config.TwoFactorConfig{})
+			s := &userService{repo: users, tokens: tokens, twoFactor: twoFactor, guard: guard}
+
+			challenge := ""
+			for _, step := range tt.steps {
+				switch step {
+				case "password", "wrong-password":
+					input := password
+					if step == "wrong-password" {
+						input = "wrong"
+					}
+					result, err := s.Login("alice", input, client)
+					if (err == nil) != (step == "password") {
+						t.Fatalf("Login(%s) error = %v", step, err)
+					}
+					if err == nil {
+						if !result.TwoFactorRequired || result.Tokens != nil {
+							t.Fatalf("Login() = %+v, want 2FA challenge", result)
+						}
+						challenge = result.ChallengeToken
+					}
+				default:
+					code := "abcde-fghjk"
+					switch step {
+					case "totp":
+						code, _ = totp.Code("JBSWY3DPEHPK3PXP", totp.Step(time.Now()))
+					case "wrong-totp":
+						// 一小时前的验证码，超出校验窗口
+						code, _ = totp.Code("JBSWY3DPEHPK3PXP", totp.Step(time.Now().Add(-time.Hour)))
+					}
+					_, pair, err := twoFactor.VerifyLogin(challenge, code, client)
+					if (err == nil) != (step == "totp") || (err == nil && pair == nil) {
+						t.Fatalf("VerifyLogin(%s) error = %v", step, err)
+					}
+				}
+			}
+
+			state, err := store.Get(context.Background(), usernameKey("alice"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if state.Failures != tt.wantFailures {
+				t.Errorf("用户名失败次数 = %d, want %d", state.Failures, tt.wantFailures)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type notificationService struct {
+	repo domain.NotificationRepository
+}
+
+// NewNotificationService 创建通知服务实例
+func NewNotificationService(repo domain.NotificationRepository) domain.NotificationService {
+	return &notificationService{repo: repo}
+}
+
+// Notify 向多个用户发送同一条通知
+func (s *notificationService) Notify(userIDs []uint, notificationType, title, content string, relatedID uint) error {
+	notifications := make([]domain.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		notifications = append(notifications, domain.Notification{
+			UserID:    userID,
+			Type:      notificationType,
+			Title:     title,
+			Content:   content,
+			RelatedID: relatedID,
+		})
+	}
+	return s.repo.CreateBatch(notifications)
+}
+
+// ListNotifications 分页获取用户的通知
+func (s *notificationService) ListNotifications(userID uint, unreadOnly bool, page, pageSize int) ([]domain.Notification, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.repo.ListByUser(userID, unreadOnly, page, pageSize)
+}
+
+// CountUnread 获取用户的未读通知数
+func (s *notificationService) CountUnread(userID uint) (int64, error) {
+	return s.repo.CountUnread(userID)
+}
+
+// MarkRead 将通知标记为已读（只能操作自己的通知）
+func (s *notificationService) MarkRead(userID uint, id uint) error {
+	if err := s.repo.MarkRead(userID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("通知不存在")
+		}
+		return err
+	}
+	return nil
+}
+
+// MarkAllRead 将用户的所有通知标记为已读
+func (s *notificationService) MarkAllRead(userID uint) (int64, error) {
+	return s.repo.MarkAllRead(userID)
+}
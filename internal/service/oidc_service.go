@@ -0,0 +1,368 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/config"
+	"bug-bounty-lite/pkg/oidc"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// defaultOIDCStateTTL 未配置 auth.oidc.state_ttl 时登录状态的有效期
+	defaultOIDCStateTTL = 10 * time.Minute
+	// oidcRequestTimeout 访问身份提供方的超时
+	oidcRequestTimeout = 30 * time.Second
+)
+
+// oidcRoleRank 多条映射匹配时取权限最高的角色
+var oidcRoleRank = map[string]int{
+	"whitehat": 1,
+	"vendor":   2,
+	"admin":    3,
+}
+
+// oidcUsernameInvalidChars 自动创建账号时从用户名中去掉的字符
+var oidcUsernameInvalidChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+type oidcService struct {
+	repo      domain.OIDCRepository
+	userRepo  domain.UserRepository
+	tokens    domain.AuthTokenService
+	twoFactor domain.TwoFactorService
+	providers map[string]*oidc.Provider
+	infos     []domain.OIDCProviderInfo
+	stateTTL  time.Duration
+}
+
+// NewOIDCService 创建单点登录服务实例
+func NewOIDCService(repo domain.OIDCRepository, userRepo domain.UserRepository, tokens domain.AuthTokenService, twoFactor domain.TwoFactorService, providers []*oidc.Provider, cfg config.OIDCConfig) domain.OIDCService {
+	stateTTL := time.Duration(cfg.StateTTL) * time.Second
+	if stateTTL <= 0 {
+		stateTTL = defaultOIDCStateTTL
+	}
+	s := &oidcService{
+		repo:      repo,
+		userRepo:  userRepo,
+		tokens:    tokens,
+		twoFactor: twoFactor,
+		providers: make(map[string]*oidc.Provider, len(providers)),
+		infos:     make([]domain.OIDCProviderInfo, 0, len(providers)),
+		stateTTL:  stateTTL,
+	}
+	for _, p := range providers {
+		s.providers[p.Name()] = p
+		s.infos = append(s.infos, domain.OIDCProviderInfo{Name: p.Name(), DisplayName: p.Config().DisplayName})
+	}
+	return s
+}
+
+// Providers 已配置的身份提供方
+func (s *oidcService) Providers() []domain.OIDCProviderInfo {
+	return s.infos
+}
+
+// Authorize 生成 state、nonce 和 PKCE 校验码并保存，返回身份提供方的授权地址
+func (s *oidcService) Authorize(provider string) (*domain.OIDCAuthorization, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, domain.ErrOIDCProviderNotFound
+	}
+
+	state, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcRequestTimeout)
+	defer cancel()
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		log.Printf("[WARN] OIDC provider %s is not available: %v", provider, err)
+		return nil, domain.ErrOIDCUnavailable
+	}
+
+	if err := s.repo.CreateState(&domain.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(s.stateTTL),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &domain.OIDCAuthorization{
+		AuthorizationURL: authURL,
+		State:            state,
+		ExpiresIn:        int(s.stateTTL.Seconds()),
+	}, nil
+}
+
+// Callback 校验 state 后用授权码换取 ID 令牌，找到或创建对应账号后按密码登录的流程签发令牌（包括两步验证）
+func (s *oidcService) Callback(provider, code, state string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, domain.ErrOIDCProviderNotFound
+	}
+	if code == "" || state == "" {
+		return nil, domain.ErrOIDCStateInvalid
+	}
+
+	// 1. state 只能使用一次，且必须属于同一身份提供方
+	loginState, err := s.repo.ConsumeState(hashToken(state))
+	if err != nil {
+		return nil, err
+	}
+	if loginState == nil || loginState.Provider != provider || time.Now().After(loginState.ExpiresAt) {
+		return nil, domain.ErrOIDCStateInvalid
+	}
+
+	// 2. 授权码 + PKCE 校验码换取并校验 ID 令牌
+	ctx, cancel := context.WithTimeout(context.Background(), oidcRequestTimeout)
+	defer cancel()
+	claims, err := p.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		log.Printf("[WARN] OIDC login via %s failed: %v", provider, err)
+		return nil, domain.ErrOIDCVerifyFailed
+	}
+
+	// 3. 按映射确定角色和组织，没有匹配且未配置默认角色时拒绝登录
+	cfg := p.Config()
+	role, orgID := mapOIDCGroups(cfg, claims.Groups)
+	if role == "" {
+		return nil, domain.ErrOIDCAccessDenied
+	}
+
+	// 4. 找到已关联的账号，或按已验证邮箱关联、自动创建
+	user, identity, err := s.resolveUser(cfg, claims, role, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SyncOnLogin && (user.Role != role || (orgID != 0 && user.OrgID != orgID)) {
+		user.Role = role
+		if orgID != 0 {
+			user.OrgID = orgID
+		}
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	if err := s.repo.UpdateIdentityLogin(identity.ID, claims.Email, now); err != nil {
+		log.Printf("[WARN] Failed to update identity %d: %v", identity.ID, err)
+	}
+
+	// 5. 已启用两步验证或角色要求两步验证：返回挑战令牌
+	challenge, err := s.twoFactor.LoginChallenge(user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+
+	// 6. 记录登录会话，签发访问令牌和刷新令牌
+	tokens, err := s.tokens.IssueTokens(user, client)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+	user.LastLoginAt = &now
+	_ = s.userRepo.UpdateLastLoginAt(user.ID, now)
+
+	return &domain.LoginResult{User: user, Tokens: tokens}, nil
+}
+
+// CleanupExpired 清理过期的登录状态
+func (s *oidcService) CleanupExpired() (int64, error) {
+	return s.repo.DeleteExpiredStates(time.Now())
+}
+
+// resolveUser 查找外部身份关联的账号；未关联时按已验证邮箱关联已有账号，或自动创建账号
+func (s *oidcService) resolveUser(cfg config.OIDCProviderConfig, claims *oidc.Claims, role string, orgID uint) (*domain.User, *domain.UserIdentity, error) {
+	identity, err := s.repo.FindIdentity(cfg.Name, claims.Subject)
+	if err != nil {
+		return nil, nil, err
+	}
+	if identity != nil {
+		user, err := s.userRepo.FindByID(identity.UserID)
+		if err != nil || user == nil {
+			return nil, nil, domain.ErrOIDCAccountNotFound
+		}
+		return user, identity, nil
+	}
+
+	// 身份提供方和本地的邮箱都已验证时才关联，防止通过未验证的邮箱接管他人账号
+	if cfg.LinkByEmail && claims.EmailVerified && claims.Email != "" {
+		users, err := s.userRepo.FindByEmail(claims.Email)
+		if err != nil {
+			return nil, nil, err
+		}
+		var matched []domain.User
+		for _, u := range users {
+			if u.EmailVerifiedAt != nil && strings.EqualFold(u.Email, claims.Email) {
+				matched = append(matched, u)
+			}
+		}
+		if len(matched) > 1 {
+			return nil, nil, domain.ErrOIDCEmailConflict
+		}
+		if len(matched) == 1 {
+			user := &matched[0]
+			identity, err := s.link(cfg.Name, claims, user.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			log.Printf("[INFO] Linked %s identity %s to user %d by verified email", cfg.Name, claims.Subject, user.ID)
+			return user, identity, nil
+		}
+	}
+
+	if !cfg.AutoProvision {
+		return nil, nil, domain.ErrOIDCAccountNotFound
+	}
+	user, err := s.provision(claims, role, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	identity, err = s.link(cfg.Name, claims, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[INFO] Provisioned user %d (%s) from %s identity %s", user.ID, user.Username, cfg.Name, claims.Subject)
+	return user, identity, nil
+}
+
+// link 保存外部身份与账号的关联
+func (s *oidcService) link(provider string, claims *oidc.Claims, userID uint) (*domain.UserIdentity, error) {
+	identity := &domain.UserIdentity{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+	if err := s.repo.CreateIdentity(identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// provision 首次登录时创建账号，密码为随机值（需要本地登录时可通过找回密码设置）
+func (s *oidcService) provision(claims *oidc.Claims, role string, orgID uint) (*domain.User, error) {
+	username, err := s.uniqueUsername(claims)
+	if err != nil {
+		return nil, err
+	}
+	password, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &domain.User{
+		Username: username,
+		Password: string(hashedPassword),
+		Name:     claims.Name,
+		Role:     role,
+		OrgID:    orgID,
+		Email:    claims.Email,
+	}
+	if claims.EmailVerified && claims.Email != "" {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// uniqueUsername 根据用户名声明或邮箱前缀生成未被占用的用户名，重名时追加 -2、-3……
+func (s *oidcService) uniqueUsername(claims *oidc.Claims) (string, error) {
+	base := claims.Username
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+	base = oidcUsernameInvalidChars.ReplaceAllString(base, "")
+	if base == "" {
+		base = "sso-user"
+	}
+	if len(base) > 56 {
+		base = base[:56]
+	}
+
+	for i := 1; i <= 20; i++ {
+		username := base
+		if i > 1 {
+			username = fmt.Sprintf("%s-%d", base, i)
+		}
+		if existing, _ := s.userRepo.FindByUsername(username); existing == nil {
+			return username, nil
+		}
+	}
+	suffix, err := randomHex(3)
+	if err != nil {
+		return "", err
+	}
+	return base + "-" + suffix, nil
+}
+
+// mapOIDCGroups 按映射确定角色和组织：匹配多条时取权限最高的角色和第一个非 0 的组织，
+// 没有匹配的角色时使用默认角色（可能为空），没有匹配的组织时使用默认组织
+func mapOIDCGroups(cfg config.OIDCProviderConfig, groups []string) (string, uint) {
+	member := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		member[g] = true
+	}
+
+	role, orgID := "", uint(0)
+	for _, m := range cfg.GroupMappings {
+		if !member[m.Group] {
+			continue
+		}
+		if oidcRoleRank[m.Role] > oidcRoleRank[role] {
+			role = m.Role
+		}
+		if orgID == 0 {
+			orgID = m.OrgID
+		}
+	}
+	if role == "" {
+		role = cfg.DefaultRole
+	}
+	if orgID == 0 {
+		orgID = cfg.DefaultOrgID
+	}
+	return role, orgID
+}
+
+// StartOIDCStateCleanup 启动后台定时清理过期的单点登录状态
+func StartOIDCStateCleanup(s domain.OIDCService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if count, err := s.CleanupExpired(); err != nil {
+				log.Printf("[WARN] Failed to clean up expired OIDC login states: %v", err)
+			} else if count > 0 {
+				log.Printf("[INFO] Cleaned up %d expired OIDC login state(s)", count)
+			}
+		}
+	}()
+}
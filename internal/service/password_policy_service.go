@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/breached"
+	"bug-bounty-lite/pkg/config"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// defaultPasswordMinLength 未配置 auth.password.min_length 时的最短密码长度
+	defaultPasswordMinLength = 8
+	// passwordMaxBytes bcrypt 只使用密码的前 72 个字节，更长的密码会被拒绝
+	passwordMaxBytes = 72
+	// minUsernameCheckLength 用户名短于该长度时不检查密码是否包含用户名
+	minUsernameCheckLength = 3
+)
+
+type passwordPolicyService struct {
+	repo     domain.PasswordHistoryRepository
+	cfg      config.PasswordConfig
+	breached breached.Source
+}
+
+// NewPasswordPolicyService 创建密码策略服务实例，breachedList 为 nil 时不检查泄露密码
+func NewPasswordPolicyService(repo domain.PasswordHistoryRepository, cfg config.PasswordConfig, breachedList breached.Source) domain.PasswordPolicyService {
+	if cfg.MinLength <= 0 {
+		cfg.MinLength = defaultPasswordMinLength
+	}
+	return &passwordPolicyService{
+		repo:     repo,
+		cfg:      cfg,
+		breached: breachedList,
+	}
+}
+
+// Validate 按顺序校验长度、字符类别、用户名、泄露密码库和历史密码，返回第一条未通过的规则
+func (s *passwordPolicyService) Validate(user *domain.User, password string) error {
+	if len(password) > passwordMaxBytes {
+		return policyError(domain.PasswordRuleMaxLength, fmt.Sprintf("密码长度不能超过%d个字节", passwordMaxBytes))
+	}
+	if utf8.RuneCountInString(password) < s.cfg.MinLength {
+		return policyError(domain.PasswordRuleMinLength, fmt.Sprintf("密码长度不能少于%d位", s.cfg.MinLength))
+	}
+
+	if err := s.checkClasses(password); err != nil {
+		return err
+	}
+
+	if s.cfg.DisallowUsername && utf8.RuneCountInString(user.Username) >= minUsernameCheckLength &&
+		strings.Contains(strings.ToLower(password), strings.ToLower(user.Username)) {
+		return policyError(domain.PasswordRuleUsername, "密码不能包含用户名")
+	}
+
+	if s.breached != nil {
+		found, err := breached.Contains(s.breached, password)
+		if err != nil {
+			// 泄露密码库读取失败不影响设置密码
+			log.Printf("[WARN] Failed to check breached password list: %v", err)
+		} else if found {
+			return policyError(domain.PasswordRuleBreached, "该密码已出现在公开泄露的密码库中，请更换其他密码")
+		}
+	}
+
+	if user.ID != 0 && s.cfg.History > 0 {
+		reused, err := s.reused(user, password)
+		if err != nil {
+			return err
+		}
+		if reused {
+			return policyError(domain.PasswordRuleReuse, fmt.Sprintf("新密码不能与最近%d次使用过的密码相同", s.cfg.History))
+		}
+	}
+	return nil
+}
+
+// Remember 记录新密码哈希并清理超出保留数量的历史密码
+func (s *passwordPolicyService) Remember(userID uint, passwordHash string) error {
+	if s.cfg.History <= 0 {
+		return nil
+	}
+	if err := s.repo.Create(&domain.PasswordHistory{UserID: userID, PasswordHash: passwordHash}); err != nil {
+		return err
+	}
+	return s.repo.Prune(userID, s.cfg.History)
+}
+
+// checkClasses 校验必须包含的字符类别和至少包含的类别数
+// 大写、小写字母之外的文字（如中文）和空格计入符号
+func (s *passwordPolicyService) checkClasses(password string) error {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	switch {
+	case s.cfg.RequireUpper && !upper:
+		return policyError(domain.PasswordRuleUpper, "密码必须包含大写字母")
+	case s.cfg.RequireLower && !lower:
+		return policyError(domain.PasswordRuleLower, "密码必须包含小写字母")
+	case s.cfg.RequireDigit && !digit:
+		return policyError(domain.PasswordRuleDigit, "密码必须包含数字")
+	case s.cfg.RequireSymbol && !symbol:
+		return policyError(domain.PasswordRuleSymbol, "密码必须包含符号")
+	}
+
+	classes := 0
+	for _, ok := range []bool{upper, lower, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+	if classes < s.cfg.MinClasses {
+		return policyError(domain.PasswordRuleClasses, fmt.Sprintf("密码至少需要包含大写字母、小写字母、数字、符号中的%d类", s.cfg.MinClasses))
+	}
+	return nil
+}
+
+// reused 新密码是否与当前密码或最近的历史密码相同
+func (s *passwordPolicyService) reused(user *domain.User, password string) (bool, error) {
+	histories, err := s.repo.ListRecent(user.ID, s.cfg.History)
+	if err != nil {
+		return false, err
+	}
+	// 启用该功能前设置的密码没有历史记录，当前密码单独比对
+	hashes := []string{user.Password}
+	for _, h := range histories {
+		if h.PasswordHash != user.Password {
+			hashes = append(hashes, h.PasswordHash)
+		}
+	}
+	for _, hash := range hashes {
+		if hash != "" && bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// policyError 创建密码策略错误
+func policyError(rule, message string) error {
+	return &domain.PasswordPolicyError{Rule: rule, Message: message}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/config"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeBreachedSource 内存泄露密码库
+type fakeBreachedSource map[string][]string
+
+func (s fakeBreachedSource) Range(prefix string) ([]string, error) {
+	return s[prefix], nil
+}
+
+// newFakeBreachedSource 由明文密码构造泄露密码库
+func newFakeBreachedSource(passwords ...string) fakeBreachedSource {
+	src := fakeBreachedSource{}
+	for _, password := range passwords {
+		sum := sha1.Sum([]byte(password))
+		hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+		src[hash[:5]] = append(src[hash[:5]], hash[5:])
+	}
+	return src
+}
+
+// fakePasswordHistoryRepo 内存历史密码仓库
+type fakePasswordHistoryRepo struct {
+	histories []domain.PasswordHistory
+}
+
+func (r *fakePasswordHistoryRepo) Create(history *domain.PasswordHistory) error {
+	r.histories = append([]domain.PasswordHistory{*history}, r.histories...)
+	return nil
+}
+
+func (r *fakePasswordHistoryRepo) ListRecent(userID uint, limit int) ([]domain.PasswordHistory, error) {
+	var result []domain.PasswordHistory
+	for _, h := range r.histories {
+		if h.UserID == userID && len(result) < limit {
+			result = append(result, h)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakePasswordHistoryRepo) Prune(userID uint, keep int) error {
+	if len(r.histories) > keep {
+		r.histories = r.histories[:keep]
+	}
+	return nil
+}
+
+// bcryptHash 测试用的低成本哈希
+func bcryptHash(t *testing.T, password string) string {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(hash)
+}
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	strict := config.PasswordConfig{
+		MinLength:        10,
+		RequireUpper:     true,
+		RequireLower:     true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		History:          3,
+		DisallowUsername: true,
+	}
+	history := &fakePasswordHistoryRepo{}
+	user := &domain.User{ID: 1, Username: "alice", Password: bcryptHash(t, "Current#Pass1")}
+	history.Create(&domain.PasswordHistory{UserID: 1, PasswordHash: bcryptHash(t, "Previous#Pass1")})
+	breachedList := newFakeBreachedSource("Password#123")
+
+	tests := []struct {
+		name     string
+		cfg      config.PasswordConfig
+		user     *domain.User
+		password string
+		wantRule string
+	}{
+		{name: "默认策略通过", cfg: config.PasswordConfig{}, user: &domain.User{}, password: "abcdefgh"},
+		{name: "默认最短 8 位", cfg: config.PasswordConfig{}, user: &domain.User{}, password: "abcdefg", wantRule: domain.PasswordRuleMinLength},
+		{name: "长度按字符计算", cfg: config.PasswordConfig{MinLength: 4}, user: &domain.User{}, password: "密码密码"},
+		{name: "超过 72 字节", cfg: config.PasswordConfig{}, user: &domain.User{}, password: strings.Repeat("a", 73), wantRule: domain.PasswordRuleMaxLength},
+		{name: "严格策略通过", cfg: strict, user: user, password: "Brand#New#Pass9"},
+		{name: "缺少大写字母", cfg: strict, user: user, password: "brand#new#pass9", wantRule: domain.PasswordRuleUpper},
+		{name: "缺少小写字母", cfg: strict, user: user, password: "BRAND#NEW#PASS9", wantRule: domain.PasswordRuleLower},
+		{name: "缺少数字", cfg: strict, user: user, password: "Brand#New#Pass", wantRule: domain.PasswordRuleDigit},
+		{name: "缺少符号", cfg: strict, user: user, password: "BrandNewPass9", wantRule: domain.PasswordRuleSymbol},
+		{name: "中文计入符号", cfg: strict, user: user, password: "Brand新New密码9x"},
+		{name: "类别数不足", cfg: config.PasswordConfig{MinClasses: 3}, user: &domain.User{}, password: "abcdefgh1", wantRule: domain.PasswordRuleClasses},
+		{name: "类别数满足", cfg: config.PasswordConfig{MinClasses: 3}, user: &domain.User{}, password: "Abcdefgh1"},
+		{name: "包含用户名（忽略大小写）", cfg: strict, user: user, password: "My#ALICE#Pass9", wantRule: domain.PasswordRuleUsername},
+		{name: "用户名过短不检查", cfg: strict, user: &domain.User{Username: "al"}, password: "My#Alpha#Pass9"},
+		{name: "与当前密码相同", cfg: strict, user: user, password: "Current#Pass1", wantRule: domain.PasswordRuleReuse},
+		{name: "与历史密码相同", cfg: strict, user: user, password: "Previous#Pass1", wantRule: domain.PasswordRuleReuse},
+		{name: "注册时不检查历史密码", cfg: strict, user: &domain.User{Username: "bob"}, password: "Previous#Pass1"},
+		{name: "泄露密码", cfg: config.PasswordConfig{}, user: &domain.User{}, password: "Password#123", wantRule: domain.PasswordRuleBreached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewPasswordPolicyService(history, tt.cfg, breachedList)
+			err := s.Validate(tt.user, tt.password)
+
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			var policyErr *domain.PasswordPolicyError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("Validate() error = %v, want PasswordPolicyError", err)
+			}
+			if policyErr.Rule != tt.wantRule {
+				t.Errorf("Validate() rule = %s, want %s", policyErr.Rule, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyRemember(t *testing.T) {
+	history := &fakePasswordHistoryRepo{}
+	s := NewPasswordPolicyService(history, config.PasswordConfig{History: 2}, nil)
+
+	for _, password := range []string{"first-pass", "second-pass", "third-pass"} {
+		if err := s.Remember(1, bcryptHash(t, password)); err != nil {
+			t.Fatalf("Remember() error = %v", err)
+		}
+	}
+	if len(history.histories) != 2 {
+		t.Errorf("kept %d histories, want 2", len(history.histories))
+	}
+
+	// 超出保留数量的密码可以再次使用
+	user := &domain.User{ID: 1, Username: "alice"}
+	if err := s.Validate(user, "first-pass"); err != nil {
+		t.Errorf("Validate(first-pass) error = %v, want nil", err)
+	}
+	if err := s.Validate(user, "third-pass"); err == nil {
+		t.Error("Validate(third-pass) error = nil, want reuse error")
+	}
+}
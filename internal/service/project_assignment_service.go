@@ -0,0 +1,260 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"fmt"
+)
+
+// maxAssignmentRankingTop 按排行榜指派时最多选取的人数
+const maxAssignmentRankingTop = 1000
+
+type projectAssignmentService struct {
+	assignmentRepo      domain.ProjectAssignmentRepository
+	taskRepo            domain.ProjectTaskRepository
+	projectRepo         domain.ProjectRepository
+	userRepo            domain.UserRepository
+	rankingRepo         domain.RankingRepository
+	notificationService domain.NotificationService
+}
+
+// NewProjectAssignmentService 创建项目指派服务实例
+func NewProjectAssignmentService(
+	assignmentRepo domain.ProjectAssignmentRepository,
+	taskRepo domain.ProjectTaskRepository,
+	projectRepo domain.ProjectRepository,
+	userRepo domain.UserRepository,
+	rankingRepo domain.RankingRepository,
+	notificationService domain.NotificationService,
+) domain.ProjectAssignmentService {
+	return &projectAssignmentService{
+		assignmentRepo:      assignmentRepo,
+		taskRepo:            taskRepo,
+		projectRepo:         projectRepo,
+		userRepo:            userRepo,
+		rankingRepo:         rankingRepo,
+		notificationService: notificationService,
+	}
+}
+
+// ListAssignees 获取项目的指派用户及是否已接受任务
+func (s *projectAssignmentService) ListAssignees(projectID uint) ([]domain.ProjectAssignee, error) {
+	if _, err := s.projectRepo.FindByID(projectID); err != nil {
+		return nil, errors.New("项目不存在")
+	}
+
+	assignments, err := s.assignmentRepo.FindByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.taskRepo.FindByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uint, 0, len(assignments))
+	for _, assignment := range assignments {
+		userIDs = append(userIDs, assignment.UserID)
+	}
+	users, err := s.userRepo.FindByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	userMap := make(map[uint]*domain.User, len(users))
+	for i := range users {
+		userMap[users[i].ID] = &users[i]
+	}
+	taskMap := make(map[uint]*domain.ProjectTask, len(tasks))
+	for i := range tasks {
+		taskMap[tasks[i].UserID] = &tasks[i]
+	}
+
+	assignees := make([]domain.ProjectAssignee, 0, len(assignments))
+	for _, assignment := range assignments {
+		assignee := domain.ProjectAssignee{
+			UserID:     assignment.UserID,
+			AssignedAt: assignment.CreatedAt,
+		}
+		if user, ok := userMap[assignment.UserID]; ok {
+			assignee.Username = user.Username
+			assignee.Name = user.Name
+			assignee.OrgID = user.OrgID
+		}
+		if task, ok := taskMap[assignment.UserID]; ok {
+			assignee.Accepted = true
+			acceptedAt := task.AcceptedAt
+			assignee.AcceptedAt = &acceptedAt
+		}
+		assignees = append(assignees, assignee)
+	}
+
+	return assignees, nil
+}
+
+// AssignUsers 批量指派用户到项目，已指派的用户跳过，新指派的用户会收到站内通知
+func (s *projectAssignmentService) AssignUsers(projectID uint, target *domain.AssignmentTarget) (*domain.AssignmentResult, error) {
+	project, err := s.projectRepo.FindByID(projectID)
+	if err != nil {
+		return nil, errors.New("项目不存在")
+	}
+
+	userIDs, err := s.resolveTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned, err := s.assignedUserSet(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.AssignmentResult{UserIDs: make([]uint, 0)}
+	assignments := make([]domain.ProjectAssignment, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if assigned[userID] {
+			result.Skipped++
+			continue
+		}
+		assignments = append(assignments, domain.ProjectAssignment{
+			ProjectID: projectID,
+			UserID:    userID,
+		})
+		result.UserIDs = append(result.UserIDs, userID)
+	}
+
+	if err := s.assignmentRepo.CreateBatch(assignments); err != nil {
+		return nil, errors.New("指派失败: " + err.Error())
+	}
+	result.Count = len(result.UserIDs)
+
+	// 通知新指派的用户（失败不影响指派结果）
+	if result.Count > 0 {
+		_ = s.notificationService.Notify(
+			result.UserIDs,
+			domain.NotificationProjectAssigned,
+			"新项目指派",
+			fmt.Sprintf("您已被指派到项目「%s」，可在项目大厅查看并接受任务", project.Name),
+			project.ID,
+		)
+	}
+
+	return result, nil
+}
+
+// UnassignUsers 批量取消用户的项目指派
+// 已接受的任务记录保留，但用户将无法再在项目大厅看到该项目
+func (s *projectAssignmentService) UnassignUsers(projectID uint, target *domain.AssignmentTarget) (*domain.AssignmentResult, error) {
+	if _, err := s.projectRepo.FindByID(projectID); err != nil {
+		return nil, errors.New("项目不存在")
+	}
+
+	userIDs, err := s.resolveTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned, err := s.assignedUserSet(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.AssignmentResult{UserIDs: make([]uint, 0)}
+	for _, userID := range userIDs {
+		if !assigned[userID] {
+			result.Skipped++
+			continue
+		}
+		result.UserIDs = append(result.UserIDs, userID)
+	}
+
+	if _, err := s.assignmentRepo.DeleteByProjectAndUsers(projectID, result.UserIDs); err != nil {
+		return nil, errors.New("取消指派失败: " + err.Error())
+	}
+	result.Count = len(result.UserIDs)
+
+	return result, nil
+}
+
+// resolveTarget 解析指派目标为白帽子用户ID列表（去重，保持选取顺序）
+func (s *projectAssignmentService) resolveTarget(target *domain.AssignmentTarget) ([]uint, error) {
+	if len(target.UserIDs) == 0 && target.OrgID == 0 && target.RankingTop <= 0 {
+		return nil, errors.New("请指定用户、组织或排行榜名次")
+	}
+	if target.RankingTop > maxAssignmentRankingTop {
+		return nil, fmt.Errorf("排行榜名次不能超过 %d", maxAssignmentRankingTop)
+	}
+
+	var candidates []domain.User
+
+	if len(target.UserIDs) > 0 {
+		users, err := s.userRepo.FindByIDs(target.UserIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(users) != len(uniqueUintSlice(target.UserIDs)) {
+			return nil, errors.New("部分用户不存在")
+		}
+		candidates = append(candidates, users...)
+	}
+
+	if target.OrgID > 0 {
+		users, err := s.userRepo.FindByOrgID(target.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, users...)
+	}
+
+	if target.RankingTop > 0 {
+		items, err := s.rankingRepo.GetGlobalRanking(target.RankingTop)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			// 排行榜只统计白帽子
+			candidates = append(candidates, domain.User{ID: item.UserID, Role: "whitehat"})
+		}
+	}
+
+	seen := make(map[uint]bool, len(candidates))
+	userIDs := make([]uint, 0, len(candidates))
+	for _, user := range candidates {
+		if user.Role != "whitehat" || seen[user.ID] {
+			continue
+		}
+		seen[user.ID] = true
+		userIDs = append(userIDs, user.ID)
+	}
+
+	if len(userIDs) == 0 {
+		return nil, errors.New("没有符合条件的白帽子")
+	}
+	return userIDs, nil
+}
+
+// assignedUserSet 获取项目已指派的用户集合
+func (s *projectAssignmentService) assignedUserSet(projectID uint) (map[uint]bool, error) {
+	assignments, err := s.assignmentRepo.FindByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	assigned := make(map[uint]bool, len(assignments))
+	for _, assignment := range assignments {
+		assigned[assignment.UserID] = true
+	}
+	return assigned, nil
+}
+
+// uniqueUintSlice 去除重复的ID
+func uniqueUintSlice(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+)
+
+type projectAttachmentService struct {
+	repo           domain.ProjectAttachmentRepository
+	projectRepo    domain.ProjectRepository
+	assignmentRepo domain.ProjectAssignmentRepository
+}
+
+// NewProjectAttachmentService 创建项目附件服务实例
+func NewProjectAttachmentService(
+	repo domain.ProjectAttachmentRepository,
+	projectRepo domain.ProjectRepository,
+	assignmentRepo domain.ProjectAssignmentRepository,
+) domain.ProjectAttachmentService {
+	return &projectAttachmentService{
+		repo:           repo,
+		projectRepo:    projectRepo,
+		assignmentRepo: assignmentRepo,
+	}
+}
+
+// ListAttachments 获取项目附件
+// 管理员和厂商可以查看所有项目的附件，白帽子只能查看被指派项目的附件
+func (s *projectAttachmentService) ListAttachments(projectID uint, userID uint, userRole string) ([]domain.ProjectAttachment, error) {
+	if _, err := s.projectRepo.FindByID(projectID); err != nil {
+		return nil, errors.New("项目不存在")
+	}
+	if userRole != "admin" && userRole != "vendor" {
+		if _, err := s.assignmentRepo.FindByProjectAndUser(projectID, userID); err != nil {
+			return nil, errors.New("您无权访问该项目")
+		}
+	}
+	return s.repo.FindByProjectID(projectID)
+}
+
+// AddAttachment 添加项目附件，排序值为当前最大值加 1
+func (s *projectAttachmentService) AddAttachment(attachment *domain.ProjectAttachment, userRole string) error {
+	if userRole != "admin" && userRole != "vendor" {
+		return errors.New("permission denied")
+	}
+	if _, err := s.projectRepo.FindByID(attachment.ProjectID); err != nil {
+		return errors.New("项目不存在")
+	}
+
+	maxOrder, err := s.repo.MaxSortOrder(attachment.ProjectID)
+	if err != nil {
+		return err
+	}
+	attachment.SortOrder = maxOrder + 1
+
+	return s.repo.Create(attachment)
+}
+
+// ReorderAttachments 按给定的附件ID顺序重排
+func (s *projectAttachmentService) ReorderAttachments(projectID uint, orderedIDs []uint, userRole string) ([]domain.ProjectAttachment, error) {
+	if userRole != "admin" && userRole != "vendor" {
+		return nil, errors.New("permission denied")
+	}
+
+	attachments, err := s.repo.FindByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 必须恰好包含项目的全部附件，避免部分重排导致排序值重复
+	if len(orderedIDs) != len(attachments) {
+		return nil, errors.New("排序列表必须包含项目的全部附件")
+	}
+	existing := make(map[uint]bool, len(attachments))
+	for _, attachment := range attachments {
+		existing[attachment.ID] = true
+	}
+	seen := make(map[uint]bool, len(orderedIDs))
+	for _, id := range orderedIDs {
+		if !existing[id] {
+			return nil, errors.New("附件不属于该项目")
+		}
+		if seen[id] {
+			return nil, errors.New("排序列表中有重复的附件")
+		}
+		seen[id] = true
+	}
+
+	if err := s.repo.UpdateSortOrders(projectID, orderedIDs); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByProjectID(projectID)
+}
+
+// DeleteAttachment 删除项目附件
+func (s *projectAttachmentService) DeleteAttachment(projectID uint, attachmentID uint, userRole string) (*domain.ProjectAttachment, error) {
+	if userRole != "admin" && userRole != "vendor" {
+		return nil, errors.New("permission denied")
+	}
+
+	attachment, err := s.repo.FindByID(attachmentID)
+	if err != nil || attachment.ProjectID != projectID {
+		return nil, errors.New("附件不存在")
+	}
+
+	if err := s.repo.Delete(attachmentID); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// hostnamePattern 域名（不含通配符）
+	hostnamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9][a-z0-9-]{0,61}[a-z0-9]$`)
+	// appIDPattern 移动应用包名/应用ID
+	appIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,254}$`)
+)
+
+// severityRanks 危害等级高低，用于资产最高危害等级校验
+var severityRanks = map[string]int{
+	"None":     0,
+	"Low":      1,
+	"Medium":   2,
+	"High":     3,
+	"Critical": 4,
+}
+
+type projectScopeService struct {
+	repo        domain.ProjectScopeRepository
+	projectRepo domain.ProjectRepository
+}
+
+// NewProjectScopeService 创建项目范围服务实例
+func NewProjectScopeService(repo domain.ProjectScopeRepository, projectRepo domain.ProjectRepository) domain.ProjectScopeService {
+	return &projectScopeService{
+		repo:        repo,
+		projectRepo: projectRepo,
+	}
+}
+
+// ListScopes 获取项目的范围资产
+func (s *projectScopeService) ListScopes(projectID uint) ([]domain.ProjectScope, error) {
+	if _, err := s.projectRepo.FindByID(projectID); err != nil {
+		return nil, errors.New("项目不存在")
+	}
+	return s.repo.ListByProject(projectID)
+}
+
+// GetScope 获取范围资产
+func (s *projectScopeService) GetScope(id uint) (*domain.ProjectScope, error) {
+	scope, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("范围资产不存在")
+	}
+	return scope, nil
+}
+
+// CreateScope 新增范围资产
+func (s *projectScopeService) CreateScope(scope *domain.ProjectScope) error {
+	if _, err := s.projectRepo.FindByID(scope.ProjectID); err != nil {
+		return errors.New("项目不存在")
+	}
+	if err := normalizeScope(scope); err != nil {
+		return err
+	}
+	return s.repo.Create(scope)
+}
+
+// UpdateScope 更新范围资产
+func (s *projectScopeService) UpdateScope(projectID uint, scope *domain.ProjectScope) (*domain.ProjectScope, error) {
+	existing, err := s.repo.FindByID(scope.ID)
+	if err != nil || existing.ProjectID != projectID {
+		return nil, errors.New("范围资产不存在")
+	}
+
+	scope.ProjectID = existing.ProjectID
+	scope.CreatedAt = existing.CreatedAt
+	if err := normalizeScope(scope); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(scope); err != nil {
+		return nil, err
+	}
+	return scope, nil
+}
+
+// DeleteScope 删除范围资产
+func (s *projectScopeService) DeleteScope(projectID uint, scopeID uint) error {
+	existing, err := s.repo.FindByID(scopeID)
+	if err != nil || existing.ProjectID != projectID {
+		return errors.New("范围资产不存在")
+	}
+	return s.repo.Delete(scopeID)
+}
+
+// CheckURL 校验漏洞链接是否在项目范围内
+// 命中多个资产时取最具体的一个，具体程度相同时范围外优先
+// 项目配置了范围但链接未命中任何资产时视为范围外
+func (s *projectScopeService) CheckURL(projectID uint, rawURL string) (*domain.ScopeCheckResult, error) {
+	scopes, err := s.repo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(scopes) == 0 {
+		return &domain.ScopeCheckResult{Status: domain.ReportScopeUnknown}, nil
+	}
+
+	target := parseScopeTarget(rawURL)
+	if target == nil {
+		return &domain.ScopeCheckResult{
+			Status:  domain.ReportScopeUnknown,
+			Message: "漏洞链接为空或无法解析，无法判断是否在范围内",
+		}, nil
+	}
+
+	var best *domain.ProjectScope
+	bestScore := 0
+	for i := range scopes {
+		score := matchScope(&scopes[i], target)
+		if score == 0 {
+			continue
+		}
+		if score > bestScore || (score == bestScore && !scopes[i].InScope) {
+			best = &scopes[i]
+			bestScore = score
+		}
+	}
+
+	switch {
+	case best == nil:
+		return &domain.ScopeCheckResult{
+			Status:  domain.ReportScopeOutOfScope,
+			Message: "漏洞链接未命中项目范围内的任何资产",
+		}, nil
+	case !best.InScope:
+		return &domain.ScopeCheckResult{
+			Status:  domain.ReportScopeOutOfScope,
+			Matched: best,
+			Message: "漏洞链接命中范围外资产: " + best.Identifier,
+		}, nil
+	default:
+		return &domain.ScopeCheckResult{
+			Status:  domain.ReportScopeInScope,
+			Matched: best,
+		}, nil
+	}
+}
+
+// normalizeScope 校验并规范化范围资产
+func normalizeScope(scope *domain.ProjectScope) error {
+	identifier := strings.TrimSpace(scope.Identifier)
+	if identifier == "" {
+		return errors.New("资产标识不能为空")
+	}
+
+	switch scope.AssetType {
+	case domain.ScopeAssetDomain:
+		identifier = strings.TrimSuffix(strings.ToLower(identifier), ".")
+		if !hostnamePattern.MatchString(identifier) {
+			return errors.New("无效的域名: " + identifier)
+		}
+	case domain.ScopeAssetWildcard:
+		identifier = strings.TrimSuffix(strings.ToLower(identifier), ".")
+		if !strings.HasPrefix(identifier, "*.") || !hostnamePattern.MatchString(identifier[2:]) {
+			return errors.New("无效的通配域名，格式应为 *.example.com")
+		}
+	case domain.ScopeAssetURLPrefix, domain.ScopeAssetSourceRepo:
+		if parseScopeTarget(identifier) == nil {
+			return errors.New("无效的地址: " + identifier)
+		}
+	case domain.ScopeAssetIPCIDR:
+		if strings.Contains(identifier, "/") {
+			_, network, err := net.ParseCIDR(identifier)
+			if err != nil {
+				return errors.New("无效的网段: " + identifier)
+			}
+			identifier = network.String()
+		} else if ip := net.ParseIP(identifier); ip != nil {
+			identifier = ip.String()
+		} else {
+			return errors.New("无效的IP地址: " + identifier)
+		}
+	case domain.ScopeAssetMobileApp:
+		if !appIDPattern.MatchString(identifier) {
+			return errors.New("无效的应用ID: " + identifier)
+		}
+	default:
+		return errors.New("无效的资产类型")
+	}
+	scope.Identifier = identifier
+
+	if scope.MaxSeverity != "" {
+		if _, ok := severityRanks[scope.MaxSeverity]; !ok {
+			return errors.New("无效的最高危害等级")
+		}
+	}
+	return nil
+}
+
+// scopeTarget 解析后的漏洞链接
+type scopeTarget struct {
+	host string // 小写主机名（不含端口）
+	path string // 主机[:端口] + 路径，用于前缀匹配
+	ip   net.IP
+}
+
+// parseScopeTarget 解析漏洞链接或资产地址，忽略协议、查询参数和锚点
+func parseScopeTarget(raw string) *scopeTarget {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return nil
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	hostPort := host
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		hostPort += ":" + port
+	}
+
+	return &scopeTarget{
+		host: host,
+		path: hostPort + u.EscapedPath(),
+		ip:   net.ParseIP(host),
+	}
+}
+
+// matchScope 判断资产是否命中漏洞链接，返回具体程度（0 表示未命中，越大越具体）
+func matchScope(scope *domain.ProjectScope, target *scopeTarget) int {
+	switch scope.AssetType {
+	case domain.ScopeAssetDomain:
+		if target.host == scope.Identifier {
+			return 500
+		}
+	case domain.ScopeAssetWildcard:
+		suffix := scope.Identifier[1:] // .example.com
+		if strings.HasSuffix(target.host, suffix) {
+			return 100 + len(suffix)
+		}
+	case domain.ScopeAssetURLPrefix, domain.ScopeAssetSourceRepo:
+		prefix := parseScopeTarget(scope.Identifier)
+		if prefix == nil {
+			return 0
+		}
+		p := strings.TrimSuffix(prefix.path, "/")
+		if target.path == p || strings.HasPrefix(target.path, p+"/") {
+			return 1000 + len(p)
+		}
+	case domain.ScopeAssetIPCIDR:
+		if target.ip == nil {
+			return 0
+		}
+		if _, network, err := net.ParseCIDR(scope.Identifier); err == nil {
+			if network.Contains(target.ip) {
+				ones, _ := network.Mask.Size()
+				return 100 + ones
+			}
+		} else if ip := net.ParseIP(scope.Identifier); ip != nil && ip.Equal(target.ip) {
+			return 500
+		}
+	case domain.ScopeAssetMobileApp:
+		// 应用深链或 android-app:// 等地址的主机部分为应用ID
+		if target.host == strings.ToLower(scope.Identifier) {
+			return 500
+		}
+	}
+	return 0
+}
+
+// exceedsMaxSeverity 判断危害等级是否超过资产允许的最高等级
+func exceedsMaxSeverity(severity, maxSeverity string) bool {
+	if maxSeverity == "" || severity == "" {
+		return false
+	}
+	return severityRanks[severity] > severityRanks[maxSeverity]
+}
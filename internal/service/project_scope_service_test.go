@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"testing"
+)
+
+// fakeScopeRepo 内存中的范围资产
+type fakeScopeRepo struct {
+	domain.ProjectScopeRepository
+	scopes []domain.ProjectScope
+}
+
+func (r *fakeScopeRepo) FindByID(id uint) (*domain.ProjectScope, error) {
+	for i := range r.scopes {
+		if r.scopes[i].ID == id {
+			return &r.scopes[i], nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeScopeRepo) ListByProject(projectID uint) ([]domain.ProjectScope, error) {
+	var result []domain.ProjectScope
+	for _, scope := range r.scopes {
+		if scope.ProjectID == projectID {
+			result = append(result, scope)
+		}
+	}
+	return result, nil
+}
+
+func TestNormalizeScope(t *testing.T) {
+	tests := []struct {
+		assetType  string
+		identifier string
+		want       string
+		wantErr    bool
+	}{
+		{domain.ScopeAssetDomain, " API.Example.com. ", "api.example.com", false},
+		{domain.ScopeAssetDomain, "example", "", true},
+		{domain.ScopeAssetDomain, "*.example.com", "", true},
+		{domain.ScopeAssetWildcard, "*.Example.com", "*.example.com", false},
+		{domain.ScopeAssetWildcard, "example.com", "", true},
+		{domain.ScopeAssetWildcard, "*.*.example.com", "", true},
+		{domain.ScopeAssetURLPrefix, "https://example.com/api/", "https://example.com/api/", false},
+		{domain.ScopeAssetURLPrefix, "https:///api", "", true},
+		{domain.ScopeAssetIPCIDR, "10.0.1.7/16", "10.0.0.0/16", false},
+		{domain.ScopeAssetIPCIDR, "2001:db8::0:1", "2001:db8::1", false},
+		{domain.ScopeAssetIPCIDR, "10.0.0.0/33", "", true},
+		{domain.ScopeAssetIPCIDR, "example.com", "", true},
+		{domain.ScopeAssetMobileApp, "com.example.app", "com.example.app", false},
+		{domain.ScopeAssetMobileApp, "com example", "", true},
+		{"other", "example.com", "", true},
+		{domain.ScopeAssetDomain, "  ", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.assetType+" "+tt.identifier, func(t *testing.T) {
+			scope := &domain.ProjectScope{AssetType: tt.assetType, Identifier: tt.identifier}
+			err := normalizeScope(scope)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeScope() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && scope.Identifier != tt.want {
+				t.Errorf("Identifier = %q, want %q", scope.Identifier, tt.want)
+			}
+		})
+	}
+
+	if err := normalizeScope(&domain.ProjectScope{AssetType: domain.ScopeAssetDomain, Identifier: "example.com", MaxSeverity: "Severe"}); err == nil {
+		t.Error("normalizeScope() 应拒绝无效的最高危害等级")
+	}
+}
+
+func TestMatchScope(t *testing.T) {
+	tests := []struct {
+		assetType  string
+		identifier string
+		url        string
+		want       int
+	}{
+		{domain.ScopeAssetDomain, "example.com", "https://EXAMPLE.com:8443/login", 500},
+		{domain.ScopeAssetDomain, "example.com", "https://api.example.com/", 0},
+		{domain.ScopeAssetWildcard, "*.example.com", "https://api.example.com/", 100 + len(".example.com")},
+		{domain.ScopeAssetWildcard, "*.example.com", "https://a.b.example.com/", 100 + len(".example.com")},
+		// 通配域名不包含根域名，也不匹配相同后缀的其他域名
+		{domain.ScopeAssetWildcard, "*.example.com", "https://example.com/", 0},
+		{domain.ScopeAssetWildcard, "*.example.com", "https://evilexample.com/", 0},
+		{domain.ScopeAssetURLPrefix, "https://example.com/api/", "http://example.com/api/v1/users?id=1", 1000 + len("example.com/api")},
+		{domain.ScopeAssetURLPrefix, "https://example.com/api", "https://example.com/api", 1000 + len("example.com/api")},
+		// 前缀按路径段匹配
+		{domain.ScopeAssetURLPrefix, "https://example.com/api", "https://example.com/apikeys", 0},
+		{domain.ScopeAssetURLPrefix, "https://example.com:8080/api", "https://example.com/api/v1", 0},
+		{domain.ScopeAssetIPCIDR, "10.0.0.0/16", "http://10.0.3.4/admin", 116},
+		{domain.ScopeAssetIPCIDR, "10.0.0.0/16", "http://10.1.0.1/", 0},
+		{domain.ScopeAssetIPCIDR, "10.0.0.0/16", "http://example.com/", 0},
+		{domain.ScopeAssetIPCIDR, "10.0.3.4", "http://10.0.3.4:8080/", 500},
+		{domain.ScopeAssetMobileApp, "com.Example.app", "android-app://com.example.app/path", 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.identifier+" "+tt.url, func(t *testing.T) {
+			target := parseScopeTarget(tt.url)
+			if target == nil {
+				t.Fatalf("parseScopeTarget(%q) = nil", tt.url)
+			}
+			scope := &domain.ProjectScope{AssetType: tt.assetType, Identifier: tt.identifier}
+			if got := matchScope(scope, target); got != tt.want {
+				t.Errorf("matchScope() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	repo := &fakeScopeRepo{scopes: []domain.ProjectScope{
+		{ID: 1, ProjectID: 1, AssetType: domain.ScopeAssetWildcard, Identifier: "*.example.com", InScope: true},
+		{ID: 2, ProjectID: 1, AssetType: domain.ScopeAssetDomain, Identifier: "legacy.example.com", InScope: false},
+		{ID: 3, ProjectID: 1, AssetType: domain.ScopeAssetURLPrefix, Identifier: "https://legacy.example.com/api/v2", InScope: true},
+		// 具体程度相同时范围外优先
+		{ID: 4, ProjectID: 1, AssetType: domain.ScopeAssetDomain, Identifier: "shop.example.com", InScope: true},
+		{ID: 5, ProjectID: 1, AssetType: domain.ScopeAssetDomain, Identifier: "shop.example.com", InScope: false},
+	}}
+	service := NewProjectScopeService(repo, nil)
+
+	tests := []struct {
+		name        string
+		projectID   uint
+		url         string
+		wantStatus  string
+		wantMatched uint
+	}{
+		{name: "命中通配域名", projectID: 1, url: "https://www.example.com/", wantStatus: domain.ReportScopeInScope, wantMatched: 1},
+		{name: "范围外域名优先于通配域名", projectID: 1, url: "https://legacy.example.com/login", wantStatus: domain.ReportScopeOutOfScope, wantMatched: 2},
+		{name: "地址前缀优先于域名", projectID: 1, url: "https://legacy.example.com/api/v2/users", wantStatus: domain.ReportScopeInScope, wantMatched: 3},
+		{name: "具体程度相同时范围外优先", projectID: 1, url: "https://shop.example.com/", wantStatus: domain.ReportScopeOutOfScope, wantMatched: 5},
+		{name: "未命中任何资产", projectID: 1, url: "https://example.org/", wantStatus: domain.ReportScopeOutOfScope},
+		{name: "链接为空", projectID: 1, url: "", wantStatus: domain.ReportScopeUnknown},
+		{name: "项目未配置范围", projectID: 2, url: "https://www.example.com/", wantStatus: domain.ReportScopeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.CheckURL(tt.projectID, tt.url)
+			if err != nil {
+				t.Fatalf("CheckURL() error = %v", err)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			var matched uint
+			if got.Matched != nil {
+				matched = got.Matched.ID
+			}
+			if matched != tt.wantMatched {
+				t.Errorf("Matched = %d, want %d", matched, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestExceedsMaxSeverity(t *testing.T) {
+	tests := []struct {
+		severity, maxSeverity string
+		want                  bool
+	}{
+		{"Critical", "High", true},
+		{"High", "High", false},
+		{"Low", "Medium", false},
+		{"Low", "None", true},
+		{"Critical", "", false},
+		{"", "Low", false},
+	}
+
+	for _, tt := range tests {
+		if got := exceedsMaxSeverity(tt.severity, tt.maxSeverity); got != tt.want {
+			t.Errorf("exceedsMaxSeverity(%q, %q) = %v, want %v", tt.severity, tt.maxSeverity, got, tt.want)
+		}
+	}
+}
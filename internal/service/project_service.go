@@ -3,14 +3,24 @@ package service
 import (
 	"bug-bounty-lite/internal/domain"
 	"errors"
+	"fmt"
 )
 
+// rewardSeverityKeys 可以设置奖金的危害等级配置键及对应的 Report.Severity
+var rewardSeverityKeys = map[string]string{
+	"CRITICAL": "Critical",
+	"HIGH":     "High",
+	"MEDIUM":   "Medium",
+	"LOW":      "Low",
+}
+
 type projectService struct {
-	repo domain.ProjectRepository
+	repo             domain.ProjectRepository
+	systemConfigRepo domain.SystemConfigRepository
 }
 
-func NewProjectService(repo domain.ProjectRepository) domain.ProjectService {
-	return &projectService{repo: repo}
+func NewProjectService(repo domain.ProjectRepository, systemConfigRepo domain.SystemConfigRepository) domain.ProjectService {
+	return &projectService{repo: repo, systemConfigRepo: systemConfigRepo}
 }
 
 // CreateProject 创建项目
@@ -104,6 +114,14 @@ func (s *projectService) UpdateProject(id uint, input *domain.ProjectUpdateInput
 		}
 		project.Status = input.Status
 	}
+	if input.ScopeEnforcement != "" {
+		switch input.ScopeEnforcement {
+		case domain.ScopeEnforcementOff, domain.ScopeEnforcementWarn, domain.ScopeEnforcementReject:
+			project.ScopeEnforcement = input.ScopeEnforcement
+		default:
+			return nil, errors.New("无效的范围校验模式")
+		}
+	}
 
 	// 3. 保存
 	if err := s.repo.Update(project); err != nil {
@@ -139,3 +157,48 @@ func (s *projectService) RestoreProject(id uint) error {
 
 	return s.repo.Restore(id)
 }
+
+// UpdateRewardTable 更新项目奖励表
+// 每个危害等级必须引用有效的 severity_level 配置，且同一等级只能出现一次
+func (s *projectService) UpdateRewardTable(id uint, tiers []domain.ProjectRewardTier) (*domain.Project, error) {
+	project, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("项目不存在")
+	}
+
+	seen := make(map[string]bool, len(tiers))
+	for i := range tiers {
+		tier := &tiers[i]
+
+		config, err := s.systemConfigRepo.FindByID(tier.SeverityLevelID)
+		if err != nil || config.ConfigType != "severity_level" {
+			return nil, fmt.Errorf("危害等级配置ID %d 不存在", tier.SeverityLevelID)
+		}
+		severity, ok := rewardSeverityKeys[config.ConfigKey]
+		if !ok {
+			return nil, fmt.Errorf("危害等级 %s 不支持设置奖金", config.ConfigValue)
+		}
+		if seen[severity] {
+			return nil, fmt.Errorf("危害等级 %s 重复设置", config.ConfigValue)
+		}
+		seen[severity] = true
+
+		if tier.MinAmount < 0 || tier.MaxAmount < tier.MinAmount {
+			return nil, fmt.Errorf("危害等级 %s 的奖金范围无效", config.ConfigValue)
+		}
+		currency, err := normalizeCurrency(tier.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		tier.Severity = severity
+		tier.Currency = currency
+	}
+
+	project.RewardTable = tiers
+	if err := s.repo.Update(project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"testing"
+)
+
+// fakeProjectRepo 单个项目的仓库
+type fakeProjectRepo struct {
+	domain.ProjectRepository
+	project *domain.Project
+	updated bool
+}
+
+func (r *fakeProjectRepo) FindByID(id uint) (*domain.Project, error) {
+	if r.project == nil || r.project.ID != id {
+		return nil, errors.New("record not found")
+	}
+	return r.project, nil
+}
+
+func (r *fakeProjectRepo) Update(project *domain.Project) error {
+	r.updated = true
+	return nil
+}
+
+// fakeSystemConfigRepo 按ID查找系统配置
+type fakeSystemConfigRepo struct {
+	domain.SystemConfigRepository
+	configs map[uint]domain.SystemConfig
+}
+
+func (r *fakeSystemConfigRepo) FindByID(id uint) (*domain.SystemConfig, error) {
+	config, ok := r.configs[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &config, nil
+}
+
+func TestUpdateRewardTable(t *testing.T) {
+	configs := &fakeSystemConfigRepo{configs: map[uint]domain.SystemConfig{
+		1: {ID: 1, ConfigType: "severity_level", ConfigKey: "CRITICAL", ConfigValue: "严重"},
+		2: {ID: 2, ConfigType: "severity_level", ConfigKey: "HIGH", ConfigValue: "高危"},
+		3: {ID: 3, ConfigType: "severity_level", ConfigKey: "INFO", ConfigValue: "信息"},
+		4: {ID: 4, ConfigType: "vulnerability_type", ConfigKey: "XSS", ConfigValue: "XSS"},
+	}}
+
+	tests := []struct {
+		name      string
+		projectID uint
+		tiers     []domain.ProjectRewardTier
+		want      []domain.ProjectRewardTier
+		wantErr   bool
+	}{
+		{
+			name:      "设置奖励表",
+			projectID: 1,
+			tiers: []domain.ProjectRewardTier{
+				{SeverityLevelID: 1, MinAmount: 50000, MaxAmount: 100000},
+				{SeverityLevelID: 2, Severity: "Critical", MinAmount: 10000, MaxAmount: 10000, Currency: "usd"},
+			},
+			want: []domain.ProjectRewardTier{
+				{SeverityLevelID: 1, Severity: "Critical", MinAmount: 50000, MaxAmount: 100000, Currency: domain.DefaultBountyCurrency},
+				{SeverityLevelID: 2, Severity: "High", MinAmount: 10000, MaxAmount: 10000, Currency: "USD"},
+			},
+		},
+		{name: "清空奖励表", projectID: 1, tiers: []domain.ProjectRewardTier{}, want: []domain.ProjectRewardTier{}},
+		{name: "项目不存在", projectID: 2, tiers: []domain.ProjectRewardTier{}, wantErr: true},
+		{name: "危害等级配置不存在", projectID: 1, tiers: []domain.ProjectRewardTier{{SeverityLevelID: 9, MaxAmount: 1}}, wantErr: true},
+		{name: "不是危害等级配置", projectID: 1, tiers: []domain.ProjectRewardTier{{SeverityLevelID: 4, MaxAmount: 1}}, wantErr: true},
+		{name: "不支持设置奖金的等级", projectID: 1, tiers: []domain.ProjectRewardTier{{SeverityLevelID: 3, MaxAmount: 1}}, wantErr: true},
+		{
+			name:      "同一等级重复设置",
+			projectID: 1,
+			tiers:     []domain.ProjectRewardTier{{SeverityLevelID: 2, MaxAmount: 1}, {SeverityLevelID: 2, MaxAmount: 2}},
+			wantErr:   true,
+		},
+		{name: "最低金额为负", projectID: 1, tiers: []domain.ProjectRewardTier{{SeverityLevelID: 2, MinAmount: -1, MaxAmount: 1}}, wantErr: true},
+		{name: "最高金额小于最低金额", projectID: 1, tiers: []domain.ProjectRewardTier{{SeverityLevelID: 2, MinAmount: 2, MaxAmount: 1}}, wantErr: true},
+		{name: "币种无效", projectID: 1, tiers: []domain.ProjectRewardTier{{SeverityLevelID: 2, MaxAmount: 1, Currency: "元"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeProjectRepo{project: &domain.Project{ID: 1}}
+			service := NewProjectService(repo, configs)
+
+			got, err := service.UpdateRewardTable(tt.projectID, tt.tiers)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateRewardTable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if repo.updated == tt.wantErr {
+				t.Errorf("project updated = %v, want %v", repo.updated, !tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got.RewardTable) != len(tt.want) {
+				t.Fatalf("RewardTable = %+v, want %+v", got.RewardTable, tt.want)
+			}
+			for i := range tt.want {
+				if got.RewardTable[i] != tt.want[i] {
+					t.Errorf("RewardTable[%d] = %+v, want %+v", i, got.RewardTable[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"fmt"
+)
+
+type reportAttachmentService struct {
+	repo       domain.ReportAttachmentRepository
+	reportRepo domain.ReportRepository
+}
+
+// NewReportAttachmentService 创建报告附件服务实例
+func NewReportAttachmentService(repo domain.ReportAttachmentRepository, reportRepo domain.ReportRepository) domain.ReportAttachmentService {
+	return &reportAttachmentService{repo: repo, reportRepo: reportRepo}
+}
+
+// checkReportAccess 校验报告存在且用户是报告作者、管理员或厂商
+func (s *reportAttachmentService) checkReportAccess(reportID uint, userID uint, userRole string) error {
+	report, err := s.reportRepo.FindByID(reportID)
+	if err != nil {
+		return errors.New("report not found")
+	}
+	if report.AuthorID != userID && userRole != "admin" && userRole != "vendor" {
+		return errors.New("permission denied")
+	}
+	return nil
+}
+
+// ListAttachments 获取报告附件
+func (s *reportAttachmentService) ListAttachments(reportID uint, userID uint, userRole string) ([]domain.ReportAttachment, error) {
+	if err := s.checkReportAccess(reportID, userID, userRole); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByReportID(reportID)
+}
+
+// CheckCanAttach 检查用户能否为报告添加附件（报告权限和附件数量），上传文件前调用，避免无权限的请求写入存储
+func (s *reportAttachmentService) CheckCanAttach(reportID uint, userID uint, userRole string) error {
+	if err := s.checkReportAccess(reportID, userID, userRole); err != nil {
+		return err
+	}
+
+	count, err := s.repo.CountByReportID(reportID)
+	if err != nil {
+		return err
+	}
+	if count >= domain.MaxReportAttachments {
+		return fmt.Errorf("每份报告最多上传 %d 个附件", domain.MaxReportAttachments)
+	}
+	return nil
+}
+
+// AddAttachment 为报告添加附件，同时记录审计事件
+func (s *reportAttachmentService) AddAttachment(attachment *domain.ReportAttachment, userRole string) error {
+	if err := s.CheckCanAttach(attachment.ReportID, attachment.UploadedBy, userRole); err != nil {
+		return err
+	}
+
+	return s.repo.Create(attachment, domain.ReportEvent{
+		ActorID:   attachment.UploadedBy,
+		ActorRole: userRole,
+		EventType: domain.ReportEventAttachmentAdded,
+		Field:     "attachments",
+		NewValue:  attachment.Filename,
+	})
+}
+
+// DeleteAttachment 删除报告附件，同时记录审计事件
+func (s *reportAttachmentService) DeleteAttachment(reportID uint, attachmentID uint, userID uint, userRole string) (*domain.ReportAttachment, error) {
+	if err := s.checkReportAccess(reportID, userID, userRole); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.repo.FindByID(attachmentID)
+	if err != nil || attachment.ReportID != reportID {
+		return nil, errors.New("附件不存在")
+	}
+
+	if err := s.repo.Delete(attachment, domain.ReportEvent{
+		ActorID:   userID,
+		ActorRole: userRole,
+		EventType: domain.ReportEventAttachmentRemoved,
+		Field:     "attachments",
+		OldValue:  attachment.Filename,
+	}); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
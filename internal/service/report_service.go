@@ -2,7 +2,9 @@ package service
 
 import (
 	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/cvss"
 	"errors"
+	"strconv"
 
 	"gorm.io/gorm"
 )
@@ -10,22 +12,33 @@ import (
 type reportService struct {
 	repo             domain.ReportRepository
 	systemConfigRepo domain.SystemConfigRepository
+	workflowService  domain.ReportWorkflowService
+	similarityRepo   domain.ReportSimilarityRepository
+	projectRepo      domain.ProjectRepository
+	scopeService     domain.ProjectScopeService
 }
 
-func NewReportService(repo domain.ReportRepository, systemConfigRepo domain.SystemConfigRepository) domain.ReportService {
+func NewReportService(
+	repo domain.ReportRepository,
+	systemConfigRepo domain.SystemConfigRepository,
+	workflowService domain.ReportWorkflowService,
+	similarityRepo domain.ReportSimilarityRepository,
+	projectRepo domain.ProjectRepository,
+	scopeService domain.ProjectScopeService,
+) domain.ReportService {
 	return &reportService{
 		repo:             repo,
 		systemConfigRepo: systemConfigRepo,
+		workflowService:  workflowService,
+		similarityRepo:   similarityRepo,
+		projectRepo:      projectRepo,
+		scopeService:     scopeService,
 	}
 }
 
 // SubmitReport 提交漏洞
 func (s *reportService) SubmitReport(report *domain.Report) error {
-	// 1. 强制初始化状态
-	// 不管前端传什么状态，后端强制设为 Pending
-	report.Status = "Pending"
-
-	// 2. 校验必填参数
+	// 1. 校验必填参数
 	if report.ProjectID == 0 {
 		return errors.New("项目ID不能为空")
 	}
@@ -39,6 +52,14 @@ func (s *reportService) SubmitReport(report *domain.Report) error {
 		return errors.New("提交者ID不能为空")
 	}
 
+	// 2. 强制初始化状态
+	// 不管前端传什么状态，后端强制设为项目工作流的初始状态
+	workflow, err := s.workflowService.GetWorkflow(report.ProjectID)
+	if err != nil {
+		return errors.New("获取工作流失败: " + err.Error())
+	}
+	report.Status = workflow.InitialStatus
+
 	// 3. 验证危害自评ID（如果提供了）
 	if report.SelfAssessmentID != nil && *report.SelfAssessmentID != 0 {
 		config, err := s.systemConfigRepo.FindByID(*report.SelfAssessmentID)
@@ -54,9 +75,50 @@ func (s *reportService) SubmitReport(report *domain.Report) error {
 	}
 
 	// Severity 字段由管理员/厂商审核后设置，新提交时保持为空
+	// 提供了 CVSS 向量时，危害等级由分数推导
+	report.DuplicateOfID = nil
+	report.SeverityOverridden = false
+	if report.CVSSVector != "" {
+		if err := applyCVSSVector(report, report.CVSSVector); err != nil {
+			return err
+		}
+		report.Severity = cvss.SeverityFromScore(*report.CVSSScore)
+	}
+
+	// 4. 校验漏洞链接是否在项目范围内，命中的资产限制了最高危害等级
+	if err := s.applyScope(report); err != nil {
+		return err
+	}
+	if err := s.checkScopeSeverity(report); err != nil {
+		return err
+	}
+
+	// 5. 与同项目已有报告比对相似度
+	existing, err := s.repo.ListByProject(report.ProjectID, domain.DuplicateScanLimit)
+	if err != nil {
+		return errors.New("查询同项目报告失败: " + err.Error())
+	}
+	similarities := findSimilarReports(report, existing)
 
-	// 5. 调用 Repo 创建
-	return s.repo.Create(report)
+	// 6. 调用 Repo 创建，同时记录提交事件
+	if err := s.repo.Create(report, domain.ReportEvent{
+		ActorID:   report.AuthorID,
+		EventType: domain.ReportEventCreated,
+		Field:     "status",
+		NewValue:  report.Status,
+	}); err != nil {
+		return err
+	}
+
+	// 7. 保存疑似重复记录（失败不影响提交）
+	if len(similarities) > 0 {
+		for i := range similarities {
+			similarities[i].ReportID = report.ID
+		}
+		_ = s.similarityRepo.Create(similarities)
+	}
+
+	return nil
 }
 
 func (s *reportService) GetReport(id uint) (*domain.Report, error) {
@@ -99,23 +161,17 @@ func (s *reportService) UpdateReport(id uint, userID uint, userRole string, inpu
 		return nil, errors.New("permission denied")
 	}
 
-	// 3. 状态更新权限校验
-	// 只有管理员和厂商可以修改状态
-	if input.Status != "" && input.Status != report.Status {
-		if userRole != "admin" && userRole != "vendor" {
-			return nil, errors.New("only admin or vendor can change status")
-		}
-		// 校验状态流转
-		if !isValidStatusTransition(report.Status, input.Status) {
-			return nil, errors.New("invalid status transition")
-		}
-		report.Status = input.Status
-	}
+	// 保存更新前的快照，用于生成审计事件
+	before := *report
 
-	// 4. 更新字段
-	if input.ProjectID != 0 {
-		report.ProjectID = input.ProjectID
+	// 3. 报告当前所属项目的工作流（状态流转和变更项目都以它为准）
+	workflow, err := s.workflowService.GetWorkflow(report.ProjectID)
+	if err != nil {
+		return nil, errors.New("获取工作流失败: " + err.Error())
 	}
+	moveProject := input.ProjectID != 0 && input.ProjectID != report.ProjectID
+
+	// 4. 更新字段（所属项目在状态流转校验通过后再变更）
 	if input.VulnerabilityName != "" {
 		report.VulnerabilityName = input.VulnerabilityName
 	}
@@ -150,41 +206,88 @@ func (s *reportService) UpdateReport(id uint, userID uint, userRole string, inpu
 	if input.VulnerabilityDetail != "" {
 		report.VulnerabilityDetail = input.VulnerabilityDetail
 	}
-	if input.AttachmentURL != "" {
-		report.AttachmentURL = input.AttachmentURL
+	// 报告进入审核后，CVSS 向量和危害等级只能由审核人员修改（会影响排行榜积分和建议奖金）
+	reviewer := userRole == "admin" || userRole == "vendor"
+	if input.CVSSVector != "" {
+		previousVector := report.CVSSVector
+		if err := applyCVSSVector(report, input.CVSSVector); err != nil {
+			return nil, err
+		}
+		if report.CVSSVector != previousVector && !reviewer && !workflow.AuthorEditable(before.Status) {
+			return nil, errors.New("报告已进入审核，只有管理员和厂商可以修改 CVSS 向量")
+		}
+	}
+	if input.Severity != "" && input.Severity != report.Severity && !reviewer && !workflow.AuthorEditable(before.Status) {
+		return nil, errors.New("报告已进入审核，只有管理员和厂商可以修改危害等级")
 	}
+
+	// 危害等级：有 CVSS 分数时默认跟随分数，与推导结果不一致视为审核人员覆盖
 	if input.Severity != "" {
+		if report.CVSSScore != nil && input.Severity != cvss.SeverityFromScore(*report.CVSSScore) {
+			if userRole != "admin" && userRole != "vendor" {
+				return nil, errors.New("只有管理员和厂商可以覆盖 CVSS 推导的危害等级")
+			}
+			report.SeverityOverridden = true
+		} else {
+			report.SeverityOverridden = false
+		}
 		report.Severity = input.Severity
+	} else if report.CVSSScore != nil && !report.SeverityOverridden {
+		report.Severity = cvss.SeverityFromScore(*report.CVSSScore)
 	}
 
-	// 5. 保存
-	if err := s.repo.Update(report); err != nil {
-		return nil, err
+	// 5. 状态流转校验
+	// 角色权限和必填字段由报告当前所属项目的工作流决定，校验时使用已更新字段后的报告
+	if input.Status != "" && input.Status != report.Status {
+		if err := s.workflowService.ValidateTransition(report, input.Status, userRole, input); err != nil {
+			return nil, err
+		}
+		report.Status = input.Status
 	}
 
-	return report, nil
-}
+	// 6. 变更所属项目：流转后的状态不能是终态，且必须存在于新项目的工作流中
+	if moveProject {
+		if state := workflow.FindState(report.Status); state != nil && state.Terminal {
+			return nil, errors.New("报告已处于终态 " + report.Status + "，不能再变更所属项目")
+		}
+		target, err := s.workflowService.GetWorkflow(input.ProjectID)
+		if err != nil {
+			return nil, errors.New("获取工作流失败: " + err.Error())
+		}
+		if target.FindState(report.Status) == nil {
+			return nil, errors.New("目标项目的工作流中没有状态 " + report.Status + "，不能变更所属项目")
+		}
+		report.ProjectID = input.ProjectID
+	}
 
-// isValidStatusTransition 校验状态流转是否合法
-func isValidStatusTransition(from, to string) bool {
-	validTransitions := map[string][]string{
-		"Pending":  {"Triaged", "Closed"},
-		"Triaged":  {"Resolved", "Closed"},
-		"Resolved": {"Closed"},
-		"Closed":   {}, // 关闭后不能再改
+	// 漏洞链接或项目变更时重新判定范围
+	if report.VulnerabilityURL != before.VulnerabilityURL || report.ProjectID != before.ProjectID {
+		if err := s.applyScope(report); err != nil {
+			return nil, err
+		}
 	}
 
-	allowed, ok := validTransitions[from]
-	if !ok {
-		return false
+	// 命中的资产限制了最高危害等级（危害等级或命中的资产变化时重新校验）
+	if report.Severity != before.Severity || formatUintPtr(report.ScopeID) != formatUintPtr(before.ScopeID) {
+		if err := s.checkScopeSeverity(report); err != nil {
+			return nil, err
+		}
 	}
 
-	for _, status := range allowed {
-		if status == to {
-			return true
+	// 7. 保存，同时写入字段变更事件
+	events := diffReportEvents(&before, report, userID, userRole, input.Reason)
+	if err := s.repo.Update(report, events...); err != nil {
+		return nil, err
+	}
+
+	// 8. 审核人员设置危害等级（或修改 CVSS 向量导致危害等级变化）时，根据项目奖励表给出建议奖金
+	if reviewer && report.Severity != "" && report.Severity != before.Severity {
+		if project, err := s.projectRepo.FindByID(report.ProjectID); err == nil {
+			report.SuggestedBounty = suggestBounty(project, report)
 		}
 	}
-	return false
+
+	return report, nil
 }
 
 // DeleteReport 软删除报告
@@ -201,7 +304,11 @@ func (s *reportService) DeleteReport(id uint, userID uint, userRole string) erro
 	}
 
 	// 3. 执行软删除
-	return s.repo.Delete(id)
+	return s.repo.Delete(id, domain.ReportEvent{
+		ActorID:   userID,
+		ActorRole: userRole,
+		EventType: domain.ReportEventDeleted,
+	})
 }
 
 // RestoreReport 恢复已删除的报告
@@ -223,5 +330,200 @@ func (s *reportService) RestoreReport(id uint, userID uint, userRole string) err
 	}
 
 	// 4. 执行恢复
-	return s.repo.Restore(id)
+	return s.repo.Restore(id, domain.ReportEvent{
+		ActorID:   userID,
+		ActorRole: userRole,
+		EventType: domain.ReportEventRestored,
+	})
+}
+
+// ListDuplicateCandidates 获取报告的疑似重复列表（仅管理员和厂商）
+func (s *reportService) ListDuplicateCandidates(id uint, userRole string) ([]domain.ReportSimilarity, error) {
+	if userRole != "admin" && userRole != "vendor" {
+		return nil, errors.New("permission denied")
+	}
+
+	if _, err := s.repo.FindByID(id); err != nil {
+		return nil, errors.New("报告不存在")
+	}
+
+	return s.similarityRepo.FindByReportID(id)
+}
+
+// MarkDuplicate 将报告标记为另一份报告的重复
+// 原始报告如果本身也是重复报告，则关联到其最终的原始报告
+func (s *reportService) MarkDuplicate(id uint, originalID uint, userID uint, userRole string, reason string) (*domain.Report, error) {
+	if userRole != "admin" && userRole != "vendor" {
+		return nil, errors.New("permission denied")
+	}
+
+	report, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("报告不存在")
+	}
+
+	original, err := s.repo.FindByID(originalID)
+	if err != nil {
+		return nil, errors.New("原始报告不存在")
+	}
+
+	// 沿重复链找到最终的原始报告
+	visited := map[uint]bool{report.ID: true}
+	for original.DuplicateOfID != nil {
+		if visited[original.ID] {
+			return nil, errors.New("不能将报告标记为自身的重复")
+		}
+		visited[original.ID] = true
+		original, err = s.repo.FindByID(*original.DuplicateOfID)
+		if err != nil {
+			return nil, errors.New("原始报告不存在")
+		}
+	}
+
+	if original.ID == report.ID {
+		return nil, errors.New("不能将报告标记为自身的重复")
+	}
+	if original.ProjectID != report.ProjectID {
+		return nil, errors.New("只能标记为同一项目下报告的重复")
+	}
+
+	before := *report
+	report.DuplicateOfID = &original.ID
+
+	input := &domain.ReportUpdateInput{Status: domain.ReportStatusDuplicate, Reason: reason}
+	if err := s.workflowService.ValidateTransition(report, domain.ReportStatusDuplicate, userRole, input); err != nil {
+		return nil, err
+	}
+	report.Status = domain.ReportStatusDuplicate
+
+	events := diffReportEvents(&before, report, userID, userRole, reason)
+	if err := s.repo.Update(report, events...); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// applyScope 根据项目范围判定报告的范围状态
+// 项目范围校验模式为 reject 时，范围外的报告直接拒绝
+func (s *reportService) applyScope(report *domain.Report) error {
+	report.ScopeStatus = domain.ReportScopeUnknown
+	report.ScopeID = nil
+
+	project, err := s.projectRepo.FindByID(report.ProjectID)
+	if err != nil {
+		return errors.New("项目不存在")
+	}
+	if project.ScopeEnforcement == domain.ScopeEnforcementOff {
+		return nil
+	}
+
+	result, err := s.scopeService.CheckURL(project.ID, report.VulnerabilityURL)
+	if err != nil {
+		return errors.New("校验项目范围失败: " + err.Error())
+	}
+	report.ScopeStatus = result.Status
+	if result.Matched != nil {
+		report.ScopeID = &result.Matched.ID
+	}
+
+	if result.Status == domain.ReportScopeOutOfScope && project.ScopeEnforcement == domain.ScopeEnforcementReject {
+		return errors.New("漏洞不在项目范围内: " + result.Message)
+	}
+	return nil
+}
+
+// checkScopeSeverity 报告命中的范围内资产设置了最高危害等级时，危害等级不能超过该等级
+func (s *reportService) checkScopeSeverity(report *domain.Report) error {
+	if report.ScopeID == nil || report.Severity == "" {
+		return nil
+	}
+	scope, err := s.scopeService.GetScope(*report.ScopeID)
+	if err != nil || !scope.InScope {
+		return nil
+	}
+	if exceedsMaxSeverity(report.Severity, scope.MaxSeverity) {
+		return errors.New("该资产可评定的最高危害等级为 " + scope.MaxSeverity)
+	}
+	return nil
+}
+
+// applyCVSSVector 解析 CVSS 向量并写入报告的向量、版本和分数
+func applyCVSSVector(report *domain.Report, vector string) error {
+	result, err := cvss.Parse(vector)
+	if err != nil {
+		return err
+	}
+	report.CVSSVector = result.Vector
+	report.CVSSVersion = result.Version
+	report.CVSSScore = &result.Score
+	return nil
+}
+
+// diffReportEvents 对比报告更新前后的字段，为每个变更字段生成一条审计事件
+func diffReportEvents(before, after *domain.Report, actorID uint, actorRole, reason string) []domain.ReportEvent {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"project_id", formatUint(before.ProjectID), formatUint(after.ProjectID)},
+		{"vulnerability_name", before.VulnerabilityName, after.VulnerabilityName},
+		{"vulnerability_type_id", formatUint(before.VulnerabilityTypeID), formatUint(after.VulnerabilityTypeID)},
+		{"vulnerability_impact", before.VulnerabilityImpact, after.VulnerabilityImpact},
+		{"self_assessment_id", formatUintPtr(before.SelfAssessmentID), formatUintPtr(after.SelfAssessmentID)},
+		{"vulnerability_url", before.VulnerabilityURL, after.VulnerabilityURL},
+		{"scope_status", before.ScopeStatus, after.ScopeStatus},
+		{"vulnerability_detail", before.VulnerabilityDetail, after.VulnerabilityDetail},
+		{"cvss_vector", before.CVSSVector, after.CVSSVector},
+		{"cvss_score", formatScorePtr(before.CVSSScore), formatScorePtr(after.CVSSScore)},
+		{"severity", before.Severity, after.Severity},
+		{"severity_overridden", strconv.FormatBool(before.SeverityOverridden), strconv.FormatBool(after.SeverityOverridden)},
+		{"duplicate_of_id", formatUintPtr(before.DuplicateOfID), formatUintPtr(after.DuplicateOfID)},
+		{"status", before.Status, after.Status},
+	}
+
+	var events []domain.ReportEvent
+	for _, f := range fields {
+		if f.old == f.new {
+			continue
+		}
+		eventType := domain.ReportEventUpdated
+		if f.name == "status" {
+			eventType = domain.ReportEventStatusChanged
+		}
+		events = append(events, domain.ReportEvent{
+			ActorID:   actorID,
+			ActorRole: actorRole,
+			EventType: eventType,
+			Field:     f.name,
+			OldValue:  f.old,
+			NewValue:  f.new,
+			Reason:    reason,
+		})
+	}
+	return events
+}
+
+// formatUint 将 ID 转为字符串（0 视为空）
+func formatUint(v uint) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+// formatUintPtr 将可空 ID 转为字符串
+func formatUintPtr(v *uint) string {
+	if v == nil {
+		return ""
+	}
+	return formatUint(*v)
+}
+
+// formatScorePtr 将可空分数转为字符串（保留一位小数）
+func formatScorePtr(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 1, 64)
 }
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func TestDiffReportEvents(t *testing.T) {
+	assessment := uint(3)
+	before := domain.Report{
+		ProjectID:         1,
+		VulnerabilityName: "存储型 XSS",
+		Severity:          "Medium",
+		Status:            domain.ReportStatusPending,
+	}
+
+	tests := []struct {
+		name   string
+		change func(r *domain.Report)
+		want   []domain.ReportEvent
+	}{
+		{name: "没有变化", change: func(r *domain.Report) {}},
+		{
+			name:   "修改字段",
+			change: func(r *domain.Report) { r.VulnerabilityName = "反射型 XSS" },
+			want: []domain.ReportEvent{
+				{EventType: domain.ReportEventUpdated, Field: "vulnerability_name", OldValue: "存储型 XSS", NewValue: "反射型 XSS"},
+			},
+		},
+		{
+			name:   "状态变更单独记录类型",
+			change: func(r *domain.Report) { r.Severity = "High"; r.Status = domain.ReportStatusTriaged },
+			want: []domain.ReportEvent{
+				{EventType: domain.ReportEventUpdated, Field: "severity", OldValue: "Medium", NewValue: "High"},
+				{EventType: domain.ReportEventStatusChanged, Field: "status", OldValue: domain.ReportStatusPending, NewValue: domain.ReportStatusTriaged},
+			},
+		},
+		{
+			name:   "ID 由空变为有值",
+			change: func(r *domain.Report) { r.SelfAssessmentID = &assessment; r.ProjectID = 2 },
+			want: []domain.ReportEvent{
+				{EventType: domain.ReportEventUpdated, Field: "project_id", OldValue: "1", NewValue: "2"},
+				{EventType: domain.ReportEventUpdated, Field: "self_assessment_id", OldValue: "", NewValue: "3"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := before
+			tt.change(&after)
+
+			got := diffReportEvents(&before, &after, 9, "admin", "复核")
+			for i := range tt.want {
+				tt.want[i].ActorID = 9
+				tt.want[i].ActorRole = "admin"
+				tt.want[i].Reason = "复核"
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffReportEvents() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckScopeSeverity(t *testing.T) {
+	service := &reportService{scopeService: NewProjectScopeService(&fakeScopeRepo{scopes: []domain.ProjectScope{
+		{ID: 1, InScope: true, MaxSeverity: "Medium"},
+		{ID: 2, InScope: true},
+		// 范围外资产不参与危害等级限制（由奖励资格控制）
+		{ID: 3, InScope: false, MaxSeverity: "Low"},
+	}}, nil)}
+	scope := func(id uint) *uint { return &id }
+
+	tests := []struct {
+		name     string
+		scopeID  *uint
+		severity string
+		wantErr  bool
+	}{
+		{name: "不超过最高等级", scopeID: scope(1), severity: "Medium"},
+		{name: "低于最高等级", scopeID: scope(1), severity: "Low"},
+		{name: "超过最高等级", scopeID: scope(1), severity: "High", wantErr: true},
+		{name: "严重超过最高等级", scopeID: scope(1), severity: "Critical", wantErr: true},
+		{name: "资产未限制等级", scopeID: scope(2), severity: "Critical"},
+		{name: "范围外资产", scopeID: scope(3), severity: "Critical"},
+		{name: "资产已删除", scopeID: scope(9), severity: "Critical"},
+		{name: "未命中资产", severity: "Critical"},
+		{name: "未评定等级", scopeID: scope(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.checkScopeSeverity(&domain.Report{ScopeID: tt.scopeID, Severity: tt.severity})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkScopeSeverity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 相似度权重：链接一致 0.4、类型一致 0.2、文本相似度 0.4
+const (
+	similarityWeightURL  = 0.4
+	similarityWeightType = 0.2
+	similarityWeightText = 0.4
+)
+
+// findSimilarReports 计算新报告与同项目已有报告的相似度，返回达到阈值的候选（按得分降序）
+func findSimilarReports(report *domain.Report, existing []domain.Report) []domain.ReportSimilarity {
+	reportURL := normalizeVulnerabilityURL(report.VulnerabilityURL)
+	reportTokens := tokenizeReportText(report.VulnerabilityName + " " + report.VulnerabilityDetail)
+
+	var candidates []domain.ReportSimilarity
+	for i := range existing {
+		other := &existing[i]
+		if other.ID == report.ID {
+			continue
+		}
+
+		urlMatch := reportURL != "" && reportURL == normalizeVulnerabilityURL(other.VulnerabilityURL)
+		typeMatch := report.VulnerabilityTypeID == other.VulnerabilityTypeID
+		textScore := jaccardSimilarity(reportTokens, tokenizeReportText(other.VulnerabilityName+" "+other.VulnerabilityDetail))
+
+		score := similarityWeightText * textScore
+		if urlMatch {
+			score += similarityWeightURL
+		}
+		if typeMatch {
+			score += similarityWeightType
+		}
+
+		if score < domain.DuplicateScoreThreshold {
+			continue
+		}
+
+		// 重复报告指向原始报告，避免把重复链上的报告都列出来
+		candidateID := other.ID
+		if other.DuplicateOfID != nil {
+			candidateID = *other.DuplicateOfID
+		}
+
+		candidates = append(candidates, domain.ReportSimilarity{
+			CandidateID: candidateID,
+			Score:       score,
+			URLMatch:    urlMatch,
+			TypeMatch:   typeMatch,
+			TextScore:   textScore,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	// 同一原始报告只保留得分最高的一条
+	seen := make(map[uint]bool)
+	result := make([]domain.ReportSimilarity, 0, domain.DuplicateMaxCandidates)
+	for _, c := range candidates {
+		if seen[c.CandidateID] || c.CandidateID == report.ID {
+			continue
+		}
+		seen[c.CandidateID] = true
+		result = append(result, c)
+		if len(result) >= domain.DuplicateMaxCandidates {
+			break
+		}
+	}
+	return result
+}
+
+// normalizeVulnerabilityURL 规范化漏洞链接
+// 忽略协议、大小写、默认端口、www 前缀、末尾斜杠、锚点，查询参数按名称排序（忽略参数值）
+func normalizeVulnerabilityURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(raw)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+
+	path := strings.TrimRight(strings.ToLower(u.EscapedPath()), "/")
+
+	keys := make([]string, 0)
+	for key := range u.Query() {
+		keys = append(keys, strings.ToLower(key))
+	}
+	sort.Strings(keys)
+
+	normalized := host + path
+	if len(keys) > 0 {
+		normalized += "?" + strings.Join(keys, "&")
+	}
+	return normalized
+}
+
+// tokenizeReportText 将文本切分为词集合
+// 英文和数字按非字母数字字符切分，中日韩文字使用二元组（bigram）
+func tokenizeReportText(text string) map[string]bool {
+	tokens := make(map[string]bool)
+	var word []rune
+	var cjk []rune
+
+	flushWord := func() {
+		if len(word) > 1 {
+			tokens[string(word)] = true
+		}
+		word = word[:0]
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 {
+			tokens[string(cjk)] = true
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			tokens[string(cjk[i:i+2])] = true
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+
+	return tokens
+}
+
+// jaccardSimilarity 计算两个词集合的 Jaccard 相似度
+func jaccardSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	intersection := 0
+	for token := range a {
+		if b[token] {
+			intersection++
+		}
+	}
+	union := len(a) + len(b) - intersection
+	return float64(intersection) / float64(union)
+}
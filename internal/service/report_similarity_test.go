@@ -0,0 +1,99 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"math"
+	"testing"
+)
+
+func TestNormalizeVulnerabilityURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"https://www.Example.com/Login/", "example.com/login"},
+		{"http://example.com:80/login", "example.com/login"},
+		{"https://example.com:443/login", "example.com/login"},
+		{"https://example.com:8443/login", "example.com:8443/login"},
+		{"example.com/login#top", "example.com/login"},
+		{"https://example.com/search?q=1&Page=2", "example.com/search?page&q"},
+		{"https://example.com/search?page=3&q=<script>", "example.com/search?page&q"},
+		{"https://example.com/a%2Fb", "example.com/a%2fb"},
+		// 无法解析出主机时退化为小写原文
+		{"http://", "http://"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := normalizeVulnerabilityURL(tt.raw); got != tt.want {
+				t.Errorf("normalizeVulnerabilityURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJaccardSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"SQL injection in login", "sql injection in login", 1},
+		{"SQL injection", "XSS payload", 0},
+		{"", "SQL injection", 0},
+		// 单字符英文词被忽略
+		{"a b c", "a b c", 0},
+		// 中文按二元组切分：登录接口 -> 登录/录接/接口
+		{"登录接口注入", "登录接口越权", 3.0 / 7},
+		{"SQL 注入", "sql注入", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"|"+tt.b, func(t *testing.T) {
+			got := jaccardSimilarity(tokenizeReportText(tt.a), tokenizeReportText(tt.b))
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("jaccardSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindSimilarReports(t *testing.T) {
+	original := uint(1)
+	report := &domain.Report{
+		ID:                  10,
+		VulnerabilityTypeID: 2,
+		VulnerabilityName:   "登录接口 SQL 注入",
+		VulnerabilityURL:    "https://example.com/login?user=a",
+	}
+	existing := []domain.Report{
+		// 链接、类型、文本全部一致
+		{ID: 1, VulnerabilityTypeID: 2, VulnerabilityName: "登录接口 SQL 注入", VulnerabilityURL: "http://www.example.com/login/?user=b"},
+		// 是 1 的重复报告，应折叠到 1
+		{ID: 2, VulnerabilityTypeID: 2, VulnerabilityName: "登录接口 SQL 注入", VulnerabilityURL: "https://example.com/login?user=c", DuplicateOfID: &original},
+		// 仅类型一致，得分低于阈值
+		{ID: 3, VulnerabilityTypeID: 2, VulnerabilityName: "头像上传 XSS", VulnerabilityURL: "https://example.com/avatar"},
+		// 链接和类型一致，文本不同（链接参数值不同也视为同一链接）
+		{ID: 4, VulnerabilityTypeID: 2, VulnerabilityName: "越权", VulnerabilityURL: "https://example.com/login?user=d"},
+		// 仅链接一致，文本不同，得分低于阈值
+		{ID: 5, VulnerabilityTypeID: 7, VulnerabilityName: "越权", VulnerabilityURL: "https://example.com/login?user=e"},
+		// 报告自身
+		{ID: 10, VulnerabilityTypeID: 2, VulnerabilityName: "登录接口 SQL 注入", VulnerabilityURL: "https://example.com/login?user=a"},
+	}
+
+	got := findSimilarReports(report, existing)
+	want := []domain.ReportSimilarity{
+		{CandidateID: 1, Score: 1, URLMatch: true, TypeMatch: true, TextScore: 1},
+		{CandidateID: 4, Score: similarityWeightURL + similarityWeightType, URLMatch: true, TypeMatch: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("findSimilarReports() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].CandidateID != want[i].CandidateID || got[i].URLMatch != want[i].URLMatch || got[i].TypeMatch != want[i].TypeMatch ||
+			math.Abs(got[i].Score-want[i].Score) > 1e-9 || math.Abs(got[i].TextScore-want[i].TextScore) > 1e-9 {
+			t.Errorf("findSimilarReports()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/types"
+	"errors"
+	"sort"
+)
+
+type reportTimelineService struct {
+	eventRepo   domain.ReportEventRepository
+	commentRepo domain.CommentRepository
+	reportRepo  domain.ReportRepository
+}
+
+// NewReportTimelineService 创建报告时间线服务实例
+func NewReportTimelineService(
+	eventRepo domain.ReportEventRepository,
+	commentRepo domain.CommentRepository,
+	reportRepo domain.ReportRepository,
+) domain.ReportTimelineService {
+	return &reportTimelineService{
+		eventRepo:   eventRepo,
+		commentRepo: commentRepo,
+		reportRepo:  reportRepo,
+	}
+}
+
+// GetTimeline 获取报告时间线
+// 白帽子只能查看自己报告的时间线，厂商和管理员可以查看所有（包括已删除的报告）
+func (s *reportTimelineService) GetTimeline(reportID uint, userID uint, userRole string) ([]domain.ReportTimelineItem, error) {
+	var report *domain.Report
+	var err error
+	if userRole == "admin" || userRole == "vendor" {
+		report, err = s.reportRepo.FindByIDWithDeleted(reportID)
+	} else {
+		report, err = s.reportRepo.FindByID(reportID)
+	}
+	if err != nil {
+		return nil, errors.New("报告不存在")
+	}
+	if userRole == "whitehat" && report.AuthorID != userID {
+		return nil, errors.New("无权查看此报告")
+	}
+
+	events, err := s.eventRepo.FindByReportID(reportID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.commentRepo.FindByReportID(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.ReportTimelineItem, 0, len(events)+len(comments))
+	for i := range events {
+		items = append(items, domain.ReportTimelineItem{
+			Type:      "event",
+			CreatedAt: events[i].CreatedAt,
+			Event:     &events[i],
+		})
+	}
+	for i := range comments {
+		items = append(items, domain.ReportTimelineItem{
+			Type:      "comment",
+			CreatedAt: types.DateTime(comments[i].CreatedAt),
+			Comment:   &comments[i],
+		})
+	}
+
+	// 按时间正序合并，同一时间的事件排在评论之前
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.Time().Before(items[j].CreatedAt.Time())
+	})
+
+	return items, nil
+}
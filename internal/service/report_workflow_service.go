@@ -0,0 +1,229 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"errors"
+	"fmt"
+)
+
+// validWorkflowRoles 工作流中允许配置的角色
+var validWorkflowRoles = map[string]bool{
+	"whitehat": true,
+	"vendor":   true,
+	"admin":    true,
+}
+
+// validWorkflowFields 工作流中允许配置的必填字段
+var validWorkflowFields = map[string]bool{
+	domain.WorkflowFieldSeverity:    true,
+	domain.WorkflowFieldReason:      true,
+	domain.WorkflowFieldDuplicateOf: true,
+}
+
+type reportWorkflowService struct {
+	repo        domain.ReportWorkflowRepository
+	projectRepo domain.ProjectRepository
+}
+
+// NewReportWorkflowService 创建工作流服务实例
+func NewReportWorkflowService(repo domain.ReportWorkflowRepository, projectRepo domain.ProjectRepository) domain.ReportWorkflowService {
+	return &reportWorkflowService{
+		repo:        repo,
+		projectRepo: projectRepo,
+	}
+}
+
+// GetWorkflow 获取项目生效的工作流
+// 查找顺序：项目自定义 -> 全局配置(project_id=0) -> 内置默认
+func (s *reportWorkflowService) GetWorkflow(projectID uint) (*domain.ReportWorkflow, error) {
+	if projectID != 0 {
+		workflow, err := s.repo.FindByProjectID(projectID)
+		if err != nil {
+			return nil, err
+		}
+		if workflow != nil {
+			return workflow, nil
+		}
+	}
+
+	workflow, err := s.repo.FindByProjectID(0)
+	if err != nil {
+		return nil, err
+	}
+	if workflow != nil {
+		return workflow, nil
+	}
+
+	return domain.DefaultReportWorkflow(), nil
+}
+
+// SaveWorkflow 保存项目工作流（projectID 为 0 时保存全局配置）
+func (s *reportWorkflowService) SaveWorkflow(projectID uint, workflow *domain.ReportWorkflow) (*domain.ReportWorkflow, error) {
+	if projectID != 0 {
+		if _, err := s.projectRepo.FindByID(projectID); err != nil {
+			return nil, errors.New("项目不存在")
+		}
+	}
+
+	if err := validateWorkflow(workflow); err != nil {
+		return nil, err
+	}
+
+	// 已存在则覆盖
+	existing, err := s.repo.FindByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		workflow.ID = existing.ID
+		workflow.CreatedAt = existing.CreatedAt
+	} else {
+		workflow.ID = 0
+	}
+	workflow.ProjectID = projectID
+
+	if err := s.repo.Save(workflow); err != nil {
+		return nil, err
+	}
+	return workflow, nil
+}
+
+// ResetWorkflow 删除自定义工作流，恢复为上一级配置
+func (s *reportWorkflowService) ResetWorkflow(projectID uint) error {
+	return s.repo.DeleteByProjectID(projectID)
+}
+
+// ValidateTransition 校验报告状态流转
+func (s *reportWorkflowService) ValidateTransition(report *domain.Report, to string, role string, input *domain.ReportUpdateInput) error {
+	workflow, err := s.GetWorkflow(report.ProjectID)
+	if err != nil {
+		return errors.New("获取工作流失败: " + err.Error())
+	}
+
+	from := workflow.FindState(report.Status)
+	if from == nil {
+		return fmt.Errorf("当前状态 %s 不在工作流中", report.Status)
+	}
+	if from.Terminal {
+		return fmt.Errorf("报告已处于终态 %s，不能再变更状态", report.Status)
+	}
+	if workflow.FindState(to) == nil {
+		return fmt.Errorf("无效的状态: %s", to)
+	}
+
+	transition := workflow.FindTransition(report.Status, to)
+	if transition == nil {
+		return fmt.Errorf("不允许从 %s 流转到 %s", report.Status, to)
+	}
+	if !transition.AllowsRole(role) {
+		return fmt.Errorf("当前角色无权将报告从 %s 流转到 %s", report.Status, to)
+	}
+
+	// 校验进入目标状态时的必填字段
+	for _, field := range transition.RequiredFields {
+		switch field {
+		case domain.WorkflowFieldSeverity:
+			if report.Severity == "" {
+				return fmt.Errorf("流转到 %s 时必须设置危害等级", to)
+			}
+		case domain.WorkflowFieldReason:
+			if input == nil || input.Reason == "" {
+				return fmt.Errorf("流转到 %s 时必须填写原因", to)
+			}
+		case domain.WorkflowFieldDuplicateOf:
+			if report.DuplicateOfID == nil {
+				return fmt.Errorf("流转到 %s 时必须指定原始报告，请使用标记重复操作", to)
+			}
+		}
+	}
+
+	return nil
+}
+
+// AvailableTransitions 获取当前角色对报告可执行的流转
+func (s *reportWorkflowService) AvailableTransitions(report *domain.Report, role string) ([]domain.WorkflowTransition, error) {
+	workflow, err := s.GetWorkflow(report.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.WorkflowTransition, 0)
+	from := workflow.FindState(report.Status)
+	if from == nil || from.Terminal {
+		return result, nil
+	}
+
+	for _, t := range workflow.Transitions {
+		if t.From == report.Status && t.AllowsRole(role) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// validateWorkflow 校验工作流定义的完整性
+func validateWorkflow(workflow *domain.ReportWorkflow) error {
+	if len(workflow.States) == 0 {
+		return errors.New("工作流至少需要一个状态")
+	}
+
+	states := make(map[string]domain.WorkflowState, len(workflow.States))
+	for _, state := range workflow.States {
+		if state.Status == "" {
+			return errors.New("状态值不能为空")
+		}
+		if len(state.Status) > 20 {
+			return fmt.Errorf("状态值 %s 过长（最多20个字符）", state.Status)
+		}
+		if _, ok := states[state.Status]; ok {
+			return fmt.Errorf("状态 %s 重复定义", state.Status)
+		}
+		states[state.Status] = state
+	}
+
+	initial, ok := states[workflow.InitialStatus]
+	if !ok {
+		return fmt.Errorf("初始状态 %s 未在状态列表中定义", workflow.InitialStatus)
+	}
+	if initial.Terminal {
+		return errors.New("初始状态不能是终态")
+	}
+
+	seen := make(map[string]bool, len(workflow.Transitions))
+	for _, t := range workflow.Transitions {
+		from, ok := states[t.From]
+		if !ok {
+			return fmt.Errorf("流转规则的源状态 %s 未定义", t.From)
+		}
+		if from.Terminal {
+			return fmt.Errorf("终态 %s 不能作为流转源状态", t.From)
+		}
+		if _, ok := states[t.To]; !ok {
+			return fmt.Errorf("流转规则的目标状态 %s 未定义", t.To)
+		}
+		if t.From == t.To {
+			return fmt.Errorf("流转规则 %s -> %s 的源状态和目标状态不能相同", t.From, t.To)
+		}
+		key := t.From + "->" + t.To
+		if seen[key] {
+			return fmt.Errorf("流转规则 %s -> %s 重复定义", t.From, t.To)
+		}
+		seen[key] = true
+
+		if len(t.Roles) == 0 {
+			return fmt.Errorf("流转规则 %s -> %s 至少需要一个角色", t.From, t.To)
+		}
+		for _, role := range t.Roles {
+			if !validWorkflowRoles[role] {
+				return fmt.Errorf("无效的角色: %s", role)
+			}
+		}
+		for _, field := range t.RequiredFields {
+			if !validWorkflowFields[field] {
+				return fmt.Errorf("无效的必填字段: %s", field)
+			}
+		}
+	}
+
+	return nil
+}
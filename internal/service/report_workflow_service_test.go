@@ -0,0 +1,208 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"testing"
+)
+
+// fakeWorkflowRepo 按项目ID保存工作流
+type fakeWorkflowRepo struct {
+	workflows map[uint]*domain.ReportWorkflow
+}
+
+func (r *fakeWorkflowRepo) FindByProjectID(projectID uint) (*domain.ReportWorkflow, error) {
+	return r.workflows[projectID], nil
+}
+
+func (r *fakeWorkflowRepo) Save(workflow *domain.ReportWorkflow) error {
+	r.workflows[workflow.ProjectID] = workflow
+	return nil
+}
+
+func (r *fakeWorkflowRepo) DeleteByProjectID(projectID uint) error {
+	delete(r.workflows, projectID)
+	return nil
+}
+
+func TestValidateWorkflow(t *testing.T) {
+	staff := []string{"admin"}
+	states := []domain.WorkflowState{
+		{Status: "Open"},
+		{Status: "Fixed"},
+		{Status: "Closed", Terminal: true},
+	}
+
+	tests := []struct {
+		name     string
+		workflow domain.ReportWorkflow
+		wantErr  bool
+	}{
+		{
+			name:     "内置默认工作流",
+			workflow: *domain.DefaultReportWorkflow(),
+		},
+		{
+			name: "最小工作流",
+			workflow: domain.ReportWorkflow{InitialStatus: "Open", States: states, Transitions: []domain.WorkflowTransition{
+				{From: "Open", To: "Closed", Roles: staff, RequiredFields: []string{domain.WorkflowFieldReason}},
+			}},
+		},
+		{name: "没有状态", workflow: domain.ReportWorkflow{InitialStatus: "Open"}, wantErr: true},
+		{name: "状态值为空", workflow: domain.ReportWorkflow{InitialStatus: "Open", States: []domain.WorkflowState{{Status: "Open"}, {Status: ""}}}, wantErr: true},
+		{name: "状态值超过列宽", workflow: domain.ReportWorkflow{InitialStatus: "Open", States: []domain.WorkflowState{{Status: "Open"}, {Status: "AwaitingVendorResponse"}}}, wantErr: true},
+		{name: "状态重复", workflow: domain.ReportWorkflow{InitialStatus: "Open", States: []domain.WorkflowState{{Status: "Open"}, {Status: "Open"}}}, wantErr: true},
+		{name: "初始状态未定义", workflow: domain.ReportWorkflow{InitialStatus: "New", States: states}, wantErr: true},
+		{name: "初始状态为终态", workflow: domain.ReportWorkflow{InitialStatus: "Closed", States: states}, wantErr: true},
+		{
+			name: "源状态未定义",
+			workflow: domain.ReportWorkflow{InitialStatus: "Open", States: states, Transitions: []domain.WorkflowTransition{
+				{From: "New", To: "Closed", Roles: staff},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "终态作为源状态",
+			workflow: domain.ReportWorkflow{InitialStatus: "Open", States: states, Transitions: []domain.WorkflowTransition{
+				{From: "Closed", To: "Open", Roles: staff},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "目标状态未定义",
+			workflow: domain.ReportWorkflow{InitialStatus: "Open", States: states, Transitions: []domain.WorkflowTransition{
+				{From: "Open", To: "Reopened", Roles: staff},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "流转到自身",
+			workflow: domain.ReportWorkflow{InitialStatus: "Open", States: states, Transitions: []domain.WorkflowTransition{
+				{From: "Open", To: "Open", Roles: staff},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "流转规则重复",
+			workflow: domain.ReportWorkflow{InitialStatus: "Open", States: states, Transitions: []domain.WorkflowTransition{
+				{From: "Open", To: "Fixed", Roles: staff},
+				{From: "Open", To: "Fixed", Roles: []string{"vendor"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "没有角色",
+			workflow: domain.ReportWorkflow{InitialStatus: "Open", States: states, Transitions: []domain.WorkflowTransition{
+				{From: "Open", To: "Fixed"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "未知角色",
+			workflow: domain.ReportWorkflow{InitialStatus: "Open", States: states, Transitions: []domain.WorkflowTransition{
+				{From: "Open", To: "Fixed", Roles: []string{"guest"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "未知必填字段",
+			workflow: domain.ReportWorkflow{InitialStatus: "Open", States: states, Transitions: []domain.WorkflowTransition{
+				{From: "Open", To: "Fixed", Roles: staff, RequiredFields: []string{"cvss"}},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWorkflow(&tt.workflow)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateWorkflow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTransition(t *testing.T) {
+	// 项目 2 使用自定义工作流，其余项目回退到内置默认工作流
+	repo := &fakeWorkflowRepo{workflows: map[uint]*domain.ReportWorkflow{
+		2: {
+			ProjectID:     2,
+			InitialStatus: "Open",
+			States:        []domain.WorkflowState{{Status: "Open"}, {Status: "Fixed", Terminal: true}},
+			Transitions:   []domain.WorkflowTransition{{From: "Open", To: "Fixed", Roles: []string{"whitehat"}}},
+		},
+	}}
+	service := NewReportWorkflowService(repo, nil)
+	withReason := &domain.ReportUpdateInput{Reason: "无法复现"}
+	original := uint(5)
+
+	tests := []struct {
+		name    string
+		report  domain.Report
+		to      string
+		role    string
+		input   *domain.ReportUpdateInput
+		wantErr bool
+	}{
+		{name: "确认报告", report: domain.Report{Status: domain.ReportStatusPending, Severity: "High"}, to: domain.ReportStatusTriaged, role: "vendor"},
+		{name: "确认报告未设置危害等级", report: domain.Report{Status: domain.ReportStatusPending}, to: domain.ReportStatusTriaged, role: "admin", wantErr: true},
+		{name: "驳回报告", report: domain.Report{Status: domain.ReportStatusPending}, to: domain.ReportStatusRejected, role: "admin", input: withReason},
+		{name: "驳回报告未填写原因", report: domain.Report{Status: domain.ReportStatusPending}, to: domain.ReportStatusRejected, role: "admin", input: &domain.ReportUpdateInput{}, wantErr: true},
+		{name: "驳回报告没有输入", report: domain.Report{Status: domain.ReportStatusPending}, to: domain.ReportStatusRejected, role: "admin", wantErr: true},
+		{name: "标记重复", report: domain.Report{Status: domain.ReportStatusTriaged, DuplicateOfID: &original}, to: domain.ReportStatusDuplicate, role: "admin"},
+		{name: "标记重复未指定原始报告", report: domain.Report{Status: domain.ReportStatusPending}, to: domain.ReportStatusDuplicate, role: "admin", input: withReason, wantErr: true},
+		{name: "白帽重新提交", report: domain.Report{Status: domain.ReportStatusNeedsMoreInfo}, to: domain.ReportStatusPending, role: "whitehat"},
+		{name: "白帽不能确认报告", report: domain.Report{Status: domain.ReportStatusPending, Severity: "High"}, to: domain.ReportStatusTriaged, role: "whitehat", wantErr: true},
+		{name: "未定义的流转", report: domain.Report{Status: domain.ReportStatusPending}, to: domain.ReportStatusResolved, role: "admin", wantErr: true},
+		{name: "终态不能流转", report: domain.Report{Status: domain.ReportStatusClosed}, to: domain.ReportStatusPending, role: "admin", wantErr: true},
+		{name: "目标状态不存在", report: domain.Report{Status: domain.ReportStatusPending}, to: "Archived", role: "admin", wantErr: true},
+		{name: "当前状态不在工作流中", report: domain.Report{Status: "Archived"}, to: domain.ReportStatusClosed, role: "admin", wantErr: true},
+		{name: "项目自定义工作流", report: domain.Report{ProjectID: 2, Status: "Open"}, to: "Fixed", role: "whitehat"},
+		{name: "项目自定义工作流不含默认状态", report: domain.Report{ProjectID: 2, Status: "Open"}, to: domain.ReportStatusClosed, role: "admin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ValidateTransition(&tt.report, tt.to, tt.role, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTransition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReportWorkflowAuthorEditable(t *testing.T) {
+	custom := &domain.ReportWorkflow{
+		InitialStatus: "Open",
+		States:        []domain.WorkflowState{{Status: "Open"}, {Status: "Review"}, {Status: "Fixed", Terminal: true}},
+		Transitions: []domain.WorkflowTransition{
+			{From: "Open", To: "Review", Roles: []string{"admin"}},
+			{From: "Review", To: "Fixed", Roles: []string{"admin", "vendor"}},
+		},
+	}
+
+	tests := []struct {
+		workflow *domain.ReportWorkflow
+		status   string
+		want     bool
+	}{
+		{domain.DefaultReportWorkflow(), domain.ReportStatusPending, true},
+		// 白帽子可以从“需补充信息”重新提交，仍由作者编辑
+		{domain.DefaultReportWorkflow(), domain.ReportStatusNeedsMoreInfo, true},
+		{domain.DefaultReportWorkflow(), domain.ReportStatusTriaged, false},
+		{domain.DefaultReportWorkflow(), domain.ReportStatusResolved, false},
+		{domain.DefaultReportWorkflow(), domain.ReportStatusClosed, false},
+		{domain.DefaultReportWorkflow(), "Unknown", false},
+		{custom, "Open", true},
+		{custom, "Review", false},
+		{custom, "Fixed", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.workflow.InitialStatus+"/"+tt.status, func(t *testing.T) {
+			if got := tt.workflow.AuthorEditable(tt.status); got != tt.want {
+				t.Errorf("AuthorEditable(%s) = %v, want %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,533 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/config"
+	"bug-bounty-lite/pkg/totp"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// defaultTwoFactorIssuer 未配置 auth.two_factor.issuer 时验证器 App 中显示的名称
+	defaultTwoFactorIssuer = "Bug Bounty Lite"
+	// defaultChallengeTTL 未配置 auth.two_factor.challenge_ttl 时登录挑战的有效期
+	defaultChallengeTTL = 5 * time.Minute
+	// maxChallengeAttempts 每个登录挑战允许提交验证码的次数
+	maxChallengeAttempts = 5
+	// recoveryCodeCount 每次生成的恢复码数量
+	recoveryCodeCount = 10
+	// recoveryCodeAlphabet 恢复码字符集（去掉了易混淆的 0/o、1/l/i）
+	recoveryCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+)
+
+// twoFactorRoles 可以要求启用两步验证的角色
+var twoFactorRoles = map[string]bool{"whitehat": true, "vendor": true, "admin": true}
+
+type twoFactorService struct {
+	repo         domain.TwoFactorRepository
+	userRepo     domain.UserRepository
+	tokens       domain.AuthTokenService
+	guard        domain.LoginGuardService
+	issuer       string
+	challengeTTL time.Duration
+	// defaultRoles 管理员未在运行时设置时使用配置文件中的角色列表
+	defaultRoles []string
+}
+
+// NewTwoFactorService 创建两步验证服务实例
+func NewTwoFactorService(repo domain.TwoFactorRepository, userRepo domain.UserRepository, tokens domain.AuthTokenService, guard domain.LoginGuardService, cfg config.TwoFactorConfig) domain.TwoFactorService {
+	issuer := cfg.Issuer
+	if issuer == "" {
+		issuer = defaultTwoFactorIssuer
+	}
+	challengeTTL := time.Duration(cfg.ChallengeTTL) * time.Second
+	if challengeTTL <= 0 {
+		challengeTTL = defaultChallengeTTL
+	}
+	return &twoFactorService{
+		repo:         repo,
+		userRepo:     userRepo,
+		tokens:       tokens,
+		guard:        guard,
+		issuer:       issuer,
+		challengeTTL: challengeTTL,
+		defaultRoles: cfg.RequiredRoles,
+	}
+}
+
+// LoginChallenge 已启用两步验证或角色要求两步验证时创建登录挑战，否则返回 nil
+func (s *twoFactorService) LoginChallenge(user *domain.User) (*domain.LoginResult, error) {
+	tf, err := s.repo.FindByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	purpose := ""
+	if tf != nil && tf.Enabled {
+		purpose = domain.ChallengeTwoFactorVerify
+	} else {
+		required, err := s.isRequired(user.Role)
+		if err != nil {
+			return nil, err
+		}
+		if required {
+			purpose = domain.ChallengeTwoFactorSetup
+		}
+	}
+	if purpose == "" {
+		return nil, nil
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	if err := s.repo.CreateChallenge(&domain.LoginChallenge{
+		ID:        hashToken(token),
+		UserID:    user.ID,
+		Purpose:   purpose,
+		ExpiresAt: time.Now().Add(s.challengeTTL),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginResult{
+		User:                   user,
+		ChallengeToken:         token,
+		ChallengeExpiresIn:     int(s.challengeTTL.Seconds()),
+		TwoFactorRequired:      purpose == domain.ChallengeTwoFactorVerify,
+		TwoFactorSetupRequired: purpose == domain.ChallengeTwoFactorSetup,
+	}, nil
+}
+
+// VerifyLogin 登录第二步：校验 TOTP 验证码或恢复码，通过后签发令牌
+func (s *twoFactorService) VerifyLogin(challengeToken, code string, client domain.ClientInfo) (*domain.User, *domain.TokenPair, error) {
+	challenge, err := s.loadChallenge(challengeToken, domain.ChallengeTwoFactorVerify)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.consumeAttempt(challenge); err != nil {
+		return nil, nil, err
+	}
+
+	tf, err := s.repo.FindByUserID(challenge.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tf == nil || !tf.Enabled {
+		// 挑战创建后管理员重置了两步验证，需重新登录
+		_ = s.repo.DeleteChallenge(challenge.ID)
+		return nil, nil, domain.ErrLoginChallengeInvalid
+	}
+
+	if err := s.verifyCode(tf, code, true); err != nil {
+		if errors.Is(err, domain.ErrTwoFactorCodeInvalid) {
+			// 密码已泄露时攻击者只能猜验证码，错误同样计入用户名和 IP 的失败次数
+			s.recordFailure(challenge.UserID, client.IP)
+		}
+		return nil, nil, err
+	}
+
+	_ = s.repo.DeleteChallenge(challenge.ID)
+	return s.completeLogin(challenge.UserID, client)
+}
+
+// SetupWithChallenge 角色要求两步验证但尚未启用时，使用挑战令牌获取绑定密钥
+func (s *twoFactorService) SetupWithChallenge(challengeToken string) (*domain.TwoFactorSetup, error) {
+	challenge, err := s.loadChallenge(challengeToken, domain.ChallengeTwoFactorSetup)
+	if err != nil {
+		return nil, err
+	}
+	return s.Setup(challenge.UserID)
+}
+
+// EnableWithChallenge 使用挑战令牌确认绑定，返回恢复码并签发令牌
+func (s *twoFactorService) EnableWithChallenge(challengeToken, code string, client domain.ClientInfo) (*domain.User, *domain.TokenPair, []string, error) {
+	challenge, err := s.loadChallenge(challengeToken, domain.ChallengeTwoFactorSetup)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := s.consumeAttempt(challenge); err != nil {
+		return nil, nil, nil, err
+	}
+
+	codes, err := s.Enable(challenge.UserID, code)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	_ = s.repo.DeleteChallenge(challenge.ID)
+	user, tokens, err := s.completeLogin(challenge.UserID, client)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return user, tokens, codes, nil
+}
+
+// GetStatus 获取两步验证状态
+func (s *twoFactorService) GetStatus(userID uint, role string) (*domain.TwoFactorStatus, error) {
+	required, err := s.isRequired(role)
+	if err != nil {
+		return nil, err
+	}
+	status := &domain.TwoFactorStatus{Required: required}
+
+	tf, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if tf == nil || !tf.Enabled {
+		return status, nil
+	}
+
+	remaining, err := s.repo.CountRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	status.Enabled = true
+	status.EnabledAt = tf.EnabledAt
+	status.RecoveryCodesRemaining = int(remaining)
+	return status, nil
+}
+
+// Setup 生成新的密钥（未启用状态），重复调用会替换尚未确认的密钥
+func (s *twoFactorService) Setup(userID uint) (*domain.TwoFactorSetup, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	tf, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if tf != nil && tf.Enabled {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if tf == nil {
+		tf = &domain.UserTwoFactor{UserID: userID}
+	}
+	tf.Secret = secret
+	tf.LastUsedStep = 0
+	if err := s.repo.Save(tf); err != nil {
+		return nil, err
+	}
+
+	return &domain.TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.issuer, user.Username, secret),
+	}, nil
+}
+
+// Enable 校验验证码后启用两步验证，返回一次性展示的恢复码
+func (s *twoFactorService) Enable(userID uint, code string) ([]string, error) {
+	tf, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if tf == nil {
+		return nil, domain.ErrTwoFactorSetupRequired
+	}
+	if tf.Enabled {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := totp.Validate(tf.Secret, code, time.Now())
+	if !ok {
+		return nil, domain.ErrTwoFactorCodeInvalid
+	}
+
+	now := time.Now()
+	tf.Enabled = true
+	tf.EnabledAt = &now
+	tf.LastUsedStep = step
+	if err := s.repo.Save(tf); err != nil {
+		return nil, err
+	}
+	return s.replaceRecoveryCodes(userID)
+}
+
+// Disable 校验密码和验证码（或恢复码）后关闭两步验证，角色要求两步验证时不允许关闭
+func (s *twoFactorService) Disable(userID uint, role, password, code string) error {
+	required, err := s.isRequired(role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return domain.ErrTwoFactorRequiredByRole
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		return errors.New("user not found")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return errors.New("当前密码不正确")
+	}
+
+	tf, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if tf == nil || !tf.Enabled {
+		return domain.ErrTwoFactorNotEnabled
+	}
+	if err := s.verifyCode(tf, code, true); err != nil {
+		return err
+	}
+	return s.repo.Delete(userID)
+}
+
+// RegenerateRecoveryCodes 校验验证码后重新生成恢复码（旧恢复码全部失效）
+func (s *twoFactorService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	tf, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if tf == nil || !tf.Enabled {
+		return nil, domain.ErrTwoFactorNotEnabled
+	}
+	if err := s.verifyCode(tf, code, false); err != nil {
+		return nil, err
+	}
+	return s.replaceRecoveryCodes(userID)
+}
+
+// Reset 管理员重置用户的两步验证，用户下次登录时按角色要求重新绑定
+func (s *twoFactorService) Reset(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		return errors.New("user not found")
+	}
+	return s.repo.Delete(userID)
+}
+
+// GetRequiredRoles 获取必须启用两步验证的角色，管理员未设置时使用配置文件中的值
+func (s *twoFactorService) GetRequiredRoles() ([]string, error) {
+	setting, err := s.repo.GetSetting(domain.SettingTwoFactorRequiredRoles)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil {
+		roles := append([]string{}, s.defaultRoles...)
+		return roles, nil
+	}
+
+	var roles []string
+	if err := json.Unmarshal([]byte(setting.Value), &roles); err != nil {
+		return nil, fmt.Errorf("invalid %s setting: %w", domain.SettingTwoFactorRequiredRoles, err)
+	}
+	return roles, nil
+}
+
+// SetRequiredRoles 设置必须启用两步验证的角色（whitehat/vendor/admin）
+func (s *twoFactorService) SetRequiredRoles(roles []string, adminID uint) ([]string, error) {
+	normalized := make([]string, 0, len(roles))
+	seen := make(map[string]bool)
+	for _, role := range roles {
+		role = strings.TrimSpace(role)
+		if !twoFactorRoles[role] {
+			return nil, fmt.Errorf("无效的角色: %s", role)
+		}
+		if !seen[role] {
+			seen[role] = true
+			normalized = append(normalized, role)
+		}
+	}
+
+	value, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveSetting(&domain.SecuritySetting{
+		Key:       domain.SettingTwoFactorRequiredRoles,
+		Value:     string(value),
+		UpdatedBy: adminID,
+	}); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// CleanupExpired 清理过期的登录挑战
+func (s *twoFactorService) CleanupExpired() (int64, error) {
+	return s.repo.DeleteExpiredChallenges(time.Now())
+}
+
+// isRequired 角色是否必须启用两步验证
+func (s *twoFactorService) isRequired(role string) (bool, error) {
+	roles, err := s.GetRequiredRoles()
+	if err != nil {
+		return false, err
+	}
+	for _, r := range roles {
+		if r == role {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// loadChallenge 查找并校验登录挑战（存在、未过期、用途一致、未用完提交次数）
+func (s *twoFactorService) loadChallenge(token, purpose string) (*domain.LoginChallenge, error) {
+	if token == "" {
+		return nil, domain.ErrLoginChallengeInvalid
+	}
+	challenge, err := s.repo.FindChallenge(hashToken(token))
+	if err != nil || challenge.Purpose != purpose {
+		return nil, domain.ErrLoginChallengeInvalid
+	}
+	if time.Now().After(challenge.ExpiresAt) {
+		_ = s.repo.DeleteChallenge(challenge.ID)
+		return nil, domain.ErrLoginChallengeInvalid
+	}
+	if challenge.Attempts >= maxChallengeAttempts {
+		_ = s.repo.DeleteChallenge(challenge.ID)
+		return nil, domain.ErrLoginChallengeTooManyAttempts
+	}
+	return challenge, nil
+}
+
+// consumeAttempt 校验验证码前先占用一次提交次数，条件更新保证并发请求不会超过上限
+func (s *twoFactorService) consumeAttempt(challenge *domain.LoginChallenge) error {
+	consumed, err := s.repo.ConsumeChallengeAttempt(challenge.ID, maxChallengeAttempts)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		_ = s.repo.DeleteChallenge(challenge.ID)
+		return domain.ErrLoginChallengeTooManyAttempts
+	}
+	return nil
+}
+
+// verifyCode 校验 6 位 TOTP 验证码（同一时间步只接受一次），allowRecovery 为 true 时也接受恢复码
+func (s *twoFactorService) verifyCode(tf *domain.UserTwoFactor, code string, allowRecovery bool) error {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(tf.Secret, code, time.Now())
+		if !ok {
+			return domain.ErrTwoFactorCodeInvalid
+		}
+		used, err := s.repo.UseStep(tf.UserID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return domain.ErrTwoFactorCodeInvalid
+		}
+		return nil
+	}
+
+	if !allowRecovery {
+		return domain.ErrTwoFactorCodeInvalid
+	}
+	used, err := s.repo.UseRecoveryCode(tf.UserID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return domain.ErrTwoFactorCodeInvalid
+	}
+	log.Printf("[INFO] User %d signed in with a recovery code", tf.UserID)
+	return nil
+}
+
+// completeLogin 第二步验证通过后清除失败计数、签发令牌并更新最后登录时间
+func (s *twoFactorService) completeLogin(userID uint, client domain.ClientInfo) (*domain.User, *domain.TokenPair, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		return nil, nil, domain.ErrLoginChallengeInvalid
+	}
+	s.guard.RecordSuccess(user.Username)
+
+	tokens, err := s.tokens.IssueTokens(user, client)
+	if err != nil {
+		return nil, nil, errors.New("failed to generate token")
+	}
+
+	now := time.Now()
+	user.LastLoginAt = &now
+	_ = s.userRepo.UpdateLastLoginAt(user.ID, now)
+	return user, tokens, nil
+}
+
+// recordFailure 第二步验证失败时按用户名和 IP 记录一次登录失败
+func (s *twoFactorService) recordFailure(userID uint, ip string) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		return
+	}
+	s.guard.RecordFailure(user.Username, ip, user.ID)
+}
+
+// replaceRecoveryCodes 生成新的恢复码，只保存哈希，明文只返回这一次
+func (s *twoFactorService) replaceRecoveryCodes(userID uint) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	records := make([]domain.RecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		records[i] = domain.RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)}
+	}
+	if err := s.repo.ReplaceRecoveryCodes(userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// newRecoveryCode 生成形如 "k7m2p-x9q4r" 的随机恢复码
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for i, b := range buf {
+		if i == 5 {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte(recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)])
+	}
+	return sb.String(), nil
+}
+
+// hashRecoveryCode 忽略大小写、空格和连字符后计算恢复码的哈希
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	return hashToken(code)
+}
+
+// StartTwoFactorCleanup 启动后台定时清理过期的登录挑战
+func StartTwoFactorCleanup(s domain.TwoFactorService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if count, err := s.CleanupExpired(); err != nil {
+				log.Printf("[WARN] Failed to clean up expired login challenges: %v", err)
+			} else if count > 0 {
+				log.Printf("[INFO] Cleaned up %d expired login challenge(s)", count)
+			}
+		}
+	}()
+}
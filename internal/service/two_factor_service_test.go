@@ -0,0 +1,130 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/config"
+	"bug-bounty-lite/pkg/lockout"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTwoFactorRepo 内存中的登录挑战和恢复码，加锁模拟数据库的行级原子更新
+type fakeTwoFactorRepo struct {
+	domain.TwoFactorRepository
+	mu         sync.Mutex
+	challenges map[string]*domain.LoginChallenge
+	lastStep   int64
+	// guesses 实际校验过的恢复码次数
+	guesses int
+}
+
+func (r *fakeTwoFactorRepo) CreateChallenge(challenge *domain.LoginChallenge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *challenge
+	r.challenges[challenge.ID] = &copied
+	return nil
+}
+
+func (r *fakeTwoFactorRepo) FindChallenge(id string) (*domain.LoginChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	challenge, ok := r.challenges[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	copied := *challenge
+	return &copied, nil
+}
+
+func (r *fakeTwoFactorRepo) ConsumeChallengeAttempt(id string, maxAttempts int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	challenge, ok := r.challenges[id]
+	if !ok || challenge.Attempts >= maxAttempts {
+		return false, nil
+	}
+	challenge.Attempts++
+	return true, nil
+}
+
+func (r *fakeTwoFactorRepo) DeleteChallenge(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.challenges, id)
+	return nil
+}
+
+func (r *fakeTwoFactorRepo) FindByUserID(userID uint) (*domain.UserTwoFactor, error) {
+	return &domain.UserTwoFactor{UserID: userID, Secret: "JBSWY3DPEHPK3PXP", Enabled: true}, nil
+}
+
+func (r *fakeTwoFactorRepo) UseStep(userID uint, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if step <= r.lastStep {
+		return false, nil
+	}
+	r.lastStep = step
+	return true, nil
+}
+
+func (r *fakeTwoFactorRepo) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	// 放大校验耗时，让并发请求在读取挑战和校验之间交错
+	time.Sleep(5 * time.Millisecond)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.guesses++
+	return false, nil
+}
+
+func TestTwoFactorVerifyLoginConcurrentAttempts(t *testing.T) {
+	const token = "challenge-token"
+	repo := &fakeTwoFactorRepo{challenges: map[string]*domain.LoginChallenge{
+		hashToken(token): {
+			ID:        hashToken(token),
+			UserID:    1,
+			Purpose:   domain.ChallengeTwoFactorVerify,
+			ExpiresAt: time.Now().Add(time.Minute),
+		},
+	}}
+	guard := NewLoginGuardService(lockout.NewMemoryStore(), &fakeSecurityEventRepo{}, config.LockoutConfig{MaxAttempts: 100})
+	s := &twoFactorService{repo: repo, userRepo: &fakeUserRepo{users: map[uint]*domain.User{1: {ID: 1, Username: "alice"}}}, guard: guard}
+
+	// 同一挑战令牌并发提交错误的恢复码
+	const requests = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	start := make(chan struct{})
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, _, err := s.VerifyLogin(token, "wrong-recovery-code", domain.ClientInfo{})
+			errs <- err
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	invalid := 0
+	for err := range errs {
+		switch {
+		case errors.Is(err, domain.ErrTwoFactorCodeInvalid):
+			invalid++
+		case errors.Is(err, domain.ErrLoginChallengeTooManyAttempts), errors.Is(err, domain.ErrLoginChallengeInvalid):
+		default:
+			t.Errorf("VerifyLogin() error = %v", err)
+		}
+	}
+	if repo.guesses != maxChallengeAttempts || invalid != maxChallengeAttempts {
+		t.Errorf("校验了 %d 次验证码（%d 次返回验证码错误），want %d", repo.guesses, invalid, maxChallengeAttempts)
+	}
+	if _, ok := repo.challenges[hashToken(token)]; ok {
+		t.Error("用完提交次数后挑战应被删除")
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/config"
+	"bug-bounty-lite/pkg/upload"
+	"context"
+	"errors"
+	"log"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultUploadGCRetention 默认保留期：7 天内的文件即使未被引用也不回收（可能刚上传还未提交报告）
+	defaultUploadGCRetention = 7 * 24 * time.Hour
+	// minUploadGCRetention 最短保留期，必须比复用文件刷新修改时间的间隔多 1 天，
+	// 保证刚被再次上传的旧文件在刷新修改时间后仍有足够时间被引用
+	minUploadGCRetention = upload.DedupRefreshAge + 24*time.Hour
+)
+
+// embeddedFileURL 正文中内嵌的上传文件地址（只取路径部分，紧跟的标点和查询参数不计入）
+var embeddedFileURL = regexp.MustCompile(`(?:` + regexp.QuoteMeta(upload.FileRoutePrefix) + `|/uploads/)[A-Za-z0-9._~%/-]+`)
+
+// gcSkipDirs 不参与回收的目录（隔离的感染文件和已回收的孤儿文件由管理员处理）
+var gcSkipDirs = map[string]bool{
+	upload.QuarantineDir: true,
+	upload.OrphanDir:     true,
+}
+
+type uploadGCService struct {
+	refRepo        domain.UploadReferenceRepository
+	uploadSessions domain.UploadSessionService
+	storage        upload.Storage
+}
+
+// NewUploadGCService 创建孤儿上传文件回收服务实例
+func NewUploadGCService(refRepo domain.UploadReferenceRepository, uploadSessions domain.UploadSessionService, storage upload.Storage) domain.UploadGCService {
+	return &uploadGCService{refRepo: refRepo, uploadSessions: uploadSessions, storage: storage}
+}
+
+// Run 回收孤儿文件
+// 1. 清理过期的上传会话及其分片
+// 2. 收集数据库中所有引用的文件（缩略图随原图保留）
+// 3. 遍历存储后端，未被引用且修改时间早于保留期的文件按 Action 删除或移动到 orphaned/ 目录
+func (s *uploadGCService) Run(ctx context.Context, opts domain.UploadGCOptions) (*domain.UploadGCResult, error) {
+	if opts.OlderThan <= 0 {
+		opts.OlderThan = defaultUploadGCRetention
+	}
+	if opts.Action == "" {
+		opts.Action = domain.UploadGCActionQuarantine
+	}
+	if opts.Action != domain.UploadGCActionQuarantine && opts.Action != domain.UploadGCActionDelete {
+		return nil, errors.New("不支持的处理方式: " + opts.Action)
+	}
+	if opts.OlderThan < minUploadGCRetention {
+		return nil, errors.New("保留期不能少于 2 天")
+	}
+
+	result := &domain.UploadGCResult{Orphans: []domain.OrphanFile{}}
+	cutoff := time.Now().Add(-opts.OlderThan)
+
+	if !opts.DryRun {
+		count, err := s.uploadSessions.CleanupExpired(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result.ExpiredSessions = count
+	}
+
+	referenced, sessions, err := s.collectReferences(cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storage.Walk(ctx, "", func(key string, info *upload.ObjectInfo) error {
+		dir, rest, _ := strings.Cut(key, "/")
+		if gcSkipDirs[dir] {
+			return nil
+		}
+		result.Scanned++
+
+		if info.ModTime.After(cutoff) {
+			return nil
+		}
+		if dir == upload.ChunkDir {
+			// 分片 chunks/{会话ID}/{序号}：会话仍存在时保留
+			sessionID, _, _ := strings.Cut(rest, "/")
+			if sessions[sessionID] {
+				return nil
+			}
+		} else if referenced[upload.SourceKey(key)] {
+			return nil
+		}
+
+		result.Orphans = append(result.Orphans, domain.OrphanFile{Key: key, Size: info.Size, ModTime: info.ModTime})
+		result.OrphanBytes += info.Size
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+	for _, orphan := range result.Orphans {
+		var err error
+		if opts.Action == domain.UploadGCActionDelete {
+			err = s.storage.Delete(ctx, orphan.Key)
+		} else {
+			err = upload.Move(ctx, s.storage, orphan.Key, path.Join(upload.OrphanDir, orphan.Key))
+		}
+		if err != nil {
+			log.Printf("[WARN] Failed to %s orphaned upload %s: %v", opts.Action, orphan.Key, err)
+			result.Failed++
+			continue
+		}
+		result.Removed++
+	}
+	return result, nil
+}
+
+// collectReferences 收集被引用的存储键和仍存在的上传会话
+func (s *uploadGCService) collectReferences(deletedBefore time.Time) (map[string]bool, map[string]bool, error) {
+	refs, err := s.refRepo.ListReferences(deletedBefore)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	referenced := make(map[string]bool)
+	addURL := func(fileURL string) {
+		if key, ok := upload.FilePathFromURL(fileURL); ok {
+			referenced[key] = true
+		}
+	}
+	for _, fileURL := range refs.URLs {
+		addURL(fileURL)
+	}
+	for _, text := range refs.Texts {
+		for _, fileURL := range embeddedFileURL.FindAllString(text, -1) {
+			// 句末的英文句号不属于地址
+			addURL(strings.TrimRight(fileURL, "."))
+		}
+	}
+	for _, key := range refs.Keys {
+		referenced[key] = true
+	}
+
+	sessions := make(map[string]bool, len(refs.Sessions))
+	for _, id := range refs.Sessions {
+		sessions[id] = true
+	}
+	return referenced, sessions, nil
+}
+
+// StartUploadGC 启动后台定时回收孤儿文件
+func StartUploadGC(s domain.UploadGCService, cfg config.UploadGCConfig) {
+	interval := time.Duration(cfg.Interval) * time.Hour
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	opts := domain.UploadGCOptions{
+		OlderThan: time.Duration(cfg.RetentionDays) * 24 * time.Hour,
+		Action:    cfg.Action,
+	}
+	if opts.Action == "" {
+		opts.Action = domain.UploadGCActionQuarantine
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			result, err := s.Run(context.Background(), opts)
+			if err != nil {
+				log.Printf("[WARN] Upload garbage collection failed: %v", err)
+				continue
+			}
+			if result.Removed > 0 || result.Failed > 0 {
+				log.Printf("[INFO] Upload garbage collection (%s): %d orphaned file(s), %s, %d failed",
+					opts.Action, result.Removed, upload.FormatSize(result.OrphanBytes), result.Failed)
+			}
+		}
+	}()
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/upload"
+	"context"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeUploadReferenceRepo 固定的引用列表
+type fakeUploadReferenceRepo struct {
+	refs domain.UploadReferences
+}
+
+func (r *fakeUploadReferenceRepo) ListReferences(deletedBefore time.Time) (*domain.UploadReferences, error) {
+	return &r.refs, nil
+}
+
+// fakeUploadSessionCleaner 记录过期会话清理次数
+type fakeUploadSessionCleaner struct {
+	domain.UploadSessionService
+	cleanups int
+}
+
+func (s *fakeUploadSessionCleaner) CleanupExpired(ctx context.Context) (int, error) {
+	s.cleanups++
+	return 0, nil
+}
+
+func TestUploadGCCollectReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		refs domain.UploadReferences
+		want []string
+	}{
+		{
+			name: "地址字段",
+			refs: domain.UploadReferences{URLs: []string{
+				"/api/v1/files/reports/ab/a.png",
+				"https://bbl.example.com/uploads/projects/2024/01/spec.pdf?expires=1&signature=x",
+				"https://cdn.example.com/avatar.png",
+				"/api/v1/files/../config.yaml",
+			}},
+			want: []string{"projects/2024/01/spec.pdf", "reports/ab/a.png"},
+		},
+		{
+			name: "Markdown 和 HTML 正文",
+			refs: domain.UploadReferences{Texts: []string{
+				"复现步骤：\n![截图](/api/v1/files/reports/ab/a.png)\n<img src=\"/uploads/reports/2023/12/old.jpg\">",
+				"[日志](https://bbl.example.com/api/v1/files/reports/cd/log.txt?expires=1&signature=x#L10)",
+			}},
+			want: []string{"reports/2023/12/old.jpg", "reports/ab/a.png", "reports/cd/log.txt"},
+		},
+		{
+			name: "地址后紧跟标点",
+			refs: domain.UploadReferences{Texts: []string{
+				"见附件 /api/v1/files/reports/ab/a.png，以及 /api/v1/files/reports/cd/b.pdf。",
+				"See /uploads/reports/ef/c.pdf. Also /uploads/reports/ef/d.pdf, and '/uploads/reports/ef/e.pdf'",
+			}},
+			want: []string{"reports/ab/a.png", "reports/cd/b.pdf", "reports/ef/c.pdf", "reports/ef/d.pdf", "reports/ef/e.pdf"},
+		},
+		{
+			name: "存储键",
+			refs: domain.UploadReferences{Keys: []string{"reports/ab/merged.zip"}},
+			want: []string{"reports/ab/merged.zip"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &uploadGCService{refRepo: &fakeUploadReferenceRepo{refs: tt.refs}}
+			referenced, _, err := service.collectReferences(time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0, len(referenced))
+			for key := range referenced {
+				got = append(got, key)
+			}
+			sort.Strings(got)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("referenced = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadGCRun(t *testing.T) {
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	// 文件 -> 是否为旧文件
+	files := map[string]bool{
+		"reports/ab/ref.png":              true,
+		"reports/ab/ref.png.thumb.jpg":    true, // 缩略图随原图保留
+		"reports/cd/text.pdf":             true,
+		"reports/ef/orphan.pdf":           true,
+		"reports/ef/orphan.png.thumb.jpg": true,
+		"reports/gh/recent.pdf":           false, // 保留期内
+		"reports/ij/merged.zip":           true,
+		"chunks/live/000000":              true,
+		"chunks/dead/000000":              true,
+		"quarantine/reports/virus.exe":    true, // 隔离目录不参与回收
+	}
+	refs := domain.UploadReferences{
+		URLs:     []string{"/api/v1/files/reports/ab/ref.png"},
+		Texts:    []string{"详见 /api/v1/files/reports/cd/text.pdf，谢谢"},
+		Keys:     []string{"reports/ij/merged.zip"},
+		Sessions: []string{"live"},
+	}
+	wantOrphans := []string{"chunks/dead/000000", "reports/ef/orphan.pdf", "reports/ef/orphan.png.thumb.jpg"}
+	isOrphan := make(map[string]bool, len(wantOrphans))
+	for _, key := range wantOrphans {
+		isOrphan[key] = true
+	}
+
+	tests := []struct {
+		name       string
+		opts       domain.UploadGCOptions
+		wantRemain bool // 孤儿文件是否仍在原位置
+		wantMoved  bool // 孤儿文件是否移动到 orphaned/ 目录
+		wantErr    bool
+	}{
+		{name: "只列出", opts: domain.UploadGCOptions{DryRun: true, Action: domain.UploadGCActionDelete}, wantRemain: true},
+		{name: "默认移动到孤儿目录", opts: domain.UploadGCOptions{}, wantMoved: true},
+		{name: "删除", opts: domain.UploadGCOptions{Action: domain.UploadGCActionDelete}},
+		{name: "保留期过短", opts: domain.UploadGCOptions{OlderThan: time.Hour}, wantErr: true},
+		{name: "不支持的处理方式", opts: domain.UploadGCOptions{Action: "archive"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			root := t.TempDir()
+			storage := upload.NewLocalStorage(root)
+			for key, isOld := range files {
+				if err := os.MkdirAll(filepath.Dir(filepath.Join(root, key)), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(root, key), []byte(key), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if isOld {
+					if err := os.Chtimes(filepath.Join(root, key), old, old); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			sessions := &fakeUploadSessionCleaner{}
+			service := NewUploadGCService(&fakeUploadReferenceRepo{refs: refs}, sessions, storage)
+
+			result, err := service.Run(ctx, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var orphans []string
+			for _, orphan := range result.Orphans {
+				orphans = append(orphans, orphan.Key)
+			}
+			sort.Strings(orphans)
+			if strings.Join(orphans, "\n") != strings.Join(wantOrphans, "\n") {
+				t.Errorf("Orphans = %q, want %q", orphans, wantOrphans)
+			}
+			if result.Scanned != len(files)-1 {
+				t.Errorf("Scanned = %d, want %d", result.Scanned, len(files)-1)
+			}
+			if wantCleanups := map[bool]int{true: 0, false: 1}[tt.opts.DryRun]; sessions.cleanups != wantCleanups {
+				t.Errorf("CleanupExpired called %d times, want %d", sessions.cleanups, wantCleanups)
+			}
+
+			exists := func(key string) bool {
+				_, _, err := storage.Open(ctx, key)
+				return !errors.Is(err, upload.ErrObjectNotFound)
+			}
+			for key := range files {
+				if !isOrphan[key] && !exists(key) {
+					t.Errorf("被引用或未过保留期的文件 %s 被回收", key)
+				}
+			}
+			for _, key := range wantOrphans {
+				if exists(key) != tt.wantRemain {
+					t.Errorf("孤儿文件 %s 存在 = %v, want %v", key, !tt.wantRemain, tt.wantRemain)
+				}
+				if exists(path.Join(upload.OrphanDir, key)) != tt.wantMoved {
+					t.Errorf("孤儿文件 %s 移动到孤儿目录 = %v, want %v", key, !tt.wantMoved, tt.wantMoved)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/config"
+	"bug-bounty-lite/pkg/upload"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 分片上传默认配置
+const (
+	defaultChunkedMaxSizeMB  = 500            // 未配置角色的单文件上限（MB）
+	defaultUploadSessionTTL  = 24 * time.Hour // 会话闲置过期时间
+	uploadSessionCleanupSize = 100            // 每次清理的会话数
+)
+
+type uploadSessionService struct {
+	repo       domain.UploadSessionRepository
+	storage    upload.Storage
+	maxSize    map[string]int64 // 按角色的单文件上限（字节）
+	chunkSize  int64
+	sessionTTL time.Duration
+}
+
+// NewUploadSessionService 创建分片上传服务实例
+func NewUploadSessionService(repo domain.UploadSessionRepository, storage upload.Storage, cfg config.ChunkedUploadConfig) domain.UploadSessionService {
+	maxSize := make(map[string]int64, len(cfg.MaxSize))
+	for role, sizeMB := range cfg.MaxSize {
+		maxSize[strings.ToLower(role)] = sizeMB * 1024 * 1024
+	}
+
+	chunkSize := int64(upload.DefaultChunkSize)
+	if cfg.ChunkSize > 0 {
+		chunkSize = cfg.ChunkSize * 1024 * 1024
+	}
+
+	sessionTTL := defaultUploadSessionTTL
+	if cfg.SessionTTL > 0 {
+		sessionTTL = time.Duration(cfg.SessionTTL) * time.Second
+	}
+
+	return &uploadSessionService{
+		repo:       repo,
+		storage:    storage,
+		maxSize:    maxSize,
+		chunkSize:  chunkSize,
+		sessionTTL: sessionTTL,
+	}
+}
+
+// maxSizeFor 获取角色的单文件上限
+func (s *uploadSessionService) maxSizeFor(userRole string) int64 {
+	if size, ok := s.maxSize[userRole]; ok && size > 0 {
+		return size
+	}
+	return defaultChunkedMaxSizeMB * 1024 * 1024
+}
+
+// CreateSession 创建上传会话
+func (s *uploadSessionService) CreateSession(userID uint, userRole string, filename string, totalSize int64) (*domain.UploadSession, error) {
+	filename = strings.TrimSpace(filename)
+	if filename == "" {
+		return nil, errors.New("文件名不能为空")
+	}
+	if totalSize <= 0 {
+		return nil, errors.New("文件大小必须大于0")
+	}
+	if maxSize := s.maxSizeFor(userRole); totalSize > maxSize {
+		return nil, fmt.Errorf("文件大小超过限制（最大%dMB）", maxSize/1024/1024)
+	}
+
+	id, err := newUploadSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &domain.UploadSession{
+		ID:        id,
+		UserID:    userID,
+		Filename:  filename,
+		TotalSize: totalSize,
+		ChunkSize: s.chunkSize,
+		Status:    domain.UploadSessionPending,
+		ExpiresAt: time.Now().Add(s.sessionTTL),
+	}
+	if err := s.repo.Create(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetSession 获取上传会话（仅上传人）
+func (s *uploadSessionService) GetSession(id string, userID uint) (*domain.UploadSession, error) {
+	session, err := s.repo.FindByID(id)
+	if err != nil || session.UserID != userID {
+		return nil, errors.New("上传会话不存在")
+	}
+	if session.Status == domain.UploadSessionPending && time.Now().After(session.ExpiresAt) {
+		return nil, errors.New("上传会话已过期")
+	}
+	return session, nil
+}
+
+// WriteChunk 写入分片
+// 分片必须按顺序上传：offset 等于已接收字节数，除最后一片外大小等于会话的分片大小
+func (s *uploadSessionService) WriteChunk(ctx context.Context, id string, userID uint, offset int64, body io.Reader, length int64) (*domain.UploadSession, error) {
+	session, err := s.GetSession(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != domain.UploadSessionPending {
+		return nil, errors.New("上传已完成")
+	}
+	if offset != session.ReceivedSize {
+		return session, domain.ErrUploadOffsetMismatch
+	}
+
+	// 1. 校验分片大小
+	expected := session.ChunkSize
+	if remaining := session.TotalSize - offset; remaining < expected {
+		expected = remaining
+	}
+	if length != expected {
+		return nil, fmt.Errorf("分片大小应为 %d 字节", expected)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, length+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取分片失败: %v", err)
+	}
+	if int64(len(data)) != length {
+		return nil, errors.New("分片内容与 Content-Length 不一致")
+	}
+
+	// 2. 第一个分片检测文件类型
+	if offset == 0 {
+		mimeType := http.DetectContentType(data)
+		if !upload.ChunkedMimeAllowed(mimeType) {
+			return nil, fmt.Errorf("不支持的文件类型: %s", mimeType)
+		}
+		if upload.ThumbnailMimeTypes[mimeType] && session.TotalSize > upload.MaxChunkedImageSize {
+			return nil, fmt.Errorf("图片大小超过限制（最大%dMB）", upload.MaxChunkedImageSize/1024/1024)
+		}
+		session.MimeType = mimeType
+	}
+
+	// 3. 在已接收内容的哈希状态上继续计算
+	hash := sha256.New()
+	if len(session.HashState) > 0 {
+		if err := hash.(encoding.BinaryUnmarshaler).UnmarshalBinary(session.HashState); err != nil {
+			return nil, fmt.Errorf("恢复上传进度失败: %v", err)
+		}
+	}
+	hash.Write(data)
+	state, err := hash.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 保存分片后更新进度
+	if err := s.storage.Put(ctx, upload.ChunkKey(session.ID, offset/session.ChunkSize), bytes.NewReader(data), length); err != nil {
+		return nil, fmt.Errorf("保存分片失败: %v", err)
+	}
+
+	session.ReceivedSize = offset + length
+	session.HashState = state
+	session.ExpiresAt = time.Now().Add(s.sessionTTL)
+	ok, err := s.repo.AdvanceOffset(session, offset)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// 同一分片被并发上传，以数据库中的进度为准
+		current, err := s.repo.FindByID(id)
+		if err != nil {
+			return nil, err
+		}
+		return current, domain.ErrUploadOffsetMismatch
+	}
+	return session, nil
+}
+
+// CompleteSession 合并分片
+// 文件按内容寻址保存，相同内容已存在时直接复用；合并时重新计算 SHA-256，与分片上传时的结果不一致则拒绝
+func (s *uploadSessionService) CompleteSession(ctx context.Context, id string, userID uint) (*domain.UploadSession, error) {
+	session, err := s.GetSession(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == domain.UploadSessionCompleted {
+		return session, nil
+	}
+	if session.ReceivedSize != session.TotalSize {
+		return nil, fmt.Errorf("上传未完成（已上传 %d / %d 字节）", session.ReceivedSize, session.TotalSize)
+	}
+
+	hash := sha256.New()
+	if err := hash.(encoding.BinaryUnmarshaler).UnmarshalBinary(session.HashState); err != nil {
+		return nil, fmt.Errorf("恢复上传进度失败: %v", err)
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	size := session.TotalSize
+	key := upload.ContentKey(upload.ReportDir, sum, session.MimeType)
+	thumbKey := ""
+
+	if upload.ThumbnailMimeTypes[session.MimeType] {
+		// 图片与普通上传一样去除元数据并生成缩略图，按去除元数据后的内容寻址
+		if sum, size, key, thumbKey, err = s.assembleImage(ctx, session, sum); err != nil {
+			return nil, err
+		}
+	} else if info, err := s.storage.Stat(ctx, key); errors.Is(err, upload.ErrObjectNotFound) {
+		if err := s.assemble(ctx, session, key, sum); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("保存文件失败: %v", err)
+	} else if upload.NeedsRefresh(info) {
+		if err := upload.Touch(ctx, s.storage, key); err != nil {
+			return nil, fmt.Errorf("保存文件失败: %v", err)
+		}
+	}
+
+	s.deleteChunks(ctx, session)
+
+	session.Status = domain.UploadSessionCompleted
+	session.SHA256 = sum
+	session.FileSize = size
+	session.FilePath = key
+	session.ThumbnailPath = thumbKey
+	session.HashState = nil
+	session.ExpiresAt = time.Now().Add(s.sessionTTL)
+	if err := s.repo.Update(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// assemble 按顺序读取分片写入最终文件，并校验内容哈希
+func (s *uploadSessionService) assemble(ctx context.Context, session *domain.UploadSession, key, sum string) error {
+	reader := upload.NewChunkReader(ctx, s.storage, session.ID, chunkCount(session.ReceivedSize, session.ChunkSize))
+	defer reader.Close()
+
+	hash := sha256.New()
+	if err := s.storage.Put(ctx, key, io.TeeReader(reader, hash), session.TotalSize); err != nil {
+		return fmt.Errorf("合并分片失败: %v", err)
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != sum {
+		_ = s.storage.Delete(ctx, key)
+		return errors.New("分片内容校验失败，请重新上传")
+	}
+	return nil
+}
+
+// assembleImage 读取图片分片并校验内容哈希，去除元数据后保存并生成缩略图
+// 返回去除元数据后的 SHA-256、文件大小、存储键和缩略图存储键
+func (s *uploadSessionService) assembleImage(ctx context.Context, session *domain.UploadSession, sum string) (string, int64, string, string, error) {
+	reader := upload.NewChunkReader(ctx, s.storage, session.ID, chunkCount(session.ReceivedSize, session.ChunkSize))
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", 0, "", "", fmt.Errorf("合并分片失败: %v", err)
+	}
+	if checksum := sha256.Sum256(data); hex.EncodeToString(checksum[:]) != sum {
+		return "", 0, "", "", errors.New("分片内容校验失败，请重新上传")
+	}
+
+	if upload.MetadataMimeTypes[session.MimeType] {
+		if data, err = upload.StripMetadata(data, session.MimeType); err != nil {
+			return "", 0, "", "", err
+		}
+	}
+	checksum := sha256.Sum256(data)
+	stripped := hex.EncodeToString(checksum[:])
+	key := upload.ContentKey(upload.ReportDir, stripped, session.MimeType)
+
+	if err := upload.StoreContent(ctx, s.storage, key, bytes.NewReader(data), int64(len(data))); err != nil {
+		return "", 0, "", "", err
+	}
+	thumbKey := upload.SaveThumbnail(ctx, s.storage, key, data)
+	return stripped, int64(len(data)), key, thumbKey, nil
+}
+
+// AbortSession 取消上传
+func (s *uploadSessionService) AbortSession(ctx context.Context, id string, userID uint) error {
+	session, err := s.repo.FindByID(id)
+	if err != nil || session.UserID != userID {
+		return errors.New("上传会话不存在")
+	}
+	if session.Status == domain.UploadSessionPending {
+		s.deleteChunks(ctx, session)
+	}
+	return s.repo.Delete(id)
+}
+
+// CleanupExpired 清理过期会话
+// 未完成的会话同时删除已上传的分片，已完成的会话只删除记录（合并后的文件可能已被附件引用）
+func (s *uploadSessionService) CleanupExpired(ctx context.Context) (int, error) {
+	cleaned := 0
+	for {
+		sessions, err := s.repo.ListExpired(time.Now(), uploadSessionCleanupSize)
+		if err != nil {
+			return cleaned, err
+		}
+		for i := range sessions {
+			if sessions[i].Status == domain.UploadSessionPending {
+				s.deleteChunks(ctx, &sessions[i])
+			}
+			if err := s.repo.Delete(sessions[i].ID); err != nil {
+				return cleaned, err
+			}
+			cleaned++
+		}
+		if len(sessions) < uploadSessionCleanupSize {
+			return cleaned, nil
+		}
+	}
+}
+
+// deleteChunks 删除会话已上传的分片（删除失败只记录日志，不影响主流程）
+func (s *uploadSessionService) deleteChunks(ctx context.Context, session *domain.UploadSession) {
+	for i := int64(0); i < chunkCount(session.ReceivedSize, session.ChunkSize); i++ {
+		if err := s.storage.Delete(ctx, upload.ChunkKey(session.ID, i)); err != nil {
+			log.Printf("[WARN] Failed to delete chunk %d of upload session %s: %v", i, session.ID, err)
+		}
+	}
+}
+
+// StartUploadSessionCleanup 启动后台定时清理过期的上传会话
+func StartUploadSessionCleanup(s domain.UploadSessionService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if count, err := s.CleanupExpired(context.Background()); err != nil {
+				log.Printf("[WARN] Failed to clean up expired upload sessions: %v", err)
+			} else if count > 0 {
+				log.Printf("[INFO] Cleaned up %d expired upload session(s)", count)
+			}
+		}
+	}()
+}
+
+// chunkCount 计算已接收内容对应的分片数
+func chunkCount(size, chunkSize int64) int64 {
+	if chunkSize <= 0 {
+		return 0
+	}
+	return (size + chunkSize - 1) / chunkSize
+}
+
+// newUploadSessionID 生成随机会话ID（32位十六进制）
+func newUploadSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/upload"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// fakeUploadSessionRepo 内存中的上传会话，读写时复制，模拟数据库行
+type fakeUploadSessionRepo struct {
+	domain.UploadSessionRepository
+	sessions map[string]domain.UploadSession
+}
+
+func newFakeUploadSessionRepo() *fakeUploadSessionRepo {
+	return &fakeUploadSessionRepo{sessions: make(map[string]domain.UploadSession)}
+}
+
+func (r *fakeUploadSessionRepo) Create(session *domain.UploadSession) error {
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *fakeUploadSessionRepo) FindByID(id string) (*domain.UploadSession, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &session, nil
+}
+
+func (r *fakeUploadSessionRepo) AdvanceOffset(session *domain.UploadSession, fromOffset int64) (bool, error) {
+	if r.sessions[session.ID].ReceivedSize != fromOffset {
+		return false, nil
+	}
+	r.sessions[session.ID] = *session
+	return true, nil
+}
+
+func (r *fakeUploadSessionRepo) Update(session *domain.UploadSession) error {
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *fakeUploadSessionRepo) Delete(id string) error {
+	delete(r.sessions, id)
+	return nil
+}
+
+// chunkWrite 一次分片上传请求
+type chunkWrite struct {
+	offset   int64
+	from, to int   // 本次发送的内容区间
+	length   int64 // Content-Length，为 0 时取 to-from
+	userID   uint  // 为 0 时使用会话所有人
+	wantErr  error // 为 errAny 时只要求返回错误
+}
+
+var errAny = errors.New("any error")
+
+func TestUploadSessionChunks(t *testing.T) {
+	const chunkSize = 8
+	// ZIP 文件头，首个分片可识别为 application/zip；共 20 字节，分为 8+8+4 三个分片
+	content := []byte("PK\x03\x04evidence-recording")[:20]
+
+	tests := []struct {
+		name   string
+		writes []chunkWrite
+		// restart 在第几次请求前重新创建服务实例（模拟断线后在另一台实例上续传），-1 表示不重启
+		restart      int
+		wantReceived int64
+		wantComplete bool
+	}{
+		{
+			name:         "按顺序上传",
+			writes:       []chunkWrite{{offset: 0, from: 0, to: 8}, {offset: 8, from: 8, to: 16}, {offset: 16, from: 16, to: 20}},
+			restart:      -1,
+			wantReceived: 20,
+			wantComplete: true,
+		},
+		{
+			name:         "断线后续传",
+			writes:       []chunkWrite{{offset: 0, from: 0, to: 8}, {offset: 8, from: 8, to: 16}, {offset: 16, from: 16, to: 20}},
+			restart:      1,
+			wantReceived: 20,
+			wantComplete: true,
+		},
+		{
+			name: "跳过分片",
+			writes: []chunkWrite{
+				{offset: 0, from: 0, to: 8},
+				{offset: 16, from: 16, to: 20, wantErr: domain.ErrUploadOffsetMismatch},
+			},
+			restart:      -1,
+			wantReceived: 8,
+		},
+		{
+			name: "重复发送已接收的分片",
+			writes: []chunkWrite{
+				{offset: 0, from: 0, to: 8},
+				{offset: 0, from: 0, to: 8, wantErr: domain.ErrUploadOffsetMismatch},
+				{offset: 8, from: 8, to: 16},
+				{offset: 16, from: 16, to: 20},
+			},
+			restart:      -1,
+			wantReceived: 20,
+			wantComplete: true,
+		},
+		{
+			name:         "分片小于分片大小",
+			writes:       []chunkWrite{{offset: 0, from: 0, to: 4, wantErr: errAny}},
+			restart:      -1,
+			wantReceived: 0,
+		},
+		{
+			name:         "最后一片超出文件大小",
+			writes:       []chunkWrite{{offset: 0, from: 0, to: 8}, {offset: 8, from: 8, to: 16}, {offset: 16, from: 12, to: 20, wantErr: errAny}},
+			restart:      -1,
+			wantReceived: 16,
+		},
+		{
+			name:         "内容短于 Content-Length",
+			writes:       []chunkWrite{{offset: 0, from: 0, to: 6, length: 8, wantErr: errAny}},
+			restart:      -1,
+			wantReceived: 0,
+		},
+		{
+			name:         "其他用户不能上传",
+			writes:       []chunkWrite{{offset: 0, from: 0, to: 8, userID: 2, wantErr: errAny}},
+			restart:      -1,
+			wantReceived: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newFakeUploadSessionRepo()
+			storage := upload.NewLocalStorage(t.TempDir())
+			newService := func() *uploadSessionService {
+				return &uploadSessionService{repo: repo, storage: storage, chunkSize: chunkSize, sessionTTL: time.Hour}
+			}
+			service := newService()
+
+			session, err := service.CreateSession(1, "whitehat", "poc.zip", int64(len(content)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, w := range tt.writes {
+				if i == tt.restart {
+					service = newService()
+				}
+				userID, length := w.userID, w.length
+				if userID == 0 {
+					userID = 1
+				}
+				if length == 0 {
+					length = int64(w.to - w.from)
+				}
+				got, err := service.WriteChunk(ctx, session.ID, userID, w.offset, bytes.NewReader(content[w.from:w.to]), length)
+				switch {
+				case w.wantErr == nil && err != nil:
+					t.Fatalf("write %d: WriteChunk() error = %v", i, err)
+				case w.wantErr == errAny && err == nil, w.wantErr != nil && w.wantErr != errAny && !errors.Is(err, w.wantErr):
+					t.Fatalf("write %d: WriteChunk() error = %v, want %v", i, err, w.wantErr)
+				}
+				// 进度不一致时返回当前会话，客户端据此续传
+				if errors.Is(err, domain.ErrUploadOffsetMismatch) && (got == nil || got.ReceivedSize != repo.sessions[session.ID].ReceivedSize) {
+					t.Errorf("write %d: offset mismatch should return current session, got %+v", i, got)
+				}
+			}
+
+			current, err := service.GetSession(session.ID, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if current.ReceivedSize != tt.wantReceived {
+				t.Errorf("ReceivedSize = %d, want %d", current.ReceivedSize, tt.wantReceived)
+			}
+
+			completed, err := service.CompleteSession(ctx, session.ID, 1)
+			if !tt.wantComplete {
+				if err == nil {
+					t.Error("CompleteSession() error = nil, want incomplete upload error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CompleteSession() error = %v", err)
+			}
+
+			sum := sha256.Sum256(content)
+			if completed.Status != domain.UploadSessionCompleted || completed.SHA256 != hex.EncodeToString(sum[:]) || completed.MimeType != "application/zip" {
+				t.Errorf("CompleteSession() = %+v", completed)
+			}
+			reader, _, err := storage.Open(ctx, completed.FilePath)
+			if err != nil {
+				t.Fatalf("Open(%s) error = %v", completed.FilePath, err)
+			}
+			data, _ := io.ReadAll(reader)
+			reader.Close()
+			if !bytes.Equal(data, content) {
+				t.Errorf("合并后的文件 = %q, want %q", data, content)
+			}
+			for i := int64(0); i < 3; i++ {
+				if _, _, err := storage.Open(ctx, upload.ChunkKey(session.ID, i)); !errors.Is(err, upload.ErrObjectNotFound) {
+					t.Errorf("分片 %d 合并后未删除", i)
+				}
+			}
+		})
+	}
+}
+
+func TestUploadSessionRejectsType(t *testing.T) {
+	repo := newFakeUploadSessionRepo()
+	service := &uploadSessionService{repo: repo, storage: upload.NewLocalStorage(t.TempDir()), chunkSize: 16, sessionTTL: time.Hour}
+	content := []byte("<html><script>x</script></html>")
+
+	session, err := service.CreateSession(1, "whitehat", "poc.zip", int64(len(content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 文件类型由首个分片的内容决定，与文件名无关
+	if _, err := service.WriteChunk(context.Background(), session.ID, 1, 0, bytes.NewReader(content[:16]), 16); err == nil {
+		t.Fatal("WriteChunk() error = nil, want unsupported type")
+	}
+	if repo.sessions[session.ID].ReceivedSize != 0 {
+		t.Errorf("ReceivedSize = %d, want 0", repo.sessions[session.ID].ReceivedSize)
+	}
+}
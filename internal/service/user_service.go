@@ -2,19 +2,23 @@ package service
 
 import (
 	"bug-bounty-lite/internal/domain"
-	"bug-bounty-lite/pkg/jwt"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type userService struct {
-	repo       domain.UserRepository
-	orgRepo    domain.OrganizationRepository
-	logRepo    domain.UserUpdateLogRepository
-	jwtManager *jwt.JWTManager
+	repo      domain.UserRepository
+	orgRepo   domain.OrganizationRepository
+	logRepo   domain.UserUpdateLogRepository
+	tokens    domain.AuthTokenService
+	twoFactor domain.TwoFactorService
+	accounts  domain.AccountService
+	guard     domain.LoginGuardService
+	passwords domain.PasswordPolicyService
 }
 
 // NewUserService 构造函数
@@ -22,18 +26,26 @@ func NewUserService(
 	repo domain.UserRepository,
 	orgRepo domain.OrganizationRepository,
 	logRepo domain.UserUpdateLogRepository,
-	jwtManager *jwt.JWTManager,
+	tokens domain.AuthTokenService,
+	twoFactor domain.TwoFactorService,
+	accounts domain.AccountService,
+	guard domain.LoginGuardService,
+	passwords domain.PasswordPolicyService,
 ) domain.UserService {
 	return &userService{
-		repo:       repo,
-		orgRepo:    orgRepo,
-		logRepo:    logRepo,
-		jwtManager: jwtManager,
+		repo:      repo,
+		orgRepo:   orgRepo,
+		logRepo:   logRepo,
+		tokens:    tokens,
+		twoFactor: twoFactor,
+		accounts:  accounts,
+		guard:     guard,
+		passwords: passwords,
 	}
 }
 
 // Register 用户注册
-// 核心逻辑：接收明文密码 -> bcrypt加密 -> 存入数据库
+// 核心逻辑：接收明文密码 -> 校验密码策略 -> bcrypt加密 -> 存入数据库
 func (s *userService) Register(user *domain.User) error {
 	// 1. 检查用户名是否已存在
 	existingUser, _ := s.repo.FindByUsername(user.Username)
@@ -41,48 +53,82 @@ func (s *userService) Register(user *domain.User) error {
 		return errors.New("username already exists")
 	}
 
-	// 2. 密码加密 (Cost 默认 10)
+	// 2. 校验密码策略
+	if err := s.passwords.Validate(user, user.Password); err != nil {
+		return err
+	}
+
+	// 3. 密码加密 (Cost 默认 10)
 	hashedPwd, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 
-	// 3. 用加密后的哈希值替换原始密码
+	// 4. 用加密后的哈希值替换原始密码
 	user.Password = string(hashedPwd)
 
-	// 4. 调用 Repo 保存到数据库
-	return s.repo.Create(user)
+	// 5. 调用 Repo 保存到数据库
+	if err := s.repo.Create(user); err != nil {
+		return err
+	}
+	if err := s.passwords.Remember(user.ID, user.Password); err != nil {
+		log.Printf("[WARN] Failed to record password history for user %d: %v", user.ID, err)
+	}
+
+	// 6. 填写了邮箱时发送验证邮件
+	if user.Email != "" {
+		go s.sendVerificationEmail(user.ID)
+	}
+	return nil
 }
 
 // Login 用户登录
-// 核心逻辑：接收明文密码 -> bcrypt比对数据库里的哈希 -> 成功则返回用户和JWT
-func (s *userService) Login(username, password string) (*domain.User, string, error) {
+// 核心逻辑：接收明文密码 -> bcrypt比对数据库里的哈希 -> 需要两步验证时返回挑战令牌，否则返回用户和令牌（访问令牌 + 刷新令牌）
+func (s *userService) Login(username, password string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	// 0. 用户名或 IP 连续失败过多时直接拒绝，不再比对密码
+	if err := s.guard.Check(username, client.IP); err != nil {
+		return nil, err
+	}
+
 	// 1. 根据用户名找用户
 	user, err := s.repo.FindByUsername(username)
 	if err != nil || user == nil {
-		// 返回模糊错误，防止攻击者枚举用户名
-		return nil, "", errors.New("invalid username or password")
+		// 返回模糊错误，防止攻击者枚举用户名（不存在的用户名同样计入失败次数）
+		s.guard.RecordFailure(username, client.IP, 0)
+		return nil, errors.New("invalid username or password")
 	}
 
 	// 2. 比对密码
 	// 注意：这里必须用 CompareHashAndPassword，不能用 ==
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return nil, "", errors.New("invalid username or password")
+		s.guard.RecordFailure(username, client.IP, user.ID)
+		return nil, errors.New("invalid username or password")
+	}
+
+	// 3. 已启用两步验证或角色要求两步验证：返回挑战令牌，验证通过后再签发令牌
+	// 失败计数在第二步验证通过后才清除，否则攻击者可以用泄露的密码反复重置计数
+	challenge, err := s.twoFactor.LoginChallenge(user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return challenge, nil
 	}
+	s.guard.RecordSuccess(username)
 
-	// 3. 生成 JWT Token
-	token, err := s.jwtManager.GenerateToken(user.ID, user.Username, user.Role)
+	// 4. 记录登录会话，签发访问令牌和刷新令牌
+	tokens, err := s.tokens.IssueTokens(user, client)
 	if err != nil {
-		return nil, "", errors.New("failed to generate token")
+		return nil, errors.New("failed to generate token")
 	}
 
-	// 4. 更新最后登录时间
+	// 5. 更新最后登录时间
 	now := time.Now()
 	user.LastLoginAt = &now
 	_ = s.repo.UpdateLastLoginAt(user.ID, now)
 
-	return user, token, nil
+	return &domain.LoginResult{User: user, Tokens: tokens}, nil
 }
 
 // GetUser 获取用户信息
@@ -122,7 +168,25 @@ func (s *userService) UpdateProfile(userID uint, name string, bio string, phone
 	}
 
 	// 应用更新（使用专用方法避免外键约束问题）
-	return s.repo.UpdateProfileFields(userID, name, bio, phone, email)
+	if err := s.repo.UpdateProfileFields(userID, name, bio, phone, email); err != nil {
+		return err
+	}
+
+	// 修改邮箱后需要重新验证
+	if email != "" && email != user.Email {
+		if err := s.repo.ClearEmailVerified(userID); err != nil {
+			return err
+		}
+		go s.sendVerificationEmail(userID)
+	}
+	return nil
+}
+
+// sendVerificationEmail 在后台发送邮箱验证邮件，失败时只记录日志（用户可重新发送）
+func (s *userService) sendVerificationEmail(userID uint) {
+	if err := s.accounts.SendVerificationEmail(userID); err != nil {
+		log.Printf("[WARN] Failed to send verification email to user %d: %v", userID, err)
+	}
 }
 
 // BindOrganization 绑定/切换组织
@@ -165,9 +229,9 @@ func (s *userService) ChangePassword(userID uint, oldPassword, newPassword strin
 		return errors.New("当前密码不正确")
 	}
 
-	// 3. 验证新密码长度
-	if len(newPassword) < 6 {
-		return errors.New("新密码长度不能少于6位")
+	// 3. 校验密码策略
+	if err := s.passwords.Validate(user, newPassword); err != nil {
+		return err
 	}
 
 	// 4. 加密新密码
@@ -178,7 +242,13 @@ func (s *userService) ChangePassword(userID uint, oldPassword, newPassword strin
 
 	// 5. 更新密码
 	user.Password = string(hashedPassword)
-	return s.repo.Update(user)
+	if err := s.repo.Update(user); err != nil {
+		return err
+	}
+	if err := s.passwords.Remember(user.ID, user.Password); err != nil {
+		log.Printf("[WARN] Failed to record password history for user %d: %v", user.ID, err)
+	}
+	return nil
 }
 
 // UpdateAvatar 更新用户头像
@@ -0,0 +1,65 @@
+package breached
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PrefixLength k-anonymity 查询使用的 SHA-1 前缀长度（十六进制字符数，与 Have I Been Pwned 的 range 接口一致）
+const PrefixLength = 5
+
+// Source 泄露密码哈希库
+// 查询时只提供密码 SHA-1 的前 5 位，由调用方在返回的同前缀后缀中比对，哈希库本身看不到完整哈希
+type Source interface {
+	// Range 返回以 prefix（5 位大写十六进制）开头的全部哈希的后缀（35 位大写十六进制）
+	Range(prefix string) ([]string, error)
+}
+
+// Open 打开本地泄露密码哈希库，path 为目录时按前缀读取分片文件，为文件时整体加载到内存
+func Open(path string) (Source, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开泄露密码库失败: %v", err)
+	}
+	if info.IsDir() {
+		return NewDirSource(path), nil
+	}
+	src, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return src, nil
+}
+
+// Contains 密码是否出现在泄露密码库中
+func Contains(src Source, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := src.Range(hash[:PrefixLength])
+	if err != nil {
+		return false, err
+	}
+	for _, suffix := range suffixes {
+		if suffix == hash[PrefixLength:] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// parseLine 解析一行哈希记录，支持 "HASH" 和 "HASH:COUNT" 两种格式（Have I Been Pwned 导出格式），
+// 空行和 # 开头的注释行返回空字符串
+func parseLine(line string) string {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ""
+	}
+	if i := strings.IndexByte(line, ':'); i >= 0 {
+		line = line[:i]
+	}
+	return strings.ToUpper(line)
+}
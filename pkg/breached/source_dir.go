@@ -0,0 +1,48 @@
+package breached
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DirSource 按前缀分片的泄露密码哈希库目录，每个前缀一个 {PREFIX}.txt 文件，每行为 "后缀:次数"
+// 与 Have I Been Pwned range 接口的返回格式相同（可用 haveibeenpwned-downloader 按前缀导出），
+// 每次查询只读取一个分片文件，适合完整的数据集
+type DirSource struct {
+	dir string
+}
+
+// NewDirSource 创建分片目录哈希库
+func NewDirSource(dir string) *DirSource {
+	return &DirSource{dir: dir}
+}
+
+// Range 读取前缀对应的分片文件，文件不存在时视为没有该前缀的哈希
+func (s *DirSource) Range(prefix string) ([]string, error) {
+	if len(prefix) != PrefixLength {
+		return nil, fmt.Errorf("无效的哈希前缀: %s", prefix)
+	}
+	f, err := os.Open(filepath.Join(s.dir, prefix+".txt"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取泄露密码库失败: %v", err)
+	}
+	defer f.Close()
+
+	var suffixes []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if suffix := parseLine(scanner.Text()); suffix != "" {
+			suffixes = append(suffixes, suffix)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取泄露密码库失败: %v", err)
+	}
+	return suffixes, nil
+}
@@ -0,0 +1,79 @@
+package breached
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// FileSource 从单个文件加载的泄露密码哈希库，每行一个完整的 SHA-1（可带 ":次数"）
+// 哈希以 20 字节保存并排序，100 万条约占 20MB 内存，完整的 Have I Been Pwned 数据请使用 DirSource
+type FileSource struct {
+	hashes [][sha1Size]byte
+}
+
+const sha1Size = 20
+
+// LoadFile 加载哈希文件，遇到无法解析的行时返回错误（包含行号）
+func LoadFile(path string) (*FileSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开泄露密码库失败: %v", err)
+	}
+	defer f.Close()
+
+	src := &FileSource{}
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := parseLine(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var hash [sha1Size]byte
+		if len(line) != sha1Size*2 {
+			return nil, fmt.Errorf("泄露密码库第 %d 行不是 SHA-1 哈希", lineNo)
+		}
+		if _, err := hex.Decode(hash[:], []byte(line)); err != nil {
+			return nil, fmt.Errorf("泄露密码库第 %d 行不是 SHA-1 哈希", lineNo)
+		}
+		src.hashes = append(src.hashes, hash)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取泄露密码库失败: %v", err)
+	}
+
+	sort.Slice(src.hashes, func(i, j int) bool {
+		return bytes.Compare(src.hashes[i][:], src.hashes[j][:]) < 0
+	})
+	return src, nil
+}
+
+// Len 哈希数量
+func (s *FileSource) Len() int {
+	return len(s.hashes)
+}
+
+// Range 二分查找同前缀的哈希
+func (s *FileSource) Range(prefix string) ([]string, error) {
+	var lo, hi [sha1Size]byte
+	if _, err := hex.Decode(lo[:], []byte(prefix+strings.Repeat("0", sha1Size*2-len(prefix)))); err != nil {
+		return nil, fmt.Errorf("无效的哈希前缀: %s", prefix)
+	}
+	if _, err := hex.Decode(hi[:], []byte(prefix+strings.Repeat("F", sha1Size*2-len(prefix)))); err != nil {
+		return nil, fmt.Errorf("无效的哈希前缀: %s", prefix)
+	}
+
+	start := sort.Search(len(s.hashes), func(i int) bool {
+		return bytes.Compare(s.hashes[i][:], lo[:]) >= 0
+	})
+	var suffixes []string
+	for i := start; i < len(s.hashes) && bytes.Compare(s.hashes[i][:], hi[:]) <= 0; i++ {
+		hash := strings.ToUpper(hex.EncodeToString(s.hashes[i][:]))
+		suffixes = append(suffixes, hash[len(prefix):])
+	}
+	return suffixes, nil
+}
@@ -13,6 +13,11 @@ type Config struct {
 	Server   ServerConfig   `mapstructure:"server"`
 	Database DatabaseConfig `mapstructure:"database"`
 	JWT      JWTConfig      `mapstructure:"jwt"`
+	Auth     AuthConfig     `mapstructure:"auth"`
+	Upload   UploadConfig   `mapstructure:"upload"`
+	Storage  StorageConfig  `mapstructure:"storage"`
+	Mail     MailConfig     `mapstructure:"mail"`
+	Redis    RedisConfig    `mapstructure:"redis"`
 }
 
 type ServerConfig struct {
@@ -28,8 +33,190 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret string `mapstructure:"secret"`
-	Expire int    `mapstructure:"expire"`
+	Secret        string `mapstructure:"secret"`
+	Expire        int    `mapstructure:"expire"`         // 访问令牌有效期（秒）
+	RefreshExpire int    `mapstructure:"refresh_expire"` // 刷新令牌有效期（秒），默认 7 天
+}
+
+// AuthConfig 登录安全配置
+type AuthConfig struct {
+	TwoFactor TwoFactorConfig    `mapstructure:"two_factor"`
+	Email     AccountEmailConfig `mapstructure:"email"`
+	Lockout   LockoutConfig      `mapstructure:"lockout"`
+	Password  PasswordConfig     `mapstructure:"password"`
+	OIDC      OIDCConfig         `mapstructure:"oidc"`
+	APIKeys   APIKeyConfig       `mapstructure:"api_keys"`
+}
+
+// TwoFactorConfig TOTP 两步验证配置
+type TwoFactorConfig struct {
+	Issuer        string   `mapstructure:"issuer"`         // 验证器 App 中显示的发行方名称，默认 Bug Bounty Lite
+	RequiredRoles []string `mapstructure:"required_roles"` // 必须启用两步验证的角色（初始值，管理员可在后台修改）
+	ChallengeTTL  int      `mapstructure:"challenge_ttl"`  // 登录第二步的挑战令牌有效期（秒），默认 300
+}
+
+// PasswordConfig 密码策略配置（注册、修改密码和重置密码时校验）
+type PasswordConfig struct {
+	MinLength        int    `mapstructure:"min_length"`        // 最短长度（字符数），默认 8
+	RequireUpper     bool   `mapstructure:"require_upper"`     // 必须包含大写字母
+	RequireLower     bool   `mapstructure:"require_lower"`     // 必须包含小写字母
+	RequireDigit     bool   `mapstructure:"require_digit"`     // 必须包含数字
+	RequireSymbol    bool   `mapstructure:"require_symbol"`    // 必须包含符号
+	MinClasses       int    `mapstructure:"min_classes"`       // 大写字母、小写字母、数字、符号中至少包含几类，0 不限制
+	History          int    `mapstructure:"history"`           // 不能与最近 N 次使用过的密码（包括当前密码）相同，0 不检查
+	DisallowUsername bool   `mapstructure:"disallow_username"` // 密码中不能包含用户名（忽略大小写）
+	BreachedList     string `mapstructure:"breached_list"`     // 本地泄露密码 SHA-1 哈希库（文件或按前缀分片的目录），为空时不检查
+}
+
+// APIKeyConfig 个人 API Key 配置（脚本通过 X-API-Key 请求头调用接口）
+type APIKeyConfig struct {
+	MaxPerUser     int `mapstructure:"max_per_user"`     // 每个用户最多有效 Key 数量，默认 10
+	DefaultTTLDays int `mapstructure:"default_ttl_days"` // 未指定有效期时的有效天数，默认 90
+	MaxTTLDays     int `mapstructure:"max_ttl_days"`     // 最长有效天数，默认 365
+}
+
+// OIDCConfig OIDC 单点登录配置
+type OIDCConfig struct {
+	StateTTL  int                  `mapstructure:"state_ttl"` // 从跳转到身份提供方到回调完成的最长时间（秒），默认 600
+	Providers []OIDCProviderConfig `mapstructure:"providers"`
+}
+
+// OIDCProviderConfig 单个 OIDC 身份提供方（授权码模式 + PKCE）
+type OIDCProviderConfig struct {
+	Name         string   `mapstructure:"name"`         // 标识，用于接口路径 /auth/oidc/{name}/...，只能包含字母、数字、- 和 _
+	DisplayName  string   `mapstructure:"display_name"` // 登录页显示的名称，默认同 name
+	Issuer       string   `mapstructure:"issuer"`       // 发行方地址，{issuer}/.well-known/openid-configuration 必须可访问
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"` // 公共客户端可为空（只使用 PKCE）
+	RedirectURL  string   `mapstructure:"redirect_url"`  // 身份提供方回调的前端页面，需在身份提供方登记
+	Scopes       []string `mapstructure:"scopes"`        // 默认 openid profile email
+
+	UsernameClaim string `mapstructure:"username_claim"` // 新建账号时用作用户名的声明，默认 preferred_username，缺失时使用邮箱前缀
+	GroupsClaim   string `mapstructure:"groups_claim"`   // 用于映射角色和组织的声明（字符串或字符串数组），默认 groups
+
+	// GroupMappings 按 groups_claim 的值映射角色和组织，匹配多条时取权限最高的角色和第一个非 0 的组织
+	GroupMappings []OIDCGroupMapping `mapstructure:"group_mappings"`
+	DefaultRole   string             `mapstructure:"default_role"`   // 没有匹配的映射时的角色，为空时拒绝登录
+	DefaultOrgID  uint               `mapstructure:"default_org_id"` // 没有匹配到组织时的组织ID，0 表示不绑定
+
+	AutoProvision bool `mapstructure:"auto_provision"` // 首次登录且无法关联已有账号时自动创建账号
+	LinkByEmail   bool `mapstructure:"link_by_email"`  // 按邮箱关联已有账号（身份提供方和本地邮箱都必须已验证）
+	SyncOnLogin   bool `mapstructure:"sync_on_login"`  // 每次登录按映射更新角色和组织
+}
+
+// OIDCGroupMapping 组（或其他声明值）到角色和组织的映射
+type OIDCGroupMapping struct {
+	Group string `mapstructure:"group"`
+	Role  string `mapstructure:"role"`   // whitehat / vendor / admin，为空时不影响角色
+	OrgID uint   `mapstructure:"org_id"` // 为 0 时不影响组织
+}
+
+// AccountEmailConfig 邮箱验证与找回密码配置
+type AccountEmailConfig struct {
+	BaseURL         string `mapstructure:"base_url"`         // 前端地址，邮件中的链接为 {base_url}/reset-password?token=...，为空时只发送令牌
+	ResetTokenTTL   int    `mapstructure:"reset_token_ttl"`  // 重置密码令牌有效期（秒），默认 1800
+	VerifyTokenTTL  int    `mapstructure:"verify_token_ttl"` // 邮箱验证令牌有效期（秒），默认 86400
+	RequireVerified bool   `mapstructure:"require_verified"` // 未验证邮箱的账号不能提交报告
+}
+
+// LockoutConfig 登录防暴力破解配置
+// 按用户名和客户端 IP 分别统计连续失败次数：达到上限的一半后从 base_delay 开始指数退避（最长 30 秒），
+// 达到上限后锁定 lockout_duration，之后每多失败一次锁定时间翻倍，最长 max_lockout_duration
+type LockoutConfig struct {
+	Store              string `mapstructure:"store"`                // memory（默认，单节点）/ redis（多节点共享，使用 redis 配置）
+	MaxAttempts        int    `mapstructure:"max_attempts"`         // 同一用户名连续失败多少次后锁定，默认 5
+	IPMaxAttempts      int    `mapstructure:"ip_max_attempts"`      // 同一 IP 连续失败多少次后锁定，默认 20
+	BaseDelay          int    `mapstructure:"base_delay"`           // 指数退避的初始等待时间（秒），默认 1
+	LockoutDuration    int    `mapstructure:"lockout_duration"`     // 首次锁定时长（秒），默认 900
+	MaxLockoutDuration int    `mapstructure:"max_lockout_duration"` // 最长锁定时长（秒），默认 86400
+	Window             int    `mapstructure:"window"`               // 最后一次失败后多久未再失败则计数清零（秒），默认 3600
+}
+
+// UploadConfig 上传文件配置
+type UploadConfig struct {
+	SigningSecret string              `mapstructure:"signing_secret"` // 下载链接签名密钥，为空时使用 JWT 密钥
+	SignedURLTTL  int                 `mapstructure:"signed_url_ttl"` // 签名下载链接有效期（秒），默认 900
+	Chunked       ChunkedUploadConfig `mapstructure:"chunked"`
+	Scanner       ScannerConfig       `mapstructure:"scanner"`
+	GC            UploadGCConfig      `mapstructure:"gc"`
+}
+
+// UploadGCConfig 孤儿上传文件定时回收配置（也可通过 cmd/gc-uploads 手动执行）
+type UploadGCConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`        // 是否在服务内定时回收，默认关闭
+	Interval      int    `mapstructure:"interval"`       // 回收间隔（小时），默认 24
+	RetentionDays int    `mapstructure:"retention_days"` // 保留期（天），未引用的文件超过保留期才回收，默认 7，最少 2
+	Action        string `mapstructure:"action"`         // quarantine（默认，移动到 orphaned/）/ delete
+}
+
+// ChunkedUploadConfig 大文件分片上传配置
+type ChunkedUploadConfig struct {
+	MaxSize    map[string]int64 `mapstructure:"max_size"`    // 按角色的单文件大小上限（MB），未配置的角色使用默认值
+	ChunkSize  int64            `mapstructure:"chunk_size"`  // 分片大小（MB），默认 8
+	SessionTTL int              `mapstructure:"session_ttl"` // 上传会话闲置过期时间（秒），默认 86400
+}
+
+// ScannerConfig 上传文件恶意软件扫描配置
+type ScannerConfig struct {
+	Driver       string             `mapstructure:"driver"` // noop（默认，不扫描）/ clamd
+	Clamd        ClamdScannerConfig `mapstructure:"clamd"`
+	RescanPeriod int                `mapstructure:"rescan_period"` // 重新扫描待扫描附件的间隔（秒），默认 300
+}
+
+// ClamdScannerConfig ClamAV clamd 守护进程连接配置
+type ClamdScannerConfig struct {
+	Network string `mapstructure:"network"` // unix（默认）/ tcp
+	Address string `mapstructure:"address"` // 如 /var/run/clamav/clamd.ctl、127.0.0.1:3310
+	Timeout int    `mapstructure:"timeout"` // 单个文件扫描超时（秒），默认 60
+}
+
+// StorageConfig 上传文件存储配置
+type StorageConfig struct {
+	Driver string             `mapstructure:"driver"` // local（默认）/ s3
+	Local  LocalStorageConfig `mapstructure:"local"`
+	S3     S3StorageConfig    `mapstructure:"s3"`
+}
+
+// LocalStorageConfig 本地文件系统存储配置
+type LocalStorageConfig struct {
+	Root string `mapstructure:"root"` // 存储根目录，默认 uploads
+}
+
+// S3StorageConfig S3 兼容对象存储配置（AWS S3、MinIO、OSS 等）
+type S3StorageConfig struct {
+	Endpoint  string `mapstructure:"endpoint"` // 如 s3.amazonaws.com、localhost:9000
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	Bucket    string `mapstructure:"bucket"`
+	Region    string `mapstructure:"region"`
+	UseSSL    bool   `mapstructure:"use_ssl"`
+	Prefix    string `mapstructure:"prefix"` // 对象键前缀，可为空
+}
+
+// MailConfig 邮件发送配置
+type MailConfig struct {
+	Driver string         `mapstructure:"driver"` // log（默认，只打印到日志）/ smtp
+	From   string         `mapstructure:"from"`   // 发件人，如 "Bug Bounty Lite <no-reply@example.com>"
+	SMTP   SMTPMailConfig `mapstructure:"smtp"`
+}
+
+// SMTPMailConfig SMTP 服务器配置
+type SMTPMailConfig struct {
+	Host       string `mapstructure:"host"`
+	Port       int    `mapstructure:"port"`     // 默认 25，encryption 为 tls 时默认 465
+	Username   string `mapstructure:"username"` // 为空时不认证
+	Password   string `mapstructure:"password"`
+	Encryption string `mapstructure:"encryption"` // 空（服务器支持时使用 STARTTLS）/ none / starttls / tls
+	Timeout    int    `mapstructure:"timeout"`    // 单封邮件发送超时（秒），默认 30
+}
+
+// RedisConfig Redis 兼容服务连接配置（Redis、Valkey、KeyDB 等）
+type RedisConfig struct {
+	Addr     string `mapstructure:"addr"` // 如 localhost:6379
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+	Prefix   string `mapstructure:"prefix"` // 键前缀，多个环境共用 Redis 时使用，默认 bug-bounty-lite:
 }
 
 // LoadConfig 读取配置文件的核心函数
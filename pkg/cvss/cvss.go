@@ -0,0 +1,86 @@
+package cvss
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	gocvss31 "github.com/pandatix/go-cvss/31"
+	gocvss40 "github.com/pandatix/go-cvss/40"
+)
+
+// 支持的 CVSS 版本
+const (
+	Version31 = "3.1"
+	Version40 = "4.0"
+)
+
+// 危害等级（与 Report.Severity 取值一致）
+const (
+	SeverityCritical = "Critical"
+	SeverityHigh     = "High"
+	SeverityMedium   = "Medium"
+	SeverityLow      = "Low"
+	SeverityNone     = "None"
+)
+
+// Result CVSS 向量解析结果
+type Result struct {
+	Version  string  `json:"version"`  // 3.1 / 4.0
+	Vector   string  `json:"vector"`   // 规范化后的向量字符串
+	Score    float64 `json:"score"`    // 分数 (0.0-10.0)
+	Severity string  `json:"severity"` // 由分数推导的危害等级
+}
+
+// Parse 解析并校验 CVSS 向量，计算分数
+// v3.1 使用基础分（Base Score），v4.0 使用向量中包含的全部指标计算（CVSS-B/BT/BE/BTE）
+func Parse(vector string) (*Result, error) {
+	vector = strings.TrimSpace(vector)
+
+	switch {
+	case strings.HasPrefix(vector, "CVSS:3.1/"):
+		v, err := gocvss31.ParseVector(vector)
+		if err != nil {
+			return nil, fmt.Errorf("无效的 CVSS 3.1 向量: %v", err)
+		}
+		score := v.BaseScore()
+		return &Result{
+			Version:  Version31,
+			Vector:   v.Vector(),
+			Score:    score,
+			Severity: SeverityFromScore(score),
+		}, nil
+
+	case strings.HasPrefix(vector, "CVSS:4.0/"):
+		v, err := gocvss40.ParseVector(vector)
+		if err != nil {
+			return nil, fmt.Errorf("无效的 CVSS 4.0 向量: %v", err)
+		}
+		score := math.Round(v.Score()*10) / 10
+		return &Result{
+			Version:  Version40,
+			Vector:   v.Vector(),
+			Score:    score,
+			Severity: SeverityFromScore(score),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("不支持的 CVSS 向量，仅支持以 CVSS:3.1/ 或 CVSS:4.0/ 开头的向量")
+	}
+}
+
+// SeverityFromScore 根据分数推导危害等级（v3.1 与 v4.0 的定性等级划分一致）
+func SeverityFromScore(score float64) string {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score >= 0.1:
+		return SeverityLow
+	default:
+		return SeverityNone
+	}
+}
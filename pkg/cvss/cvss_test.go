@@ -0,0 +1,66 @@
+package cvss
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		vector       string
+		wantVersion  string
+		wantScore    float64
+		wantSeverity string
+		wantErr      bool
+	}{
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", wantVersion: Version31, wantScore: 9.8, wantSeverity: SeverityCritical},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", wantVersion: Version31, wantScore: 6.1, wantSeverity: SeverityMedium},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", wantVersion: Version31, wantScore: 0, wantSeverity: SeverityNone},
+		{vector: "  CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H  ", wantVersion: Version31, wantScore: 7.8, wantSeverity: SeverityHigh},
+		{vector: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", wantVersion: Version40, wantScore: 9.3, wantSeverity: SeverityCritical},
+		{vector: "CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", wantVersion: Version40, wantScore: 8.5, wantSeverity: SeverityHigh},
+		{vector: "", wantErr: true},
+		{vector: "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", wantErr: true},
+		{vector: "AV:N/AC:L/Au:N/C:P/I:P/A:P", wantErr: true},
+		// 缺少必需指标
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H", wantErr: true},
+		// 指标取值非法
+		{vector: "CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", wantErr: true},
+		{vector: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.vector, func(t *testing.T) {
+			got, err := Parse(tt.vector)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Version != tt.wantVersion || got.Score != tt.wantScore || got.Severity != tt.wantSeverity {
+				t.Errorf("Parse() = %+v, want version %s score %v severity %s", got, tt.wantVersion, tt.wantScore, tt.wantSeverity)
+			}
+		})
+	}
+}
+
+func TestSeverityFromScore(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{10.0, SeverityCritical},
+		{9.0, SeverityCritical},
+		{8.9, SeverityHigh},
+		{7.0, SeverityHigh},
+		{6.9, SeverityMedium},
+		{4.0, SeverityMedium},
+		{3.9, SeverityLow},
+		{0.1, SeverityLow},
+		{0.0, SeverityNone},
+	}
+
+	for _, tt := range tests {
+		if got := SeverityFromScore(tt.score); got != tt.want {
+			t.Errorf("SeverityFromScore(%v) = %s, want %s", tt.score, got, tt.want)
+		}
+	}
+}
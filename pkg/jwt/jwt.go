@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -17,6 +19,10 @@ type Claims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// SessionID 登录会话ID，同一次登录签发的访问令牌和刷新令牌属于同一会话
+	SessionID string `json:"sid,omitempty"`
+	// Version 签发时用户的令牌版本，用户退出全部会话后版本递增，旧令牌随之失效
+	Version int `json:"ver"`
 	jwt.RegisteredClaims
 }
 
@@ -34,14 +40,27 @@ func NewJWTManager(secret string, expireSeconds int) *JWTManager {
 	}
 }
 
-// GenerateToken 生成 JWT Token
-func (m *JWTManager) GenerateToken(userID uint, username, role string) (string, error) {
+// Expire 访问令牌有效期
+func (m *JWTManager) Expire() time.Duration {
+	return m.expire
+}
+
+// GenerateToken 生成 JWT Token（每个 Token 带有随机 jti，用于单独吊销）
+func (m *JWTManager) GenerateToken(userID uint, username, role, sessionID string, version int) (string, error) {
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
 	now := time.Now()
 	claims := Claims{
-		UserID:   userID,
-		Username: username,
-		Role:     role,
+		UserID:    userID,
+		Username:  username,
+		Role:      role,
+		SessionID: sessionID,
+		Version:   version,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
 			ExpiresAt: jwt.NewNumericDate(now.Add(m.expire)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
@@ -77,3 +96,11 @@ func (m *JWTManager) ParseToken(tokenString string) (*Claims, error) {
 	return nil, ErrInvalidToken
 }
 
+// newTokenID 生成随机的 Token ID
+func newTokenID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
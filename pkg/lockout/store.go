@@ -0,0 +1,50 @@
+package lockout
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"fmt"
+	"time"
+)
+
+// 存储驱动
+const (
+	StoreDriverMemory = "memory"
+	StoreDriverRedis  = "redis"
+)
+
+// State 某个键（用户名或 IP）的失败计数和锁定状态
+type State struct {
+	Failures    int       // 计数窗口内的连续失败次数
+	LockedUntil time.Time // 锁定截止时间，零值或早于当前时间表示未锁定
+}
+
+// Locked 在时间 now 是否处于锁定状态
+func (s State) Locked(now time.Time) bool {
+	return now.Before(s.LockedUntil)
+}
+
+// Store 登录失败计数存储接口
+// 单节点部署使用内存存储，多节点部署需使用 Redis 兼容存储以共享计数
+type Store interface {
+	// Get 获取键的当前状态，不存在时返回零值
+	Get(ctx context.Context, key string) (State, error)
+	// AddFailure 失败次数加一并返回新的次数，最后一次失败后超过 window 未再失败时计数清零
+	AddFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	// LockUntil 锁定键直到 until
+	LockUntil(ctx context.Context, key string, until time.Time) error
+	// Reset 清除失败计数和锁定
+	Reset(ctx context.Context, key string) error
+}
+
+// NewStore 根据配置创建失败计数存储
+func NewStore(driver string, redisCfg config.RedisConfig) (Store, error) {
+	switch driver {
+	case "", StoreDriverMemory:
+		return NewMemoryStore(), nil
+	case StoreDriverRedis:
+		return NewRedisStore(redisCfg)
+	default:
+		return nil, fmt.Errorf("不支持的登录失败计数存储: %s", driver)
+	}
+}
@@ -0,0 +1,101 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval 清理过期条目的最小间隔
+const memorySweepInterval = time.Minute
+
+type memoryEntry struct {
+	state     State
+	expiresAt time.Time // 失败计数过期时间
+}
+
+// MemoryStore 进程内存储（仅适用于单节点部署，重启后计数清零）
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]*memoryEntry)}
+}
+
+// Get 获取键的当前状态
+func (s *MemoryStore) Get(ctx context.Context, key string) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	entry, ok := s.entries[key]
+	if !ok {
+		return State{}, nil
+	}
+	state := entry.state
+	if now.After(entry.expiresAt) {
+		state.Failures = 0
+	}
+	return state, nil
+}
+
+// AddFailure 失败次数加一
+func (s *MemoryStore) AddFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &memoryEntry{}
+		s.entries[key] = entry
+	}
+	if now.After(entry.expiresAt) {
+		entry.state.Failures = 0
+	}
+	entry.state.Failures++
+	entry.expiresAt = now.Add(window)
+	return entry.state.Failures, nil
+}
+
+// LockUntil 锁定键直到 until
+func (s *MemoryStore) LockUntil(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &memoryEntry{expiresAt: time.Now()}
+		s.entries[key] = entry
+	}
+	entry.state.LockedUntil = until
+	return nil
+}
+
+// Reset 清除失败计数和锁定
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// sweep 按间隔删除计数和锁定都已过期的条目，避免大量随机用户名撑大内存（调用方需持有锁）
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) && !entry.state.Locked(now) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package lockout
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultRedisPrefix 未配置 redis.prefix 时的键前缀
+const defaultRedisPrefix = "bug-bounty-lite:"
+
+// RedisStore Redis 兼容存储（Redis、Valkey、KeyDB 等），多节点共享失败计数
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore 创建 Redis 存储（不在创建时连接，可调用 Ping 检查连通性）
+func NewRedisStore(cfg config.RedisConfig) (*RedisStore, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("未配置 redis.addr")
+	}
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = defaultRedisPrefix
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	return &RedisStore{client: client, prefix: prefix + "login:"}, nil
+}
+
+// Ping 检查 Redis 是否可用
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+// Get 获取键的当前状态
+func (s *RedisStore) Get(ctx context.Context, key string) (State, error) {
+	values, err := s.client.MGet(ctx, s.failKey(key), s.lockKey(key)).Result()
+	if err != nil {
+		return State{}, err
+	}
+
+	var state State
+	if v, ok := values[0].(string); ok {
+		state.Failures, _ = strconv.Atoi(v)
+	}
+	if v, ok := values[1].(string); ok {
+		if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+			state.LockedUntil = time.UnixMilli(ms)
+		}
+	}
+	return state, nil
+}
+
+// AddFailure 失败次数加一，并将计数的过期时间顺延 window
+func (s *RedisStore) AddFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	var incr *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, s.failKey(key))
+		pipe.PExpire(ctx, s.failKey(key), window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(incr.Val()), nil
+}
+
+// LockUntil 锁定键直到 until，锁定记录到期后由 Redis 自动删除
+func (s *RedisStore) LockUntil(ctx context.Context, key string, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.client.Set(ctx, s.lockKey(key), strconv.FormatInt(until.UnixMilli(), 10), ttl).Err()
+}
+
+// Reset 清除失败计数和锁定
+func (s *RedisStore) Reset(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.failKey(key), s.lockKey(key)).Err()
+}
+
+func (s *RedisStore) failKey(key string) string {
+	return s.prefix + "fail:" + key
+}
+
+func (s *RedisStore) lockKey(key string) string {
+	return s.prefix + "lock:" + key
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"fmt"
+	"log"
+)
+
+// 发信驱动
+const (
+	DriverLog  = "log"
+	DriverSMTP = "smtp"
+)
+
+// Message 纯文本邮件
+type Message struct {
+	To      string // 收件人地址
+	Subject string
+	Body    string
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	// Send 发送邮件，发信服务不可用或收件人被拒绝时返回 error
+	Send(ctx context.Context, msg *Message) error
+}
+
+// NewMailer 根据配置创建邮件发送器
+func NewMailer(cfg config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "", DriverLog:
+		return NewLogMailer(), nil
+	case DriverSMTP:
+		return NewSMTPMailer(cfg)
+	default:
+		return nil, fmt.Errorf("不支持的发信驱动: %s", cfg.Driver)
+	}
+}
+
+// logMailer 只把邮件内容打印到日志，不真正发送（本地开发、未配置发信服务时使用）
+type logMailer struct{}
+
+// NewLogMailer 创建只打印日志的邮件发送器
+func NewLogMailer() Mailer {
+	return logMailer{}
+}
+
+// Send 打印邮件内容（包含链接和令牌，不要在生产环境使用）
+func (logMailer) Send(ctx context.Context, msg *Message) error {
+	log.Printf("[MAIL] To: %s | Subject: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,210 @@
+package mailer
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP 连接加密方式
+const (
+	EncryptionAuto     = ""         // 服务器支持时使用 STARTTLS
+	EncryptionNone     = "none"     // 明文（MailHog 等本地测试服务）
+	EncryptionSTARTTLS = "starttls" // 必须使用 STARTTLS（通常为 587 端口）
+	EncryptionTLS      = "tls"      // 直接 TLS 连接（通常为 465 端口）
+)
+
+// defaultSMTPTimeout 默认单封邮件发送超时
+const defaultSMTPTimeout = 30 * time.Second
+
+// SMTPMailer 通过 SMTP 发送邮件
+type SMTPMailer struct {
+	host       string
+	addr       string
+	username   string
+	password   string
+	encryption string
+	from       *mail.Address
+	timeout    time.Duration
+}
+
+// NewSMTPMailer 创建 SMTP 邮件发送器（不在创建时连接，发信服务暂不可用不影响服务启动）
+func NewSMTPMailer(cfg config.MailConfig) (*SMTPMailer, error) {
+	if cfg.SMTP.Host == "" {
+		return nil, errors.New("未配置 mail.smtp.host")
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("发件人地址无效: %v", err)
+	}
+
+	encryption := strings.ToLower(cfg.SMTP.Encryption)
+	switch encryption {
+	case EncryptionAuto, EncryptionNone, EncryptionSTARTTLS, EncryptionTLS:
+	default:
+		return nil, fmt.Errorf("不支持的 SMTP 加密方式: %s", cfg.SMTP.Encryption)
+	}
+
+	port := cfg.SMTP.Port
+	if port == 0 {
+		port = 25
+		if encryption == EncryptionTLS {
+			port = 465
+		}
+	}
+	timeout := defaultSMTPTimeout
+	if cfg.SMTP.Timeout > 0 {
+		timeout = time.Duration(cfg.SMTP.Timeout) * time.Second
+	}
+
+	return &SMTPMailer{
+		host:       cfg.SMTP.Host,
+		addr:       net.JoinHostPort(cfg.SMTP.Host, strconv.Itoa(port)),
+		username:   cfg.SMTP.Username,
+		password:   cfg.SMTP.Password,
+		encryption: encryption,
+		from:       from,
+		timeout:    timeout,
+	}, nil
+}
+
+// Send 连接 SMTP 服务器发送一封邮件
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("收件人地址无效: %v", err)
+	}
+	data, err := m.buildMessage(to, msg)
+	if err != nil {
+		return err
+	}
+
+	conn, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP 握手失败: %v", err)
+	}
+	defer client.Close()
+
+	if m.encryption == EncryptionAuto || m.encryption == EncryptionSTARTTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+				return fmt.Errorf("STARTTLS 失败: %v", err)
+			}
+		} else if m.encryption == EncryptionSTARTTLS {
+			return errors.New("SMTP 服务器不支持 STARTTLS")
+		}
+	}
+
+	if m.username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			// PlainAuth 只允许在加密连接或 localhost 上发送密码
+			if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+				return fmt.Errorf("SMTP 认证失败: %v", err)
+			}
+		}
+	}
+
+	if err := client.Mail(m.from.Address); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM 失败: %v", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("SMTP 收件人被拒绝: %v", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA 失败: %v", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("发送邮件内容失败: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("发送邮件内容失败: %v", err)
+	}
+	return client.Quit()
+}
+
+// dial 连接 SMTP 服务器，连接的读写截止时间为发送超时
+func (m *SMTPMailer) dial(ctx context.Context) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	var conn net.Conn
+	var err error
+	if m.encryption == EncryptionTLS {
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: m.host}}
+		conn, err = dialer.DialContext(ctx, "tcp", m.addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", m.addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("连接 SMTP 服务器失败: %v", err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(m.timeout))
+	return conn, nil
+}
+
+// buildMessage 组装邮件头和 quoted-printable 编码的 UTF-8 正文
+func (m *SMTPMailer) buildMessage(to *mail.Address, msg *Message) ([]byte, error) {
+	messageID, err := newMessageID(m.from.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	headers := [][2]string{
+		{"From", m.from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", msg.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", messageID},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=UTF-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", h[0], h[1])
+	}
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	if _, err := qp.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// newMessageID 生成 Message-ID，域名部分取发件人地址的域名
+func newMessageID(from string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 && at < len(from)-1 {
+		domain = from[at+1:]
+	}
+	return "<" + hex.EncodeToString(buf) + "@" + domain + ">", nil
+}
@@ -0,0 +1,74 @@
+//go:build integration
+
+package mailer
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// mailhogSearchResult MailHog v2 搜索接口的返回（只取用到的字段）
+type mailhogSearchResult struct {
+	Total int `json:"total"`
+	Items []struct {
+		Raw struct {
+			Data string `json:"Data"`
+		} `json:"Raw"`
+	} `json:"items"`
+}
+
+// 需要运行中的 MailHog（或兼容 API 的 Mailpit 等），例如：
+//
+//	docker run -d -p 1025:1025 -p 8025:8025 mailhog/mailhog
+//	MAILHOG_SMTP_ADDR=127.0.0.1:1025 MAILHOG_API_URL=http://127.0.0.1:8025 go test -tags integration ./pkg/mailer/
+func TestSMTPMailerMailHogIntegration(t *testing.T) {
+	smtpAddr := os.Getenv("MAILHOG_SMTP_ADDR")
+	apiURL := os.Getenv("MAILHOG_API_URL")
+	if smtpAddr == "" || apiURL == "" {
+		t.Skip("MAILHOG_SMTP_ADDR 或 MAILHOG_API_URL 未设置，跳过 MailHog 集成测试")
+	}
+	host, port, err := net.SplitHostPort(smtpAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	portNum, _ := strconv.Atoi(port)
+
+	m, err := NewSMTPMailer(config.MailConfig{
+		Driver: DriverSMTP,
+		From:   "Bug Bounty Lite <no-reply@example.com>",
+		SMTP:   config.SMTPMailConfig{Host: host, Port: portNum, Encryption: EncryptionNone},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 每次使用不同的收件人，避免读到之前测试留下的邮件
+	to := fmt.Sprintf("it-%d@example.com", time.Now().UnixNano())
+	body := "你好：\n请点击链接 https://example.com/reset?token=abc=123 重置密码。"
+	if err := m.Send(context.Background(), &Message{To: to, Subject: "重置密码", Body: body}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	resp, err := http.Get(apiURL + "/api/v2/search?kind=to&query=" + url.QueryEscape(to))
+	if err != nil {
+		t.Fatalf("查询 MailHog 失败: %v", err)
+	}
+	defer resp.Body.Close()
+	var result mailhogSearchResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("解析 MailHog 返回失败: %v", err)
+	}
+	if result.Total != 1 || len(result.Items) != 1 {
+		t.Fatalf("MailHog 收到 %d 封邮件，want 1", result.Total)
+	}
+	assertMessage(t, result.Items[0].Raw.Data, "重置密码", "请点击链接 https://example.com/reset?token=abc=123 重置密码。")
+}
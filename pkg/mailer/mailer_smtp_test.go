@@ -0,0 +1,217 @@
+package mailer
+
+import (
+	"bufio"
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer 本地 SMTP 收信服务（类似 MailHog），记录收到的邮件，不支持 STARTTLS 和 AUTH
+type fakeSMTPServer struct {
+	listener net.Listener
+	rejected string // 拒收的收件人地址
+	messages chan receivedMail
+}
+
+// receivedMail 收到的邮件
+type receivedMail struct {
+	from string
+	to   []string
+	data string
+}
+
+func newFakeSMTPServer(t *testing.T, rejected string) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSMTPServer{listener: listener, rejected: rejected, messages: make(chan receivedMail, 1)}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+// config 连接本服务的发信配置
+func (s *fakeSMTPServer) config() config.MailConfig {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	return config.MailConfig{
+		Driver: DriverSMTP,
+		From:   "Bug Bounty Lite <no-reply@example.com>",
+		SMTP:   config.SMTPMailConfig{Host: host, Port: portNum, Encryption: EncryptionNone, Timeout: 5},
+	}
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	var current receivedMail
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 fake")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			current = receivedMail{from: strings.Trim(line[len("MAIL FROM:"):], "<> ")}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			rcpt := strings.Trim(line[len("RCPT TO:"):], "<> ")
+			if rcpt == s.rejected {
+				reply("550 mailbox unavailable")
+				continue
+			}
+			current.to = append(current.to, rcpt)
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			current.data = data.String()
+			s.messages <- current
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestNewSMTPMailer(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.MailConfig
+		wantAddr string
+		wantErr  bool
+	}{
+		{name: "默认 25 端口", cfg: config.MailConfig{From: "a@example.com", SMTP: config.SMTPMailConfig{Host: "smtp.example.com"}}, wantAddr: "smtp.example.com:25"},
+		{name: "TLS 默认 465 端口", cfg: config.MailConfig{From: "a@example.com", SMTP: config.SMTPMailConfig{Host: "smtp.example.com", Encryption: "TLS"}}, wantAddr: "smtp.example.com:465"},
+		{name: "指定端口", cfg: config.MailConfig{From: "a@example.com", SMTP: config.SMTPMailConfig{Host: "smtp.example.com", Port: 587, Encryption: "starttls"}}, wantAddr: "smtp.example.com:587"},
+		{name: "未配置主机", cfg: config.MailConfig{From: "a@example.com"}, wantErr: true},
+		{name: "发件人无效", cfg: config.MailConfig{From: "not an address", SMTP: config.SMTPMailConfig{Host: "smtp.example.com"}}, wantErr: true},
+		{name: "不支持的加密方式", cfg: config.MailConfig{From: "a@example.com", SMTP: config.SMTPMailConfig{Host: "smtp.example.com", Encryption: "ssl"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewSMTPMailer(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSMTPMailer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && m.addr != tt.wantAddr {
+				t.Errorf("addr = %s, want %s", m.addr, tt.wantAddr)
+			}
+		})
+	}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	server := newFakeSMTPServer(t, "blocked@example.com")
+
+	tests := []struct {
+		name       string
+		encryption string
+		to         string
+		wantErr    bool
+	}{
+		{name: "明文发送", encryption: EncryptionNone, to: "Alice <alice@example.com>"},
+		{name: "自动加密时服务器不支持 STARTTLS 回退明文", encryption: EncryptionAuto, to: "alice@example.com"},
+		{name: "强制 STARTTLS 时服务器不支持", encryption: EncryptionSTARTTLS, to: "alice@example.com", wantErr: true},
+		{name: "收件人被拒绝", encryption: EncryptionNone, to: "blocked@example.com", wantErr: true},
+		{name: "收件人地址无效", encryption: EncryptionNone, to: "not an address", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := server.config()
+			cfg.SMTP.Encryption = tt.encryption
+			m, err := NewSMTPMailer(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = m.Send(context.Background(), &Message{To: tt.to, Subject: "验证邮箱", Body: "你好：\n请点击链接 https://example.com/verify?token=abc=123 完成验证。"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var received receivedMail
+			select {
+			case received = <-server.messages:
+			case <-time.After(5 * time.Second):
+				t.Fatal("没有收到邮件")
+			}
+			if received.from != "no-reply@example.com" || len(received.to) != 1 || received.to[0] != "alice@example.com" {
+				t.Errorf("envelope = %s -> %v", received.from, received.to)
+			}
+			assertMessage(t, received.data, "验证邮箱", "请点击链接 https://example.com/verify?token=abc=123 完成验证。")
+		})
+	}
+}
+
+// assertMessage 解析邮件并检查主题和正文（解码后）
+func assertMessage(t *testing.T, data, wantSubject, wantBodyPart string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("mail.ReadMessage() error = %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != wantSubject {
+		t.Errorf("Subject = %q, want %q", subject, wantSubject)
+	}
+	if msg.Header.Get("Message-ID") == "" {
+		t.Error("Message-ID 为空")
+	}
+	if got := msg.Header.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+		t.Fatalf("Content-Transfer-Encoding = %q", got)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatalf("读取正文失败: %v", err)
+	}
+	if !strings.Contains(string(body), wantBodyPart) {
+		t.Errorf("body = %q, want contains %q", body, wantBodyPart)
+	}
+}
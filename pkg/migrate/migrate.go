@@ -2,8 +2,11 @@ package migrate
 
 import (
 	"bug-bounty-lite/internal/domain"
+	"bug-bounty-lite/pkg/upload"
+	"context"
 	"fmt"
 	"log"
+	"path"
 	"time"
 
 	"gorm.io/gorm"
@@ -11,12 +14,13 @@ import (
 
 // Migrator 数据库迁移器
 type Migrator struct {
-	db *gorm.DB
+	db      *gorm.DB
+	storage upload.Storage // 上传文件存储，为空时迁移附件不补全大小和 SHA-256
 }
 
 // NewMigrator 创建迁移器实例
-func NewMigrator(db *gorm.DB) *Migrator {
-	return &Migrator{db: db}
+func NewMigrator(db *gorm.DB, storage upload.Storage) *Migrator {
+	return &Migrator{db: db, storage: storage}
 }
 
 // Run 执行数据库迁移
@@ -46,6 +50,29 @@ func (m *Migrator) Run() error {
 		&domain.ProjectAssignment{}, // 项目指派记录
 		&domain.ProjectTask{},       // 项目任务记录
 		&domain.ProjectAttachment{}, // 项目附件
+		&domain.ReportWorkflow{},    // 报告状态工作流
+		&domain.ReportEvent{},       // 报告审计事件
+		&domain.ReportSimilarity{},  // 报告疑似重复记录
+		&domain.BountyReward{},      // 报告奖励
+		&domain.BountyLedgerEntry{}, // 奖励账本
+		&domain.PayoutBatch{},       // 奖励发放批次
+		&domain.ProjectScope{},      // 项目范围资产
+		&domain.Notification{},      // 站内通知
+		&domain.ReportAttachment{},  // 报告附件
+		&domain.UploadSession{},     // 大文件分片上传会话
+		&domain.UserSession{},       // 登录会话
+		&domain.RefreshToken{},      // 刷新令牌
+		&domain.RevokedToken{},      // 已吊销的访问令牌
+		&domain.UserTwoFactor{},     // 两步验证配置
+		&domain.RecoveryCode{},      // 两步验证恢复码
+		&domain.LoginChallenge{},    // 两步验证登录挑战
+		&domain.SecuritySetting{},   // 安全设置
+		&domain.AccountToken{},      // 邮箱验证与重置密码令牌
+		&domain.SecurityEvent{},     // 安全事件日志
+		&domain.PasswordHistory{},   // 历史密码
+		&domain.UserIdentity{},      // 外部身份关联
+		&domain.OIDCLoginState{},    // 单点登录状态
+		&domain.APIKey{},            // 个人 API Key
 	)
 
 	if err != nil {
@@ -59,6 +86,15 @@ func (m *Migrator) Run() error {
 	// 删除 reports 表的外键约束（改用代码逻辑验证）
 	m.dropForeignKeys()
 
+	// 统一历史报告状态到工作流状态
+	m.normalizeReportStatuses()
+
+	// 将报告的单个附件地址迁移到报告附件表
+	m.migrateReportAttachments()
+
+	// 将历史附件地址迁移到受控下载地址
+	m.migrateUploadURLs()
+
 	// 添加表注释 (MySQL)
 	m.addTableComments()
 
@@ -174,6 +210,115 @@ func (m *Migrator) dropForeignKeys() {
 	}
 }
 
+// normalizeReportStatuses 将旧版审核工具写入的状态统一为工作流状态
+// 旧版 review-reports 使用 Audited 表示审核通过，对应工作流中的 Triaged
+func (m *Migrator) normalizeReportStatuses() {
+	result := m.db.Model(&domain.Report{}).Unscoped().
+		Where("status = ?", "Audited").
+		Update("status", domain.ReportStatusTriaged)
+	if result.Error != nil {
+		log.Printf("[WARN] Failed to normalize report statuses: %v", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		fmt.Printf("[OK] Normalized %d report(s) from Audited to %s\n", result.RowsAffected, domain.ReportStatusTriaged)
+	}
+}
+
+// migrateReportAttachments 将 reports.attachment_url 中的历史附件迁移为 report_attachments 记录
+// 迁移后清空原字段，重复执行不会产生重复记录；文件大小和 SHA-256 尽量从存储中读取补全，读取失败时留空
+func (m *Migrator) migrateReportAttachments() {
+	if !m.db.Migrator().HasColumn("reports", "attachment_url") {
+		return
+	}
+
+	var rows []struct {
+		ID            uint
+		AuthorID      uint
+		AttachmentURL string
+		CreatedAt     time.Time
+	}
+	if err := m.db.Table("reports").
+		Select("id, author_id, attachment_url, created_at").
+		Where("attachment_url IS NOT NULL AND attachment_url <> ''").
+		Find(&rows).Error; err != nil {
+		log.Printf("[WARN] Failed to query report attachment URLs: %v", err)
+		return
+	}
+
+	migrated := 0
+	for _, row := range rows {
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			attachment := domain.ReportAttachment{
+				CreatedAt:  row.CreatedAt,
+				ReportID:   row.ID,
+				Filename:   path.Base(row.AttachmentURL),
+				URL:        row.AttachmentURL,
+				MimeType:   upload.ContentTypeForKey(row.AttachmentURL),
+				UploadedBy: row.AuthorID,
+			}
+			attachment.SHA256, attachment.FileSize = m.hashAttachment(row.AttachmentURL)
+			if err := tx.Create(&attachment).Error; err != nil {
+				return err
+			}
+			return tx.Table("reports").Where("id = ?", row.ID).Update("attachment_url", "").Error
+		})
+		if err != nil {
+			log.Printf("[WARN] Failed to migrate attachment of report %d: %v", row.ID, err)
+			continue
+		}
+		migrated++
+	}
+	if migrated > 0 {
+		fmt.Printf("[OK] Migrated %d report attachment URL(s) to report_attachments\n", migrated)
+	}
+}
+
+// hashAttachment 读取历史附件计算 SHA-256 和文件大小，存储未配置或文件无法读取时返回空
+func (m *Migrator) hashAttachment(fileURL string) (string, int64) {
+	if m.storage == nil {
+		return "", 0
+	}
+	key, ok := upload.FilePathFromURL(fileURL)
+	if !ok {
+		return "", 0
+	}
+	sum, size, err := upload.HashObject(context.Background(), m.storage, key)
+	if err != nil {
+		log.Printf("[WARN] Failed to hash attachment %s: %v", fileURL, err)
+		return "", 0
+	}
+	return sum, size
+}
+
+// migrateUploadURLs 将报告和项目附件的 /uploads/ 静态地址改写为 /api/v1/files/ 受控下载地址
+// 静态目录只保留头像，旧地址迁移后才能继续访问
+func (m *Migrator) migrateUploadURLs() {
+	targets := []struct {
+		model  interface{}
+		column string
+		dir    string
+	}{
+		{&domain.ReportAttachment{}, "url", domain.FileDirReports},
+		{&domain.ProjectAttachment{}, "url", domain.FileDirProjects},
+	}
+
+	for _, t := range targets {
+		oldPrefix := "/uploads/" + t.dir + "/"
+		newPrefix := upload.FileRoutePrefix + t.dir + "/"
+		result := m.db.Model(t.model).Unscoped().
+			Where(t.column+" LIKE ?", "%"+oldPrefix+"%").
+			Update(t.column, gorm.Expr("REPLACE("+t.column+", ?, ?)", oldPrefix, newPrefix))
+		if result.Error != nil {
+			log.Printf("[WARN] Failed to migrate upload URLs in %s: %v", t.column, result.Error)
+			continue
+		}
+		if result.RowsAffected > 0 {
+			fmt.Printf("[OK] Migrated %d %s upload URL(s) to %s\n", result.RowsAffected, t.dir, newPrefix)
+		}
+	}
+}
+
 // addTableComments 添加表级别注释 (MySQL)
 func (m *Migrator) addTableComments() {
 	tableComments := map[string]string{
@@ -184,6 +329,29 @@ func (m *Migrator) addTableComments() {
 		"system_configs":            "系统配置表 - 存储各类系统配置信息（漏洞类型、危害等级等），支持通过config_type区分不同类型的配置",
 		"organizations":             "组织管理表 - 存储机构、部门等组织架构信息",
 		"user_update_logs":          "用户修改记录表 - 存储用户关键信息（如简介、组织绑定）的变更审计日志",
+		"report_workflows":          "报告工作流表 - 存储报告状态、流转规则、角色权限及必填字段，支持按项目配置",
+		"report_events":             "报告事件表 - 记录报告每次变更的操作人、变更前后的值及原因，只追加不修改",
+		"report_similarities":       "报告疑似重复表 - 记录报告提交时与同项目已有报告的相似度，供审核人员判断重复",
+		"bounty_rewards":            "报告奖励表 - 存储报告的奖金和额外奖励、金额币种及发放状态",
+		"bounty_ledger_entries":     "奖励账本表 - 记录奖励的新增、状态变更和打包发放，只追加不修改",
+		"payout_batches":            "奖励发放批次表 - 存储管理员导出的发放批次及打款确认信息",
+		"notifications":             "站内通知表 - 存储发送给用户的通知（如项目指派），记录阅读时间",
+		"report_attachments":        "报告附件表 - 存储报告的多个附件及文件名、大小、MIME类型、SHA-256和上传人",
+		"upload_sessions":           "分片上传会话表 - 存储大文件分片上传的进度、哈希中间状态和过期时间，支持断点续传",
+		"user_sessions":             "登录会话表 - 存储每次登录的设备、User-Agent、IP和最近活动时间，会话注销后其令牌全部失效",
+		"refresh_tokens":            "刷新令牌表 - 存储刷新令牌的哈希、所属登录会话、过期和吊销时间，每次刷新轮换",
+		"revoked_tokens":            "访问令牌吊销表 - 存储注销时吊销的访问令牌ID(jti)，令牌过期后清理",
+		"user_two_factors":          "两步验证表 - 存储用户的TOTP密钥、启用状态和最近使用的时间步（防止验证码重放）",
+		"two_factor_recovery_codes": "两步验证恢复码表 - 存储恢复码的SHA-256哈希和使用时间，每个恢复码只能使用一次",
+		"login_challenges":          "登录挑战表 - 存储密码验证通过后等待两步验证的挑战令牌哈希、错误次数和过期时间",
+		"security_settings":         "安全设置表 - 存储管理员在运行时修改的安全策略（如必须启用两步验证的角色）",
+		"security_events":           "安全事件表 - 记录登录失败锁定、管理员解除锁定等安全事件，只追加不修改",
+		"password_histories":        "历史密码表 - 保存用户最近使用过的密码哈希，用于禁止重复使用",
+		"user_identities":           "外部身份表 - 记录用户与 OIDC 身份提供方账号(provider + sub)的关联",
+		"oidc_login_states":         "单点登录状态表 - 跳转身份提供方前保存的 state、nonce 和 PKCE 校验码，回调时使用一次即删除",
+		"api_keys":                  "API Key表 - 存储用户个人 API Key 的SHA-256哈希、授权范围、过期时间、最近使用和吊销时间，明文只在创建时返回一次",
+		"account_tokens":            "账号令牌表 - 存储通过邮件发送的邮箱验证和重置密码令牌的哈希，一次性使用，过期后清理",
+		"project_scopes":            "项目范围表 - 存储项目范围内外的资产（域名/通配域名/URL前缀/IP网段/应用/仓库），用于校验报告的漏洞链接",
 	}
 
 	for table, comment := range tableComments {
@@ -275,11 +443,6 @@ func (m *Migrator) addColumnComments() {
 			column:  "vulnerability_detail",
 			comment: "漏洞详情(文本输入，详细描述漏洞情况)",
 		},
-		{
-			table:   "reports",
-			column:  "attachment_url",
-			comment: "附件地址(文件上传后的URL，单个文件，后续可扩展为多个)",
-		},
 		// users 表新增字段
 		{
 			table:   "users",
@@ -0,0 +1,231 @@
+package oidc
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// defaultUsernameClaim 新建账号时默认用作用户名的声明
+	defaultUsernameClaim = "preferred_username"
+	// defaultGroupsClaim 默认用于映射角色和组织的声明
+	defaultGroupsClaim = "groups"
+	// httpTimeout 访问发现文档、JWKS 和令牌端点的超时
+	httpTimeout = 15 * time.Second
+)
+
+// providerNamePattern 身份提供方标识，出现在接口路径中
+var providerNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// validRoles 映射中允许配置的角色
+var validRoles = map[string]bool{
+	"whitehat": true,
+	"vendor":   true,
+	"admin":    true,
+}
+
+// Claims 从 ID 令牌中取出的用户信息
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Username      string   // username_claim 的值
+	Groups        []string // groups_claim 的值
+}
+
+// Provider OIDC 身份提供方客户端（授权码模式 + PKCE）
+// 首次使用时才读取发现文档，身份提供方暂不可用不影响服务启动，读取失败时下次使用再重试
+type Provider struct {
+	cfg    config.OIDCProviderConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+// NewProviders 根据配置创建全部身份提供方，配置无效或标识重复时返回错误
+func NewProviders(cfgs []config.OIDCProviderConfig) ([]*Provider, error) {
+	seen := make(map[string]bool)
+	providers := make([]*Provider, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		p, err := NewProvider(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if seen[p.Name()] {
+			return nil, fmt.Errorf("OIDC 身份提供方标识重复: %s", p.Name())
+		}
+		seen[p.Name()] = true
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+// NewProvider 校验配置并创建身份提供方客户端（不在创建时连接）
+func NewProvider(cfg config.OIDCProviderConfig) (*Provider, error) {
+	if !providerNamePattern.MatchString(cfg.Name) {
+		return nil, fmt.Errorf("OIDC 身份提供方标识无效: %q", cfg.Name)
+	}
+	if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return nil, fmt.Errorf("OIDC 身份提供方 %s 缺少 issuer、client_id 或 redirect_url", cfg.Name)
+	}
+	if cfg.DefaultRole != "" && !validRoles[cfg.DefaultRole] {
+		return nil, fmt.Errorf("OIDC 身份提供方 %s 的 default_role 无效: %s", cfg.Name, cfg.DefaultRole)
+	}
+	for _, m := range cfg.GroupMappings {
+		if m.Group == "" {
+			return nil, fmt.Errorf("OIDC 身份提供方 %s 的 group_mappings 缺少 group", cfg.Name)
+		}
+		if m.Role != "" && !validRoles[m.Role] {
+			return nil, fmt.Errorf("OIDC 身份提供方 %s 的映射角色无效: %s", cfg.Name, m.Role)
+		}
+	}
+
+	if cfg.DisplayName == "" {
+		cfg.DisplayName = cfg.Name
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{gooidc.ScopeOpenID, "profile", "email"}
+	}
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = defaultUsernameClaim
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = defaultGroupsClaim
+	}
+	return &Provider{cfg: cfg, client: &http.Client{Timeout: httpTimeout}}, nil
+}
+
+// Name 身份提供方标识
+func (p *Provider) Name() string {
+	return p.cfg.Name
+}
+
+// Config 补全默认值后的配置
+func (p *Provider) Config() config.OIDCProviderConfig {
+	return p.cfg
+}
+
+// AuthCodeURL 生成跳转到身份提供方的授权地址，verifier 为 PKCE 校验码（只发送 S256 摘要）
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	oauth, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return oauth.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange 使用授权码和 PKCE 校验码换取令牌，校验 ID 令牌的签名、发行方、受众、有效期和 nonce 后返回用户信息
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	oauth, idVerifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = p.clientContext(ctx)
+	token, err := oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("换取令牌失败: %v", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("令牌响应中没有 id_token")
+	}
+	idToken, err := idVerifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("ID 令牌校验失败: %v", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("ID 令牌 nonce 不匹配")
+	}
+
+	var raw map[string]interface{}
+	if err := idToken.Claims(&raw); err != nil {
+		return nil, fmt.Errorf("解析 ID 令牌失败: %v", err)
+	}
+	return &Claims{
+		Subject:       idToken.Subject,
+		Email:         stringClaim(raw, "email"),
+		EmailVerified: boolClaim(raw, "email_verified"),
+		Name:          stringClaim(raw, "name"),
+		Username:      stringClaim(raw, p.cfg.UsernameClaim),
+		Groups:        stringsClaim(raw, p.cfg.GroupsClaim),
+	}, nil
+}
+
+// discover 读取发现文档并缓存 OAuth2 配置和 ID 令牌校验器
+func (p *Provider) discover(ctx context.Context) (*oauth2.Config, *gooidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.oauth != nil {
+		return p.oauth, p.verifier, nil
+	}
+
+	// 之后按需刷新 JWKS 时 go-oidc 只沿用这里的 HTTP 客户端，不受请求 ctx 取消的影响
+	provider, err := gooidc.NewProvider(p.clientContext(ctx), p.cfg.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取 OIDC 发现文档失败: %v", err)
+	}
+	p.oauth = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       p.cfg.Scopes,
+	}
+	p.verifier = provider.Verifier(&gooidc.Config{ClientID: p.cfg.ClientID})
+	return p.oauth, p.verifier, nil
+}
+
+// clientContext 让 go-oidc 和 oauth2 使用带超时的 HTTP 客户端
+func (p *Provider) clientContext(ctx context.Context) context.Context {
+	return gooidc.ClientContext(ctx, p.client)
+}
+
+// stringClaim 读取字符串声明，不存在或类型不符时返回空字符串
+func stringClaim(raw map[string]interface{}, name string) string {
+	s, _ := raw[name].(string)
+	return strings.TrimSpace(s)
+}
+
+// boolClaim 读取布尔声明，兼容部分身份提供方返回的 "true" 字符串
+func boolClaim(raw map[string]interface{}, name string) bool {
+	switch v := raw[name].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+	return false
+}
+
+// stringsClaim 读取字符串或字符串数组声明
+func stringsClaim(raw map[string]interface{}, name string) []string {
+	switch v := raw[name].(type) {
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+//go:build integration
+
+package oidc
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+)
+
+// 需要运行中的 mock-oauth2-server（授权端点不需要登录，直接重定向回授权码），例如：
+//
+//	docker run -d -p 8080:8080 ghcr.io/navikt/mock-oauth2-server:2.1.10
+//	OIDC_MOCK_ISSUER=http://127.0.0.1:8080/default go test -tags integration ./pkg/oidc/
+func TestProviderMockServerIntegration(t *testing.T) {
+	issuer := os.Getenv("OIDC_MOCK_ISSUER")
+	if issuer == "" {
+		t.Skip("OIDC_MOCK_ISSUER 未设置，跳过 OIDC 集成测试")
+	}
+	p, err := NewProvider(config.OIDCProviderConfig{
+		Name:         "mock",
+		Issuer:       issuer,
+		ClientID:     "bbl-client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://127.0.0.1/oidc/callback",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	verifier := "integration-test-verifier-0123456789abcdefghij"
+
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("请求授权端点失败: %v", err)
+	}
+	resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || location.Query().Get("code") == "" {
+		t.Fatalf("授权端点没有重定向回授权码: status = %d, Location = %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	if got := location.Query().Get("state"); got != "state-1" {
+		t.Errorf("state = %q, want state-1", got)
+	}
+
+	claims, err := p.Exchange(ctx, location.Query().Get("code"), verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if claims.Subject == "" {
+		t.Error("Exchange() subject is empty")
+	}
+}
@@ -0,0 +1,268 @@
+package oidc
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockIssuer 本地 OIDC 身份提供方：发现文档、JWKS 和令牌端点，授权码由测试预先登记
+type mockIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	codes  map[string]mockGrant
+}
+
+// mockGrant 授权码对应的 PKCE 校验码和 ID 令牌声明
+type mockGrant struct {
+	verifier string
+	claims   map[string]interface{}
+	signKey  *rsa.PrivateKey // 为空时使用身份提供方的密钥
+	noToken  bool            // 令牌响应中不返回 id_token
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+	m := &mockIssuer{key: newRSAKey(t), codes: make(map[string]mockGrant)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("/jwks", m.jwks)
+	mux.HandleFunc("/token", m.token)
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func (m *mockIssuer) discovery(w http.ResponseWriter, r *http.Request) {
+	issuer := m.server.URL
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"issuer":                                issuer,
+		"authorization_endpoint":                issuer + "/authorize",
+		"token_endpoint":                        issuer + "/token",
+		"jwks_uri":                              issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (m *mockIssuer) jwks(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": "test",
+			"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (m *mockIssuer) token(w http.ResponseWriter, r *http.Request) {
+	grant, ok := m.codes[r.PostFormValue("code")]
+	if !ok || r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("code_verifier") != grant.verifier {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+	resp := map[string]interface{}{"access_token": "access", "token_type": "Bearer", "expires_in": 3600}
+	if !grant.noToken {
+		key := grant.signKey
+		if key == nil {
+			key = m.key
+		}
+		resp["id_token"] = signJWT(key, grant.claims)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// signJWT 使用 RS256 签名 JWT
+func signJWT(key *rsa.PrivateKey, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": "test"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// idTokenClaims 合法的 ID 令牌声明，按需覆盖
+func (m *mockIssuer) idTokenClaims(overrides map[string]interface{}) map[string]interface{} {
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss":   m.server.URL,
+		"aud":   "bbl-client",
+		"sub":   "user-1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": "nonce-1",
+	}
+	for k, v := range overrides {
+		claims[k] = v
+	}
+	return claims
+}
+
+// provider 连接本身份提供方的客户端
+func (m *mockIssuer) provider(t *testing.T) *Provider {
+	t.Helper()
+	p, err := NewProvider(config.OIDCProviderConfig{
+		Name:         "mock",
+		Issuer:       m.server.URL,
+		ClientID:     "bbl-client",
+		ClientSecret: "secret",
+		RedirectURL:  "https://bbl.example.com/oidc/callback",
+		GroupsClaim:  "roles",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestProviderAuthCodeURL(t *testing.T) {
+	issuer := newMockIssuer(t)
+	p := issuer.provider(t)
+
+	authURL, err := p.AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(authURL, issuer.server.URL+"/authorize?") {
+		t.Errorf("AuthCodeURL() = %s, want authorization endpoint", authURL)
+	}
+	challenge := sha256.Sum256([]byte("verifier-1"))
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             "bbl-client",
+		"redirect_uri":          "https://bbl.example.com/oidc/callback",
+		"scope":                 "openid profile email",
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge_method": "S256",
+		"code_challenge":        base64.RawURLEncoding.EncodeToString(challenge[:]),
+	}
+	query := parsed.Query()
+	for key, value := range want {
+		if query.Get(key) != value {
+			t.Errorf("%s = %q, want %q", key, query.Get(key), value)
+		}
+	}
+	if query.Has("code_verifier") {
+		t.Error("授权地址不应包含 PKCE 校验码明文")
+	}
+}
+
+func TestProviderExchange(t *testing.T) {
+	issuer := newMockIssuer(t)
+	p := issuer.provider(t)
+
+	tests := []struct {
+		name     string
+		grant    mockGrant
+		code     string
+		verifier string
+		want     *Claims
+		wantErr  bool
+	}{
+		{
+			name: "正常登录",
+			grant: mockGrant{verifier: "v", claims: issuer.idTokenClaims(map[string]interface{}{
+				"email":              "alice@example.com",
+				"email_verified":     "true",
+				"name":               " Alice ",
+				"preferred_username": "alice",
+				"roles":              []interface{}{"security", "", 1},
+			})},
+			verifier: "v",
+			want: &Claims{
+				Subject:       "user-1",
+				Email:         "alice@example.com",
+				EmailVerified: true,
+				Name:          "Alice",
+				Username:      "alice",
+				Groups:        []string{"security"},
+			},
+		},
+		{
+			name:     "单个字符串组",
+			grant:    mockGrant{verifier: "v", claims: issuer.idTokenClaims(map[string]interface{}{"roles": "admins", "email_verified": false})},
+			verifier: "v",
+			want:     &Claims{Subject: "user-1", Groups: []string{"admins"}},
+		},
+		{name: "PKCE 校验码错误", grant: mockGrant{verifier: "v", claims: issuer.idTokenClaims(nil)}, verifier: "other", wantErr: true},
+		{name: "授权码无效", grant: mockGrant{verifier: "v", claims: issuer.idTokenClaims(nil)}, code: "unknown", verifier: "v", wantErr: true},
+		{name: "没有 id_token", grant: mockGrant{verifier: "v", noToken: true}, verifier: "v", wantErr: true},
+		{name: "nonce 不匹配", grant: mockGrant{verifier: "v", claims: issuer.idTokenClaims(map[string]interface{}{"nonce": "other"})}, verifier: "v", wantErr: true},
+		{name: "受众不匹配", grant: mockGrant{verifier: "v", claims: issuer.idTokenClaims(map[string]interface{}{"aud": "other-client"})}, verifier: "v", wantErr: true},
+		{name: "发行方不匹配", grant: mockGrant{verifier: "v", claims: issuer.idTokenClaims(map[string]interface{}{"iss": "https://evil.example.com"})}, verifier: "v", wantErr: true},
+		{name: "已过期", grant: mockGrant{verifier: "v", claims: issuer.idTokenClaims(map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()})}, verifier: "v", wantErr: true},
+		{name: "签名密钥不匹配", grant: mockGrant{verifier: "v", claims: issuer.idTokenClaims(nil), signKey: newRSAKey(t)}, verifier: "v", wantErr: true},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := "code-" + strconv.Itoa(i)
+			issuer.codes[code] = tt.grant
+			if tt.code != "" {
+				code = tt.code
+			}
+
+			got, err := p.Exchange(context.Background(), code, tt.verifier, "nonce-1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Exchange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(tt.want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("Exchange() = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestProviderDiscoveryRetry(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.server.Close()
+	p := issuer.provider(t)
+
+	// 身份提供方不可用时返回错误，不缓存失败结果
+	if _, err := p.AuthCodeURL(context.Background(), "s", "n", "v"); err == nil {
+		t.Fatal("AuthCodeURL() error = nil, want discovery error")
+	}
+	if p.oauth != nil {
+		t.Error("发现文档读取失败后不应缓存配置")
+	}
+}
@@ -0,0 +1,142 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret RFC 6238 附录 B 的 SHA-1 测试密钥 "12345678901234567890" 的 Base32 编码
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeRFC6238(t *testing.T) {
+	// RFC 6238 附录 B 的 8 位验证码取后 6 位
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(time.Unix(tt.unix, 0).UTC().Format(time.RFC3339), func(t *testing.T) {
+			got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+			if err != nil {
+				t.Fatalf("Code() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Code() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeSecretFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{name: "小写", secret: strings.ToLower(rfcSecret)},
+		{name: "带填充", secret: rfcSecret + "===="},
+		{name: "非法字符", secret: "NOT-BASE32!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Code(tt.secret, Step(time.Unix(59, 0)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Code() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != "287082" {
+				t.Errorf("Code() = %s, want 287082", got)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+	codeAt := func(step int64) string {
+		code, err := Code(rfcSecret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		wantOK   bool
+		wantStep int64
+	}{
+		{name: "当前时间步", code: codeAt(current), wantOK: true, wantStep: current},
+		{name: "前一个时间步", code: codeAt(current - 1), wantOK: true, wantStep: current - 1},
+		{name: "后一个时间步", code: codeAt(current + 1), wantOK: true, wantStep: current + 1},
+		{name: "首尾空白", code: " " + codeAt(current) + "\n", wantOK: true, wantStep: current},
+		{name: "超出偏差", code: codeAt(current - 2)},
+		{name: "位数错误", code: "12345"},
+		{name: "错误验证码", code: "000000"},
+		{name: "空验证码", code: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(rfcSecret, tt.code, now)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("Validate() = %d, %v, want %d, %v", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("len(secret) = %d, want 32", len(secret))
+	}
+	code, err := Code(secret, Step(time.Now()))
+	if err != nil {
+		t.Fatalf("Code() error = %v", err)
+	}
+	if _, ok := Validate(secret, code, time.Now()); !ok {
+		t.Error("Validate() rejected a freshly generated code")
+	}
+
+	other, _ := GenerateSecret()
+	if other == secret {
+		t.Error("GenerateSecret() returned the same secret twice")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Bug Bounty", "alice@example.com", rfcSecret)
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	if parsed.Scheme != "otpauth" || parsed.Host != "totp" {
+		t.Errorf("uri = %s, want otpauth://totp/...", uri)
+	}
+	if parsed.Path != "/Bug Bounty:alice@example.com" {
+		t.Errorf("label = %q", parsed.Path)
+	}
+	query := parsed.Query()
+	want := map[string]string{"secret": rfcSecret, "issuer": "Bug Bounty", "algorithm": "SHA1", "digits": "6", "period": "30"}
+	for key, value := range want {
+		if query.Get(key) != value {
+			t.Errorf("%s = %q, want %q", key, query.Get(key), value)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+)
+
+const (
+	// ChunkDir 分片上传临时分片目录（不可通过下载接口访问）
+	ChunkDir = "chunks"
+	// DefaultChunkSize 默认分片大小 8MB
+	DefaultChunkSize = 8 * 1024 * 1024
+	// MaxChunkedImageSize 分片上传图片的最大文件大小 50MB（合并时需要整体读入内存去除元数据和生成缩略图）
+	MaxChunkedImageSize = 50 * 1024 * 1024
+)
+
+// ChunkedMimeTypes 分片上传额外允许的 MIME 类型（录屏、压缩包和内存转储等二进制证据文件）
+var ChunkedMimeTypes = map[string]bool{
+	"video/mp4":                    true,
+	"video/webm":                   true,
+	"video/avi":                    true,
+	"application/zip":              true,
+	"application/x-gzip":           true,
+	"application/x-rar-compressed": true,
+}
+
+// ChunkedMimeAllowed 判断分片上传是否允许该类型（普通附件类型同样允许，均按检测到的类型判断，无法识别的二进制文件不允许）
+func ChunkedMimeAllowed(mimeType string) bool {
+	return AllowedMimeTypes[mimeType] || ChunkedMimeTypes[mimeType]
+}
+
+// ChunkKey 分片的存储键，如 chunks/{会话ID}/000003
+func ChunkKey(sessionID string, index int64) string {
+	return path.Join(ChunkDir, sessionID, fmt.Sprintf("%06d", index))
+}
+
+// chunkReader 按顺序读取会话的全部分片，读到某个分片时才打开它
+type chunkReader struct {
+	ctx       context.Context
+	storage   Storage
+	sessionID string
+	count     int64
+	index     int64
+	current   io.ReadCloser
+}
+
+// NewChunkReader 创建按顺序拼接会话分片的读取器，调用方负责关闭
+func NewChunkReader(ctx context.Context, storage Storage, sessionID string, count int64) io.ReadCloser {
+	return &chunkReader{ctx: ctx, storage: storage, sessionID: sessionID, count: count}
+}
+
+// Read 读取当前分片，读完后切换到下一个分片
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if r.index >= r.count {
+				return 0, io.EOF
+			}
+			reader, _, err := r.storage.Open(r.ctx, ChunkKey(r.sessionID, r.index))
+			if err != nil {
+				return 0, fmt.Errorf("读取分片 %d 失败: %v", r.index, err)
+			}
+			r.current = reader
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			r.index++
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+// Close 关闭当前打开的分片
+func (r *chunkReader) Close() error {
+	if r.current != nil {
+		return r.current.Close()
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package upload
+
+import (
+	"path"
+	"strings"
+)
+
+// DefaultContentType 无法识别的文件按二进制流下载
+const DefaultContentType = "application/octet-stream"
+
+// mimeExtensions 检测到的 MIME 类型对应的存储扩展名
+// 存储键的扩展名只由服务端检测的类型决定，不使用客户端文件名，避免 .html/.svg 等被浏览器按页面渲染
+var mimeExtensions = map[string]string{
+	"application/pdf":    ".pdf",
+	"image/jpeg":         ".jpg",
+	"image/jpg":          ".jpg",
+	"image/png":          ".png",
+	"image/gif":          ".gif",
+	"image/webp":         ".webp",
+	"application/msword": ".doc",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": ".docx",
+	"text/plain":                   ".txt",
+	"video/mp4":                    ".mp4",
+	"video/webm":                   ".webm",
+	"video/avi":                    ".avi",
+	"application/zip":              ".zip",
+	"application/x-gzip":           ".gz",
+	"application/x-rar-compressed": ".rar",
+}
+
+// extContentTypes 存储键扩展名对应的响应内容类型（固定映射，不依赖系统 MIME 表）
+var extContentTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".doc":  "application/msword",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".txt":  "text/plain; charset=utf-8",
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+	".avi":  "video/avi",
+	".zip":  "application/zip",
+	".gz":   "application/gzip",
+	".rar":  "application/vnd.rar",
+}
+
+// InlineContentTypes 可以在浏览器中直接显示的内容类型（栅格图片），其他类型一律作为附件下载
+var InlineContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// ExtensionForMime 返回检测到的 MIME 类型对应的存储扩展名，不在允许列表中时返回空
+func ExtensionForMime(mimeType string) string {
+	mediaType, _, _ := strings.Cut(mimeType, ";")
+	return mimeExtensions[strings.TrimSpace(mediaType)]
+}
+
+// ContentTypeForKey 根据存储键的扩展名返回内容类型，本地和 S3 存储统一使用，未知扩展名返回 application/octet-stream
+func ContentTypeForKey(key string) string {
+	if contentType, ok := extContentTypes[strings.ToLower(path.Ext(key))]; ok {
+		return contentType
+	}
+	return DefaultContentType
+}
@@ -0,0 +1,74 @@
+package upload
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContentKey(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name     string
+		mimeType string
+		want     string
+	}{
+		{name: "PDF", mimeType: "application/pdf", want: "reports/ab/" + sum + ".pdf"},
+		{name: "JPEG", mimeType: "image/jpeg", want: "reports/ab/" + sum + ".jpg"},
+		{name: "带参数的文本类型", mimeType: "text/plain; charset=utf-8", want: "reports/ab/" + sum + ".txt"},
+		{name: "HTML 不保留扩展名", mimeType: "text/html; charset=utf-8", want: "reports/ab/" + sum},
+		{name: "SVG 不保留扩展名", mimeType: "image/svg+xml", want: "reports/ab/" + sum},
+		{name: "未知类型", mimeType: "application/octet-stream", want: "reports/ab/" + sum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := ContentKey(ReportDir, sum, tt.mimeType)
+			if key != tt.want {
+				t.Errorf("ContentKey() = %q, want %q", key, tt.want)
+			}
+			if got, ok := HashFromKey(key); !ok || got != sum {
+				t.Errorf("HashFromKey(%q) = %q, %v, want %q", key, got, ok, sum)
+			}
+		})
+	}
+}
+
+func TestContentTypeForKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"reports/ab/abcd.pdf", "application/pdf"},
+		{"reports/ab/abcd.jpg", "image/jpeg"},
+		{"reports/ab/abcd.jpg.thumb.jpg", "image/jpeg"},
+		{"reports/2024/01/legacy.JPEG", "image/jpeg"},
+		{"reports/ab/abcd.txt", "text/plain; charset=utf-8"},
+		{"reports/2024/01/poc.html", DefaultContentType},
+		{"reports/2024/01/poc.svg", DefaultContentType},
+		{"reports/2024/01/poc.xhtml", DefaultContentType},
+		{"reports/ab/abcd", DefaultContentType},
+		{"chunks/session/000001", DefaultContentType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := ContentTypeForKey(tt.key); got != tt.want {
+				t.Errorf("ContentTypeForKey(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMimeExtensionsRoundTrip(t *testing.T) {
+	// 允许上传的类型生成的存储键，下载时都能得到对应的内容类型
+	for mimeType, ext := range mimeExtensions {
+		contentType := ContentTypeForKey("reports/ab/abcd" + ext)
+		if contentType == DefaultContentType {
+			t.Errorf("extension %s of %s has no content type", ext, mimeType)
+		}
+		if InlineContentTypes[contentType] && !strings.HasPrefix(mimeType, "image/") {
+			t.Errorf("%s would be served inline as %s", mimeType, contentType)
+		}
+	}
+}
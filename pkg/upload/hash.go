@@ -0,0 +1,54 @@
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"path"
+	"regexp"
+	"time"
+)
+
+// DedupRefreshAge 复用已存在的相同内容文件时，修改时间早于该时长的文件会重新写入以刷新修改时间，
+// 避免刚被再次上传的旧文件在关联到报告前被孤儿文件回收（回收保留期不能短于该时长）
+const DedupRefreshAge = 24 * time.Hour
+
+// contentKeyPattern 内容寻址存储键的文件名部分
+var contentKeyPattern = regexp.MustCompile(`^([0-9a-f]{64})(\.[a-z0-9]{1,10})?$`)
+
+// ContentKey 根据目录、SHA-256 和检测到的 MIME 类型生成内容寻址的存储键
+// 如 reports/9f/9f86d081...0a08.pdf，前两位分目录避免单目录文件过多；扩展名由 MIME 类型决定，不使用客户端文件名
+func ContentKey(dir, sum, mimeType string) string {
+	return path.Join(dir, sum[:2], sum+ExtensionForMime(mimeType))
+}
+
+// HashFromKey 从内容寻址的存储键中解析 SHA-256，历史文件（按时间命名）返回 false
+func HashFromKey(key string) (string, bool) {
+	m := contentKeyPattern.FindStringSubmatch(path.Base(key))
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// HashObject 读取存储中的文件并计算 SHA-256，返回十六进制哈希和实际字节数
+func HashObject(ctx context.Context, storage Storage, key string) (string, int64, error) {
+	reader, _, err := storage.Open(ctx, key)
+	if err != nil {
+		return "", 0, err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, reader)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// NeedsRefresh 判断复用的已存在文件是否需要重新写入以刷新修改时间
+func NeedsRefresh(info *ObjectInfo) bool {
+	return time.Since(info.ModTime) > DedupRefreshAge
+}
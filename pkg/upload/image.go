@@ -0,0 +1,362 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // 注册 GIF 解码器（生成缩略图）
+	"image/jpeg"
+	_ "image/png" // 注册 PNG 解码器（生成缩略图）
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 注册 WebP 解码器（生成缩略图）
+)
+
+const (
+	// ThumbnailSize 缩略图边长（像素），按中心裁剪为正方形
+	ThumbnailSize = 256
+	// ThumbnailSuffix 缩略图存储键后缀，缩略图与原图放在同一目录：{原图存储键}.thumb.jpg
+	ThumbnailSuffix = ".thumb.jpg"
+	// MaxImagePixels 生成缩略图和旋转图片时允许解码的最大像素数，防止解压炸弹
+	MaxImagePixels = 50 * 1000 * 1000
+
+	thumbnailQuality = 80
+	orientedQuality  = 95
+)
+
+// ErrInvalidImage 图片格式错误
+var ErrInvalidImage = errors.New("图片格式错误")
+
+// MetadataMimeTypes 上传时需要去除元数据（EXIF GPS、设备信息等）的图片类型
+var MetadataMimeTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/jpg":  true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// ThumbnailMimeTypes 上传时生成缩略图的图片类型
+var ThumbnailMimeTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/jpg":  true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// ThumbnailKey 缩略图的存储键
+func ThumbnailKey(key string) string {
+	return key + ThumbnailSuffix
+}
+
+// SourceKey 缩略图对应的原图存储键，非缩略图原样返回（用于下载鉴权）
+func SourceKey(key string) string {
+	return strings.TrimSuffix(key, ThumbnailSuffix)
+}
+
+// StripMetadata 去除图片中的元数据，不重新编码像素数据
+// JPEG 去除 EXIF/XMP/IPTC 等 APP 段和注释（带方向信息的照片会先按方向旋转后重新编码），
+// PNG 去除文本、EXIF 和时间块，WebP 去除 EXIF 和 XMP 块
+func StripMetadata(data []byte, mimeType string) ([]byte, error) {
+	switch mimeType {
+	case "image/jpeg", "image/jpg":
+		return stripJPEG(data)
+	case "image/png":
+		return stripPNG(data)
+	case "image/webp":
+		return stripWebP(data)
+	default:
+		return data, nil
+	}
+}
+
+// MakeThumbnail 生成 ThumbnailSize x ThumbnailSize 的 JPEG 缩略图（按中心裁剪，透明背景填充为白色）
+func MakeThumbnail(data []byte) ([]byte, error) {
+	img, err := decodeImage(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// 取中心的正方形区域
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+	src := image.Rect(x0, y0, x0+side, y0+side)
+
+	thumb := image.NewRGBA(image.Rect(0, 0, ThumbnailSize, ThumbnailSize))
+	draw.Draw(thumb, thumb.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(thumb, thumb.Bounds(), img, src, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeImage 解码图片，先检查尺寸避免解码超大图片
+func decodeImage(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > MaxImagePixels {
+		return nil, fmt.Errorf("图片尺寸过大（%dx%d）", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	return img, nil
+}
+
+// ---------- JPEG ----------
+
+// stripJPEG 去除 JPEG 的元数据段
+// 保留 APP0 (JFIF)、APP2 (ICC 色彩配置) 和 APP14 (Adobe 色彩变换)，其余 APP 段和 COM 注释全部去除
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, ErrInvalidImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+	orientation := 1
+
+	pos := 2
+	for {
+		if pos+4 > len(data) || data[pos] != 0xFF {
+			return nil, ErrInvalidImage
+		}
+		marker := data[pos+1]
+		if marker == 0xFF {
+			// 填充字节
+			pos++
+			continue
+		}
+		if marker == 0xD9 {
+			// EOI
+			out = append(out, data[pos:]...)
+			break
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			// 无长度的独立标记
+			out = append(out, data[pos:pos+2]...)
+			pos += 2
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, ErrInvalidImage
+		}
+		segment := data[pos:end]
+		payload := data[pos+4 : end]
+
+		if marker == 0xDA {
+			// SOS 之后是压缩数据，原样保留到文件结尾
+			out = append(out, data[pos:]...)
+			break
+		}
+
+		switch {
+		case marker == 0xE1 && bytes.HasPrefix(payload, []byte("Exif\x00\x00")):
+			orientation = exifOrientation(payload[6:])
+		case marker == 0xE0, marker == 0xEE,
+			marker == 0xE2 && bytes.HasPrefix(payload, []byte("ICC_PROFILE\x00")):
+			out = append(out, segment...)
+		case marker >= 0xE0 && marker <= 0xEF, marker == 0xFE:
+			// 其余 APP 段（XMP、IPTC、厂商私有数据等）和注释
+		default:
+			out = append(out, segment...)
+		}
+		pos = end
+	}
+
+	if orientation > 1 && orientation <= 8 {
+		// 去除 EXIF 后方向信息随之丢失，按方向旋转像素后重新编码，保证显示方向不变
+		return orientJPEG(out, orientation)
+	}
+	return out, nil
+}
+
+// exifOrientation 从 EXIF (TIFF) 数据中读取 IFD0 的方向标签 (0x0112)，读取失败返回 1
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8 : entry+10]))
+		}
+	}
+	return 1
+}
+
+// orientJPEG 按 EXIF 方向旋转/翻转图片并重新编码
+func orientJPEG(data []byte, orientation int) ([]byte, error) {
+	img, err := decodeImage(data)
+	if err != nil {
+		return nil, err
+	}
+	oriented := applyOrientation(img, orientation)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, oriented, &jpeg.Options{Quality: orientedQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// applyOrientation 将 EXIF 方向 2-8 对应的变换应用到像素上
+func applyOrientation(img image.Image, orientation int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if orientation >= 5 {
+		w, h = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // 水平翻转
+				dx, dy = b.Dx()-1-x, y
+			case 3: // 旋转 180°
+				dx, dy = b.Dx()-1-x, b.Dy()-1-y
+			case 4: // 垂直翻转
+				dx, dy = x, b.Dy()-1-y
+			case 5: // 沿主对角线翻转
+				dx, dy = y, x
+			case 6: // 顺时针旋转 90°
+				dx, dy = b.Dy()-1-y, x
+			case 7: // 沿副对角线翻转
+				dx, dy = b.Dy()-1-y, b.Dx()-1-x
+			case 8: // 逆时针旋转 90°
+				dx, dy = y, b.Dx()-1-x
+			default:
+				dx, dy = x, y
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// ---------- PNG ----------
+
+// pngMetadataChunks PNG 中需要去除的元数据块
+var pngMetadataChunks = map[string]bool{
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"eXIf": true,
+	"tIME": true,
+}
+
+// stripPNG 去除 PNG 的文本、EXIF 和时间块
+func stripPNG(data []byte) ([]byte, error) {
+	const signature = "\x89PNG\r\n\x1a\n"
+	if !bytes.HasPrefix(data, []byte(signature)) {
+		return nil, ErrInvalidImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, signature...)
+
+	pos := len(signature)
+	for pos < len(data) {
+		// 块结构：[长度 4][类型 4][数据][CRC 4]
+		if pos+8 > len(data) {
+			return nil, ErrInvalidImage
+		}
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		chunkType := string(data[pos+4 : pos+8])
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, ErrInvalidImage
+		}
+		if !pngMetadataChunks[chunkType] {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+		if chunkType == "IEND" {
+			break
+		}
+	}
+	return out, nil
+}
+
+// ---------- WebP ----------
+
+// VP8X 扩展头中的 EXIF 和 XMP 标志位
+const (
+	webpFlagEXIF = 0x08
+	webpFlagXMP  = 0x04
+)
+
+// stripWebP 去除 WebP 的 EXIF 和 XMP 块，并清除 VP8X 头中对应的标志位
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, ErrInvalidImage
+	}
+
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+
+	pos := 12
+	for pos < len(data) {
+		// 块结构：[FourCC 4][长度 4, 小端][数据][补齐到偶数字节]
+		if pos+8 > len(data) {
+			return nil, ErrInvalidImage
+		}
+		fourCC := string(data[pos : pos+4])
+		length := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		end := pos + 8 + length + length%2
+		if length < 0 || end > len(data) {
+			return nil, ErrInvalidImage
+		}
+
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			start := len(out)
+			out = append(out, data[pos:end]...)
+			if length > 0 {
+				out[start+8] &^= webpFlagEXIF | webpFlagXMP
+			}
+		default:
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	return out, nil
+}
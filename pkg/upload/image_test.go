@@ -0,0 +1,273 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// testImage 生成 w x h 的纯色图片
+func testImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	return img
+}
+
+// jpegSegment 构造 JPEG 段：FF marker + 长度 + 数据
+func jpegSegment(marker byte, payload []byte) []byte {
+	seg := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(payload)+2))
+	return append(seg, payload...)
+}
+
+// exifPayload 构造只含方向标签的 APP1 EXIF 数据（大端 TIFF）
+func exifPayload(orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	entry := make([]byte, 2+12)
+	binary.BigEndian.PutUint16(entry[0:], 1)      // 条目数
+	binary.BigEndian.PutUint16(entry[2:], 0x0112) // Orientation
+	binary.BigEndian.PutUint16(entry[4:], 3)      // SHORT
+	binary.BigEndian.PutUint32(entry[6:], 1)
+	binary.BigEndian.PutUint16(entry[10:], orientation)
+	return append([]byte("Exif\x00\x00"), append(tiff, entry...)...)
+}
+
+// jpegWithSegments 编码 w x h 的 JPEG 并在 SOI 之后插入额外的段
+func jpegWithSegments(t *testing.T, w, h int, segments ...[]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(w, h), nil); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	out := append([]byte{}, data[:2]...)
+	for _, seg := range segments {
+		out = append(out, seg...)
+	}
+	return append(out, data[2:]...)
+}
+
+// pngChunk 构造带正确 CRC 的 PNG 块
+func pngChunk(chunkType string, data []byte) []byte {
+	chunk := make([]byte, 8, 12+len(data))
+	binary.BigEndian.PutUint32(chunk[0:], uint32(len(data)))
+	copy(chunk[4:], chunkType)
+	chunk = append(chunk, data...)
+	crc := crc32.ChecksumIEEE(chunk[4:])
+	return binary.BigEndian.AppendUint32(chunk, crc)
+}
+
+// pngWithChunks 编码 PNG 并在 IHDR 之后插入额外的块
+func pngWithChunks(t *testing.T, chunks ...[]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(2, 2)); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	ihdrEnd := 8 + 12 + int(binary.BigEndian.Uint32(data[8:12]))
+	out := append([]byte{}, data[:ihdrEnd]...)
+	for _, chunk := range chunks {
+		out = append(out, chunk...)
+	}
+	return append(out, data[ihdrEnd:]...)
+}
+
+// webpChunk 构造 WebP 块（奇数长度补齐一个字节）
+func webpChunk(fourCC string, data []byte) []byte {
+	chunk := make([]byte, 8, 8+len(data)+1)
+	copy(chunk, fourCC)
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(len(data)))
+	chunk = append(chunk, data...)
+	if len(data)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+// webpFile 拼接 RIFF 头和块
+func webpFile(chunks ...[]byte) []byte {
+	body := []byte("WEBP")
+	for _, chunk := range chunks {
+		body = append(body, chunk...)
+	}
+	out := []byte("RIFF\x00\x00\x00\x00")
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(body)))
+	return append(out, body...)
+}
+
+func TestStripMetadataJPEG(t *testing.T) {
+	tests := []struct {
+		name       string
+		segments   [][]byte
+		wantWidth  int
+		wantHeight int
+	}{
+		{
+			name:      "无元数据",
+			wantWidth: 4, wantHeight: 2,
+		},
+		{
+			name: "去除 EXIF、XMP 和注释",
+			segments: [][]byte{
+				jpegSegment(0xE1, exifPayload(1)),
+				jpegSegment(0xE1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta/>")),
+				jpegSegment(0xED, []byte("Photoshop 3.0\x00IPTC")),
+				jpegSegment(0xFE, []byte("secret comment")),
+			},
+			wantWidth: 4, wantHeight: 2,
+		},
+		{
+			name:      "方向 6 旋转 90 度",
+			segments:  [][]byte{jpegSegment(0xE1, exifPayload(6))},
+			wantWidth: 2, wantHeight: 4,
+		},
+		{
+			name:      "方向 3 尺寸不变",
+			segments:  [][]byte{jpegSegment(0xE1, exifPayload(3))},
+			wantWidth: 4, wantHeight: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := jpegWithSegments(t, 4, 2, tt.segments...)
+			out, err := StripMetadata(data, "image/jpeg")
+			if err != nil {
+				t.Fatalf("StripMetadata() error = %v", err)
+			}
+			for _, leaked := range []string{"Exif", "xmpmeta", "Photoshop", "secret comment"} {
+				if bytes.Contains(out, []byte(leaked)) {
+					t.Errorf("output still contains %q", leaked)
+				}
+			}
+			cfg, err := jpeg.DecodeConfig(bytes.NewReader(out))
+			if err != nil {
+				t.Fatalf("output is not a valid JPEG: %v", err)
+			}
+			if cfg.Width != tt.wantWidth || cfg.Height != tt.wantHeight {
+				t.Errorf("size = %dx%d, want %dx%d", cfg.Width, cfg.Height, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestStripMetadataPNG(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks [][]byte
+	}{
+		{name: "无元数据"},
+		{
+			name: "去除文本、EXIF 和时间块",
+			chunks: [][]byte{
+				pngChunk("tEXt", []byte("Author\x00secret")),
+				pngChunk("zTXt", []byte("Comment\x00\x00secret")),
+				pngChunk("iTXt", []byte("XML:com.adobe.xmp\x00\x00\x00\x00\x00secret")),
+				pngChunk("eXIf", []byte("MM\x00\x2asecret")),
+				pngChunk("tIME", []byte{0x07, 0xE8, 1, 1, 0, 0, 0}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := StripMetadata(pngWithChunks(t, tt.chunks...), "image/png")
+			if err != nil {
+				t.Fatalf("StripMetadata() error = %v", err)
+			}
+			for _, leaked := range []string{"tEXt", "zTXt", "iTXt", "eXIf", "tIME", "secret"} {
+				if bytes.Contains(out, []byte(leaked)) {
+					t.Errorf("output still contains %q", leaked)
+				}
+			}
+			if _, err := png.Decode(bytes.NewReader(out)); err != nil {
+				t.Fatalf("output is not a valid PNG: %v", err)
+			}
+		})
+	}
+}
+
+func TestStripMetadataWebP(t *testing.T) {
+	vp8x := make([]byte, 10)
+	vp8x[0] = webpFlagEXIF | webpFlagXMP | 0x10 // 0x10：Alpha 标志，应保留
+	pixels := []byte("VP8L-image-data")
+
+	tests := []struct {
+		name      string
+		input     []byte
+		wantFlags byte
+	}{
+		{
+			name:      "去除 EXIF 和 XMP 并清除标志位",
+			input:     webpFile(webpChunk("VP8X", vp8x), webpChunk("VP8L", pixels), webpChunk("EXIF", []byte("MM\x00\x2asecret")), webpChunk("XMP ", []byte("<x:xmpmeta>secret"))),
+			wantFlags: 0x10,
+		},
+		{
+			name:  "简单格式无 VP8X",
+			input: webpFile(webpChunk("VP8L", pixels)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := StripMetadata(tt.input, "image/webp")
+			if err != nil {
+				t.Fatalf("StripMetadata() error = %v", err)
+			}
+			if bytes.Contains(out, []byte("secret")) || bytes.Contains(out, []byte("EXIF")) || bytes.Contains(out, []byte("XMP ")) {
+				t.Error("output still contains metadata")
+			}
+			if !bytes.Contains(out, pixels) {
+				t.Error("image data was removed")
+			}
+			if size := binary.LittleEndian.Uint32(out[4:8]); int(size) != len(out)-8 {
+				t.Errorf("RIFF size = %d, want %d", size, len(out)-8)
+			}
+			if i := bytes.Index(out, []byte("VP8X")); i >= 0 && out[i+8] != tt.wantFlags {
+				t.Errorf("VP8X flags = %#x, want %#x", out[i+8], tt.wantFlags)
+			}
+		})
+	}
+}
+
+func TestStripMetadataInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		mimeType string
+	}{
+		{"JPEG 缺少 SOI", []byte("not a jpeg"), "image/jpeg"},
+		{"JPEG 段长度越界", []byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF, 0xFF, 0x00}, "image/jpeg"},
+		{"PNG 签名错误", []byte("\x89PNX\r\n\x1a\n"), "image/png"},
+		{"PNG 块长度越界", append([]byte("\x89PNG\r\n\x1a\n"), 0, 0, 0xFF, 0xFF, 'I', 'H', 'D', 'R'), "image/png"},
+		{"WebP 头错误", []byte("RIFF\x04\x00\x00\x00WEBX"), "image/webp"},
+		{"WebP 块长度越界", webpFile([]byte("VP8L\xFF\xFF\x00\x00")), "image/webp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := StripMetadata(tt.data, tt.mimeType); !errors.Is(err, ErrInvalidImage) {
+				t.Errorf("StripMetadata() error = %v, want ErrInvalidImage", err)
+			}
+		})
+	}
+}
+
+func TestStripMetadataOtherTypes(t *testing.T) {
+	data := []byte("GIF89a...")
+	out, err := StripMetadata(data, "image/gif")
+	if err != nil || !bytes.Equal(out, data) {
+		t.Errorf("StripMetadata() = %q, %v, want input unchanged", out, err)
+	}
+}
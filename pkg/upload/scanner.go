@@ -0,0 +1,78 @@
+package upload
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"fmt"
+	"io"
+	"path"
+)
+
+// 扫描驱动
+const (
+	ScannerDriverNoop  = "noop"
+	ScannerDriverClamd = "clamd"
+)
+
+// QuarantineDir 隔离目录，感染文件移动到 quarantine/{原存储键}（不可通过下载接口访问）
+const QuarantineDir = "quarantine"
+
+// ScanResult 扫描结果
+type ScanResult struct {
+	Infected  bool
+	Signature string // 命中的病毒特征名称，如 Eicar-Signature
+}
+
+// Scanner 恶意软件扫描器接口
+type Scanner interface {
+	// Scan 扫描文件内容，扫描器不可用或扫描失败时返回 error（此时无法判断文件是否安全）
+	Scan(ctx context.Context, r io.Reader) (*ScanResult, error)
+}
+
+// NewScanner 根据配置创建扫描器
+func NewScanner(cfg config.ScannerConfig) (Scanner, error) {
+	switch cfg.Driver {
+	case "", ScannerDriverNoop:
+		return NewNoopScanner(), nil
+	case ScannerDriverClamd:
+		return NewClamdScanner(cfg.Clamd), nil
+	default:
+		return nil, fmt.Errorf("不支持的扫描驱动: %s", cfg.Driver)
+	}
+}
+
+// ScanObject 从存储后端读取文件并扫描
+func ScanObject(ctx context.Context, storage Storage, scanner Scanner, key string) (*ScanResult, error) {
+	reader, _, err := storage.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return scanner.Scan(ctx, reader)
+}
+
+// QuarantineKey 文件隔离后的存储键
+func QuarantineKey(key string) string {
+	return path.Join(QuarantineDir, key)
+}
+
+// Quarantine 将文件移动到隔离目录，原存储键不再可访问
+func Quarantine(ctx context.Context, storage Storage, key string) error {
+	if err := Move(ctx, storage, key, QuarantineKey(key)); err != nil {
+		return fmt.Errorf("隔离文件失败: %v", err)
+	}
+	return nil
+}
+
+// noopScanner 不扫描，所有文件都视为安全（未部署扫描服务时使用）
+type noopScanner struct{}
+
+// NewNoopScanner 创建不执行扫描的扫描器
+func NewNoopScanner() Scanner {
+	return noopScanner{}
+}
+
+// Scan 直接返回安全
+func (noopScanner) Scan(ctx context.Context, r io.Reader) (*ScanResult, error) {
+	return &ScanResult{}, nil
+}
@@ -0,0 +1,153 @@
+package upload
+
+import (
+	"bufio"
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// clamdChunkSize INSTREAM 每次发送的数据块大小
+	clamdChunkSize = 32 * 1024
+	// defaultClamdTimeout 默认单个文件扫描超时
+	defaultClamdTimeout = 60 * time.Second
+	// defaultClamdSocket 默认 clamd Unix 套接字（Debian/Ubuntu 安装位置）
+	defaultClamdSocket = "/var/run/clamav/clamd.ctl"
+)
+
+// ClamdScanner 通过 clamd 协议（INSTREAM 命令）将文件内容发送给 ClamAV 守护进程扫描
+type ClamdScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamdScanner 创建 ClamAV 扫描器（不在创建时连接，clamd 暂不可用不影响服务启动）
+func NewClamdScanner(cfg config.ClamdScannerConfig) *ClamdScanner {
+	network := cfg.Network
+	if network == "" {
+		network = "unix"
+	}
+	address := cfg.Address
+	if address == "" && network == "unix" {
+		address = defaultClamdSocket
+	}
+	timeout := defaultClamdTimeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	return &ClamdScanner{network: network, address: address, timeout: timeout}
+}
+
+// dial 连接 clamd，连接的读写截止时间为扫描超时
+func (s *ClamdScanner) dial(ctx context.Context) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return nil, fmt.Errorf("连接 clamd 失败: %v", err)
+	}
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+	return conn, nil
+}
+
+// Ping 检查 clamd 是否可用
+func (s *ClamdScanner) Ping(ctx context.Context) error {
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("zPING\x00")); err != nil {
+		return fmt.Errorf("发送 clamd 命令失败: %v", err)
+	}
+	reply, err := readClamdReply(conn)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("clamd 响应异常: %s", reply)
+	}
+	return nil
+}
+
+// Scan 扫描文件内容
+// 协议：发送 zINSTREAM\0，然后发送若干 [4字节大端长度][数据] 块，以长度为 0 的块结束；
+// clamd 返回 "stream: OK"、"stream: {特征名} FOUND" 或 "... ERROR"
+func (s *ClamdScanner) Scan(ctx context.Context, r io.Reader) (*ScanResult, error) {
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, fmt.Errorf("发送 clamd 命令失败: %v", err)
+	}
+
+	buf := make([]byte, 4+clamdChunkSize)
+	for {
+		n, readErr := io.ReadFull(r, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, err := conn.Write(buf[:4+n]); err != nil {
+				// clamd 超出 StreamMaxLength 时会提前关闭连接，尝试读取它返回的错误
+				if reply, replyErr := readClamdReply(conn); replyErr == nil {
+					return nil, fmt.Errorf("clamd 扫描失败: %s", reply)
+				}
+				return nil, fmt.Errorf("发送文件内容失败: %v", err)
+			}
+		}
+		if readErr == io.EOF || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("读取文件失败: %v", readErr)
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return nil, fmt.Errorf("发送文件内容失败: %v", err)
+	}
+
+	reply, err := readClamdReply(conn)
+	if err != nil {
+		return nil, err
+	}
+	return parseClamdReply(reply)
+}
+
+// readClamdReply 读取以 \0 结尾的 clamd 响应
+func readClamdReply(conn net.Conn) (string, error) {
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return "", fmt.Errorf("读取 clamd 响应失败: %v", err)
+	}
+	return strings.TrimSpace(strings.TrimRight(reply, "\x00")), nil
+}
+
+// parseClamdReply 解析 INSTREAM 扫描结果
+func parseClamdReply(reply string) (*ScanResult, error) {
+	result := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case result == "OK":
+		return &ScanResult{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return &ScanResult{Infected: true, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamd 扫描失败: %s", reply)
+	}
+}
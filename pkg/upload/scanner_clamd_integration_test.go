@@ -0,0 +1,54 @@
+//go:build integration
+
+package upload
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+// eicarTestFile EICAR 标准反病毒测试文件（无害，所有杀毒引擎都会报毒）
+const eicarTestFile = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// 需要运行中的 clamd，例如：
+//
+//	docker run -d -p 3310:3310 clamav/clamav
+//	CLAMD_ADDR=127.0.0.1:3310 go test -tags integration ./pkg/upload/
+func TestClamdScannerIntegration(t *testing.T) {
+	address := os.Getenv("CLAMD_ADDR")
+	if address == "" {
+		t.Skip("CLAMD_ADDR 未设置，跳过 clamd 集成测试")
+	}
+	scanner := NewClamdScanner(config.ClamdScannerConfig{Network: "tcp", Address: address})
+
+	if err := scanner.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		content      string
+		wantInfected bool
+	}{
+		{name: "干净文件", content: "hello, world"},
+		{name: "EICAR 测试文件", content: eicarTestFile, wantInfected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := scanner.Scan(context.Background(), strings.NewReader(tt.content))
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if result.Infected != tt.wantInfected {
+				t.Errorf("Scan() infected = %v, want %v", result.Infected, tt.wantInfected)
+			}
+			if tt.wantInfected && result.Signature == "" {
+				t.Error("Scan() signature is empty")
+			}
+		})
+	}
+}
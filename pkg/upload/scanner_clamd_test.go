@@ -0,0 +1,146 @@
+package upload
+
+import (
+	"bufio"
+	"bug-bounty-lite/pkg/config"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestParseClamdReply(t *testing.T) {
+	tests := []struct {
+		name          string
+		reply         string
+		wantInfected  bool
+		wantSignature string
+		wantErr       bool
+	}{
+		{name: "干净文件", reply: "stream: OK"},
+		{name: "无 stream 前缀", reply: "OK"},
+		{name: "发现病毒", reply: "stream: Eicar-Signature FOUND", wantInfected: true, wantSignature: "Eicar-Signature"},
+		{name: "特征名含空格", reply: "stream: Win.Test.EICAR_HDB-1 (v2) FOUND", wantInfected: true, wantSignature: "Win.Test.EICAR_HDB-1 (v2)"},
+		{name: "超出大小限制", reply: "INSTREAM size limit exceeded. ERROR", wantErr: true},
+		{name: "扫描错误", reply: "stream: Can't allocate memory ERROR", wantErr: true},
+		{name: "空响应", reply: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseClamdReply(tt.reply)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseClamdReply(%q) error = nil, want error", tt.reply)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseClamdReply(%q) error = %v", tt.reply, err)
+			}
+			if result.Infected != tt.wantInfected || result.Signature != tt.wantSignature {
+				t.Errorf("parseClamdReply(%q) = %+v, want infected=%v signature=%q", tt.reply, result, tt.wantInfected, tt.wantSignature)
+			}
+		})
+	}
+}
+
+// fakeClamd 模拟 clamd：读取 zINSTREAM 命令和数据块，把收到的内容交给 reply 生成响应
+func fakeClamd(t *testing.T, reply func(content []byte) string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				command, err := r.ReadString(0)
+				if err != nil {
+					return
+				}
+				if command == "zPING\x00" {
+					conn.Write([]byte("PONG\x00"))
+					return
+				}
+				var content bytes.Buffer
+				for {
+					var size uint32
+					if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+						return
+					}
+					if size == 0 {
+						break
+					}
+					if _, err := io.CopyN(&content, r, int64(size)); err != nil {
+						return
+					}
+				}
+				conn.Write([]byte(reply(content.Bytes()) + "\x00"))
+			}(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestClamdScannerScan(t *testing.T) {
+	address := fakeClamd(t, func(content []byte) string {
+		if bytes.Contains(content, []byte("EICAR")) {
+			return "stream: Eicar-Signature FOUND"
+		}
+		return "stream: OK"
+	})
+	scanner := NewClamdScanner(config.ClamdScannerConfig{Network: "tcp", Address: address})
+
+	if err := scanner.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		content      string
+		wantInfected bool
+	}{
+		{name: "空文件", content: ""},
+		{name: "小文件", content: "hello"},
+		{name: "跨多个数据块", content: strings.Repeat("a", clamdChunkSize*2+1)},
+		{name: "恶意文件", content: strings.Repeat("a", clamdChunkSize) + "EICAR", wantInfected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := scanner.Scan(context.Background(), strings.NewReader(tt.content))
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if result.Infected != tt.wantInfected {
+				t.Errorf("Scan() infected = %v, want %v", result.Infected, tt.wantInfected)
+			}
+		})
+	}
+}
+
+func TestClamdScannerUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	scanner := NewClamdScanner(config.ClamdScannerConfig{Network: "tcp", Address: address, Timeout: 1})
+	if _, err := scanner.Scan(context.Background(), strings.NewReader("hello")); err == nil {
+		t.Error("Scan() error = nil, want connection error")
+	}
+}
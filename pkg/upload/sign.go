@@ -0,0 +1,105 @@
+package upload
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// FileRoutePrefix 受控文件下载路由前缀，上传文件的访问 URL 均以此开头
+	FileRoutePrefix = "/api/v1/files/"
+	// DefaultSignedURLTTL 签名 URL 默认有效期
+	DefaultSignedURLTTL = 15 * time.Minute
+)
+
+// URLSigner 为文件下载 URL 生成和校验 HMAC 签名
+// 签名内容为 "文件路径\n过期时间戳"，签名和过期时间通过 signature、expires 查询参数传递
+type URLSigner struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewURLSigner 创建 URL 签名器，ttl 不大于 0 时使用默认有效期
+func NewURLSigner(secret string, ttl time.Duration) *URLSigner {
+	if ttl <= 0 {
+		ttl = DefaultSignedURLTTL
+	}
+	return &URLSigner{secret: []byte(secret), ttl: ttl}
+}
+
+// TTL 返回签名 URL 的有效期
+func (s *URLSigner) TTL() time.Duration {
+	return s.ttl
+}
+
+// SignURL 为文件 URL 追加签名参数，URL 不是受控文件地址时原样返回
+func (s *URLSigner) SignURL(fileURL string) string {
+	filePath, ok := FilePathFromURL(fileURL)
+	if !ok {
+		return fileURL
+	}
+
+	expires := time.Now().Add(s.ttl).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.sign(filePath, expires))
+
+	base := fileURL
+	if i := strings.IndexAny(base, "?#"); i >= 0 {
+		base = base[:i]
+	}
+	return base + "?" + query.Encode()
+}
+
+// Verify 校验文件路径的签名是否有效且未过期
+func (s *URLSigner) Verify(filePath string, expiresParam string, signature string) bool {
+	expires, err := strconv.ParseInt(expiresParam, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+	expected := s.sign(filePath, expires)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func (s *URLSigner) sign(filePath string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(filePath + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// FilePathFromURL 从文件访问 URL 中取出 uploads 目录下的相对路径（如 reports/2024/01/xxx.pdf）
+// 兼容迁移前的 /uploads/ 地址
+func FilePathFromURL(fileURL string) (string, bool) {
+	if i := strings.IndexAny(fileURL, "?#"); i >= 0 {
+		fileURL = fileURL[:i]
+	}
+
+	var rel string
+	if i := strings.Index(fileURL, FileRoutePrefix); i >= 0 {
+		rel = fileURL[i+len(FileRoutePrefix):]
+	} else if i := strings.Index(fileURL, "/uploads/"); i >= 0 {
+		rel = fileURL[i+len("/uploads/"):]
+	} else {
+		return "", false
+	}
+	return CleanFilePath(rel)
+}
+
+// CleanFilePath 规范化 uploads 目录下的相对路径，拒绝空路径、绝对路径和目录穿越
+func CleanFilePath(rel string) (string, bool) {
+	rel = strings.TrimPrefix(rel, "/")
+	if rel == "" || strings.HasPrefix(rel, "/") || strings.Contains(rel, "\\") {
+		return "", false
+	}
+	cleaned := path.Clean(rel)
+	if cleaned != rel || cleaned == "." || strings.HasPrefix(cleaned, "../") || cleaned == ".." {
+		return "", false
+	}
+	return cleaned, true
+}
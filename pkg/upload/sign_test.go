@@ -0,0 +1,153 @@
+package upload
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestURLSignerSignAndVerify(t *testing.T) {
+	signer := NewURLSigner("secret", time.Minute)
+	signed := signer.SignURL("/api/v1/files/reports/2024/01/a.pdf")
+
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Path != "/api/v1/files/reports/2024/01/a.pdf" {
+		t.Fatalf("SignURL() path = %s", u.Path)
+	}
+	expires := u.Query().Get("expires")
+	signature := u.Query().Get("signature")
+	if exp, _ := strconv.ParseInt(expires, 10, 64); exp < time.Now().Unix() || exp > time.Now().Add(time.Minute).Unix() {
+		t.Errorf("expires = %s, want within TTL", expires)
+	}
+
+	other := NewURLSigner("other-secret", time.Minute)
+	past := strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)
+	pastSignature := signer.sign("reports/2024/01/a.pdf", time.Now().Add(-time.Second).Unix())
+	farFuture := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		signer    *URLSigner
+		filePath  string
+		expires   string
+		signature string
+		want      bool
+	}{
+		{name: "有效签名", signer: signer, filePath: "reports/2024/01/a.pdf", expires: expires, signature: signature, want: true},
+		{name: "换一个文件", signer: signer, filePath: "reports/2024/01/b.pdf", expires: expires, signature: signature},
+		{name: "延长过期时间", signer: signer, filePath: "reports/2024/01/a.pdf", expires: farFuture, signature: signature},
+		{name: "已过期", signer: signer, filePath: "reports/2024/01/a.pdf", expires: past, signature: pastSignature},
+		{name: "其他密钥", signer: other, filePath: "reports/2024/01/a.pdf", expires: expires, signature: signature},
+		{name: "签名大小写不同", signer: signer, filePath: "reports/2024/01/a.pdf", expires: expires, signature: strings.ToUpper(signature)},
+		{name: "签名为空", signer: signer, filePath: "reports/2024/01/a.pdf", expires: expires},
+		{name: "过期时间无效", signer: signer, filePath: "reports/2024/01/a.pdf", expires: "never", signature: signature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.signer.Verify(tt.filePath, tt.expires, tt.signature); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLSignerSignURL(t *testing.T) {
+	signer := NewURLSigner("secret", 0)
+	if signer.TTL() != DefaultSignedURLTTL {
+		t.Errorf("TTL() = %v, want default %v", signer.TTL(), DefaultSignedURLTTL)
+	}
+
+	tests := []struct {
+		fileURL  string
+		wantBase string // 为空表示原样返回
+	}{
+		{"/api/v1/files/reports/a.pdf", "/api/v1/files/reports/a.pdf"},
+		// 已有的查询参数和锚点（包括旧签名）被替换
+		{"/api/v1/files/reports/a.pdf?expires=1&signature=abc#x", "/api/v1/files/reports/a.pdf"},
+		{"https://bbl.example.com/api/v1/files/projects/b.png", "https://bbl.example.com/api/v1/files/projects/b.png"},
+		{"/uploads/reports/a.pdf", "/uploads/reports/a.pdf"},
+		{"https://cdn.example.com/a.pdf", ""},
+		{"/api/v1/files/../config.yaml", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fileURL, func(t *testing.T) {
+			got := signer.SignURL(tt.fileURL)
+			if tt.wantBase == "" {
+				if got != tt.fileURL {
+					t.Errorf("SignURL() = %s, want unchanged", got)
+				}
+				return
+			}
+			base, query, _ := strings.Cut(got, "?")
+			values, _ := url.ParseQuery(query)
+			if base != tt.wantBase || len(values) != 2 || values.Get("signature") == "" || values.Get("expires") == "" {
+				t.Errorf("SignURL() = %s, want %s?expires=...&signature=...", got, tt.wantBase)
+			}
+		})
+	}
+}
+
+func TestFilePathFromURL(t *testing.T) {
+	tests := []struct {
+		fileURL string
+		want    string
+		wantOK  bool
+	}{
+		{"/api/v1/files/reports/2024/01/a.pdf", "reports/2024/01/a.pdf", true},
+		{"https://bbl.example.com/api/v1/files/reports/a.pdf?expires=1&signature=x", "reports/a.pdf", true},
+		{"/uploads/projects/b.png#preview", "projects/b.png", true},
+		{"/api/v1/files/", "", false},
+		{"/api/v1/files/reports/../../config.yaml", "", false},
+		{"/static/a.pdf", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fileURL, func(t *testing.T) {
+			got, ok := FilePathFromURL(tt.fileURL)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("FilePathFromURL() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCleanFilePath(t *testing.T) {
+	tests := []struct {
+		rel    string
+		want   string
+		wantOK bool
+	}{
+		{"reports/2024/01/a.pdf", "reports/2024/01/a.pdf", true},
+		{"/reports/a.pdf", "reports/a.pdf", true},
+		{"", "", false},
+		{"/", "", false},
+		{".", "", false},
+		{"..", "", false},
+		{"../config.yaml", "", false},
+		{"reports/../../config.yaml", "", false},
+		// 非规范路径一律拒绝，不尝试修正
+		{"reports/../projects/a.png", "", false},
+		{"reports/./a.pdf", "", false},
+		{"reports//a.pdf", "", false},
+		{"reports/", "", false},
+		{`reports\..\..\config.yaml`, "", false},
+		{"//etc/passwd", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rel, func(t *testing.T) {
+			got, ok := CleanFilePath(tt.rel)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("CleanFilePath(%q) = %q, %v, want %q, %v", tt.rel, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
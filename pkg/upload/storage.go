@@ -0,0 +1,80 @@
+package upload
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// 存储驱动
+const (
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
+)
+
+// OrphanDir 回收的孤儿文件目录，文件移动到 orphaned/{原存储键}（不可通过下载接口访问）
+const OrphanDir = "orphaned"
+
+// ErrObjectNotFound 文件不存在
+var ErrObjectNotFound = errors.New("文件不存在")
+
+// ObjectInfo 存储对象信息
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage 上传文件存储后端接口
+// key 为 uploads 目录下的相对路径（使用 / 分隔），如 reports/2024/01/xxx.pdf
+type Storage interface {
+	// Put 写入文件，size 未知时传 -1；内容类型由存储键扩展名确定（见 ContentTypeForKey）
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Open 读取文件，文件不存在时返回 ErrObjectNotFound，调用方负责关闭
+	Open(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	// Stat 获取文件信息，文件不存在时返回 ErrObjectNotFound
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Delete 删除文件，文件不存在不视为错误
+	Delete(ctx context.Context, key string) error
+	// Walk 遍历 prefix 下的所有文件（prefix 为空时遍历全部），fn 返回错误时停止遍历
+	Walk(ctx context.Context, prefix string, fn func(key string, info *ObjectInfo) error) error
+}
+
+// Move 移动文件（复制后删除原文件）
+func Move(ctx context.Context, storage Storage, from, to string) error {
+	reader, info, err := storage.Open(ctx, from)
+	if err != nil {
+		return err
+	}
+	err = storage.Put(ctx, to, reader, info.Size)
+	reader.Close()
+	if err != nil {
+		return err
+	}
+	return storage.Delete(ctx, from)
+}
+
+// Touch 原样重新写入文件以刷新修改时间
+func Touch(ctx context.Context, storage Storage, key string) error {
+	reader, info, err := storage.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return storage.Put(ctx, key, reader, info.Size)
+}
+
+// NewStorage 根据配置创建存储后端
+func NewStorage(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "", StorageDriverLocal:
+		return NewLocalStorage(cfg.Local.Root), nil
+	case StorageDriverS3:
+		return NewS3Storage(cfg.S3)
+	default:
+		return nil, fmt.Errorf("不支持的存储驱动: %s", cfg.Driver)
+	}
+}
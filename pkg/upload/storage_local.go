@@ -0,0 +1,165 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultLocalRoot 本地存储默认根目录
+const DefaultLocalRoot = "uploads"
+
+// LocalStorage 本地文件系统存储
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage 创建本地存储，root 为空时使用 uploads 目录
+func NewLocalStorage(root string) *LocalStorage {
+	if root == "" {
+		root = DefaultLocalRoot
+	}
+	return &LocalStorage{root: root}
+}
+
+// path 将存储键转换为本地路径，拒绝目录穿越
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned, ok := CleanFilePath(key)
+	if !ok {
+		return "", fmt.Errorf("无效的文件路径: %s", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+// Put 写入文件，先写临时文件再重命名，避免读到写了一半的文件
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fullPath)
+}
+
+// Open 读取文件，内容类型按存储键扩展名确定
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return nil, nil, ErrObjectNotFound
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, ErrObjectNotFound
+		}
+		return nil, nil, err
+	}
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		file.Close()
+		return nil, nil, ErrObjectNotFound
+	}
+
+	return file, &ObjectInfo{
+		Size:        info.Size(),
+		ContentType: ContentTypeForKey(key),
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
+// Stat 获取文件信息
+func (s *LocalStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return nil, ErrObjectNotFound
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, ErrObjectNotFound
+	}
+
+	return &ObjectInfo{
+		Size:        info.Size(),
+		ContentType: ContentTypeForKey(key),
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
+// Delete 删除文件
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Walk 遍历目录下的文件（跳过写入中的临时文件）
+func (s *LocalStorage) Walk(ctx context.Context, prefix string, fn func(key string, info *ObjectInfo) error) error {
+	start := s.root
+	if prefix != "" {
+		fullPath, err := s.path(prefix)
+		if err != nil {
+			return err
+		}
+		start = fullPath
+	}
+
+	err := filepath.WalkDir(start, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, fullPath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		info, err := s.Stat(ctx, key)
+		if err != nil {
+			return err
+		}
+		return fn(key, info)
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,171 @@
+package upload
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Storage S3 兼容对象存储（AWS S3、MinIO 等），多个应用实例可共享同一存储桶
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Storage 创建 S3 存储并检查存储桶是否存在
+func NewS3Storage(cfg config.S3StorageConfig) (*S3Storage, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("S3 存储需配置 endpoint 和 bucket")
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建 S3 客户端失败: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("连接 S3 存储失败: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("S3 存储桶不存在: %s", cfg.Bucket)
+	}
+
+	return &S3Storage{
+		client: client,
+		bucket: cfg.Bucket,
+		prefix: strings.Trim(cfg.Prefix, "/"),
+	}, nil
+}
+
+// objectName 将存储键转换为对象名（加上配置的前缀）
+func (s *S3Storage) objectName(key string) (string, error) {
+	cleaned, ok := CleanFilePath(key)
+	if !ok {
+		return "", fmt.Errorf("无效的文件路径: %s", key)
+	}
+	if s.prefix == "" {
+		return cleaned, nil
+	}
+	return path.Join(s.prefix, cleaned), nil
+}
+
+// Put 上传对象，对象的内容类型按存储键扩展名确定（与本地存储一致）
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	name, err := s.objectName(key)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(ctx, s.bucket, name, r, size, minio.PutObjectOptions{
+		ContentType: ContentTypeForKey(key),
+	})
+	return err
+}
+
+// Open 读取对象
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	name, err := s.objectName(key)
+	if err != nil {
+		return nil, nil, ErrObjectNotFound
+	}
+
+	obj, err := s.client.GetObject(ctx, s.bucket, name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	// GetObject 不会发起请求，通过 Stat 确认对象存在并获取元信息
+	stat, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		if isS3NotFound(err) {
+			return nil, nil, ErrObjectNotFound
+		}
+		return nil, nil, err
+	}
+
+	return obj, &ObjectInfo{
+		Size:        stat.Size,
+		ContentType: ContentTypeForKey(key),
+		ModTime:     stat.LastModified,
+	}, nil
+}
+
+// Stat 获取对象信息
+func (s *S3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	name, err := s.objectName(key)
+	if err != nil {
+		return nil, ErrObjectNotFound
+	}
+
+	stat, err := s.client.StatObject(ctx, s.bucket, name, minio.StatObjectOptions{})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	return &ObjectInfo{
+		Size:        stat.Size,
+		ContentType: ContentTypeForKey(key),
+		ModTime:     stat.LastModified,
+	}, nil
+}
+
+// Delete 删除对象（S3 删除不存在的对象不会报错）
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	name, err := s.objectName(key)
+	if err != nil {
+		return err
+	}
+	return s.client.RemoveObject(ctx, s.bucket, name, minio.RemoveObjectOptions{})
+}
+
+// Walk 遍历前缀下的对象
+func (s *S3Storage) Walk(ctx context.Context, prefix string, fn func(key string, info *ObjectInfo) error) error {
+	listPrefix := path.Join(s.prefix, prefix)
+	if listPrefix != "" {
+		listPrefix += "/"
+	}
+
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{
+		Prefix:    listPrefix,
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			return object.Err
+		}
+		key := strings.TrimPrefix(strings.TrimPrefix(object.Key, s.prefix), "/")
+		if key == "" || strings.HasSuffix(key, "/") {
+			continue
+		}
+		if err := fn(key, &ObjectInfo{
+			Size:        object.Size,
+			ContentType: ContentTypeForKey(key),
+			ModTime:     object.LastModified,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isS3NotFound 判断是否为对象不存在错误
+func isS3NotFound(err error) bool {
+	resp := minio.ToErrorResponse(err)
+	return resp.Code == "NoSuchKey" || resp.StatusCode == 404
+}
@@ -0,0 +1,41 @@
+//go:build integration
+
+package upload
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+// 需要 S3 兼容存储和已创建的存储桶，例如：
+//
+//	docker run -d -p 9000:9000 minio/minio server /data
+//	mc mb local/bug-bounty-test
+//	MINIO_ENDPOINT=127.0.0.1:9000 MINIO_ACCESS_KEY=minioadmin MINIO_SECRET_KEY=minioadmin \
+//	MINIO_BUCKET=bug-bounty-test go test -tags integration ./pkg/upload/
+func TestS3StorageIntegration(t *testing.T) {
+	endpoint := os.Getenv("MINIO_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("MINIO_ENDPOINT 未设置，跳过 S3 集成测试")
+	}
+	bucket := os.Getenv("MINIO_BUCKET")
+	if bucket == "" {
+		bucket = "bug-bounty-test"
+	}
+
+	storage, err := NewS3Storage(config.S3StorageConfig{
+		Endpoint:  endpoint,
+		AccessKey: os.Getenv("MINIO_ACCESS_KEY"),
+		SecretKey: os.Getenv("MINIO_SECRET_KEY"),
+		Bucket:    bucket,
+		UseSSL:    os.Getenv("MINIO_USE_SSL") == "true",
+		Prefix:    fmt.Sprintf("integration-%d", time.Now().UnixNano()),
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage() error = %v", err)
+	}
+	testStorageContract(t, storage, "reports")
+}
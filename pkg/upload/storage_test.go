@@ -0,0 +1,111 @@
+package upload
+
+import (
+	"bug-bounty-lite/pkg/config"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// testStorageContract 检查存储后端的通用行为（本地存储直接运行，S3 在集成测试中运行）
+func testStorageContract(t *testing.T, storage Storage, prefix string) {
+	t.Helper()
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		key             string
+		content         string
+		wantContentType string
+	}{
+		{name: "PDF", key: prefix + "/ab/abcd.pdf", content: "%PDF-1.4", wantContentType: "application/pdf"},
+		{name: "PNG 缩略图", key: prefix + "/ab/abcd.png.thumb.jpg", content: "jpeg", wantContentType: "image/jpeg"},
+		{name: "HTML 按二进制返回", key: prefix + "/2024/01/poc.html", content: "<script>alert(1)</script>", wantContentType: DefaultContentType},
+		{name: "SVG 按二进制返回", key: prefix + "/2024/01/poc.svg", content: "<svg onload=alert(1)>", wantContentType: DefaultContentType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := storage.Put(ctx, tt.key, strings.NewReader(tt.content), int64(len(tt.content))); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			t.Cleanup(func() { storage.Delete(ctx, tt.key) })
+
+			info, err := storage.Stat(ctx, tt.key)
+			if err != nil {
+				t.Fatalf("Stat() error = %v", err)
+			}
+			if info.Size != int64(len(tt.content)) || info.ContentType != tt.wantContentType {
+				t.Errorf("Stat() = %+v, want size %d content type %q", info, len(tt.content), tt.wantContentType)
+			}
+
+			reader, info, err := storage.Open(ctx, tt.key)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			data, err := io.ReadAll(reader)
+			reader.Close()
+			if err != nil || string(data) != tt.content {
+				t.Errorf("Open() content = %q, %v, want %q", data, err, tt.content)
+			}
+			if info.ContentType != tt.wantContentType {
+				t.Errorf("Open() content type = %q, want %q", info.ContentType, tt.wantContentType)
+			}
+		})
+	}
+
+	t.Run("Walk", func(t *testing.T) {
+		key := prefix + "/cd/walk.txt"
+		if err := storage.Put(ctx, key, strings.NewReader("walk"), 4); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+		defer storage.Delete(ctx, key)
+
+		found := false
+		err := storage.Walk(ctx, prefix, func(walked string, info *ObjectInfo) error {
+			if walked == key {
+				found = info.ContentType == "text/plain; charset=utf-8" && info.Size == 4
+			}
+			return nil
+		})
+		if err != nil || !found {
+			t.Errorf("Walk() found = %v, err = %v", found, err)
+		}
+	})
+
+	t.Run("不存在的文件", func(t *testing.T) {
+		key := prefix + "/ef/missing.pdf"
+		if _, err := storage.Stat(ctx, key); !errors.Is(err, ErrObjectNotFound) {
+			t.Errorf("Stat() error = %v, want ErrObjectNotFound", err)
+		}
+		if _, _, err := storage.Open(ctx, key); !errors.Is(err, ErrObjectNotFound) {
+			t.Errorf("Open() error = %v, want ErrObjectNotFound", err)
+		}
+		if err := storage.Delete(ctx, key); err != nil {
+			t.Errorf("Delete() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("拒绝目录穿越", func(t *testing.T) {
+		for _, key := range []string{"../escape.txt", prefix + "/../../escape.txt", "//etc/passwd"} {
+			if err := storage.Put(ctx, key, strings.NewReader("x"), 1); err == nil {
+				t.Errorf("Put(%q) error = nil, want invalid path error", key)
+			}
+		}
+	})
+}
+
+func TestLocalStorage(t *testing.T) {
+	testStorageContract(t, NewLocalStorage(t.TempDir()), "reports")
+}
+
+func TestNewStorage(t *testing.T) {
+	if _, err := NewStorage(config.StorageConfig{}); err != nil {
+		t.Errorf("NewStorage() 默认驱动 error = %v", err)
+	}
+	if _, err := NewStorage(config.StorageConfig{Driver: "ftp"}); err == nil {
+		t.Error("NewStorage() 不支持的驱动 error = nil")
+	}
+}
@@ -1,21 +1,32 @@
 package upload
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strings"
-	"time"
 )
 
 const (
 	// MaxFileSize 最大文件大小 10MB
 	MaxFileSize = 10 * 1024 * 1024
-	// UploadDir 上传目录
-	UploadDir = "uploads/reports"
+	// MaxAvatarSize 头像最大文件大小 2MB
+	MaxAvatarSize = 2 * 1024 * 1024
+	// ReportDir 报告附件目录
+	ReportDir = "reports"
+	// ProjectAttachmentDir 项目附件目录
+	ProjectAttachmentDir = "projects"
+	// AvatarDir 头像目录（公开访问）
+	AvatarDir = "avatars"
+	// AvatarRoutePrefix 头像公开访问路由前缀
+	AvatarRoutePrefix = "/uploads/avatars/"
 )
 
 // AllowedMimeTypes 允许的 MIME 类型
@@ -30,25 +41,92 @@ var AllowedMimeTypes = map[string]bool{
 	"text/plain": true,
 }
 
+// AvatarMimeTypes 头像允许的 MIME 类型
+var AvatarMimeTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/jpg":  true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
 // UploadResult 上传结果
 type UploadResult struct {
 	URL      string `json:"url"`
 	Filename string `json:"filename"`
 	Size     int64  `json:"size"`
 	MimeType string `json:"mime_type"`
+	SHA256   string `json:"sha256"`
+	// 缩略图地址（仅图片），ThumbnailSize x ThumbnailSize 的 JPEG
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	// 安全扫描状态（clean/pending），pending 时不返回签名下载地址
+	ScanStatus string `json:"scan_status,omitempty"`
+	// 带签名的临时下载地址，供上传者在文件被报告/项目引用前预览（仅通过安全扫描的文件）
+	SignedURL          string `json:"signed_url,omitempty"`
+	SignedThumbnailURL string `json:"signed_thumbnail_url,omitempty"`
+}
+
+// Uploader 文件上传器，校验文件后写入存储后端
+// 文件按内容寻址：存储键为 {dir}/{sha256前2位}/{sha256}{扩展名}（扩展名由检测到的 MIME 类型决定），与下载地址中的文件路径一致，
+// 相同内容的文件只保存一份，由多条附件记录共享。
+// JPEG/PNG/WebP 图片在保存前去除元数据（哈希按去除后的内容计算），图片同时生成缩略图 {存储键}.thumb.jpg
+type Uploader struct {
+	storage Storage
+}
+
+// NewUploader 创建文件上传器
+func NewUploader(storage Storage) *Uploader {
+	return &Uploader{storage: storage}
+}
+
+// Storage 返回上传器使用的存储后端
+func (u *Uploader) Storage() Storage {
+	return u.storage
+}
+
+// UploadFile 上传报告附件
+func (u *Uploader) UploadFile(ctx context.Context, fileHeader *multipart.FileHeader, baseURL string) (*UploadResult, error) {
+	return u.UploadFileTo(ctx, fileHeader, baseURL, ReportDir)
 }
 
-// UploadFile 上传单个文件
-func UploadFile(fileHeader *multipart.FileHeader, baseURL string) (*UploadResult, error) {
+// UploadFileTo 上传单个文件到指定目录，通过受控下载接口访问
+func (u *Uploader) UploadFileTo(ctx context.Context, fileHeader *multipart.FileHeader, baseURL string, dir string) (*UploadResult, error) {
+	result, key, thumbKey, err := u.save(ctx, fileHeader, dir, MaxFileSize, AllowedMimeTypes)
+	if err != nil {
+		return nil, err
+	}
+	result.URL = strings.TrimSuffix(baseURL, "/") + FileRoutePrefix + key
+	if thumbKey != "" {
+		result.ThumbnailURL = strings.TrimSuffix(baseURL, "/") + FileRoutePrefix + thumbKey
+	}
+	return result, nil
+}
+
+// UploadAvatar 上传头像（公开访问）
+func (u *Uploader) UploadAvatar(ctx context.Context, fileHeader *multipart.FileHeader, baseURL string) (*UploadResult, error) {
+	result, key, thumbKey, err := u.save(ctx, fileHeader, AvatarDir, MaxAvatarSize, AvatarMimeTypes)
+	if err != nil {
+		return nil, err
+	}
+	result.URL = strings.TrimSuffix(baseURL, "/") + AvatarRoutePrefix + strings.TrimPrefix(key, AvatarDir+"/")
+	if thumbKey != "" {
+		result.ThumbnailURL = strings.TrimSuffix(baseURL, "/") + AvatarRoutePrefix + strings.TrimPrefix(thumbKey, AvatarDir+"/")
+	}
+	return result, nil
+}
+
+// save 校验文件大小和类型，图片去除元数据后计算 SHA-256 写入存储（内容已存在时跳过写入）
+// 返回不含访问地址的上传结果、存储键和缩略图存储键（非图片或缩略图生成失败时为空）
+func (u *Uploader) save(ctx context.Context, fileHeader *multipart.FileHeader, dir string, maxSize int64, allowed map[string]bool) (*UploadResult, string, string, error) {
 	// 1. 验证文件大小
-	if fileHeader.Size > MaxFileSize {
-		return nil, fmt.Errorf("文件大小超过限制（最大10MB）")
+	if fileHeader.Size > maxSize {
+		return nil, "", "", fmt.Errorf("文件大小超过限制（最大%dMB）", maxSize/1024/1024)
 	}
 
 	// 2. 打开文件
 	file, err := fileHeader.Open()
 	if err != nil {
-		return nil, fmt.Errorf("打开文件失败: %v", err)
+		return nil, "", "", fmt.Errorf("打开文件失败: %v", err)
 	}
 	defer file.Close()
 
@@ -56,123 +134,127 @@ func UploadFile(fileHeader *multipart.FileHeader, baseURL string) (*UploadResult
 	buffer := make([]byte, 512)
 	_, err = file.Read(buffer)
 	if err != nil && err != io.EOF {
-		return nil, fmt.Errorf("读取文件失败: %v", err)
+		return nil, "", "", fmt.Errorf("读取文件失败: %v", err)
 	}
-	file.Seek(0, 0) // 重置文件指针
 
 	mimeType := http.DetectContentType(buffer)
-	if !AllowedMimeTypes[mimeType] {
-		return nil, fmt.Errorf("不支持的文件类型: %s", mimeType)
+	if !allowed[mimeType] {
+		if maxSize == MaxAvatarSize {
+			return nil, "", "", fmt.Errorf("不支持的文件类型，只支持 jpg/png/gif/webp 格式")
+		}
+		return nil, "", "", fmt.Errorf("不支持的文件类型: %s", mimeType)
 	}
-
-	// 4. 生成文件名和路径
-	now := time.Now()
-	year := now.Format("2006")
-	month := now.Format("01")
-
-	// 生成唯一文件名
-	ext := filepath.Ext(fileHeader.Filename)
-	filename := fmt.Sprintf("%d%s", time.Now().UnixNano(), ext)
-
-	// 创建目录
-	dir := filepath.Join(UploadDir, year, month)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, fmt.Errorf("创建目录失败: %v", err)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, "", "", fmt.Errorf("读取文件失败: %v", err)
 	}
 
-	// 完整路径
-	fullPath := filepath.Join(dir, filename)
-
-	// 5. 保存文件
-	dst, err := os.Create(fullPath)
-	if err != nil {
-		return nil, fmt.Errorf("创建文件失败: %v", err)
+	// 4. 图片去除元数据（EXIF GPS、设备信息等）
+	var content io.ReadSeeker = file
+	size := fileHeader.Size
+	var imageData []byte
+	if ThumbnailMimeTypes[mimeType] {
+		if imageData, err = io.ReadAll(file); err != nil {
+			return nil, "", "", fmt.Errorf("读取文件失败: %v", err)
+		}
+		if MetadataMimeTypes[mimeType] {
+			if imageData, err = StripMetadata(imageData, mimeType); err != nil {
+				return nil, "", "", err
+			}
+		}
+		content = bytes.NewReader(imageData)
+		size = int64(len(imageData))
 	}
-	defer dst.Close()
 
-	if _, err := io.Copy(dst, file); err != nil {
-		return nil, fmt.Errorf("保存文件失败: %v", err)
+	// 5. 计算内容哈希，生成存储键
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return nil, "", "", fmt.Errorf("读取文件失败: %v", err)
 	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	key := ContentKey(dir, sum, mimeType)
 
-	// 6. 生成访问 URL
-	url := fmt.Sprintf("%s/%s/%s/%s/%s", strings.TrimSuffix(baseURL, "/"), UploadDir, year, month, filename)
-
-	return &UploadResult{
-		URL:      url,
+	result := &UploadResult{
 		Filename: fileHeader.Filename,
-		Size:     fileHeader.Size,
+		Size:     size,
 		MimeType: mimeType,
-	}, nil
-}
-
-// AvatarMimeTypes 头像允许的 MIME 类型
-var AvatarMimeTypes = map[string]bool{
-	"image/jpeg": true,
-	"image/jpg":  true,
-	"image/png":  true,
-	"image/gif":  true,
-	"image/webp": true,
-}
-
-// UploadFileToDir 上传文件到指定子目录（用于头像等特殊上传）
-func UploadFileToDir(fileHeader *multipart.FileHeader, baseURL string, subDir string) (*UploadResult, error) {
-	// 1. 验证文件大小（头像限制为 2MB）
-	maxSize := int64(2 * 1024 * 1024)
-	if fileHeader.Size > maxSize {
-		return nil, fmt.Errorf("文件大小超过限制（最大2MB）")
+		SHA256:   sum,
 	}
 
-	// 2. 打开文件
-	file, err := fileHeader.Open()
-	if err != nil {
-		return nil, fmt.Errorf("打开文件失败: %v", err)
+	// 6. 写入存储后端（相同内容已存在时直接复用）
+	if err := StoreContent(ctx, u.storage, key, content, size); err != nil {
+		return nil, "", "", err
 	}
-	defer file.Close()
 
-	// 3. 验证 MIME 类型（头像只允许图片）
-	buffer := make([]byte, 512)
-	_, err = file.Read(buffer)
-	if err != nil && err != io.EOF {
-		return nil, fmt.Errorf("读取文件失败: %v", err)
+	// 7. 图片生成缩略图（失败不影响上传）
+	thumbKey := ""
+	if imageData != nil {
+		thumbKey = SaveThumbnail(ctx, u.storage, key, imageData)
 	}
-	file.Seek(0, 0) // 重置文件指针
+	return result, key, thumbKey, nil
+}
 
-	mimeType := http.DetectContentType(buffer)
-	if !AvatarMimeTypes[mimeType] {
-		return nil, fmt.Errorf("不支持的文件类型，只支持 jpg/png/gif/webp 格式")
+// StoreContent 写入内容寻址的文件，相同内容已存在时直接复用（修改时间过早的重新写入以刷新修改时间）
+func StoreContent(ctx context.Context, storage Storage, key string, content io.ReadSeeker, size int64) error {
+	info, err := storage.Stat(ctx, key)
+	if err != nil && !errors.Is(err, ErrObjectNotFound) {
+		return fmt.Errorf("保存文件失败: %v", err)
 	}
-
-	// 4. 生成文件名和路径
-	ext := filepath.Ext(fileHeader.Filename)
-	filename := fmt.Sprintf("%d%s", time.Now().UnixNano(), ext)
-
-	// 创建目录
-	dir := filepath.Join("uploads", subDir)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, fmt.Errorf("创建目录失败: %v", err)
+	if err == nil && !NeedsRefresh(info) {
+		return nil
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("读取文件失败: %v", err)
 	}
+	if err := storage.Put(ctx, key, content, size); err != nil {
+		return fmt.Errorf("保存文件失败: %v", err)
+	}
+	return nil
+}
 
-	// 完整路径
-	fullPath := filepath.Join(dir, filename)
+// SaveThumbnail 生成并保存缩略图，返回缩略图存储键（失败时记录日志并返回空）
+func SaveThumbnail(ctx context.Context, storage Storage, key string, imageData []byte) string {
+	thumbKey := ThumbnailKey(key)
+	if _, err := storage.Stat(ctx, thumbKey); err == nil {
+		return thumbKey
+	}
 
-	// 5. 保存文件
-	dst, err := os.Create(fullPath)
+	thumb, err := MakeThumbnail(imageData)
 	if err != nil {
-		return nil, fmt.Errorf("创建文件失败: %v", err)
+		log.Printf("[WARN] Failed to generate thumbnail for %s: %v", key, err)
+		return ""
 	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, file); err != nil {
-		return nil, fmt.Errorf("保存文件失败: %v", err)
+	if err := storage.Put(ctx, thumbKey, bytes.NewReader(thumb), int64(len(thumb))); err != nil {
+		log.Printf("[WARN] Failed to save thumbnail for %s: %v", key, err)
+		return ""
 	}
+	return thumbKey
+}
 
-	// 6. 生成访问 URL
-	url := fmt.Sprintf("%s/uploads/%s/%s", strings.TrimSuffix(baseURL, "/"), subDir, filename)
+// FormatSize 将字节数格式化为显示用的大小，如 "256KB"、"1.2MB"
+func FormatSize(size int64) string {
+	switch {
+	case size >= 1024*1024:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(size)/1024/1024), ".0") + "MB"
+	case size >= 1024:
+		return fmt.Sprintf("%dKB", size/1024)
+	default:
+		return fmt.Sprintf("%dB", size)
+	}
+}
 
-	return &UploadResult{
-		URL:      url,
-		Filename: fileHeader.Filename,
-		Size:     fileHeader.Size,
-		MimeType: mimeType,
-	}, nil
+// FileTypeFromMime 将 MIME 类型归类为显示用的文件类型（pdf, doc, image, text）
+func FileTypeFromMime(mimeType string) string {
+	switch {
+	case mimeType == "application/pdf":
+		return "pdf"
+	case strings.HasPrefix(mimeType, "image/"):
+		return "image"
+	case strings.HasPrefix(mimeType, "text/"):
+		return "text"
+	case mimeType == "application/msword",
+		mimeType == "application/vnd.openxmlformats-officedocument.wordprocessingml.document":
+		return "doc"
+	default:
+		return "file"
+	}
 }